@@ -0,0 +1,52 @@
+package core2p2p
+
+import (
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/NethermindEth/juno/p2p/gen"
+	"github.com/NethermindEth/juno/utils"
+)
+
+func AdaptProof(proof *trie.ProofNodeSet) *gen.PatriciaRangeProof {
+	return &gen.PatriciaRangeProof{
+		Nodes: utils.Map(proof.List(), adaptProofNode),
+	}
+}
+
+func adaptProofNode(node trie.ProofNode) *gen.PatriciaNode {
+	switch n := node.(type) {
+	case *trie.Binary:
+		return &gen.PatriciaNode{
+			Node: &gen.PatriciaNode_Binary_{
+				Binary: &gen.PatriciaNode_Binary{
+					Left:  AdaptHash(n.LeftHash),
+					Right: AdaptHash(n.RightHash),
+				},
+			},
+		}
+	case *trie.Edge:
+		path := n.Path.Felt()
+		return &gen.PatriciaNode{
+			Node: &gen.PatriciaNode_Edge_{
+				Edge: &gen.PatriciaNode_Edge{
+					Length: uint32(n.Path.Len()),
+					Path:   AdaptFelt(&path),
+					Child:  AdaptHash(n.Child),
+				},
+			},
+		}
+	default:
+		panic(fmt.Errorf("unknown proof node type %T", n))
+	}
+}
+
+func AdaptContractState(addr, classHash, nonce, storageRoot *felt.Felt) *gen.ContractState {
+	return &gen.ContractState{
+		Address:     AdaptAddress(addr),
+		ClassHash:   AdaptHash(classHash),
+		Nonce:       AdaptFelt(nonce),
+		StorageRoot: AdaptHash(storageRoot),
+	}
+}
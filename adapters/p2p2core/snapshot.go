@@ -0,0 +1,53 @@
+package p2p2core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/NethermindEth/juno/p2p/gen"
+)
+
+const maxEdgeLength = 251
+
+// AdaptProof adapts the nodes of a range proof of a trie hashed with hash. Unlike most adapters it validates its
+// input, since the proof is only checked against a root after being adapted.
+func AdaptProof(proof *gen.PatriciaRangeProof, hash crypto.HashFn) (*trie.ProofNodeSet, error) {
+	nodes := trie.NewProofNodeSet()
+	for _, node := range proof.GetNodes() {
+		var proofNode trie.ProofNode
+		switch n := node.GetNode().(type) {
+		case *gen.PatriciaNode_Binary_:
+			if n.Binary.Left == nil || n.Binary.Right == nil {
+				return nil, errors.New("binary node without children")
+			}
+			proofNode = &trie.Binary{
+				LeftHash:  AdaptHash(n.Binary.Left),
+				RightHash: AdaptHash(n.Binary.Right),
+			}
+		case *gen.PatriciaNode_Edge_:
+			if n.Edge.Child == nil || n.Edge.Path == nil {
+				return nil, errors.New("edge node without child or path")
+			}
+			if n.Edge.Length == 0 || n.Edge.Length > maxEdgeLength {
+				return nil, fmt.Errorf("invalid edge length %d", n.Edge.Length)
+			}
+
+			path := trie.FeltToKey(uint8(n.Edge.Length), AdaptFelt(n.Edge.Path))
+			truncated := path
+			truncated.Truncate(path.Len())
+			if !truncated.Equal(&path) {
+				return nil, fmt.Errorf("edge path is longer than %d bits", n.Edge.Length)
+			}
+			proofNode = &trie.Edge{
+				Child: AdaptHash(n.Edge.Child),
+				Path:  &path,
+			}
+		default:
+			return nil, fmt.Errorf("unknown proof node type %T", n)
+		}
+		nodes.Put(*proofNode.Hash(hash), proofNode)
+	}
+	return nodes, nil
+}
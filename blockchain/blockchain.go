@@ -20,12 +20,18 @@ type L1HeadSubscription struct {
 	*feed.Subscription[*core.L1Head]
 }
 
+type StoredBlockSubscription struct {
+	*feed.Subscription[*core.Block]
+}
+
 //go:generate mockgen -destination=../mocks/mock_blockchain.go -package=mocks github.com/NethermindEth/juno/blockchain Reader
 type Reader interface {
 	Height() (height uint64, err error)
 
 	Head() (head *core.Block, err error)
 	L1Head() (*core.L1Head, error)
+	L1VerifiedHeight() (uint64, error)
+	L1StateUpdateByBlockNumber(number uint64) (*core.L1StateUpdate, error)
 	SubscribeL1Head() L1HeadSubscription
 	BlockByNumber(number uint64) (block *core.Block, err error)
 	BlockByHash(hash *felt.Felt) (block *core.Block, err error)
@@ -40,8 +46,21 @@ type Reader interface {
 	StateUpdateByNumber(number uint64) (update *core.StateUpdate, err error)
 	StateUpdateByHash(hash *felt.Felt) (update *core.StateUpdate, err error)
 	L1HandlerTxnHash(msgHash *common.Hash) (l1HandlerTxnHash *felt.Felt, err error)
+	L1ToL2Message(hash common.Hash) (msg *core.L1ToL2MessageLog, err error)
+	L2ToL1Message(hash common.Hash) (msg *core.L2ToL1MessageLog, err error)
+	L1ToL2Messages(address *felt.Felt, status *core.MessageStatus, startAt common.Hash, limit uint64) (
+		msgs []*core.L1ToL2MessageLog, err error)
+	L2ToL1Messages(address *felt.Felt, status *core.MessageStatus, startAt common.Hash, limit uint64) (
+		msgs []*core.L2ToL1MessageLog, err error)
+	L1ToL2MessagesByL1TxnHash(txnHash common.Hash) (msgs []*core.L1ToL2MessageLog, err error)
+	TransactionBySenderAndNonce(sender, nonce *felt.Felt) (transaction *SenderTransaction, err error)
+	TransactionsBySender(sender, fromNonce *felt.Felt, limit uint64) (transactions []SenderTransaction, err error)
+	ContractInfo(address *felt.Felt) (info *ContractInfo, err error)
+	ClassDeclaration(classHash *felt.Felt) (declaration *ClassDeclaration, err error)
+	ContractsByClassHash(classHash, startAt *felt.Felt, limit uint64) (addresses []*felt.Felt, err error)
 
 	HeadState() (core.StateReader, StateCloser, error)
+	HeadStateTries() (core.StateTrieReader, StateCloser, error)
 	StateAtBlockHash(blockHash *felt.Felt) (core.StateReader, StateCloser, error)
 	StateAtBlockNumber(blockNumber uint64) (core.StateReader, StateCloser, error)
 
@@ -88,6 +107,7 @@ type Blockchain struct {
 	database       db.DB
 	listener       EventListener
 	l1HeadFeed     *feed.Feed[*core.L1Head]
+	storedFeed     *feed.Feed[*core.Block]
 	pendingBlockFn func() *core.Block
 }
 
@@ -97,6 +117,7 @@ func New(database db.DB, network *utils.Network, pendingBlockFn func() *core.Blo
 		network:        network,
 		listener:       &SelectiveListener{},
 		l1HeadFeed:     feed.New[*core.L1Head](),
+		storedFeed:     feed.New[*core.Block](),
 		pendingBlockFn: pendingBlockFn,
 	}
 }
@@ -327,11 +348,77 @@ func (b *Blockchain) SetL1Head(update *core.L1Head) error {
 	return nil
 }
 
+// L1VerifiedHeight returns the latest block number whose hash and state root were checked against
+// the ones finalised on L1.
+func (b *Blockchain) L1VerifiedHeight() (uint64, error) {
+	b.listener.OnRead("L1VerifiedHeight")
+	var height uint64
+	return height, b.database.View(func(txn db.Transaction) error {
+		return txn.Get(db.L1VerifiedHeight.Key(), func(heightBytes []byte) error {
+			height = binary.BigEndian.Uint64(heightBytes)
+			return nil
+		})
+	})
+}
+
+func (b *Blockchain) SetL1VerifiedHeight(height uint64) error {
+	return b.database.Update(func(txn db.Transaction) error {
+		return txn.Set(db.L1VerifiedHeight.Key(), core.MarshalBlockNumber(height))
+	})
+}
+
+// StoreL1StateUpdate records the L1 transaction that settled a range of blocks as follows:
+//
+// [db.L1StateUpdatesByBlockNumber](BlockNumber) -> L1StateUpdate
+func (b *Blockchain) StoreL1StateUpdate(update *core.L1StateUpdate) error {
+	updateBytes, err := encoder.Marshal(update)
+	if err != nil {
+		return err
+	}
+	return b.database.Update(func(txn db.Transaction) error {
+		return txn.Set(db.L1StateUpdatesByBlockNumber.Key(core.MarshalBlockNumber(update.BlockNumber)), updateBytes)
+	})
+}
+
+// L1StateUpdateByBlockNumber returns the state update that settled the given block on L1.
+func (b *Blockchain) L1StateUpdateByBlockNumber(number uint64) (*core.L1StateUpdate, error) {
+	b.listener.OnRead("L1StateUpdateByBlockNumber")
+	var update *core.L1StateUpdate
+	return update, b.database.View(func(txn db.Transaction) error {
+		it, err := txn.NewIterator(db.L1StateUpdatesByBlockNumber.Key(), true)
+		if err != nil {
+			return err
+		}
+
+		// the first update settling a block at or after the given one is the only one that can settle it
+		if !it.Seek(db.L1StateUpdatesByBlockNumber.Key(core.MarshalBlockNumber(number))) {
+			return errors.Join(db.ErrKeyNotFound, it.Close())
+		}
+		updateBytes, err := it.Value()
+		if err != nil {
+			return errors.Join(err, it.Close())
+		}
+		if err = encoder.Unmarshal(updateBytes, &update); err != nil {
+			return errors.Join(err, it.Close())
+		}
+		if update.FirstBlockNumber > number {
+			update = nil
+			return errors.Join(db.ErrKeyNotFound, it.Close())
+		}
+		return it.Close()
+	})
+}
+
+// SubscribeStoredBlocks notifies the subscriber of every block stored with [Blockchain.Store].
+func (b *Blockchain) SubscribeStoredBlocks() StoredBlockSubscription {
+	return StoredBlockSubscription{b.storedFeed.Subscribe()}
+}
+
 // Store takes a block and state update and performs sanity checks before putting in the database.
 func (b *Blockchain) Store(block *core.Block, blockCommitments *core.BlockCommitments,
 	stateUpdate *core.StateUpdate, newClasses map[felt.Felt]core.Class,
 ) error {
-	return b.database.Update(func(txn db.Transaction) error {
+	err := b.database.Update(func(txn db.Transaction) error {
 		if err := verifyBlock(txn, block); err != nil {
 			return err
 		}
@@ -339,34 +426,51 @@ func (b *Blockchain) Store(block *core.Block, blockCommitments *core.BlockCommit
 		if err := core.NewState(txn).Update(block.Number, stateUpdate, newClasses); err != nil {
 			return err
 		}
-		if err := StoreBlockHeader(txn, block.Header); err != nil {
-			return err
-		}
+		return storeBlock(txn, block, blockCommitments, stateUpdate)
+	})
+	if err != nil {
+		return err
+	}
 
-		for i, tx := range block.Transactions {
-			if err := storeTransactionAndReceipt(txn, block.Number, uint64(i), tx,
-				block.Receipts[i]); err != nil {
-				return err
-			}
-		}
+	b.storedFeed.Send(block)
+	return nil
+}
 
-		if err := storeStateUpdate(txn, block.Number, stateUpdate); err != nil {
-			return err
-		}
+// storeBlock stores everything but the state of a block and makes it the head of the chain.
+func storeBlock(txn db.Transaction, block *core.Block, blockCommitments *core.BlockCommitments,
+	stateUpdate *core.StateUpdate,
+) error {
+	if err := StoreBlockHeader(txn, block.Header); err != nil {
+		return err
+	}
 
-		if err := StoreBlockCommitments(txn, block.Number, blockCommitments); err != nil {
+	for i, tx := range block.Transactions {
+		if err := storeTransactionAndReceipt(txn, block.Number, uint64(i), tx,
+			block.Receipts[i]); err != nil {
 			return err
 		}
+	}
 
-		if err := StoreL1HandlerMsgHashes(txn, block.Transactions); err != nil {
-			return err
-		}
+	if err := storeStateUpdate(txn, block.Number, stateUpdate); err != nil {
+		return err
+	}
 
-		// Head of the blockchain is maintained as follows:
-		// [db.ChainHeight]() -> (BlockNumber)
-		heightBin := core.MarshalBlockNumber(block.Number)
-		return txn.Set(db.ChainHeight.Key(), heightBin)
-	})
+	if err := StoreBlockCommitments(txn, block.Number, blockCommitments); err != nil {
+		return err
+	}
+
+	if err := StoreL1HandlerMsgHashes(txn, block.Transactions); err != nil {
+		return err
+	}
+
+	if err := StoreSenderNonceIndex(txn, block.Number, block.Transactions); err != nil {
+		return err
+	}
+
+	// Head of the blockchain is maintained as follows:
+	// [db.ChainHeight]() -> (BlockNumber)
+	heightBin := core.MarshalBlockNumber(block.Number)
+	return txn.Set(db.ChainHeight.Key(), heightBin)
 }
 
 // VerifyBlock assumes the block has already been sanity-checked.
@@ -902,6 +1006,10 @@ func (b *Blockchain) revertHead(txn db.Transaction) error {
 		return err
 	}
 
+	if err = revertL1VerifiedHeight(txn, blockNumber); err != nil {
+		return err
+	}
+
 	// Revert chain height.
 	if genesisBlock {
 		return txn.Delete(db.ChainHeight.Key())
@@ -911,6 +1019,29 @@ func (b *Blockchain) revertHead(txn db.Transaction) error {
 	return txn.Set(db.ChainHeight.Key(), heightBin)
 }
 
+// revertL1VerifiedHeight caps the L1 verified height below the reverted block, since the block that
+// replaces it hasn't been checked against L1 yet.
+func revertL1VerifiedHeight(txn db.Transaction, blockNumber uint64) error {
+	var verifiedHeight uint64
+	if err := txn.Get(db.L1VerifiedHeight.Key(), func(heightBytes []byte) error {
+		verifiedHeight = binary.BigEndian.Uint64(heightBytes)
+		return nil
+	}); err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if verifiedHeight < blockNumber {
+		return nil
+	}
+	if blockNumber == 0 {
+		return txn.Delete(db.L1VerifiedHeight.Key())
+	}
+	return txn.Set(db.L1VerifiedHeight.Key(), core.MarshalBlockNumber(blockNumber-1))
+}
+
 func removeTxsAndReceipts(txn db.Transaction, blockNumber, numTxs uint64) error {
 	blockIDAndIndex := txAndReceiptDBKey{
 		Number: blockNumber,
@@ -938,6 +1069,12 @@ func removeTxsAndReceipts(txn db.Transaction, blockNumber, numTxs uint64) error
 				return err
 			}
 		}
+		if sender, nonce, ok := senderAndNonce(reorgedTxn); ok {
+			if err = txn.Delete(db.TransactionBlockNumbersAndIndicesBySenderAndNonce.Key(sender.Marshal(),
+				nonce.Marshal())); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
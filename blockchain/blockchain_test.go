@@ -8,6 +8,7 @@ import (
 	"github.com/NethermindEth/juno/blockchain"
 	"github.com/NethermindEth/juno/clients/feeder"
 	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/db"
 	"github.com/NethermindEth/juno/db/pebble"
@@ -300,6 +301,136 @@ func TestStoreL1HandlerTxnHash(t *testing.T) {
 	require.Equal(t, utils.HexToFelt(t, "0x785c2ada3f53fbc66078d47715c27718f92e6e48b96372b36e5197de69b82b5"), l1HandlerTxnHash)
 }
 
+func TestTransactionsBySender(t *testing.T) {
+	client := feeder.NewTestClient(t, &utils.Sepolia)
+	gw := adaptfeeder.New(client)
+	chain := blockchain.New(pebble.NewMemTest(t), &utils.Sepolia, nil)
+	for i := range uint64(5) {
+		block, err := gw.BlockByNumber(context.Background(), i)
+		require.NoError(t, err)
+		stateUpdate, err := gw.StateUpdate(context.Background(), i)
+		require.NoError(t, err)
+		require.NoError(t, chain.Store(block, &emptyCommitments, stateUpdate, nil))
+	}
+
+	sender := utils.HexToFelt(t, "0x43abaa073c768ebf039c0c4f46db9acc39e9ec165690418060a652aab39e7d8")
+
+	t.Run("by sender and nonce", func(t *testing.T) {
+		txn, err := chain.TransactionBySenderAndNonce(sender, new(felt.Felt).SetUint64(5))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), txn.BlockNumber)
+
+		expected, err := chain.TransactionByBlockNumberAndIndex(txn.BlockNumber, txn.Index)
+		require.NoError(t, err)
+		assert.Equal(t, expected, txn.Transaction)
+
+		header, err := chain.BlockHeaderByNumber(txn.BlockNumber)
+		require.NoError(t, err)
+		assert.Equal(t, header.Hash, txn.BlockHash)
+	})
+
+	t.Run("version 0 declares are not indexed", func(t *testing.T) {
+		_, err := chain.TransactionBySenderAndNonce(new(felt.Felt).SetUint64(1), &felt.Zero)
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+
+	t.Run("in nonce order", func(t *testing.T) {
+		txns, err := chain.TransactionsBySender(sender, new(felt.Felt).SetUint64(2), 4)
+		require.NoError(t, err)
+		require.Len(t, txns, 4)
+		for i, txn := range txns {
+			invoke, ok := txn.Transaction.(*core.InvokeTransaction)
+			require.True(t, ok)
+			assert.Equal(t, new(felt.Felt).SetUint64(uint64(i)+2), invoke.Nonce)
+		}
+	})
+
+	t.Run("unknown sender", func(t *testing.T) {
+		txns, err := chain.TransactionsBySender(utils.HexToFelt(t, "0xdead"), &felt.Zero, 10)
+		require.NoError(t, err)
+		assert.Empty(t, txns)
+	})
+
+	t.Run("reverted transactions are removed from the index", func(t *testing.T) {
+		require.NoError(t, chain.RevertHead())
+
+		_, err := chain.TransactionBySenderAndNonce(sender, new(felt.Felt).SetUint64(7))
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+		txns, err := chain.TransactionsBySender(sender, &felt.Zero, 100)
+		require.NoError(t, err)
+		assert.Len(t, txns, 7)
+	})
+}
+
+func TestContractInfoAndClassDeclaration(t *testing.T) {
+	client := feeder.NewTestClient(t, &utils.Sepolia)
+	gw := adaptfeeder.New(client)
+	chain := blockchain.New(pebble.NewMemTest(t), &utils.Sepolia, nil)
+	block0, err := gw.BlockByNumber(context.Background(), 0)
+	require.NoError(t, err)
+	stateUpdate0, err := gw.StateUpdate(context.Background(), 0)
+	require.NoError(t, err)
+
+	accountClassHash := utils.HexToFelt(t, "0x5c478ee27f2112411f86f207605b2e2c58cdb647bac0df27f660ef2252359c6")
+	erc20ClassHash := utils.HexToFelt(t, "0xd0e183745e9dae3e4e78a8ffedcce0903fc4900beace4e0abf192d4c202da3")
+	// the definition is irrelevant to where a class was declared, so any class can stand in for it
+	class, err := gw.Class(context.Background(), utils.HexToFelt(t, "0x7db5c2c2676c2a5bfc892ee4f596b49514e3056a0eee8ad125870b4fb1dd909"))
+	require.NoError(t, err)
+	// an event claiming the deployment of the ERC20 contract, emitted by a contract other than the universal deployer
+	erc20Address := utils.HexToFelt(t, "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+	block0.Receipts[0].Events = append(block0.Receipts[0].Events, &core.Event{
+		From: utils.HexToFelt(t, "0xbad"),
+		Keys: []*felt.Felt{crypto.StarknetKeccak([]byte("ContractDeployed"))},
+		Data: []*felt.Felt{erc20Address},
+	})
+	require.NoError(t, chain.Store(block0, &emptyCommitments, stateUpdate0, map[felt.Felt]core.Class{
+		*erc20ClassHash: class,
+	}))
+
+	t.Run("contract deployed by deploy account", func(t *testing.T) {
+		address := utils.HexToFelt(t, "0x43abaa073c768ebf039c0c4f46db9acc39e9ec165690418060a652aab39e7d8")
+		info, err := chain.ContractInfo(address)
+		require.NoError(t, err)
+		assert.Equal(t, &blockchain.ContractInfo{
+			DeployedAt:      0,
+			DeployBlockHash: block0.Hash,
+			DeployTxnHash:   utils.HexToFelt(t, "0x144f41e654d0916810a83df0fe8984043671200f28df1206f58566144e302dd"),
+			ClassHash:       accountClassHash,
+			ClassHistory:    []blockchain.ClassHashChange{{BlockNumber: 0, ClassHash: accountClassHash}},
+		}, info)
+	})
+
+	t.Run("contract deployed by a deploy syscall", func(t *testing.T) {
+		info, err := chain.ContractInfo(erc20Address)
+		require.NoError(t, err)
+		assert.Equal(t, erc20ClassHash, info.ClassHash)
+		assert.Equal(t, block0.Hash, info.DeployBlockHash)
+		// deployments outside of the universal deployer are only claimed by events that other contracts emit
+		assert.Nil(t, info.DeployTxnHash)
+	})
+
+	t.Run("unknown contract", func(t *testing.T) {
+		_, err := chain.ContractInfo(utils.HexToFelt(t, "0xdead"))
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+
+	t.Run("class declaration", func(t *testing.T) {
+		declaration, err := chain.ClassDeclaration(erc20ClassHash)
+		require.NoError(t, err)
+		assert.Equal(t, &blockchain.ClassDeclaration{
+			BlockNumber:    0,
+			BlockHash:      block0.Hash,
+			DeclareTxnHash: utils.HexToFelt(t, "0x32538718071ad83ccd09fca03fe3a17add776ec12002d1c4e16ad4b92ddf752"),
+		}, declaration)
+	})
+
+	t.Run("unknown class", func(t *testing.T) {
+		_, err := chain.ClassDeclaration(utils.HexToFelt(t, "0xdead"))
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+}
+
 func TestBlockCommitments(t *testing.T) {
 	chain := blockchain.New(pebble.NewMemTest(t), &utils.Mainnet, nil)
 	client := feeder.NewTestClient(t, &utils.Mainnet)
@@ -530,7 +661,7 @@ func TestEvents(t *testing.T) {
 
 		allEvents := []*blockchain.FilteredEvent{}
 		t.Run("get all events without pagination", func(t *testing.T) {
-			events, cToken, eErr := filter.Events(nil, 10)
+			events, cToken, eErr := filter.Events(context.Background(), nil, 10)
 			require.Empty(t, cToken)
 			require.NoError(t, eErr)
 			require.Len(t, events, 3)
@@ -547,7 +678,7 @@ func TestEvents(t *testing.T) {
 				var lastToken *blockchain.ContinuationToken
 				var gotEvents []*blockchain.FilteredEvent
 				for i := 0; i < len(allEvents)+1; i++ {
-					gotEvents, lastToken, err = filter.Events(lastToken, chunkSize)
+					gotEvents, lastToken, err = filter.Events(context.Background(), lastToken, chunkSize)
 					require.NoError(t, err)
 					accEvents = append(accEvents, gotEvents...)
 					if lastToken == nil {
@@ -572,7 +703,7 @@ func TestEvents(t *testing.T) {
 			utils.HexToFelt(t, "0x3b43b334f46b921938854ba85ffc890c1b1321f8fd69e7b2961b18b4260de14")))
 
 		t.Run("get all events without pagination", func(t *testing.T) {
-			events, cToken, err := filter.Events(nil, 10)
+			events, cToken, err := filter.Events(context.Background(), nil, 10)
 			require.Empty(t, cToken)
 			require.NoError(t, err)
 			require.Len(t, events, 1)
@@ -588,12 +719,23 @@ func TestEvents(t *testing.T) {
 		require.NoError(t, err)
 		require.NoError(t, filter.SetRangeEndBlockByNumber(blockchain.EventFilterFrom, 0))
 		require.NoError(t, filter.SetRangeEndBlockByNumber(blockchain.EventFilterTo, 6))
-		events, cToken, err := filter.Events(nil, 10)
+		events, cToken, err := filter.Events(context.Background(), nil, 10)
 		require.NoError(t, err)
 		require.Nil(t, cToken)
 		require.Empty(t, events)
 		require.NoError(t, filter.Close())
 	})
+
+	t.Run("scan stops once the context is done", func(t *testing.T) {
+		filter, err := chain.EventFilter(from, nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, _, err = filter.Events(ctx, nil, 10)
+		require.ErrorIs(t, err, context.Canceled)
+		require.NoError(t, filter.Close())
+	})
 }
 
 func TestRevert(t *testing.T) {
@@ -612,6 +754,7 @@ func TestRevert(t *testing.T) {
 
 		require.NoError(t, chain.Store(b, &emptyCommitments, su, nil))
 	}
+	require.NoError(t, chain.SetL1VerifiedHeight(2))
 
 	require.NoError(t, chain.RevertHead())
 
@@ -630,6 +773,11 @@ func TestRevert(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, uint64(1), header.Number)
 	})
+	t.Run("L1 verified height should be capped at the head", func(t *testing.T) {
+		height, err := chain.L1VerifiedHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(1), height)
+	})
 
 	revertedHeight := uint64(2)
 	t.Run("BlockByNumber should fail with reverted height", func(t *testing.T) {
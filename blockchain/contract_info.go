@@ -0,0 +1,170 @@
+package blockchain
+
+import (
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+)
+
+// contractDeployedSelector is the key of the event the Universal Deployer Contract emits for every
+// deployment. The address of the deployed contract is the first data element.
+var contractDeployedSelector = crypto.StarknetKeccak([]byte("ContractDeployed"))
+
+// ClassHashChange records that a contract started to instantiate ClassHash at BlockNumber.
+type ClassHashChange struct {
+	BlockNumber uint64
+	ClassHash   *felt.Felt
+}
+
+// ContractInfo describes the deployment of a contract and the classes it instantiated over time.
+type ContractInfo struct {
+	DeployedAt      uint64
+	DeployBlockHash *felt.Felt
+	DeployTxnHash   *felt.Felt // nil if the deploying transaction can't be identified
+	ClassHash       *felt.Felt
+	// ClassHistory starts with the class the contract was deployed with, followed by every replacement.
+	ClassHistory []ClassHashChange
+}
+
+// ClassDeclaration describes where a class was declared.
+type ClassDeclaration struct {
+	BlockNumber    uint64
+	BlockHash      *felt.Felt
+	DeclareTxnHash *felt.Felt // nil if the class was not declared by a transaction, e.g. in the genesis state
+}
+
+// ContractInfo returns the deployment and class history of the contract at the given address.
+func (b *Blockchain) ContractInfo(address *felt.Felt) (*ContractInfo, error) {
+	b.listener.OnRead("ContractInfo")
+	var info *ContractInfo
+	return info, b.database.View(func(txn db.Transaction) error {
+		state := core.NewState(txn)
+		deployedAt, err := state.ContractDeploymentHeight(address)
+		if err != nil {
+			return err
+		}
+
+		classHash, err := state.ContractClassHash(address)
+		if err != nil {
+			return err
+		}
+
+		replacements, err := state.ContractClassReplacements(address)
+		if err != nil {
+			return err
+		}
+
+		block, err := BlockByNumber(txn, deployedAt)
+		if err != nil {
+			return err
+		}
+
+		info = &ContractInfo{
+			DeployedAt:      deployedAt,
+			DeployBlockHash: block.Hash,
+			DeployTxnHash:   deployTxnHash(block, address, b.network.UDCAddress),
+			ClassHash:       classHash,
+			ClassHistory:    make([]ClassHashChange, 0, len(replacements)+1),
+		}
+
+		// every replacement logs the class hash that was replaced, so the class of each period
+		// is the one logged by the next replacement, or the current one for the last period
+		changedAt := deployedAt
+		for _, replacement := range replacements {
+			info.ClassHistory = append(info.ClassHistory, ClassHashChange{
+				BlockNumber: changedAt,
+				ClassHash:   replacement.OldClassHash,
+			})
+			changedAt = replacement.BlockNumber
+		}
+		info.ClassHistory = append(info.ClassHistory, ClassHashChange{
+			BlockNumber: changedAt,
+			ClassHash:   classHash,
+		})
+		return nil
+	})
+}
+
+// deployTxnHash finds the transaction that deployed address in the given block. Deployments through the
+// deploy syscall can only be attributed when they go through the Universal Deployer Contract at udcAddress,
+// since any contract can emit an event of the same shape.
+func deployTxnHash(block *core.Block, address, udcAddress *felt.Felt) *felt.Felt {
+	for i, t := range block.Transactions {
+		switch t := t.(type) {
+		case *core.DeployTransaction:
+			if t.ContractAddress.Equal(address) {
+				return t.TransactionHash
+			}
+		case *core.DeployAccountTransaction:
+			if t.ContractAddress.Equal(address) {
+				return t.TransactionHash
+			}
+		}
+
+		if udcAddress == nil || i >= len(block.Receipts) {
+			continue
+		}
+		for _, event := range block.Receipts[i].Events {
+			if len(event.Keys) > 0 && len(event.Data) > 0 && event.From.Equal(udcAddress) &&
+				event.Keys[0].Equal(contractDeployedSelector) && event.Data[0].Equal(address) {
+				return t.Hash()
+			}
+		}
+	}
+	return nil
+}
+
+// ClassDeclaration returns the block and transaction that declared the class with the given hash.
+func (b *Blockchain) ClassDeclaration(classHash *felt.Felt) (*ClassDeclaration, error) {
+	b.listener.OnRead("ClassDeclaration")
+	var declaration *ClassDeclaration
+	return declaration, b.database.View(func(txn db.Transaction) error {
+		class, err := core.NewState(txn).Class(classHash)
+		if err != nil {
+			return err
+		}
+
+		block, err := BlockByNumber(txn, class.At)
+		if err != nil {
+			return err
+		}
+
+		declaration = &ClassDeclaration{
+			BlockNumber:    class.At,
+			BlockHash:      block.Hash,
+			DeclareTxnHash: declareTxnHash(block, classHash),
+		}
+		return nil
+	})
+}
+
+// declareTxnHash finds the transaction that declared classHash in the given block. Before Starknet 0.11,
+// deploy transactions implicitly declared the class they instantiated.
+func declareTxnHash(block *core.Block, classHash *felt.Felt) *felt.Felt {
+	for _, t := range block.Transactions {
+		switch t := t.(type) {
+		case *core.DeclareTransaction:
+			if t.ClassHash.Equal(classHash) {
+				return t.TransactionHash
+			}
+		case *core.DeployTransaction:
+			if t.ClassHash.Equal(classHash) {
+				return t.TransactionHash
+			}
+		}
+	}
+	return nil
+}
+
+// ContractsByClassHash returns up to limit addresses of the contracts that currently instantiate
+// the given class, in ascending order starting from startAt.
+func (b *Blockchain) ContractsByClassHash(classHash, startAt *felt.Felt, limit uint64) ([]*felt.Felt, error) {
+	b.listener.OnRead("ContractsByClassHash")
+	var addresses []*felt.Felt
+	return addresses, b.database.View(func(txn db.Transaction) error {
+		var err error
+		addresses, err = core.NewState(txn).ContractsByClassHash(classHash, startAt, limit)
+		return err
+	})
+}
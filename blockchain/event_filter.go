@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -19,7 +20,7 @@ var errChunkSizeReached = errors.New("chunk size reached")
 type EventFilterer interface {
 	io.Closer
 
-	Events(cToken *ContinuationToken, chunkSize uint64) ([]*FilteredEvent, *ContinuationToken, error)
+	Events(ctx context.Context, cToken *ContinuationToken, chunkSize uint64) ([]*FilteredEvent, *ContinuationToken, error)
 	SetRangeEndBlockByNumber(filterRange EventFilterRange, blockNumber uint64) error
 	SetRangeEndBlockByHash(filterRange EventFilterRange, blockHash *felt.Felt) error
 	WithLimit(limit uint) *EventFilter
@@ -107,10 +108,17 @@ type FilteredEvent struct {
 	BlockNumber     uint64
 	BlockHash       *felt.Felt
 	TransactionHash *felt.Felt
+	// EventIndex is the position of the event among all the events of its block
+	EventIndex uint64
 }
 
+// Events returns up to chunkSize events matching the filter, and a token to get the next ones if there are more.
+// The scan stops with the error of ctx once it is done.
+//
 //nolint:gocyclo
-func (e *EventFilter) Events(cToken *ContinuationToken, chunkSize uint64) ([]*FilteredEvent, *ContinuationToken, error) {
+func (e *EventFilter) Events(ctx context.Context, cToken *ContinuationToken, chunkSize uint64) ([]*FilteredEvent,
+	*ContinuationToken, error,
+) {
 	var matchedEvents []*FilteredEvent
 	latest, err := ChainHeight(e.txn)
 	if err != nil {
@@ -140,6 +148,10 @@ func (e *EventFilter) Events(cToken *ContinuationToken, chunkSize uint64) ([]*Fi
 		rToken                 *ContinuationToken
 	)
 	for ; curBlock <= e.toBlock && remainingScannedBlocks > 0; curBlock, remainingScannedBlocks = curBlock+1, remainingScannedBlocks-1 {
+		if err = ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
 		var header *core.Header
 		if curBlock != latest+1 {
 			header, err = blockHeaderByNumber(e.txn, curBlock)
@@ -240,6 +252,7 @@ func (e *EventFilter) appendBlockEvents(matchedEventsSofar []*FilteredEvent, hea
 						BlockHash:       header.Hash,
 						TransactionHash: receipt.TransactionHash,
 						Event:           event,
+						EventIndex:      processedEvents,
 					})
 				} else {
 					// we are at the capacity, return what we have accumulated so far and a continuation token
@@ -0,0 +1,212 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/encoder"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type message interface {
+	*core.L1ToL2MessageLog | *core.L2ToL1MessageLog
+	Hash() common.Hash
+	Status() core.MessageStatus
+}
+
+// L1MessagesHeight returns the latest L1 block number whose messaging events were indexed.
+func (b *Blockchain) L1MessagesHeight() (uint64, error) {
+	b.listener.OnRead("L1MessagesHeight")
+	var height uint64
+	return height, b.database.View(func(txn db.Transaction) error {
+		return txn.Get(db.L1MessagesHeight.Key(), func(heightBytes []byte) error {
+			height = binary.BigEndian.Uint64(heightBytes)
+			return nil
+		})
+	})
+}
+
+// StoreL1Messages merges the status changes of the given messages, which were found in the L1 blocks
+// up to l1Height, with the ones already known. Messages are indexed as follows:
+//
+// [db.L1ToL2MessagesByHash](MessageHash) -> L1ToL2MessageLog
+// [db.L1ToL2MessageHashesByAddress](SenderAddress, MessageHash) -> ()
+// [db.L1ToL2MessageHashesByAddress](RecipientAddress, MessageHash) -> ()
+// [db.L1ToL2MessageHashesByL1TxnHash](SentTxnHash, MessageHash) -> ()
+// [db.L2ToL1MessagesByHash](MessageHash) -> L2ToL1MessageLog
+// [db.L2ToL1MessageHashesByAddress](SenderAddress, MessageHash) -> ()
+// [db.L2ToL1MessageHashesByAddress](RecipientAddress, MessageHash) -> ()
+//
+// L1 addresses are indexed as felts.
+func (b *Blockchain) StoreL1Messages(l1Height uint64, l1ToL2 []*core.L1ToL2MessageLog, l2ToL1 []*core.L2ToL1MessageLog) error {
+	return b.database.Update(func(txn db.Transaction) error {
+		for _, msg := range l1ToL2 {
+			hash := msg.Hash()
+			stored, err := getMessage[*core.L1ToL2MessageLog](txn, db.L1ToL2MessagesByHash, hash)
+			if err == nil {
+				stored.Merge(msg)
+				msg = stored
+			} else if !errors.Is(err, db.ErrKeyNotFound) {
+				return err
+			}
+
+			if err = setMessage(txn, db.L1ToL2MessagesByHash, hash, msg); err != nil {
+				return err
+			}
+			for _, address := range []*felt.Felt{addressFelt(msg.From), msg.To} {
+				if err = txn.Set(db.L1ToL2MessageHashesByAddress.Key(address.Marshal(), hash.Bytes()), []byte{}); err != nil {
+					return err
+				}
+			}
+			if msg.SentTxnHash != nil {
+				if err = txn.Set(db.L1ToL2MessageHashesByL1TxnHash.Key(msg.SentTxnHash.Bytes(), hash.Bytes()), []byte{}); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, msg := range l2ToL1 {
+			hash := msg.Hash()
+			stored, err := getMessage[*core.L2ToL1MessageLog](txn, db.L2ToL1MessagesByHash, hash)
+			if err == nil {
+				stored.Merge(msg)
+				msg = stored
+			} else if !errors.Is(err, db.ErrKeyNotFound) {
+				return err
+			}
+
+			if err = setMessage(txn, db.L2ToL1MessagesByHash, hash, msg); err != nil {
+				return err
+			}
+			for _, address := range []*felt.Felt{msg.From, addressFelt(msg.To)} {
+				if err = txn.Set(db.L2ToL1MessageHashesByAddress.Key(address.Marshal(), hash.Bytes()), []byte{}); err != nil {
+					return err
+				}
+			}
+		}
+
+		return txn.Set(db.L1MessagesHeight.Key(), core.MarshalBlockNumber(l1Height))
+	})
+}
+
+// L1ToL2Message returns the message sent to L2 with the given hash.
+func (b *Blockchain) L1ToL2Message(hash common.Hash) (*core.L1ToL2MessageLog, error) {
+	b.listener.OnRead("L1ToL2Message")
+	var msg *core.L1ToL2MessageLog
+	return msg, b.database.View(func(txn db.Transaction) error {
+		var err error
+		msg, err = getMessage[*core.L1ToL2MessageLog](txn, db.L1ToL2MessagesByHash, hash)
+		return err
+	})
+}
+
+// L2ToL1Message returns the message sent to L1 with the given hash.
+func (b *Blockchain) L2ToL1Message(hash common.Hash) (*core.L2ToL1MessageLog, error) {
+	b.listener.OnRead("L2ToL1Message")
+	var msg *core.L2ToL1MessageLog
+	return msg, b.database.View(func(txn db.Transaction) error {
+		var err error
+		msg, err = getMessage[*core.L2ToL1MessageLog](txn, db.L2ToL1MessagesByHash, hash)
+		return err
+	})
+}
+
+// L1ToL2Messages returns up to limit messages sent to L2 by or to the given address, in ascending
+// order of hash starting from startAt. If status is set, only the messages with that status are returned.
+func (b *Blockchain) L1ToL2Messages(address *felt.Felt, status *core.MessageStatus, startAt common.Hash,
+	limit uint64,
+) ([]*core.L1ToL2MessageLog, error) {
+	b.listener.OnRead("L1ToL2Messages")
+	var msgs []*core.L1ToL2MessageLog
+	return msgs, b.database.View(func(txn db.Transaction) error {
+		var err error
+		msgs, err = messagesByAddress[*core.L1ToL2MessageLog](txn, db.L1ToL2MessageHashesByAddress, db.L1ToL2MessagesByHash,
+			address, status, startAt, limit)
+		return err
+	})
+}
+
+// L2ToL1Messages returns up to limit messages sent to L1 by or to the given address, in ascending
+// order of hash starting from startAt. If status is set, only the messages with that status are returned.
+func (b *Blockchain) L2ToL1Messages(address *felt.Felt, status *core.MessageStatus, startAt common.Hash,
+	limit uint64,
+) ([]*core.L2ToL1MessageLog, error) {
+	b.listener.OnRead("L2ToL1Messages")
+	var msgs []*core.L2ToL1MessageLog
+	return msgs, b.database.View(func(txn db.Transaction) error {
+		var err error
+		msgs, err = messagesByAddress[*core.L2ToL1MessageLog](txn, db.L2ToL1MessageHashesByAddress, db.L2ToL1MessagesByHash,
+			address, status, startAt, limit)
+		return err
+	})
+}
+
+// L1ToL2MessagesByL1TxnHash returns the messages sent to L2 by the given L1 transaction.
+func (b *Blockchain) L1ToL2MessagesByL1TxnHash(txnHash common.Hash) ([]*core.L1ToL2MessageLog, error) {
+	b.listener.OnRead("L1ToL2MessagesByL1TxnHash")
+	var msgs []*core.L1ToL2MessageLog
+	return msgs, b.database.View(func(txn db.Transaction) error {
+		prefix := db.L1ToL2MessageHashesByL1TxnHash.Key(txnHash.Bytes())
+		it, err := txn.NewIterator(prefix, true)
+		if err != nil {
+			return err
+		}
+
+		for it.First(); it.Valid(); it.Next() {
+			msg, err := getMessage[*core.L1ToL2MessageLog](txn, db.L1ToL2MessagesByHash, common.BytesToHash(it.Key()[len(prefix):]))
+			if err != nil {
+				return errors.Join(err, it.Close())
+			}
+			msgs = append(msgs, msg)
+		}
+		return it.Close()
+	})
+}
+
+func messagesByAddress[M message](txn db.Transaction, index, bucket db.Bucket, address *felt.Felt,
+	status *core.MessageStatus, startAt common.Hash, limit uint64,
+) ([]M, error) {
+	prefix := index.Key(address.Marshal())
+	it, err := txn.NewIterator(prefix, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var msgs []M
+	for it.Seek(index.Key(address.Marshal(), startAt.Bytes())); it.Valid(); it.Next() {
+		if uint64(len(msgs)) >= limit {
+			break
+		}
+
+		msg, err := getMessage[M](txn, bucket, common.BytesToHash(it.Key()[len(prefix):]))
+		if err != nil {
+			return nil, errors.Join(err, it.Close())
+		}
+		if status == nil || msg.Status() == *status {
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs, it.Close()
+}
+
+func getMessage[M message](txn db.Transaction, bucket db.Bucket, hash common.Hash) (M, error) {
+	var msg M
+	return msg, txn.Get(bucket.Key(hash.Bytes()), func(val []byte) error {
+		return encoder.Unmarshal(val, &msg)
+	})
+}
+
+func setMessage[M message](txn db.Transaction, bucket db.Bucket, hash common.Hash, msg M) error {
+	msgBytes, err := encoder.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return txn.Set(bucket.Key(hash.Bytes()), msgBytes)
+}
+
+func addressFelt(address common.Address) *felt.Felt {
+	return new(felt.Felt).SetBytes(address.Bytes())
+}
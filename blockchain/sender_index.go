@@ -0,0 +1,137 @@
+package blockchain
+
+import (
+	"bytes"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/utils"
+)
+
+// SenderTransaction is a transaction found through the sender and nonce index, together with its
+// position in the chain.
+type SenderTransaction struct {
+	Transaction core.Transaction
+	BlockHash   *felt.Felt
+	BlockNumber uint64
+	Index       uint64
+}
+
+// senderAndNonce returns the account that sent the transaction and the nonce it used.
+// Transactions that are not sent by an account, or that predate account nonces, are not indexed.
+// Version 0 declares don't increment the nonce of their sender, so they are skipped as well.
+func senderAndNonce(t core.Transaction) (*felt.Felt, *felt.Felt, bool) {
+	var sender, nonce *felt.Felt
+	switch t := t.(type) {
+	case *core.InvokeTransaction:
+		sender, nonce = t.SenderAddress, t.Nonce
+	case *core.DeclareTransaction:
+		if t.Version != nil && t.Version.Is(0) {
+			return nil, nil, false
+		}
+		sender, nonce = t.SenderAddress, t.Nonce
+	case *core.DeployAccountTransaction:
+		sender, nonce = t.ContractAddress, t.Nonce
+	}
+
+	if sender == nil || nonce == nil {
+		return nil, nil, false
+	}
+	return sender, nonce, true
+}
+
+// StoreSenderNonceIndex indexes the account transactions of a block as follows:
+//
+// [db.TransactionBlockNumbersAndIndicesBySenderAndNonce](SenderAddress, Nonce) -> (BlockNumber, Index)
+//
+// Nonces are marshalled big-endian, so the transactions of an account are iterated in nonce order.
+func StoreSenderNonceIndex(txn db.Transaction, blockNumber uint64, blockTxns []core.Transaction) error {
+	for i, t := range blockTxns {
+		sender, nonce, ok := senderAndNonce(t)
+		if !ok {
+			continue
+		}
+
+		bnIndexBytes := (&txAndReceiptDBKey{blockNumber, uint64(i)}).MarshalBinary()
+		if err := txn.Set(db.TransactionBlockNumbersAndIndicesBySenderAndNonce.Key(sender.Marshal(), nonce.Marshal()),
+			bnIndexBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TransactionBySenderAndNonce gets the transaction an account sent with the given nonce.
+func (b *Blockchain) TransactionBySenderAndNonce(sender, nonce *felt.Felt) (*SenderTransaction, error) {
+	b.listener.OnRead("TransactionBySenderAndNonce")
+	var transaction *SenderTransaction
+	return transaction, b.database.View(func(txn db.Transaction) error {
+		var bnIndex txAndReceiptDBKey
+		if err := txn.Get(db.TransactionBlockNumbersAndIndicesBySenderAndNonce.Key(sender.Marshal(), nonce.Marshal()),
+			bnIndex.UnmarshalBinary); err != nil {
+			return err
+		}
+
+		var err error
+		transaction, err = senderTransaction(txn, &bnIndex)
+		return err
+	})
+}
+
+// TransactionsBySender returns up to limit transactions sent by an account, in nonce order, starting
+// from fromNonce.
+func (b *Blockchain) TransactionsBySender(sender, fromNonce *felt.Felt, limit uint64) ([]SenderTransaction, error) {
+	b.listener.OnRead("TransactionsBySender")
+	var transactions []SenderTransaction
+	return transactions, b.database.View(func(txn db.Transaction) error {
+		prefix := db.TransactionBlockNumbersAndIndicesBySenderAndNonce.Key(sender.Marshal())
+		iterator, err := txn.NewIterator(prefix, true)
+		if err != nil {
+			return err
+		}
+
+		for iterator.Seek(append(bytes.Clone(prefix), fromNonce.Marshal()...)); iterator.Valid(); iterator.Next() {
+			if uint64(len(transactions)) >= limit {
+				break
+			}
+
+			val, vErr := iterator.Value()
+			if vErr != nil {
+				return utils.RunAndWrapOnError(iterator.Close, vErr)
+			}
+
+			var bnIndex txAndReceiptDBKey
+			if err = bnIndex.UnmarshalBinary(val); err != nil {
+				return utils.RunAndWrapOnError(iterator.Close, err)
+			}
+
+			transaction, tErr := senderTransaction(txn, &bnIndex)
+			if tErr != nil {
+				return utils.RunAndWrapOnError(iterator.Close, tErr)
+			}
+			transactions = append(transactions, *transaction)
+		}
+
+		return iterator.Close()
+	})
+}
+
+func senderTransaction(txn db.Transaction, bnIndex *txAndReceiptDBKey) (*SenderTransaction, error) {
+	transaction, err := transactionByBlockNumberAndIndex(txn, bnIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := blockHeaderByNumber(txn, bnIndex.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SenderTransaction{
+		Transaction: transaction,
+		BlockHash:   header.Hash,
+		BlockNumber: bnIndex.Number,
+		Index:       bnIndex.Index,
+	}, nil
+}
@@ -0,0 +1,159 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/utils"
+)
+
+var (
+	ErrChainNotEmpty     = errors.New("chain is not empty")
+	ErrStateRootMismatch = errors.New("state root does not match the block")
+)
+
+// snapStateBuckets hold the state written by a snap sync, along with its pivots.
+var snapStateBuckets = []db.Bucket{
+	db.StateTrie, db.ClassesTrie, db.ContractClassHash, db.ContractStorage, db.ContractNonce, db.Class,
+	db.ContractClassHashHistory, db.ContractStorageHistory, db.ContractNonceHistory, db.ContractDeploymentHeight,
+	db.ClassBlobsByHash, db.ContractAddressesByClassHash, db.SnapSyncPivot,
+}
+
+// HeadStateTries returns the tries committing to the latest state, to prove ranges of it to peers.
+func (b *Blockchain) HeadStateTries() (core.StateTrieReader, StateCloser, error) {
+	b.listener.OnRead("HeadStateTries")
+	txn, err := b.database.NewTransaction(false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err = ChainHeight(txn); err != nil {
+		return nil, nil, utils.RunAndWrapOnError(txn.Discard, err)
+	}
+	return core.NewState(txn), txn.Discard, nil
+}
+
+// SnapSyncPivots returns the lowest and highest block numbers the state being snap synced was downloaded at, or
+// [db.ErrKeyNotFound] if no snap sync is in progress.
+func (b *Blockchain) SnapSyncPivots() (uint64, uint64, error) {
+	var lowest, highest uint64
+	return lowest, highest, b.database.View(func(txn db.Transaction) error {
+		var err error
+		lowest, highest, err = snapSyncPivots(txn)
+		return err
+	})
+}
+
+func snapSyncPivots(txn db.Transaction) (uint64, uint64, error) {
+	var lowest, highest uint64
+	return lowest, highest, txn.Get(db.SnapSyncPivot.Key(), func(val []byte) error {
+		lowest = binary.BigEndian.Uint64(val[:8])
+		highest = binary.BigEndian.Uint64(val[8:])
+		return nil
+	})
+}
+
+// StoreSnapState runs fn to write state downloaded from peers at block pivot while the chain is empty. The lowest
+// and highest pivots are recorded so that the state can be brought up to date with the state diffs of the blocks
+// that follow the lowest one, even if the snap sync is interrupted.
+func (b *Blockchain) StoreSnapState(pivot uint64, fn func(state *core.State) error) error {
+	return b.database.Update(func(txn db.Transaction) error {
+		if err := checkEmptyChain(txn); err != nil {
+			return err
+		}
+
+		lowest, highest, err := snapSyncPivots(txn)
+		if errors.Is(err, db.ErrKeyNotFound) {
+			lowest, highest, err = pivot, pivot, nil
+		}
+		if err != nil {
+			return err
+		}
+
+		pivots := binary.BigEndian.AppendUint64(core.MarshalBlockNumber(min(lowest, pivot)), max(highest, pivot))
+		if err = txn.Set(db.SnapSyncPivot.Key(), pivots); err != nil {
+			return err
+		}
+		return fn(core.NewState(txn))
+	})
+}
+
+// StoreSnapHead makes block the head of the empty chain once its state has been written with
+// [Blockchain.StoreSnapState]. The blocks before it are never stored.
+func (b *Blockchain) StoreSnapHead(block *core.Block, blockCommitments *core.BlockCommitments,
+	stateUpdate *core.StateUpdate,
+) error {
+	err := b.database.Update(func(txn db.Transaction) error {
+		if err := CheckBlockVersion(block.ProtocolVersion); err != nil {
+			return err
+		}
+		if err := checkEmptyChain(txn); err != nil {
+			return err
+		}
+
+		root, err := core.NewState(txn).Root()
+		if err != nil {
+			return err
+		}
+		if !root.Equal(block.GlobalStateRoot) {
+			return fmt.Errorf("%w: state root %s does not match the root of block %d: %s", ErrStateRootMismatch, root, block.Number,
+				block.GlobalStateRoot)
+		}
+
+		if err = txn.Delete(db.SnapSyncPivot.Key()); err != nil {
+			return err
+		}
+		return storeBlock(txn, block, blockCommitments, stateUpdate)
+	})
+	if err != nil {
+		return err
+	}
+
+	b.storedFeed.Send(block)
+	return nil
+}
+
+// DiscardSnapState deletes the state written with [Blockchain.StoreSnapState] while the chain is empty, so that
+// a snap sync can start over when the state turns out not to match the block it was downloaded at.
+func (b *Blockchain) DiscardSnapState() error {
+	return b.database.Update(func(txn db.Transaction) error {
+		if err := checkEmptyChain(txn); err != nil {
+			return err
+		}
+
+		for _, bucket := range snapStateBuckets {
+			if err := deleteBucket(txn, bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func deleteBucket(txn db.Transaction, bucket db.Bucket) error {
+	it, err := txn.NewIterator(bucket.Key(), true)
+	if err != nil {
+		return err
+	}
+
+	for it.First(); it.Valid(); it.Next() {
+		if err = txn.Delete(bytes.Clone(it.Key())); err != nil {
+			return utils.RunAndWrapOnError(it.Close, err)
+		}
+	}
+	return it.Close()
+}
+
+func checkEmptyChain(txn db.Transaction) error {
+	_, err := ChainHeight(txn)
+	if err == nil {
+		return ErrChainNotEmpty
+	} else if !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	}
+	return nil
+}
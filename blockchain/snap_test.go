@@ -0,0 +1,113 @@
+package blockchain_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/pebble"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreSnapHead(t *testing.T) {
+	client := feeder.NewTestClient(t, &utils.Mainnet)
+	gw := adaptfeeder.New(client)
+
+	var stateUpdates []*core.StateUpdate
+	for i := range uint64(3) {
+		su, err := gw.StateUpdate(context.Background(), i)
+		require.NoError(t, err)
+		stateUpdates = append(stateUpdates, su)
+	}
+	block2, err := gw.BlockByNumber(context.Background(), 2)
+	require.NoError(t, err)
+
+	chain := blockchain.New(pebble.NewMemTest(t), &utils.Mainnet, nil)
+	_, _, err = chain.SnapSyncPivots()
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+	for _, pivot := range []uint64{1, 0, 2} {
+		require.NoError(t, chain.StoreSnapState(pivot, func(*core.State) error { return nil }))
+	}
+	lowest, highest, err := chain.SnapSyncPivots()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), lowest)
+	assert.Equal(t, uint64(2), highest)
+
+	require.NoError(t, chain.StoreSnapState(2, func(state *core.State) error {
+		for i, su := range stateUpdates {
+			if err := state.ApplyStateDiff(uint64(i), su.StateDiff, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	t.Run("state must match the head", func(t *testing.T) {
+		block1, err := gw.BlockByNumber(context.Background(), 1)
+		require.NoError(t, err)
+		err = chain.StoreSnapHead(block1, &emptyCommitments, stateUpdates[1])
+		require.ErrorIs(t, err, blockchain.ErrStateRootMismatch)
+		require.ErrorContains(t, err, "does not match the root of block 1")
+	})
+
+	require.NoError(t, chain.StoreSnapHead(block2, &emptyCommitments, stateUpdates[2]))
+
+	head, err := chain.Head()
+	require.NoError(t, err)
+	assert.Equal(t, block2, head)
+	_, err = chain.BlockByNumber(1)
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+	_, _, err = chain.SnapSyncPivots()
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+
+	tries, closer, err := chain.HeadStateTries()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, closer())
+	})
+	contracts, err := tries.ContractsTrie()
+	require.NoError(t, err)
+	classes, err := tries.ClassesTrie()
+	require.NoError(t, err)
+	contractsRoot, err := contracts.Root()
+	require.NoError(t, err)
+	classesRoot, err := classes.Root()
+	require.NoError(t, err)
+	assert.Equal(t, block2.GlobalStateRoot, core.StateCommitment(contractsRoot, classesRoot))
+
+	require.ErrorIs(t, chain.StoreSnapState(3, func(*core.State) error { return nil }), blockchain.ErrChainNotEmpty)
+	require.ErrorIs(t, chain.DiscardSnapState(), blockchain.ErrChainNotEmpty)
+}
+
+func TestDiscardSnapState(t *testing.T) {
+	client := feeder.NewTestClient(t, &utils.Mainnet)
+	gw := adaptfeeder.New(client)
+	su, err := gw.StateUpdate(context.Background(), 0)
+	require.NoError(t, err)
+
+	chain := blockchain.New(pebble.NewMemTest(t), &utils.Mainnet, nil)
+	require.NoError(t, chain.StoreSnapState(0, func(state *core.State) error {
+		return state.ApplyStateDiff(0, su.StateDiff, nil)
+	}))
+	require.NoError(t, chain.DiscardSnapState())
+
+	_, _, err = chain.SnapSyncPivots()
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+	require.NoError(t, chain.StoreSnapState(0, func(state *core.State) error {
+		root, err := state.Root()
+		require.NoError(t, err)
+		assert.True(t, root.IsZero())
+		for addr := range su.StateDiff.DeployedContracts {
+			_, err = state.ContractClassHash(&addr)
+			require.ErrorIs(t, err, db.ErrKeyNotFound)
+		}
+		return nil
+	}))
+}
@@ -216,7 +216,8 @@ func dbSize(cmd *cobra.Command, args []string) error {
 		totalSize += bucketItem.Size
 		totalCount += bucketItem.Count
 
-		if utils.AnyOf(b, db.StateTrie, db.ContractStorage, db.Class, db.ContractNonce, db.ContractDeploymentHeight) {
+		if utils.AnyOf(b, db.StateTrie, db.ContractStorage, db.Class, db.ClassBlobsByHash, db.ContractNonce,
+			db.ContractDeploymentHeight, db.ContractAddressesByClassHash) {
 			withoutHistorySize += bucketItem.Size
 			withHistorySize += bucketItem.Size
 
@@ -45,10 +45,15 @@ const (
 	wsF                     = "ws"
 	wsHostF                 = "ws-host"
 	wsPortF                 = "ws-port"
+	ipcPathF                = "ipc-path"
+	ipcPermissionsF         = "ipc-permissions"
 	dbPathF                 = "db-path"
 	networkF                = "network"
 	ethNodeF                = "eth-node"
+	ethNodeQuorumF          = "eth-node-quorum"
+	ethMessagesStartHeightF = "eth-messages-start-height"
 	disableL1VerificationF  = "disable-l1-verification"
+	haltSyncOnL1MismatchF   = "halt-sync-on-l1-mismatch"
 	pprofF                  = "pprof"
 	pprofHostF              = "pprof-host"
 	pprofPortF              = "pprof-port"
@@ -59,7 +64,9 @@ const (
 	p2pPublicAddrF          = "p2p-public-addr"
 	p2pPeersF               = "p2p-peers"
 	p2pFeederNodeF          = "p2p-feeder-node"
+	p2pFeederPeersF         = "p2p-feeder-peers"
 	p2pPrivateKey           = "p2p-private-key"
+	p2pSnapSyncF            = "p2p-snap-sync"
 	metricsF                = "metrics"
 	metricsHostF            = "metrics-host"
 	metricsPortF            = "metrics-port"
@@ -83,8 +90,22 @@ const (
 	cnUnverifiableRangeF    = "cn-unverifiable-range"
 	callMaxStepsF           = "rpc-call-max-steps"
 	corsEnableF             = "rpc-cors-enable"
+	rpcAPIKeysF             = "rpc-api-keys"   //nolint: gosec
+	rpcJWTSecretF           = "rpc-jwt-secret" //nolint: gosec
+	rpcKeyRateLimitF        = "rpc-key-rate-limit"
+	rpcIPRateLimitF         = "rpc-ip-rate-limit"
+	rpcAllowedMethodsF      = "rpc-allowed-methods"
+	rpcDeniedMethodsF       = "rpc-denied-methods"
+	rpcMethodTimeoutsF      = "rpc-method-timeouts"
+	rpcSubQueueSizeF        = "rpc-subscription-queue-size"
+	rpcSubOverflowF         = "rpc-subscription-overflow-policy"
 	versionedConstantsFileF = "versioned-constants-file"
 	pluginPathF             = "plugin-path"
+	adminF                  = "admin"
+	adminHostF              = "admin-host"
+	adminPortF              = "admin-port"
+	adminAPIKeysF           = "admin-api-keys"
+	adminJWTSecretF         = "admin-jwt-secret"
 
 	defaultConfig                   = ""
 	defaulHost                      = "localhost"
@@ -92,8 +113,13 @@ const (
 	defaultHTTPPort                 = 6060
 	defaultWS                       = false
 	defaultWSPort                   = 6061
+	defaultIPCPath                  = ""
+	defaultIPCPermissions           = "0600"
 	defaultEthNode                  = ""
+	defaultEthNodeQuorum            = uint(0)
+	defaultEthMessagesStartHeight   = uint64(0)
 	defaultDisableL1Verification    = false
+	defaultHaltSyncOnL1Mismatch     = false
 	defaultPprof                    = false
 	defaultPprofPort                = 6062
 	defaultColour                   = true
@@ -103,7 +129,9 @@ const (
 	defaultP2pPublicAddr            = ""
 	defaultP2pPeers                 = ""
 	defaultP2pFeederNode            = false
+	defaultP2pFeederPeers           = ""
 	defaultP2pPrivateKey            = ""
+	defaultP2pSnapSync              = false
 	defaultMetrics                  = false
 	defaultMetricsPort              = 9090
 	defaultGRPC                     = false
@@ -122,8 +150,21 @@ const (
 	defaultCallMaxSteps             = 4_000_000
 	defaultGwTimeout                = 5 * time.Second
 	defaultCorsEnable               = false
+	defaultRPCAPIKeys               = ""
+	defaultRPCJWTSecret             = ""
+	defaultRPCKeyRateLimit          = 0.0
+	defaultRPCIPRateLimit           = 0.0
+	defaultRPCAllowedMethods        = ""
+	defaultRPCDeniedMethods         = ""
+	defaultRPCMethodTimeouts        = ""
+	defaultRPCSubQueueSize          = uint(1024)
+	defaultRPCSubOverflow           = "disconnect"
 	defaultVersionedConstantsFile   = ""
 	defaultPluginPath               = ""
+	defaultAdmin                    = false
+	defaultAdminPort                = 6065
+	defaultAdminAPIKeys             = ""
+	defaultAdminJWTSecret           = ""
 
 	configFlagUsage                       = "The YAML configuration file."
 	logLevelFlagUsage                     = "Options: trace, debug, info, warn, error."
@@ -146,9 +187,15 @@ const (
 	pprofHostUsage                        = "The interface on which the pprof HTTP server will listen for requests."
 	pprofPortUsage                        = "The port on which the pprof HTTP server will listen for requests."
 	colourUsage                           = "Use `--colour=false` command to disable colourized outputs (ANSI Escape Codes)."
-	ethNodeUsage                          = "WebSocket endpoint of the Ethereum node. To verify the correctness of the L2 chain, " +
-		"Juno must connect to an Ethereum node and parse events in the Starknet contract."
+	ethNodeUsage                          = "WebSocket or HTTP endpoint of the Ethereum node. To verify the correctness of the L2 chain, " +
+		"Juno must connect to an Ethereum node and parse events in the Starknet contract. " +
+		"Several comma-separated endpoints can be given to not rely on a single Ethereum node."
+	ethNodeQuorumUsage = "Number of the endpoints given with --eth-node that must agree on a Starknet state update " +
+		"before it is accepted as the L1 head. Defaults to a majority of the endpoints."
+	ethMessagesStartHeightUsage = "Ethereum block from which the L1 <-> L2 messages of the Starknet contract are indexed " +
+		"when the database has no messages yet, such as the block the contract was deployed at."
 	disableL1VerificationUsage = "Disables L1 verification since an Ethereum node is not provided."
+	haltSyncOnL1MismatchUsage  = "Stops syncing when a block does not match the hash and state root finalised on L1."
 	pendingPollIntervalUsage   = "Sets how frequently pending block will be updated (0s will disable fetching of pending block)."
 	p2pUsage                   = "EXPERIMENTAL: Enables p2p server."
 	p2pAddrUsage               = "EXPERIMENTAL: Specify p2p listening source address as multiaddr.  Example: /ip4/0.0.0.0/tcp/7777"
@@ -157,7 +204,12 @@ const (
 		"These peers can be either Feeder or regular nodes."
 	p2pFeederNodeUsage = "EXPERIMENTAL: Run juno as a feeder node which will only sync from feeder gateway and gossip the new" +
 		" blocks to the network."
-	p2pPrivateKeyUsage   = "EXPERIMENTAL: Hexadecimal representation of a private key on the Ed25519 elliptic curve."
+	p2pFeederPeersUsage = "EXPERIMENTAL: Comma-separated IDs of the feeder nodes whose block announcements are trusted. " +
+		"Announcements of other peers are ignored."
+	p2pPrivateKeyUsage = "EXPERIMENTAL: Hexadecimal representation of a private key on the Ed25519 elliptic curve."
+	p2pSnapSyncUsage   = "EXPERIMENTAL: When the database is empty, download the state at a recent block from p2p peers" +
+		" instead of replaying every block since genesis. The blocks up to it are checked against the L1 head," +
+		" so L1 verification can't be disabled."
 	metricsUsage         = "Enables the Prometheus metrics endpoint on the default port."
 	metricsHostUsage     = "The interface on which the Prometheus endpoint will listen for requests."
 	metricsPortUsage     = "The port on which the Prometheus endpoint will listen for requests."
@@ -174,9 +226,35 @@ const (
 	gwTimeoutUsage       = "Timeout for requests made to the gateway"          //nolint: gosec
 	callMaxStepsUsage    = "Maximum number of steps to be executed in starknet_call requests. " +
 		"The upper limit is 4 million steps, and any higher value will still be capped at 4 million."
-	corsEnableUsage             = "Enable CORS on RPC endpoints"
+	corsEnableUsage = "Enable CORS on RPC endpoints"
+	rpcAPIKeysUsage = "Comma-separated API keys that RPC requests must carry, either in the X-API-Key header " +
+		"or as a bearer token in the Authorization header. RPC requests are not authenticated if neither this " +
+		"nor --rpc-jwt-secret is set."
+	rpcJWTSecretUsage = "Secret that JWTs carried by RPC requests as a bearer token in the Authorization header must be " +
+		"signed with, using HS256. The exp and nbf claims are checked if present."
+	rpcKeyRateLimitUsage = "Maximum number of RPC requests per second allowed for each API key or JWT subject. " +
+		"0 disables the limit."
+	rpcIPRateLimitUsage    = "Maximum number of RPC requests per second allowed for each IP address. 0 disables the limit."
+	rpcAllowedMethodsUsage = "Comma-separated RPC methods that can be called, all of them if empty. A trailing * matches any suffix."
+	rpcDeniedMethodsUsage  = "Comma-separated RPC methods that cannot be called. A trailing * matches any suffix, e.g. starknet_trace*."
+	rpcMethodTimeoutsUsage = "Comma-separated method=duration pairs, e.g. starknet_getEvents=10s. Requests for these methods are " +
+		"cancelled after the duration and answered with a timeout error. Subscription methods must not be listed."
+	adminUsage = "Enables the admin RPC server on the default port, which serves the juno_admin_* methods. It requires " +
+		"--admin-api-keys or --admin-jwt-secret."
+	rpcSubQueueSizeUsage = "Maximum number of notifications queued for each subscription while they are written to its " +
+		"client. Catching up with past blocks only fills half of it."
+	rpcSubOverflowUsage = "What happens to the notifications of a subscription whose queue is full. Options: disconnect " +
+		"ends the subscription, drop-oldest drops the oldest notification and sends a juno_subscriptionGap notification, " +
+		"coalesce-heads drops the oldest new head and otherwise ends the subscription."
+	ipcPathUsage = "Path of the Unix domain socket on which the IPC RPC server will listen for newline-delimited requests. " +
+		"IPC is disabled if empty."
+	ipcPermissionsUsage         = "Permissions of the IPC socket file, in octal. Only the users it allows to write to the socket can connect."
 	versionedConstantsFileUsage = "Use custom versioned constants from provided file"
 	pluginPathUsage             = "Path to the plugin .so file"
+	adminHostUsage              = "The interface on which the admin RPC server will listen for requests."
+	adminPortUsage              = "The port on which the admin RPC server will listen for requests."
+	adminAPIKeysUsage           = "Comma-separated API keys that admin RPC requests must carry, like --rpc-api-keys."
+	adminJWTSecretUsage         = "Secret that JWTs carried by admin RPC requests must be signed with, like --rpc-jwt-secret."
 )
 
 var Version string
@@ -321,6 +399,8 @@ func NewCmd(config *node.Config, run func(*cobra.Command, []string) error) *cobr
 	junoCmd.Flags().Bool(wsF, defaultWS, wsUsage)
 	junoCmd.Flags().String(wsHostF, defaulHost, wsHostUsage)
 	junoCmd.Flags().Uint16(wsPortF, defaultWSPort, wsPortUsage)
+	junoCmd.Flags().String(ipcPathF, defaultIPCPath, ipcPathUsage)
+	junoCmd.Flags().String(ipcPermissionsF, defaultIPCPermissions, ipcPermissionsUsage)
 	junoCmd.Flags().String(dbPathF, defaultDBPath, dbPathUsage)
 	junoCmd.Flags().Var(&defaultNetwork, networkF, networkUsage)
 	junoCmd.Flags().String(cnNameF, defaultCNName, networkCustomName)
@@ -331,8 +411,11 @@ func NewCmd(config *node.Config, run func(*cobra.Command, []string) error) *cobr
 	junoCmd.Flags().String(cnCoreContractAddressF, defaultCNCoreContractAddressStr, networkCustomCoreContractAddressUsage)
 	junoCmd.Flags().IntSlice(cnUnverifiableRangeF, defaultCNUnverifiableRange, networkCustomUnverifiableRange)
 	junoCmd.Flags().String(ethNodeF, defaultEthNode, ethNodeUsage)
+	junoCmd.Flags().Uint(ethNodeQuorumF, defaultEthNodeQuorum, ethNodeQuorumUsage)
+	junoCmd.Flags().Uint64(ethMessagesStartHeightF, defaultEthMessagesStartHeight, ethMessagesStartHeightUsage)
 	junoCmd.Flags().Bool(disableL1VerificationF, defaultDisableL1Verification, disableL1VerificationUsage)
 	junoCmd.MarkFlagsMutuallyExclusive(ethNodeF, disableL1VerificationF)
+	junoCmd.Flags().Bool(haltSyncOnL1MismatchF, defaultHaltSyncOnL1Mismatch, haltSyncOnL1MismatchUsage)
 	junoCmd.Flags().Bool(pprofF, defaultPprof, pprofUsage)
 	junoCmd.Flags().String(pprofHostF, defaulHost, pprofHostUsage)
 	junoCmd.Flags().Uint16(pprofPortF, defaultPprofPort, pprofPortUsage)
@@ -343,7 +426,9 @@ func NewCmd(config *node.Config, run func(*cobra.Command, []string) error) *cobr
 	junoCmd.Flags().String(p2pPublicAddrF, defaultP2pPublicAddr, p2pPublicAddrUsage)
 	junoCmd.Flags().String(p2pPeersF, defaultP2pPeers, p2pPeersUsage)
 	junoCmd.Flags().Bool(p2pFeederNodeF, defaultP2pFeederNode, p2pFeederNodeUsage)
+	junoCmd.Flags().String(p2pFeederPeersF, defaultP2pFeederPeers, p2pFeederPeersUsage)
 	junoCmd.Flags().String(p2pPrivateKey, defaultP2pPrivateKey, p2pPrivateKeyUsage)
+	junoCmd.Flags().Bool(p2pSnapSyncF, defaultP2pSnapSync, p2pSnapSyncUsage)
 	junoCmd.Flags().Bool(metricsF, defaultMetrics, metricsUsage)
 	junoCmd.Flags().String(metricsHostF, defaulHost, metricsHostUsage)
 	junoCmd.Flags().Uint16(metricsPortF, defaultMetricsPort, metricsPortUsage)
@@ -362,9 +447,25 @@ func NewCmd(config *node.Config, run func(*cobra.Command, []string) error) *cobr
 	junoCmd.Flags().Uint(callMaxStepsF, defaultCallMaxSteps, callMaxStepsUsage)
 	junoCmd.Flags().Duration(gwTimeoutF, defaultGwTimeout, gwTimeoutUsage)
 	junoCmd.Flags().Bool(corsEnableF, defaultCorsEnable, corsEnableUsage)
+	junoCmd.Flags().String(rpcAPIKeysF, defaultRPCAPIKeys, rpcAPIKeysUsage)
+	junoCmd.Flags().String(rpcJWTSecretF, defaultRPCJWTSecret, rpcJWTSecretUsage)
+	junoCmd.Flags().Float64(rpcKeyRateLimitF, defaultRPCKeyRateLimit, rpcKeyRateLimitUsage)
+	junoCmd.Flags().Float64(rpcIPRateLimitF, defaultRPCIPRateLimit, rpcIPRateLimitUsage)
+	junoCmd.Flags().String(rpcAllowedMethodsF, defaultRPCAllowedMethods, rpcAllowedMethodsUsage)
+	junoCmd.Flags().String(rpcDeniedMethodsF, defaultRPCDeniedMethods, rpcDeniedMethodsUsage)
+	junoCmd.Flags().String(rpcMethodTimeoutsF, defaultRPCMethodTimeouts, rpcMethodTimeoutsUsage)
+	junoCmd.Flags().Uint(rpcSubQueueSizeF, defaultRPCSubQueueSize, rpcSubQueueSizeUsage)
+	junoCmd.Flags().String(rpcSubOverflowF, defaultRPCSubOverflow, rpcSubOverflowUsage)
 	junoCmd.Flags().String(versionedConstantsFileF, defaultVersionedConstantsFile, versionedConstantsFileUsage)
 	junoCmd.MarkFlagsMutuallyExclusive(p2pFeederNodeF, p2pPeersF)
+	junoCmd.MarkFlagsMutuallyExclusive(p2pFeederNodeF, p2pSnapSyncF)
+	junoCmd.MarkFlagsMutuallyExclusive(disableL1VerificationF, p2pSnapSyncF)
 	junoCmd.Flags().String(pluginPathF, defaultPluginPath, pluginPathUsage)
+	junoCmd.Flags().Bool(adminF, defaultAdmin, adminUsage)
+	junoCmd.Flags().String(adminHostF, defaulHost, adminHostUsage)
+	junoCmd.Flags().Uint16(adminPortF, defaultAdminPort, adminPortUsage)
+	junoCmd.Flags().String(adminAPIKeysF, defaultAdminAPIKeys, adminAPIKeysUsage)
+	junoCmd.Flags().String(adminJWTSecretF, defaultAdminJWTSecret, adminJWTSecretUsage)
 
 	junoCmd.AddCommand(GenP2PKeyPair(), DBCmd(defaultDBPath))
 
@@ -35,6 +35,9 @@ func TestConfigPrecedence(t *testing.T) {
 	defaultHTTPPort := uint16(6060)
 	defaultWS := false
 	defaultWSPort := uint16(6061)
+	defaultIPCPermissions := "0600"
+	defaultRPCSubQueueSize := uint(1024)
+	defaultRPCSubOverflow := "disconnect"
 	defaultDBPath := filepath.Join(pwd, "juno")
 	defaultCoreContractAddress := common.HexToAddress("0xc662c410C0ECf747543f5bA90660f6ABeBD9C8c4")
 	defaultNetwork := utils.Mainnet
@@ -64,6 +67,7 @@ func TestConfigPrecedence(t *testing.T) {
 	defaultMaxHandles := 1024
 	defaultCallMaxSteps := uint(4_000_000)
 	defaultGwTimeout := 5 * time.Second
+	defaultAdminPort := uint16(6065)
 
 	tests := map[string]struct {
 		cfgFile         bool
@@ -90,6 +94,7 @@ func TestConfigPrecedence(t *testing.T) {
 				Websocket:           defaultWS,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				GRPC:                defaultGRPC,
 				GRPCHost:            defaultHost,
 				GRPCPort:            defaultGRPCPort,
@@ -109,6 +114,10 @@ func TestConfigPrecedence(t *testing.T) {
 				DBCacheSize:         defaultMaxCacheSize,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
@@ -135,6 +144,7 @@ cn-unverifiable-range: [0,10]
 				Websocket:           defaultWS,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				GRPC:                defaultGRPC,
 				GRPCHost:            defaultHost,
 				GRPCPort:            defaultGRPCPort,
@@ -154,6 +164,10 @@ cn-unverifiable-range: [0,10]
 				DBCacheSize:         defaultMaxCacheSize,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
@@ -167,6 +181,7 @@ cn-unverifiable-range: [0,10]
 				Websocket:           defaultWS,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				DatabasePath:        defaultDBPath,
 				Network:             defaultNetwork,
 				Pprof:               defaultPprof,
@@ -186,6 +201,10 @@ cn-unverifiable-range: [0,10]
 				DBCacheSize:         defaultMaxCacheSize,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
@@ -199,6 +218,7 @@ cn-unverifiable-range: [0,10]
 				Websocket:           defaultWS,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				GRPC:                defaultGRPC,
 				GRPCHost:            defaultHost,
 				GRPCPort:            defaultGRPCPort,
@@ -218,6 +238,10 @@ cn-unverifiable-range: [0,10]
 				DBCacheSize:         defaultMaxCacheSize,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
@@ -236,6 +260,7 @@ cn-unverifiable-range: [0,10]
 				Websocket:           defaultWS,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				GRPC:                defaultGRPC,
 				GRPCHost:            defaultHost,
 				GRPCPort:            defaultGRPCPort,
@@ -255,6 +280,10 @@ cn-unverifiable-range: [0,10]
 				DBCacheSize:         defaultMaxCacheSize,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
@@ -275,6 +304,7 @@ pprof: true
 				Websocket:           defaultWS,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				GRPC:                defaultGRPC,
 				GRPCHost:            defaultHost,
 				GRPCPort:            defaultGRPCPort,
@@ -294,6 +324,10 @@ pprof: true
 				DBCacheSize:         defaultMaxCacheSize,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
@@ -311,6 +345,7 @@ http-port: 4576
 				Websocket:           defaultWS,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				GRPC:                defaultGRPC,
 				GRPCHost:            defaultHost,
 				GRPCPort:            defaultGRPCPort,
@@ -330,6 +365,10 @@ http-port: 4576
 				DBCacheSize:         defaultMaxCacheSize,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
@@ -346,6 +385,7 @@ http-port: 4576
 				Websocket:           defaultWS,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				GRPC:                defaultGRPC,
 				GRPCHost:            defaultHost,
 				GRPCPort:            defaultGRPCPort,
@@ -364,6 +404,10 @@ http-port: 4576
 				DBCacheSize:         defaultMaxCacheSize,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 				PendingPollInterval: defaultPendingPollInterval,
 			},
@@ -381,6 +425,7 @@ http-port: 4576
 				Websocket:           defaultWS,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				GRPC:                defaultGRPC,
 				GRPCHost:            defaultHost,
 				GRPCPort:            defaultGRPCPort,
@@ -400,6 +445,10 @@ http-port: 4576
 				DBCacheSize:         defaultMaxCacheSize,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
@@ -440,6 +489,7 @@ db-cache-size: 1024
 				Websocket:           true,
 				WebsocketHost:       "127.0.0.1",
 				WebsocketPort:       4577,
+				IPCPermissions:      defaultIPCPermissions,
 				Metrics:             true,
 				MetricsHost:         "127.0.0.1",
 				MetricsPort:         4577,
@@ -459,6 +509,10 @@ db-cache-size: 1024
 				DBCacheSize:         9,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
@@ -478,6 +532,7 @@ network: sepolia
 				Websocket:           defaultWS,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				GRPC:                defaultGRPC,
 				GRPCHost:            defaultHost,
 				GRPCPort:            defaultGRPCPort,
@@ -497,6 +552,10 @@ network: sepolia
 				DBCacheSize:         defaultMaxCacheSize,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
@@ -512,6 +571,7 @@ network: sepolia
 				Websocket:           defaultWS,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				GRPC:                defaultGRPC,
 				GRPCHost:            defaultHost,
 				GRPCPort:            defaultGRPCPort,
@@ -531,6 +591,10 @@ network: sepolia
 				DBCacheSize:         defaultMaxCacheSize,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
@@ -544,6 +608,7 @@ network: sepolia
 				Websocket:           true,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				GRPC:                defaultGRPC,
 				GRPCHost:            defaultHost,
 				GRPCPort:            defaultGRPCPort,
@@ -563,6 +628,10 @@ network: sepolia
 				DBCacheSize:         defaultMaxCacheSize,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
@@ -577,6 +646,7 @@ network: sepolia
 				Websocket:           defaultWS,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				GRPC:                defaultGRPC,
 				GRPCHost:            defaultHost,
 				GRPCPort:            defaultGRPCPort,
@@ -596,6 +666,10 @@ network: sepolia
 				DBCacheSize:         defaultMaxCacheSize,
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
@@ -610,6 +684,7 @@ network: sepolia
 				Websocket:           defaultWS,
 				WebsocketHost:       defaultHost,
 				WebsocketPort:       defaultWSPort,
+				IPCPermissions:      defaultIPCPermissions,
 				GRPC:                defaultGRPC,
 				GRPCHost:            defaultHost,
 				GRPCPort:            defaultGRPCPort,
@@ -630,6 +705,10 @@ network: sepolia
 				GatewayAPIKey:       "apikey",
 				DBMaxHandles:        defaultMaxHandles,
 				RPCCallMaxSteps:     defaultCallMaxSteps,
+				AdminHost:           defaultHost,
+				AdminPort:           defaultAdminPort,
+				RPCSubQueueSize:     defaultRPCSubQueueSize,
+				RPCSubOverflow:      defaultRPCSubOverflow,
 				GatewayTimeout:      defaultGwTimeout,
 			},
 		},
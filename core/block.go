@@ -59,6 +59,8 @@ var (
 	starknetBlockHash0 = new(felt.Felt).SetBytes([]byte("STARKNET_BLOCK_HASH0"))
 	starknetBlockHash1 = new(felt.Felt).SetBytes([]byte("STARKNET_BLOCK_HASH1"))
 	starknetGasPrices0 = new(felt.Felt).SetBytes([]byte("STARKNET_GAS_PRICES0"))
+
+	ErrUnverifiableHeader = errors.New("block hash can't be computed from the header alone")
 )
 
 type GasPrice struct {
@@ -134,6 +136,27 @@ func VerifyBlockHash(b *Block, network *utils.Network, stateDiff *StateDiff) (*B
 	return nil, errors.New("can not verify hash in block header")
 }
 
+// HeaderHash computes the hash of a block from its header, commitments and state diff length, which is only
+// possible since Starknet 0.13.2: the hashes of older blocks depend on their transactions. The hashes of
+// blocks since Starknet 0.13.4 also depend on the L2 gas price. ErrUnverifiableHeader is returned otherwise.
+func HeaderHash(h *Header, commitments *BlockCommitments, stateDiffLength uint64) (*felt.Felt, error) {
+	blockVer, err := ParseBlockVersion(h.ProtocolVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if blockVer.GreaterThanEqual(semver.MustParse("0.13.4")) {
+		if h.L2GasPrice == nil {
+			return nil, ErrUnverifiableHeader
+		}
+		return post0134HeaderHash(h, commitments, stateDiffLength), nil
+	}
+	if blockVer.GreaterThanEqual(semver.MustParse("0.13.2")) {
+		return post0132HeaderHash(h, commitments, stateDiffLength), nil
+	}
+	return nil, ErrUnverifiableHeader
+}
+
 // blockHash computes the block hash, with option to override sequence address
 func blockHash(b *Block, stateDiff *StateDiff, network *utils.Network, overrideSeqAddr *felt.Felt) (*felt.Felt,
 	*BlockCommitments, error,
@@ -220,38 +243,43 @@ func post0134Hash(b *Block, stateDiff *StateDiff) (*felt.Felt, *BlockCommitments
 		return nil, nil, rErr
 	}
 
-	concatCounts := concatCounts(b.TransactionCount, b.EventCount, sdLength, b.L1DAMode)
+	commitments := &BlockCommitments{
+		TransactionCommitment: txCommitment,
+		EventCommitment:       eCommitment,
+		ReceiptCommitment:     rCommitment,
+		StateDiffCommitment:   sdCommitment,
+	}
+	return post0134HeaderHash(b.Header, commitments, sdLength), commitments, nil
+}
+
+func post0134HeaderHash(h *Header, commitments *BlockCommitments, stateDiffLength uint64) *felt.Felt {
+	concatCounts := concatCounts(h.TransactionCount, h.EventCount, stateDiffLength, h.L1DAMode)
 
 	pricesHash := gasPricesHash(
 		GasPrice{
-			PriceInFri: b.GasPriceSTRK,
-			PriceInWei: b.GasPrice,
+			PriceInFri: h.GasPriceSTRK,
+			PriceInWei: h.GasPrice,
 		},
-		*b.L1DataGasPrice,
-		*b.L2GasPrice,
+		*h.L1DataGasPrice,
+		*h.L2GasPrice,
 	)
 
 	return crypto.PoseidonArray(
-			starknetBlockHash1,
-			new(felt.Felt).SetUint64(b.Number),    // block number
-			b.GlobalStateRoot,                     // global state root
-			b.SequencerAddress,                    // sequencer address
-			new(felt.Felt).SetUint64(b.Timestamp), // block timestamp
-			concatCounts,
-			sdCommitment,
-			txCommitment, // transaction commitment
-			eCommitment,  // event commitment
-			rCommitment,  // receipt commitment
-			pricesHash,   // gas prices hash
-			new(felt.Felt).SetBytes([]byte(b.ProtocolVersion)),
-			&felt.Zero,   // reserved: extra data
-			b.ParentHash, // parent block hash
-		), &BlockCommitments{
-			TransactionCommitment: txCommitment,
-			EventCommitment:       eCommitment,
-			ReceiptCommitment:     rCommitment,
-			StateDiffCommitment:   sdCommitment,
-		}, nil
+		starknetBlockHash1,
+		new(felt.Felt).SetUint64(h.Number),    // block number
+		h.GlobalStateRoot,                     // global state root
+		h.SequencerAddress,                    // sequencer address
+		new(felt.Felt).SetUint64(h.Timestamp), // block timestamp
+		concatCounts,
+		commitments.StateDiffCommitment,
+		commitments.TransactionCommitment, // transaction commitment
+		commitments.EventCommitment,       // event commitment
+		commitments.ReceiptCommitment,     // receipt commitment
+		pricesHash,                        // gas prices hash
+		new(felt.Felt).SetBytes([]byte(h.ProtocolVersion)),
+		&felt.Zero,   // reserved: extra data
+		h.ParentHash, // parent block hash
+	)
 }
 
 func post0132Hash(b *Block, stateDiff *StateDiff) (*felt.Felt, *BlockCommitments, error) {
@@ -287,32 +315,37 @@ func post0132Hash(b *Block, stateDiff *StateDiff) (*felt.Felt, *BlockCommitments
 		return nil, nil, rErr
 	}
 
-	concatCounts := concatCounts(b.TransactionCount, b.EventCount, sdLength, b.L1DAMode)
+	commitments := &BlockCommitments{
+		TransactionCommitment: txCommitment,
+		EventCommitment:       eCommitment,
+		ReceiptCommitment:     rCommitment,
+		StateDiffCommitment:   sdCommitment,
+	}
+	return post0132HeaderHash(b.Header, commitments, sdLength), commitments, nil
+}
+
+func post0132HeaderHash(h *Header, commitments *BlockCommitments, stateDiffLength uint64) *felt.Felt {
+	concatCounts := concatCounts(h.TransactionCount, h.EventCount, stateDiffLength, h.L1DAMode)
 
 	return crypto.PoseidonArray(
-			starknetBlockHash0,
-			new(felt.Felt).SetUint64(b.Number),    // block number
-			b.GlobalStateRoot,                     // global state root
-			b.SequencerAddress,                    // sequencer address
-			new(felt.Felt).SetUint64(b.Timestamp), // block timestamp
-			concatCounts,
-			sdCommitment,
-			txCommitment,   // transaction commitment
-			eCommitment,    // event commitment
-			rCommitment,    // receipt commitment
-			b.GasPrice,     // gas price in wei
-			b.GasPriceSTRK, // gas price in fri
-			b.L1DataGasPrice.PriceInWei,
-			b.L1DataGasPrice.PriceInFri,
-			new(felt.Felt).SetBytes([]byte(b.ProtocolVersion)),
-			&felt.Zero,   // reserved: extra data
-			b.ParentHash, // parent block hash
-		), &BlockCommitments{
-			TransactionCommitment: txCommitment,
-			EventCommitment:       eCommitment,
-			ReceiptCommitment:     rCommitment,
-			StateDiffCommitment:   sdCommitment,
-		}, nil
+		starknetBlockHash0,
+		new(felt.Felt).SetUint64(h.Number),    // block number
+		h.GlobalStateRoot,                     // global state root
+		h.SequencerAddress,                    // sequencer address
+		new(felt.Felt).SetUint64(h.Timestamp), // block timestamp
+		concatCounts,
+		commitments.StateDiffCommitment,
+		commitments.TransactionCommitment, // transaction commitment
+		commitments.EventCommitment,       // event commitment
+		commitments.ReceiptCommitment,     // receipt commitment
+		h.GasPrice,                        // gas price in wei
+		h.GasPriceSTRK,                    // gas price in fri
+		h.L1DataGasPrice.PriceInWei,
+		h.L1DataGasPrice.PriceInFri,
+		new(felt.Felt).SetBytes([]byte(h.ProtocolVersion)),
+		&felt.Zero,   // reserved: extra data
+		h.ParentHash, // parent block hash
+	)
 }
 
 // post07Hash computes the block hash for blocks generated after Cairo 0.7.0
@@ -293,3 +293,40 @@ func Test0134BlockHash(t *testing.T) {
 		})
 	}
 }
+
+func TestHeaderHash(t *testing.T) {
+	t.Parallel()
+	client := feeder.NewTestClient(t, &utils.SepoliaIntegration)
+	gw := adaptfeeder.New(client)
+
+	for _, blockNum := range []uint64{35748, 64164} {
+		t.Run(fmt.Sprintf("blockNum=%v", blockNum), func(t *testing.T) {
+			t.Parallel()
+			b, err := gw.BlockByNumber(context.Background(), blockNum)
+			require.NoError(t, err)
+
+			su, err := gw.StateUpdate(context.Background(), blockNum)
+			require.NoError(t, err)
+
+			c, err := core.VerifyBlockHash(b, &utils.SepoliaIntegration, su.StateDiff)
+			require.NoError(t, err)
+
+			hash, err := core.HeaderHash(b.Header, c, su.StateDiff.Length())
+			require.NoError(t, err)
+			assert.Equal(t, b.Hash, hash)
+
+			b.L2GasPrice = nil
+			if _, err = core.HeaderHash(b.Header, c, su.StateDiff.Length()); blockNum == 64164 {
+				assert.ErrorIs(t, err, core.ErrUnverifiableHeader)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("pre 0.13.2 block", func(t *testing.T) {
+		t.Parallel()
+		_, err := core.HeaderHash(&core.Header{ProtocolVersion: "0.13.1"}, &core.BlockCommitments{}, 0)
+		assert.ErrorIs(t, err, core.ErrUnverifiableHeader)
+	})
+}
@@ -0,0 +1,340 @@
+package core
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/encoder"
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressedClassEncoding is the first byte of every value in the [db.Class] bucket that uses the
+// compressed layout. A legacy value is a CBOR encoded [DeclaredClass], which always starts with a map
+// header, so the two layouts never collide.
+const compressedClassEncoding byte = 0x01
+
+const blobRefCountSize = 8
+
+var (
+	classEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	classDecoder, _ = zstd.NewReader(nil)
+)
+
+// storedClass is the compressed on-disk representation of a [DeclaredClass].
+// Large fields are either compressed inline (ABIs) or moved into the [db.ClassBlobsByHash]
+// bucket (programs), where identical blobs are shared between classes.
+type storedClass struct {
+	At     uint64
+	Cairo0 *storedCairo0Class `cbor:",omitempty"`
+	Cairo1 *storedCairo1Class `cbor:",omitempty"`
+}
+
+type storedCairo0Class struct {
+	Abi          []byte
+	Externals    []EntryPoint
+	L1Handlers   []EntryPoint
+	Constructors []EntryPoint
+	ProgramKey   []byte
+}
+
+type storedCairo1Class struct {
+	Abi         []byte
+	AbiHash     *felt.Felt
+	EntryPoints struct {
+		Constructor []SierraEntryPoint
+		External    []SierraEntryPoint
+		L1Handler   []SierraEntryPoint
+	}
+	ProgramKey      []byte
+	ProgramHash     *felt.Felt
+	SemanticVersion string
+	CompiledKey     []byte
+}
+
+// IsCompressedClassEncoding reports whether a value of the [db.Class] bucket is stored in the
+// compressed layout.
+func IsCompressedClassEncoding(val []byte) bool {
+	return len(val) > 0 && val[0] == compressedClassEncoding
+}
+
+// StoreDeclaredClass writes the class under the given hash using the compressed layout, overwriting
+// any existing value. Program blobs referenced by a previous value are released first.
+func StoreDeclaredClass(txn db.Transaction, classHash *felt.Felt, declaredClass *DeclaredClass) error {
+	if err := releaseClassBlobs(txn, classHash); err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	}
+
+	stored := storedClass{At: declaredClass.At}
+	switch class := declaredClass.Class.(type) {
+	case *Cairo0Class:
+		programKey, err := putClassBlob(txn, []byte(class.Program))
+		if err != nil {
+			return err
+		}
+		stored.Cairo0 = &storedCairo0Class{
+			Abi:          compressClassData(class.Abi),
+			Externals:    class.Externals,
+			L1Handlers:   class.L1Handlers,
+			Constructors: class.Constructors,
+			ProgramKey:   programKey,
+		}
+	case *Cairo1Class:
+		program, err := encoder.Marshal(class.Program)
+		if err != nil {
+			return err
+		}
+		programKey, err := putClassBlob(txn, program)
+		if err != nil {
+			return err
+		}
+
+		var compiledKey []byte
+		if class.Compiled != nil {
+			compiled, err := encoder.Marshal(class.Compiled)
+			if err != nil {
+				return err
+			}
+			if compiledKey, err = putClassBlob(txn, compiled); err != nil {
+				return err
+			}
+		}
+
+		stored.Cairo1 = &storedCairo1Class{
+			Abi:             compressClassData([]byte(class.Abi)),
+			AbiHash:         class.AbiHash,
+			EntryPoints:     class.EntryPoints,
+			ProgramKey:      programKey,
+			ProgramHash:     class.ProgramHash,
+			SemanticVersion: class.SemanticVersion,
+			CompiledKey:     compiledKey,
+		}
+	default:
+		return fmt.Errorf("unsupported class type %T", declaredClass.Class)
+	}
+
+	storedBytes, err := encoder.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return txn.Set(db.Class.Key(classHash.Marshal()), append([]byte{compressedClassEncoding}, storedBytes...))
+}
+
+// declaredClass reads a class from the [db.Class] bucket, accepting both the legacy and the
+// compressed layout.
+func declaredClass(txn db.Transaction, classHash *felt.Felt) (*DeclaredClass, error) {
+	var (
+		class  DeclaredClass
+		stored *storedClass
+	)
+	err := txn.Get(db.Class.Key(classHash.Marshal()), func(val []byte) error {
+		if !IsCompressedClassEncoding(val) {
+			return encoder.Unmarshal(val, &class)
+		}
+		stored = new(storedClass)
+		return encoder.Unmarshal(val[1:], stored)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if stored != nil {
+		class.At = stored.At
+		if class.Class, err = stored.inflate(txn); err != nil {
+			return nil, err
+		}
+	}
+	return &class, nil
+}
+
+// deleteDeclaredClass removes a class and releases the program blobs it references.
+func deleteDeclaredClass(txn db.Transaction, classHash *felt.Felt) error {
+	if err := releaseClassBlobs(txn, classHash); err != nil {
+		return err
+	}
+	return txn.Delete(db.Class.Key(classHash.Marshal()))
+}
+
+func (s *storedClass) inflate(txn db.Transaction) (Class, error) {
+	switch {
+	case s.Cairo0 != nil:
+		abi, err := decompressClassData(s.Cairo0.Abi)
+		if err != nil {
+			return nil, fmt.Errorf("decompress abi: %v", err)
+		}
+		program, err := classBlob(txn, s.Cairo0.ProgramKey)
+		if err != nil {
+			return nil, err
+		}
+		return &Cairo0Class{
+			Abi:          json.RawMessage(abi),
+			Externals:    s.Cairo0.Externals,
+			L1Handlers:   s.Cairo0.L1Handlers,
+			Constructors: s.Cairo0.Constructors,
+			Program:      string(program),
+		}, nil
+	case s.Cairo1 != nil:
+		abi, err := decompressClassData(s.Cairo1.Abi)
+		if err != nil {
+			return nil, fmt.Errorf("decompress abi: %v", err)
+		}
+
+		class := &Cairo1Class{
+			Abi:             string(abi),
+			AbiHash:         s.Cairo1.AbiHash,
+			EntryPoints:     s.Cairo1.EntryPoints,
+			ProgramHash:     s.Cairo1.ProgramHash,
+			SemanticVersion: s.Cairo1.SemanticVersion,
+		}
+
+		program, err := classBlob(txn, s.Cairo1.ProgramKey)
+		if err != nil {
+			return nil, err
+		}
+		if err = encoder.Unmarshal(program, &class.Program); err != nil {
+			return nil, err
+		}
+
+		if s.Cairo1.CompiledKey != nil {
+			compiled, err := classBlob(txn, s.Cairo1.CompiledKey)
+			if err != nil {
+				return nil, err
+			}
+			class.Compiled = new(CompiledClass)
+			if err = encoder.Unmarshal(compiled, class.Compiled); err != nil {
+				return nil, err
+			}
+		}
+		return class, nil
+	default:
+		return nil, errors.New("stored class has no definition")
+	}
+}
+
+func (s *storedClass) blobKeys() [][]byte {
+	switch {
+	case s.Cairo0 != nil:
+		return [][]byte{s.Cairo0.ProgramKey}
+	case s.Cairo1 != nil:
+		if s.Cairo1.CompiledKey == nil {
+			return [][]byte{s.Cairo1.ProgramKey}
+		}
+		return [][]byte{s.Cairo1.ProgramKey, s.Cairo1.CompiledKey}
+	default:
+		return nil
+	}
+}
+
+// releaseClassBlobs drops the references that the currently stored class holds on shared blobs.
+// Legacy values own no blobs.
+func releaseClassBlobs(txn db.Transaction, classHash *felt.Felt) error {
+	var stored *storedClass
+	if err := txn.Get(db.Class.Key(classHash.Marshal()), func(val []byte) error {
+		if !IsCompressedClassEncoding(val) {
+			return nil
+		}
+		stored = new(storedClass)
+		return encoder.Unmarshal(val[1:], stored)
+	}); err != nil {
+		return err
+	}
+
+	if stored == nil {
+		return nil
+	}
+	for _, key := range stored.blobKeys() {
+		if err := releaseClassBlob(txn, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putClassBlob stores data in the [db.ClassBlobsByHash] bucket and returns the key it can be
+// retrieved with. The bucket is maintained as follows:
+//
+// [db.ClassBlobsByHash](ContentHash) -> (ReferenceCount, CompressedData)
+//
+// Storing data that is already present only increments its reference count.
+func putClassBlob(txn db.Transaction, data []byte) ([]byte, error) {
+	key := crypto.StarknetKeccak(data).Marshal()
+	dbKey := db.ClassBlobsByHash.Key(key)
+
+	var (
+		refCount   uint64
+		compressed []byte
+	)
+	err := txn.Get(dbKey, func(val []byte) error {
+		refCount = binary.BigEndian.Uint64(val[:blobRefCountSize])
+		compressed = slices.Clone(val[blobRefCountSize:])
+		return nil
+	})
+	if errors.Is(err, db.ErrKeyNotFound) {
+		compressed = compressClassData(data)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return key, setClassBlob(txn, dbKey, refCount+1, compressed)
+}
+
+// releaseClassBlob decrements the reference count of a blob and deletes it once it reaches zero.
+func releaseClassBlob(txn db.Transaction, key []byte) error {
+	dbKey := db.ClassBlobsByHash.Key(key)
+
+	var (
+		refCount   uint64
+		compressed []byte
+	)
+	if err := txn.Get(dbKey, func(val []byte) error {
+		refCount = binary.BigEndian.Uint64(val[:blobRefCountSize])
+		compressed = slices.Clone(val[blobRefCountSize:])
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if refCount <= 1 {
+		return txn.Delete(dbKey)
+	}
+	return setClassBlob(txn, dbKey, refCount-1, compressed)
+}
+
+func setClassBlob(txn db.Transaction, dbKey []byte, refCount uint64, compressed []byte) error {
+	val := make([]byte, blobRefCountSize, blobRefCountSize+len(compressed))
+	binary.BigEndian.PutUint64(val, refCount)
+	return txn.Set(dbKey, append(val, compressed...))
+}
+
+// classBlob returns the decompressed blob stored under key.
+func classBlob(txn db.Transaction, key []byte) ([]byte, error) {
+	var data []byte
+	err := txn.Get(db.ClassBlobsByHash.Key(key), func(val []byte) error {
+		var err error
+		data, err = decompressClassData(val[blobRefCountSize:])
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get class blob %x: %w", key, err)
+	}
+	return data, nil
+}
+
+func compressClassData(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	return classEncoder.EncodeAll(data, nil)
+}
+
+func decompressClassData(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	return classDecoder.DecodeAll(data, nil)
+}
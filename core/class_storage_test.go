@@ -0,0 +1,80 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/encoder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func countBucketEntries(t *testing.T, txn db.Transaction, bucket db.Bucket) int {
+	t.Helper()
+
+	it, err := txn.NewIterator(bucket.Key(), true)
+	require.NoError(t, err)
+	count := 0
+	for it.First(); it.Valid(); it.Next() {
+		count++
+	}
+	require.NoError(t, it.Close())
+	return count
+}
+
+func TestStoreDeclaredClass(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+	txn, err := testDB.NewTransaction(true)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, txn.Discard())
+	})
+
+	cairo1Class := &core.Cairo1Class{
+		Abi:             `[{"type":"function","name":"foo"}]`,
+		AbiHash:         utils.HexToFelt(t, "0x1"),
+		Program:         []*felt.Felt{utils.HexToFelt(t, "0x2"), utils.HexToFelt(t, "0x3")},
+		ProgramHash:     utils.HexToFelt(t, "0x4"),
+		SemanticVersion: "0.1.0",
+		Compiled: &core.CompiledClass{
+			Bytecode:        []*felt.Felt{utils.HexToFelt(t, "0x5")},
+			CompilerVersion: "2.6.0",
+		},
+	}
+
+	t.Run("legacy values are still readable", func(t *testing.T) {
+		legacyHash := utils.HexToFelt(t, "0xabc")
+		legacy := core.DeclaredClass{At: 3, Class: cairo1Class}
+		legacyBytes, err := encoder.Marshal(legacy)
+		require.NoError(t, err)
+		require.NoError(t, txn.Set(db.Class.Key(legacyHash.Marshal()), legacyBytes))
+		require.False(t, core.IsCompressedClassEncoding(legacyBytes))
+
+		got, err := core.NewState(txn).Class(legacyHash)
+		require.NoError(t, err)
+		assert.Equal(t, &legacy, got)
+
+		require.NoError(t, core.StoreDeclaredClass(txn, legacyHash, &legacy))
+		got, err = core.NewState(txn).Class(legacyHash)
+		require.NoError(t, err)
+		assert.Equal(t, &legacy, got)
+	})
+
+	t.Run("identical programs are stored once", func(t *testing.T) {
+		blobs := countBucketEntries(t, txn, db.ClassBlobsByHash)
+
+		otherHash := utils.HexToFelt(t, "0xdef")
+		require.NoError(t, core.StoreDeclaredClass(txn, otherHash, &core.DeclaredClass{At: 4, Class: cairo1Class}))
+		assert.Equal(t, blobs, countBucketEntries(t, txn, db.ClassBlobsByHash))
+
+		// rewriting a class must not leak references
+		require.NoError(t, core.StoreDeclaredClass(txn, otherHash, &core.DeclaredClass{At: 4, Class: cairo1Class}))
+		got, err := core.NewState(txn).Class(otherHash)
+		require.NoError(t, err)
+		assert.Equal(t, cairo1Class, got.Class)
+	})
+}
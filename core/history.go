@@ -132,3 +132,38 @@ func (h *history) ContractClassHashAt(contractAddress *felt.Felt, height uint64)
 
 	return new(felt.Felt).SetBytes(value), nil
 }
+
+// ClassReplacement records that the class of a contract was replaced at BlockNumber, and the class hash
+// the contract instantiated before that.
+type ClassReplacement struct {
+	BlockNumber  uint64
+	OldClassHash *felt.Felt
+}
+
+// ContractClassReplacements returns every class replacement of the given contract in ascending height order
+func (h *history) ContractClassReplacements(contractAddress *felt.Felt) ([]ClassReplacement, error) {
+	key := classHashLogKey(contractAddress)
+	it, err := h.txn.NewIterator(key, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var replacements []ClassReplacement
+	for it.First(); it.Valid(); it.Next() {
+		seekedKey := it.Key()
+		if len(seekedKey) != len(key)+8 {
+			continue
+		}
+
+		val, itErr := it.Value()
+		if itErr != nil {
+			return nil, utils.RunAndWrapOnError(it.Close, itErr)
+		}
+		replacements = append(replacements, ClassReplacement{
+			BlockNumber:  binary.BigEndian.Uint64(seekedKey[len(key):]),
+			OldClassHash: new(felt.Felt).SetBytes(val),
+		})
+	}
+
+	return replacements, it.Close()
+}
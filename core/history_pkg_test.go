@@ -102,3 +102,33 @@ func TestHistory(t *testing.T) {
 		})
 	}
 }
+
+func TestContractClassReplacements(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+	txn, err := testDB.NewTransaction(true)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, txn.Discard())
+	})
+
+	history := &history{txn: txn}
+	contractAddress := new(felt.Felt).SetUint64(123)
+	otherAddress := new(felt.Felt).SetUint64(124)
+
+	replacements, err := history.ContractClassReplacements(contractAddress)
+	require.NoError(t, err)
+	assert.Empty(t, replacements)
+
+	classHashA := new(felt.Felt).SetUint64(1)
+	classHashB := new(felt.Felt).SetUint64(2)
+	require.NoError(t, history.LogContractClassHash(contractAddress, classHashB, 10))
+	require.NoError(t, history.LogContractClassHash(contractAddress, classHashA, 5))
+	require.NoError(t, history.LogContractClassHash(otherAddress, classHashA, 7))
+
+	replacements, err = history.ContractClassReplacements(contractAddress)
+	require.NoError(t, err)
+	assert.Equal(t, []ClassReplacement{
+		{BlockNumber: 5, OldClassHash: classHashA},
+		{BlockNumber: 10, OldClassHash: classHashB},
+	}, replacements)
+}
@@ -1,9 +1,25 @@
 package core
 
-import "github.com/NethermindEth/juno/core/felt"
+import (
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/ethereum/go-ethereum/common"
+)
 
 type L1Head struct {
 	BlockNumber uint64
 	BlockHash   *felt.Felt
 	StateRoot   *felt.Felt
 }
+
+// L1StateUpdate is a LogStateUpdate event of the core contract, which settles a range of L2 blocks on L1.
+type L1StateUpdate struct {
+	L1BlockNumber uint64
+	L1BlockHash   common.Hash
+	L1TxnHash     common.Hash
+	// FirstBlockNumber is the first L2 block settled by the update. It is only known if the previous
+	// update was seen, otherwise it is the same as BlockNumber.
+	FirstBlockNumber uint64
+	BlockNumber      uint64
+	BlockHash        *felt.Felt
+	StateRoot        *felt.Felt
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/crypto/sha3"
+)
+
+type MessageStatus uint8
+
+const (
+	MessagePending MessageStatus = iota
+	MessageConsumed
+	MessageCancellationStarted
+	MessageCancelled
+)
+
+// Hash computes the hash the core contract identifies the message with.
+func (m *L1ToL2Message) Hash() common.Hash {
+	digest := sha3.NewLegacyKeccak256()
+	digest.Write(common.LeftPadBytes(m.From.Bytes(), common.HashLength))
+	for _, f := range []*felt.Felt{m.To, m.Nonce, m.Selector, new(felt.Felt).SetUint64(uint64(len(m.Payload)))} {
+		b := f.Bytes()
+		digest.Write(b[:])
+	}
+	for _, f := range m.Payload {
+		b := f.Bytes()
+		digest.Write(b[:])
+	}
+	return common.BytesToHash(digest.Sum(nil))
+}
+
+// L1ToL2MessageLog tracks a message sent to L2 through the L1 transactions that changed its status.
+type L1ToL2MessageLog struct {
+	L1ToL2Message
+	// Fee is only known if the transaction that sent the message has been seen
+	Fee *felt.Felt
+
+	SentTxnHash                *common.Hash
+	ConsumedTxnHash            *common.Hash
+	CancellationStartedTxnHash *common.Hash
+	CancelledTxnHash           *common.Hash
+}
+
+func (m *L1ToL2MessageLog) Status() MessageStatus {
+	switch {
+	case m.CancelledTxnHash != nil:
+		return MessageCancelled
+	case m.ConsumedTxnHash != nil:
+		return MessageConsumed
+	case m.CancellationStartedTxnHash != nil:
+		return MessageCancellationStarted
+	default:
+		return MessagePending
+	}
+}
+
+// Merge adds the fee and the status changes known by other, which must be the same message.
+func (m *L1ToL2MessageLog) Merge(other *L1ToL2MessageLog) {
+	if other.Fee != nil {
+		m.Fee = other.Fee
+	}
+	if other.SentTxnHash != nil {
+		m.SentTxnHash = other.SentTxnHash
+	}
+	if other.ConsumedTxnHash != nil {
+		m.ConsumedTxnHash = other.ConsumedTxnHash
+	}
+	if other.CancellationStartedTxnHash != nil {
+		m.CancellationStartedTxnHash = other.CancellationStartedTxnHash
+	}
+	if other.CancelledTxnHash != nil {
+		m.CancelledTxnHash = other.CancelledTxnHash
+	}
+}
+
+// Hash computes the hash the core contract identifies the message with. Since the message has no
+// nonce, identical messages share the same hash.
+func (m *L2ToL1Message) Hash() common.Hash {
+	digest := sha3.NewLegacyKeccak256()
+	for _, f := range []*felt.Felt{m.From, new(felt.Felt).SetBytes(m.To.Bytes()), new(felt.Felt).SetUint64(uint64(len(m.Payload)))} {
+		b := f.Bytes()
+		digest.Write(b[:])
+	}
+	for _, f := range m.Payload {
+		b := f.Bytes()
+		digest.Write(b[:])
+	}
+	return common.BytesToHash(digest.Sum(nil))
+}
+
+// L2ToL1MessageLog tracks a message sent to L1. The core contract counts how many copies of a message
+// were logged by state updates and how many of them were consumed.
+type L2ToL1MessageLog struct {
+	L2ToL1Message
+	Logged              uint64
+	Consumed            uint64
+	LastLoggedTxnHash   *common.Hash
+	LastConsumedTxnHash *common.Hash
+}
+
+func (m *L2ToL1MessageLog) Status() MessageStatus {
+	if m.Consumed < m.Logged {
+		return MessagePending
+	}
+	return MessageConsumed
+}
+
+// Merge adds the copies logged and consumed in other, which must be the same message.
+func (m *L2ToL1MessageLog) Merge(other *L2ToL1MessageLog) {
+	m.Logged += other.Logged
+	m.Consumed += other.Consumed
+	if other.LastLoggedTxnHash != nil {
+		m.LastLoggedTxnHash = other.LastLoggedTxnHash
+	}
+	if other.LastConsumedTxnHash != nil {
+		m.LastConsumedTxnHash = other.LastConsumedTxnHash
+	}
+}
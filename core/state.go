@@ -14,7 +14,6 @@ import (
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/core/trie"
 	"github.com/NethermindEth/juno/db"
-	"github.com/NethermindEth/juno/encoder"
 	"github.com/sourcegraph/conc/pool"
 )
 
@@ -25,6 +24,9 @@ var (
 	leafVersion  = new(felt.Felt).SetBytes([]byte(`CONTRACT_CLASS_LEAF_V0`))
 )
 
+// ErrMismatchedRoot is returned when the root of the state doesn't match the root a state update expects.
+var ErrMismatchedRoot = errors.New("does not match the expected root")
+
 var _ StateHistoryReader = (*State)(nil)
 
 //go:generate mockgen -destination=../mocks/mock_state.go -package=mocks github.com/NethermindEth/juno/core StateHistoryReader
@@ -69,6 +71,10 @@ func (s *State) putNewContract(stateTrie *trie.Trie, addr, classHash *felt.Felt,
 		return err
 	}
 
+	if err = s.indexContractClass(addr, classHash); err != nil {
+		return err
+	}
+
 	return s.updateContractCommitment(stateTrie, contract)
 }
 
@@ -117,11 +123,16 @@ func (s *State) Root() (*felt.Felt, error) {
 		return nil, err
 	}
 
+	return StateCommitment(storageRoot, classesRoot), nil
+}
+
+// StateCommitment returns the commitment to the state with the given roots of the contracts and classes tries.
+func StateCommitment(contractsRoot, classesRoot *felt.Felt) *felt.Felt {
 	if classesRoot.IsZero() {
-		return storageRoot, nil
+		return contractsRoot
 	}
 
-	return crypto.PoseidonArray(stateVersion, storageRoot, classesRoot), nil
+	return crypto.PoseidonArray(stateVersion, contractsRoot, classesRoot)
 }
 
 // storage returns a [core.Trie] that represents the Starknet global state in the given Txn context.
@@ -189,7 +200,7 @@ func (s *State) verifyStateUpdateRoot(root *felt.Felt) error {
 	}
 
 	if !root.Equal(currentRoot) {
-		return fmt.Errorf("state's current root: %s does not match the expected root: %s", currentRoot, root)
+		return fmt.Errorf("state's current root: %s %w: %s", currentRoot, ErrMismatchedRoot, root)
 	}
 	return nil
 }
@@ -295,6 +306,13 @@ func (s *State) replaceContract(stateTrie *trie.Trie, addr, classHash *felt.Felt
 		return nil, err
 	}
 
+	if err = s.unindexContractClass(addr, oldClassHash); err != nil {
+		return nil, err
+	}
+	if err = s.indexContractClass(addr, classHash); err != nil {
+		return nil, err
+	}
+
 	if err = s.updateContractCommitment(stateTrie, contract); err != nil {
 		return nil, err
 	}
@@ -315,31 +333,17 @@ func (s *State) putClass(classHash *felt.Felt, class Class, declaredAt uint64) e
 	})
 
 	if errors.Is(err, db.ErrKeyNotFound) {
-		classEncoded, encErr := encoder.Marshal(DeclaredClass{
+		return StoreDeclaredClass(s.txn, classHash, &DeclaredClass{
 			At:    declaredAt,
 			Class: class,
 		})
-		if encErr != nil {
-			return encErr
-		}
-
-		return s.txn.Set(classKey, classEncoded)
 	}
 	return err
 }
 
 // Class returns the class object corresponding to the given classHash
 func (s *State) Class(classHash *felt.Felt) (*DeclaredClass, error) {
-	classKey := db.Class.Key(classHash.Marshal())
-
-	var class DeclaredClass
-	err := s.txn.Get(classKey, func(val []byte) error {
-		return encoder.Unmarshal(val, &class)
-	})
-	if err != nil {
-		return nil, err
-	}
-	return &class, nil
+	return declaredClass(s.txn, classHash)
 }
 
 func (s *State) updateStorageBuffered(contractAddr *felt.Felt, updateDiff map[felt.Felt]*felt.Felt, blockNumber uint64, logChanges bool) (
@@ -485,13 +489,14 @@ func (s *State) updateContractCommitment(stateTrie *trie.Trie, contract *Contrac
 		return err
 	}
 
-	commitment := calculateContractCommitment(root, cHash, nonce)
+	commitment := ContractCommitment(root, cHash, nonce)
 
 	_, err = stateTrie.Put(contract.Address, commitment)
 	return err
 }
 
-func calculateContractCommitment(storageRoot, classHash, nonce *felt.Felt) *felt.Felt {
+// ContractCommitment returns the leaf of a contract in the contracts trie.
+func ContractCommitment(storageRoot, classHash, nonce *felt.Felt) *felt.Felt {
 	return crypto.Pedersen(crypto.Pedersen(crypto.Pedersen(classHash, storageRoot), nonce), &felt.Zero)
 }
 
@@ -506,8 +511,7 @@ func (s *State) updateDeclaredClassesTrie(declaredClasses map[felt.Felt]*felt.Fe
 			continue
 		}
 
-		leafValue := crypto.Poseidon(leafVersion, compiledClassHash)
-		if _, err = classesTrie.Put(&classHash, leafValue); err != nil {
+		if _, err = classesTrie.Put(&classHash, ClassCommitment(compiledClassHash)); err != nil {
 			return err
 		}
 	}
@@ -515,6 +519,51 @@ func (s *State) updateDeclaredClassesTrie(declaredClasses map[felt.Felt]*felt.Fe
 	return classesCloser()
 }
 
+// ClassCommitment returns the leaf of a Cairo 1 class in the classes trie.
+func ClassCommitment(compiledClassHash *felt.Felt) *felt.Felt {
+	return crypto.Poseidon(leafVersion, compiledClassHash)
+}
+
+// ContractDeploymentHeight returns the height at which the contract at addr was deployed
+func (s *State) ContractDeploymentHeight(addr *felt.Felt) (uint64, error) {
+	var deployedAt uint64
+	return deployedAt, s.txn.Get(db.ContractDeploymentHeight.Key(addr.Marshal()), func(bytes []byte) error {
+		deployedAt = binary.BigEndian.Uint64(bytes)
+		return nil
+	})
+}
+
+// indexContractClass records that the contract at addr instantiates classHash as follows:
+//
+// [db.ContractAddressesByClassHash](ClassHash, ContractAddress) -> ()
+func (s *State) indexContractClass(addr, classHash *felt.Felt) error {
+	return s.txn.Set(db.ContractAddressesByClassHash.Key(classHash.Marshal(), addr.Marshal()), []byte{})
+}
+
+func (s *State) unindexContractClass(addr, classHash *felt.Felt) error {
+	return s.txn.Delete(db.ContractAddressesByClassHash.Key(classHash.Marshal(), addr.Marshal()))
+}
+
+// ContractsByClassHash returns up to limit addresses of the contracts that currently instantiate
+// classHash, in ascending order starting from startAt.
+func (s *State) ContractsByClassHash(classHash, startAt *felt.Felt, limit uint64) ([]*felt.Felt, error) {
+	prefix := db.ContractAddressesByClassHash.Key(classHash.Marshal())
+	it, err := s.txn.NewIterator(prefix, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var addresses []*felt.Felt
+	for it.Seek(db.ContractAddressesByClassHash.Key(classHash.Marshal(), startAt.Marshal())); it.Valid(); it.Next() {
+		if uint64(len(addresses)) >= limit {
+			break
+		}
+		addresses = append(addresses, new(felt.Felt).SetBytes(it.Key()[len(prefix):]))
+	}
+
+	return addresses, it.Close()
+}
+
 // ContractIsAlreadyDeployedAt returns if contract at given addr was deployed at blockNumber
 func (s *State) ContractIsAlreadyDeployedAt(addr *felt.Felt, blockNumber uint64) (bool, error) {
 	var deployedAt uint64
@@ -625,7 +674,7 @@ func (s *State) removeDeclaredClasses(blockNumber uint64, v0Classes []*felt.Felt
 			continue
 		}
 
-		if err = s.txn.Delete(db.Class.Key(cHash.Marshal())); err != nil {
+		if err = deleteDeclaredClass(s.txn, cHash); err != nil {
 			return fmt.Errorf("delete class: %v", err)
 		}
 
@@ -654,6 +703,14 @@ func (s *State) purgeContract(addr *felt.Felt) error {
 		return err
 	}
 
+	classHash, err := ContractClassHash(addr, s.txn)
+	if err != nil {
+		return err
+	}
+	if err = s.unindexContractClass(addr, classHash); err != nil {
+		return err
+	}
+
 	if _, err = state.Put(contract.Address, &felt.Zero); err != nil {
 		return err
 	}
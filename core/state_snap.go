@@ -0,0 +1,147 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
+)
+
+// StateTrieReader gives access to the tries committing to the state, to prove ranges of their leaves to peers.
+// The tries are meant to be read, changes to them aren't persisted.
+type StateTrieReader interface {
+	StateReader
+
+	ContractsTrie() (*trie.Trie, error)
+	ClassesTrie() (*trie.Trie, error)
+	ContractStorageTrie(addr *felt.Felt) (*trie.Trie, error)
+}
+
+var _ StateTrieReader = (*State)(nil)
+
+// ContractsTrie returns the trie mapping contract addresses to their commitment.
+func (s *State) ContractsTrie() (*trie.Trie, error) {
+	contracts, _, err := s.storage()
+	return contracts, err
+}
+
+// ClassesTrie returns the trie mapping the hashes of Cairo 1 classes to their commitment.
+func (s *State) ClassesTrie() (*trie.Trie, error) {
+	classes, _, err := s.classesTrie()
+	return classes, err
+}
+
+// ContractStorageTrie returns the storage trie of the contract at addr.
+func (s *State) ContractStorageTrie(addr *felt.Felt) (*trie.Trie, error) {
+	return storage(addr, s.txn)
+}
+
+// PutContract writes the state of a contract received from a peer rather than derived from state updates. The class
+// hash and nonce are left unchanged if nil, and only the given storage slots are written. The history of the contract
+// isn't recorded, so the state can't be reverted past blockNumber.
+func (s *State) PutContract(addr, classHash, nonce *felt.Felt, storageSlots map[felt.Felt]*felt.Felt, blockNumber uint64) error {
+	contracts, contractsCloser, err := s.storage()
+	if err != nil {
+		return err
+	}
+
+	contract, err := NewContractUpdater(addr, s.txn)
+	switch {
+	case errors.Is(err, ErrContractNotDeployed):
+		if classHash == nil {
+			classHash = noClassContractsClassHash
+		}
+		if err = s.putNewContract(contracts, addr, classHash, blockNumber); err != nil {
+			return err
+		}
+		if contract, err = NewContractUpdater(addr, s.txn); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case classHash != nil:
+		oldClassHash, err := ContractClassHash(addr, s.txn)
+		if err != nil {
+			return err
+		}
+		if !oldClassHash.Equal(classHash) {
+			if _, err = s.replaceContract(contracts, addr, classHash); err != nil {
+				return err
+			}
+		}
+	}
+
+	if nonce != nil {
+		if err = contract.UpdateNonce(nonce); err != nil {
+			return err
+		}
+	}
+
+	if err = contract.UpdateStorage(storageSlots, func(_, _ *felt.Felt) error { return nil }); err != nil {
+		return err
+	}
+
+	if err = s.updateContractCommitment(contracts, contract); err != nil {
+		return err
+	}
+	return contractsCloser()
+}
+
+// PutCompiledClassHashes writes the leaves of the given Cairo 1 classes, mapped to their compiled class hash, in the
+// classes trie.
+func (s *State) PutCompiledClassHashes(compiledClassHashes map[felt.Felt]*felt.Felt) error {
+	classes, classesCloser, err := s.classesTrie()
+	if err != nil {
+		return err
+	}
+
+	for classHash, compiledClassHash := range compiledClassHashes {
+		if _, err = classes.Put(&classHash, ClassCommitment(compiledClassHash)); err != nil {
+			return err
+		}
+	}
+	return classesCloser()
+}
+
+// PutClass stores the definition of a class declared at the given block, unless it is already known.
+func (s *State) PutClass(classHash *felt.Felt, class Class, declaredAt uint64) error {
+	return s.putClass(classHash, class, declaredAt)
+}
+
+// ApplyStateDiff writes the values set by the state diff of the given block without checking the state roots.
+// Since state diffs only hold the latest value of what they change, a state assembled from ranges of the state
+// at different blocks is brought to the state at the last one by applying the state diffs of the blocks that
+// follow the first one, in order.
+func (s *State) ApplyStateDiff(blockNumber uint64, diff *StateDiff, declaredClasses map[felt.Felt]Class) error {
+	for classHash, class := range declaredClasses {
+		if err := s.putClass(&classHash, class, blockNumber); err != nil {
+			return err
+		}
+	}
+
+	if err := s.PutCompiledClassHashes(diff.DeclaredV1Classes); err != nil {
+		return err
+	}
+
+	addresses := make(map[felt.Felt]struct{})
+	for _, changed := range []map[felt.Felt]*felt.Felt{diff.DeployedContracts, diff.ReplacedClasses, diff.Nonces} {
+		for addr := range changed {
+			addresses[addr] = struct{}{}
+		}
+	}
+	for addr := range diff.StorageDiffs {
+		addresses[addr] = struct{}{}
+	}
+
+	for addr := range addresses {
+		classHash := diff.DeployedContracts[addr]
+		if replacedBy, ok := diff.ReplacedClasses[addr]; ok {
+			classHash = replacedBy
+		}
+
+		if err := s.PutContract(&addr, classHash, diff.Nonces[addr], diff.StorageDiffs[addr], blockNumber); err != nil {
+			return err
+		}
+	}
+	return nil
+}
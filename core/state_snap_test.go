@@ -0,0 +1,95 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db/pebble"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutContract(t *testing.T) {
+	client := feeder.NewTestClient(t, &utils.Mainnet)
+	gw := adaptfeeder.New(client)
+
+	newState := func(t *testing.T) *core.State {
+		txn, err := pebble.NewMemTest(t).NewTransaction(true)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, txn.Discard())
+		})
+		return core.NewState(txn)
+	}
+
+	var stateUpdates []*core.StateUpdate
+	for i := range uint64(3) {
+		su, err := gw.StateUpdate(context.Background(), i)
+		require.NoError(t, err)
+		stateUpdates = append(stateUpdates, su)
+	}
+
+	// copyState writes every contract of from into to, the way a node downloading the state from peers would
+	copyState := func(t *testing.T, from, to *core.State, blockNumber uint64) {
+		t.Helper()
+
+		contracts, err := from.ContractsTrie()
+		require.NoError(t, err)
+		addresses, _, err := contracts.Leaves(&felt.Zero, 1000)
+		require.NoError(t, err)
+		require.NotEmpty(t, addresses)
+
+		for _, addr := range addresses {
+			classHash, err := from.ContractClassHash(addr)
+			require.NoError(t, err)
+			nonce, err := from.ContractNonce(addr)
+			require.NoError(t, err)
+
+			storage, err := from.ContractStorageTrie(addr)
+			require.NoError(t, err)
+			keys, values, err := storage.Leaves(&felt.Zero, 1000)
+			require.NoError(t, err)
+			slots := make(map[felt.Felt]*felt.Felt, len(keys))
+			for i, key := range keys {
+				slots[*key] = values[i]
+			}
+
+			require.NoError(t, to.PutContract(addr, classHash, nonce, slots, blockNumber))
+		}
+	}
+
+	t.Run("state written contract by contract has the same root", func(t *testing.T) {
+		source, target := newState(t), newState(t)
+		for i, su := range stateUpdates {
+			require.NoError(t, source.Update(uint64(i), su, nil))
+		}
+
+		copyState(t, source, target, 2)
+		root, err := target.Root()
+		require.NoError(t, err)
+		assert.Equal(t, stateUpdates[2].NewRoot, root)
+
+		deployedAt, err := target.ContractDeploymentHeight(&su1FirstDeployedAddress)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), deployedAt)
+	})
+
+	t.Run("state diffs bring an older state up to date", func(t *testing.T) {
+		source, target := newState(t), newState(t)
+		require.NoError(t, source.Update(0, stateUpdates[0], nil))
+		copyState(t, source, target, 0)
+
+		for i, su := range stateUpdates {
+			// applying the state diff of the block the state was copied at again changes nothing
+			require.NoError(t, target.ApplyStateDiff(uint64(i), su.StateDiff, nil))
+		}
+		root, err := target.Root()
+		require.NoError(t, err)
+		assert.Equal(t, stateUpdates[2].NewRoot, root)
+	})
+}
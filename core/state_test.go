@@ -69,7 +69,9 @@ func TestUpdate(t *testing.T) {
 			StateDiff: new(core.StateDiff),
 		}
 		expectedErr := fmt.Sprintf("state's current root: %s does not match the expected root: %s", su0.NewRoot, newRoot)
-		require.EqualError(t, state.Update(1, su, nil), expectedErr)
+		err := state.Update(1, su, nil)
+		require.EqualError(t, err, expectedErr)
+		require.ErrorIs(t, err, core.ErrMismatchedRoot)
 	})
 
 	t.Run("non-empty state updated multiple times", func(t *testing.T) {
@@ -396,6 +398,89 @@ func TestClass(t *testing.T) {
 	assert.Equal(t, cairo0Class, gotCairo0Class.Class)
 }
 
+func TestContractsByClassHash(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+	txn, err := testDB.NewTransaction(true)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, txn.Discard())
+	})
+
+	client := feeder.NewTestClient(t, &utils.Mainnet)
+	gw := adaptfeeder.New(client)
+
+	state := core.NewState(txn)
+	su0, err := gw.StateUpdate(context.Background(), 0)
+	require.NoError(t, err)
+	require.NoError(t, state.Update(0, su0, nil))
+	su1, err := gw.StateUpdate(context.Background(), 1)
+	require.NoError(t, err)
+	require.NoError(t, state.Update(1, su1, nil))
+
+	address := new(felt.Felt).Set(&su1FirstDeployedAddress)
+	classHash := su1.StateDiff.DeployedContracts[*address]
+
+	contractsOf := func(t *testing.T, classHash *felt.Felt) []*felt.Felt {
+		t.Helper()
+		addresses, err := state.ContractsByClassHash(classHash, &felt.Zero, 1000)
+		require.NoError(t, err)
+		return addresses
+	}
+
+	t.Run("deployed contracts are indexed", func(t *testing.T) {
+		var expected []*felt.Felt
+		for _, diff := range []*core.StateDiff{su0.StateDiff, su1.StateDiff} {
+			for addr, deployedClassHash := range diff.DeployedContracts {
+				if deployedClassHash.Equal(classHash) {
+					expected = append(expected, addr.Clone())
+				}
+			}
+		}
+		assert.ElementsMatch(t, expected, contractsOf(t, classHash))
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		all := contractsOf(t, classHash)
+		require.Greater(t, len(all), 1)
+
+		first, err := state.ContractsByClassHash(classHash, &felt.Zero, 1)
+		require.NoError(t, err)
+		assert.Equal(t, all[:1], first)
+
+		rest, err := state.ContractsByClassHash(classHash, all[1], uint64(len(all)))
+		require.NoError(t, err)
+		assert.Equal(t, all[1:], rest)
+	})
+
+	replacedClassHash := utils.HexToFelt(t, "0xDEADBEEF")
+	replaceStateUpdate := &core.StateUpdate{
+		NewRoot: utils.HexToFelt(t, "0x30b1741b28893b892ac30350e6372eac3a6f32edee12f9cdca7fbe7540a5ee"),
+		OldRoot: su1.NewRoot,
+		StateDiff: &core.StateDiff{
+			ReplacedClasses: map[felt.Felt]*felt.Felt{
+				*address: replacedClassHash,
+			},
+		},
+	}
+
+	t.Run("replaced classes are reindexed", func(t *testing.T) {
+		require.NoError(t, state.Update(2, replaceStateUpdate, nil))
+		assert.Equal(t, []*felt.Felt{address}, contractsOf(t, replacedClassHash))
+		assert.NotContains(t, contractsOf(t, classHash), address)
+	})
+
+	t.Run("reverted replacements are reindexed", func(t *testing.T) {
+		require.NoError(t, state.Revert(2, replaceStateUpdate))
+		assert.Empty(t, contractsOf(t, replacedClassHash))
+		assert.Contains(t, contractsOf(t, classHash), address)
+	})
+
+	t.Run("reverted deployments are removed", func(t *testing.T) {
+		require.NoError(t, state.Revert(1, su1))
+		assert.NotContains(t, contractsOf(t, classHash), address)
+	})
+}
+
 func TestRevert(t *testing.T) {
 	testDB := pebble.NewMemTest(t)
 	txn, err := testDB.NewTransaction(true)
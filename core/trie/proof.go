@@ -193,12 +193,15 @@ func VerifyProof(root, keyFelt *felt.Felt, proof *ProofNodeSet, hash crypto.Hash
 //   - Zero element proof: A single edge proof suffices for verification. The proof is invalid if there are additional elements.
 //
 // The function returns a boolean indicating if there are more elements and an error if the range proof is invalid.
+// hash is the hash function of the trie the proof comes from.
 //
 // TODO(weiihann): Given a binary leaf and a left-sibling first key, if the right sibling is removed, the proof would still be valid.
 // Conversely, given a binary leaf and a right-sibling last key, if the left sibling is removed, the proof would still be valid.
 // Range proof should not be valid for both of these cases, but currently is, which is an attack vector.
 // The problem probably lies in how we do root hash calculation.
-func VerifyRangeProof(root, first *felt.Felt, keys, values []*felt.Felt, proof *ProofNodeSet) (bool, error) { //nolint:funlen,gocyclo
+func VerifyRangeProof(root, first *felt.Felt, keys, values []*felt.Felt, proof *ProofNodeSet, //nolint:funlen,gocyclo
+	hash crypto.HashFn,
+) (bool, error) {
 	// Ensure the number of keys and values are the same
 	if len(keys) != len(values) {
 		return false, fmt.Errorf("inconsistent length of proof data, keys: %d, values: %d", len(keys), len(values))
@@ -217,7 +220,7 @@ func VerifyRangeProof(root, first *felt.Felt, keys, values []*felt.Felt, proof *
 
 	// Special case: no edge proof provided; the given range contains all leaves in the trie
 	if proof == nil {
-		tr, err := buildTrie(globalTrieHeight, nil, nil, keys, values)
+		tr, err := buildTrie(globalTrieHeight, nil, nil, keys, values, hash)
 		if err != nil {
 			return false, err
 		}
@@ -296,7 +299,7 @@ func VerifyRangeProof(root, first *felt.Felt, keys, values []*felt.Felt, proof *
 	}
 
 	// Build the trie from the proof paths
-	tr, err := buildTrie(globalTrieHeight, rootKey, nodes.List(), keys, values)
+	tr, err := buildTrie(globalTrieHeight, rootKey, nodes.List(), keys, values, hash)
 	if err != nil {
 		return false, err
 	}
@@ -579,8 +582,8 @@ func verifyEdgePath(key, edgePath *Key, curPos uint8) bool {
 }
 
 // buildTrie builds a trie from a list of storage nodes and a list of keys and values.
-func buildTrie(height uint8, rootKey *Key, nodes []*StorageNode, keys, values []*felt.Felt) (*Trie, error) {
-	tr, err := NewTriePedersen(newMemStorage(), height)
+func buildTrie(height uint8, rootKey *Key, nodes []*StorageNode, keys, values []*felt.Felt, hash crypto.HashFn) (*Trie, error) {
+	tr, err := newTrie(newMemStorage(), height, hash)
 	if err != nil {
 		return nil, err
 	}
@@ -219,7 +219,7 @@ func TestRangeProof(t *testing.T) {
 			values = append(values, records[i].value)
 		}
 
-		_, err = trie.VerifyRangeProof(root, records[start].key, keys, values, proof)
+		_, err = trie.VerifyRangeProof(root, records[start].key, keys, values, proof, crypto.Pedersen)
 		require.NoError(t, err)
 	}
 }
@@ -253,7 +253,7 @@ func TestRangeProofWithNonExistentProof(t *testing.T) {
 			values[i-start] = records[i].value
 		}
 
-		_, err = trie.VerifyRangeProof(root, first, keys, values, proof)
+		_, err = trie.VerifyRangeProof(root, first, keys, values, proof, crypto.Pedersen)
 		require.NoError(t, err)
 	}
 }
@@ -283,7 +283,7 @@ func TestRangeProofWithInvalidNonExistentProof(t *testing.T) {
 		values[i-start] = records[i].value
 	}
 
-	_, err = trie.VerifyRangeProof(root, first, keys, values, proof)
+	_, err = trie.VerifyRangeProof(root, first, keys, values, proof, crypto.Pedersen)
 	require.Error(t, err)
 }
 
@@ -303,7 +303,7 @@ func TestOneElementRangeProof(t *testing.T) {
 		err = tr.GetRangeProof(records[start].key, records[start].key, proof)
 		require.NoError(t, err)
 
-		_, err = trie.VerifyRangeProof(root, records[start].key, []*felt.Felt{records[start].key}, []*felt.Felt{records[start].value}, proof)
+		_, err = trie.VerifyRangeProof(root, records[start].key, []*felt.Felt{records[start].key}, []*felt.Felt{records[start].value}, proof, crypto.Pedersen)
 		require.NoError(t, err)
 	})
 
@@ -315,7 +315,7 @@ func TestOneElementRangeProof(t *testing.T) {
 		err = tr.GetRangeProof(decrementFelt(records[start].key), records[start].key, proof)
 		require.NoError(t, err)
 
-		_, err = trie.VerifyRangeProof(root, decrementFelt(records[start].key), []*felt.Felt{records[start].key}, []*felt.Felt{records[start].value}, proof)
+		_, err = trie.VerifyRangeProof(root, decrementFelt(records[start].key), []*felt.Felt{records[start].key}, []*felt.Felt{records[start].value}, proof, crypto.Pedersen)
 		require.NoError(t, err)
 	})
 
@@ -327,7 +327,7 @@ func TestOneElementRangeProof(t *testing.T) {
 		err = tr.GetRangeProof(records[end].key, incrementFelt(records[end].key), proof)
 		require.NoError(t, err)
 
-		_, err = trie.VerifyRangeProof(root, records[end].key, []*felt.Felt{records[end].key}, []*felt.Felt{records[end].value}, proof)
+		_, err = trie.VerifyRangeProof(root, records[end].key, []*felt.Felt{records[end].key}, []*felt.Felt{records[end].value}, proof, crypto.Pedersen)
 		require.NoError(t, err)
 	})
 
@@ -340,7 +340,7 @@ func TestOneElementRangeProof(t *testing.T) {
 		err = tr.GetRangeProof(first, last, proof)
 		require.NoError(t, err)
 
-		_, err = trie.VerifyRangeProof(root, first, []*felt.Felt{records[start].key}, []*felt.Felt{records[start].value}, proof)
+		_, err = trie.VerifyRangeProof(root, first, []*felt.Felt{records[start].key}, []*felt.Felt{records[start].value}, proof, crypto.Pedersen)
 		require.NoError(t, err)
 	})
 
@@ -355,7 +355,7 @@ func TestOneElementRangeProof(t *testing.T) {
 		err = tr.GetRangeProof(&felt.Zero, records[0].key, proof)
 		require.NoError(t, err)
 
-		_, err = trie.VerifyRangeProof(root, records[0].key, []*felt.Felt{records[0].key}, []*felt.Felt{records[0].value}, proof)
+		_, err = trie.VerifyRangeProof(root, records[0].key, []*felt.Felt{records[0].key}, []*felt.Felt{records[0].value}, proof, crypto.Pedersen)
 		require.NoError(t, err)
 	})
 }
@@ -376,7 +376,7 @@ func TestAllElementsRangeProof(t *testing.T) {
 		values[i] = record.value
 	}
 
-	_, err = trie.VerifyRangeProof(root, nil, keys, values, nil)
+	_, err = trie.VerifyRangeProof(root, nil, keys, values, nil, crypto.Pedersen)
 	require.NoError(t, err)
 
 	// Should also work with proof
@@ -384,7 +384,7 @@ func TestAllElementsRangeProof(t *testing.T) {
 	err = tr.GetRangeProof(records[0].key, records[n-1].key, proof)
 	require.NoError(t, err)
 
-	_, err = trie.VerifyRangeProof(root, keys[0], keys, values, proof)
+	_, err = trie.VerifyRangeProof(root, keys[0], keys, values, proof, crypto.Pedersen)
 	require.NoError(t, err)
 }
 
@@ -408,7 +408,7 @@ func TestSingleSideRangeProof(t *testing.T) {
 			values[j] = records[j].value
 		}
 
-		_, err = trie.VerifyRangeProof(root, &felt.Zero, keys, values, proof)
+		_, err = trie.VerifyRangeProof(root, &felt.Zero, keys, values, proof, crypto.Pedersen)
 		require.NoError(t, err)
 	}
 }
@@ -437,7 +437,7 @@ func TestGappedRangeProof(t *testing.T) {
 		values = append(values, records[i].value)
 	}
 
-	_, err = trie.VerifyRangeProof(root, records[first].key, keys, values, proof)
+	_, err = trie.VerifyRangeProof(root, records[first].key, keys, values, proof, crypto.Pedersen)
 	require.Error(t, err)
 }
 
@@ -462,7 +462,7 @@ func TestEmptyRangeProof(t *testing.T) {
 		err = tr.GetRangeProof(first, first, proof)
 		require.NoError(t, err)
 
-		_, err := trie.VerifyRangeProof(root, first, nil, nil, proof)
+		_, err := trie.VerifyRangeProof(root, first, nil, nil, proof, crypto.Pedersen)
 		if c.err {
 			require.Error(t, err)
 		} else {
@@ -517,7 +517,7 @@ func TestHasRightElement(t *testing.T) {
 			values = append(values, records[i].value)
 		}
 
-		hasMore, err := trie.VerifyRangeProof(root, first, keys, values, proof)
+		hasMore, err := trie.VerifyRangeProof(root, first, keys, values, proof, crypto.Pedersen)
 		require.NoError(t, err)
 		require.Equal(t, c.hasMore, hasMore)
 	}
@@ -574,7 +574,7 @@ func TestBadRangeProof(t *testing.T) {
 			// 	keys = append(keys[:index], keys[index+1:]...)
 			// 	values = append(values[:index], values[index+1:]...)
 		}
-		_, err = trie.VerifyRangeProof(root, first, keys, values, proof)
+		_, err = trie.VerifyRangeProof(root, first, keys, values, proof, crypto.Pedersen)
 		if err == nil {
 			t.Fatalf("expected error for test case %d, index %d, start %d, end %d", testCase, index, start, end)
 		}
@@ -637,7 +637,7 @@ func BenchmarkVerifyRangeProof(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := trie.VerifyRangeProof(root, keys[0], keys, values, proof)
+		_, err := trie.VerifyRangeProof(root, keys[0], keys, values, proof, crypto.Pedersen)
 		require.NoError(b, err)
 	}
 }
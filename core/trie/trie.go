@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"slices"
 	"strings"
 	"sync"
 
@@ -266,6 +267,40 @@ func (t *Trie) Get(key *felt.Felt) (*felt.Felt, error) {
 	return &leafValue, nil
 }
 
+// Leaves returns up to limit leaves of the trie with a key at or after start, in ascending key order.
+// Only the leaves committed to the storage are returned.
+func (t *Trie) Leaves(start *felt.Felt, limit int) ([]*felt.Felt, []*felt.Felt, error) {
+	// leaves are stored as prefix | height | key, so iterating over them follows the key order
+	leavesPrefix := append(slices.Clone(t.storage.prefix), t.height)
+	it, err := t.storage.txn.NewIterator(leavesPrefix, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	startKey := t.FeltToKey(start)
+	buffer := getBuffer()
+	defer bufferPool.Put(buffer)
+	if _, err = t.storage.dbKey(&startKey, buffer); err != nil {
+		return nil, nil, utils.RunAndWrapOnError(it.Close, err)
+	}
+
+	var keys, values []*felt.Felt
+	for it.Seek(buffer.Bytes()); it.Valid() && len(keys) < limit; it.Next() {
+		val, err := it.Value()
+		if err != nil {
+			return nil, nil, utils.RunAndWrapOnError(it.Close, err)
+		}
+
+		var node Node
+		if err = node.UnmarshalBinary(val); err != nil {
+			return nil, nil, utils.RunAndWrapOnError(it.Close, err)
+		}
+		keys = append(keys, new(felt.Felt).SetBytes(it.Key()[len(leavesPrefix):]))
+		values = append(values, node.Value)
+	}
+	return keys, values, it.Close()
+}
+
 // GetNodeFromKey returns the node for a given key.
 func (t *Trie) GetNodeFromKey(key *Key) (*Node, error) {
 	return t.storage.Get(key)
@@ -7,6 +7,7 @@ import (
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/core/trie"
 	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/pebble"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -380,3 +381,38 @@ func BenchmarkTriePut(b *testing.B) {
 		return t.Commit()
 	}))
 }
+
+func TestLeaves(t *testing.T) {
+	txn, err := pebble.NewMemTest(t).NewTransaction(true)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, txn.Discard())
+	})
+
+	newTrie := func(prefix byte, keys ...uint64) *trie.Trie {
+		tempTrie, err := trie.NewTriePedersen(trie.NewStorage(txn, []byte{prefix}), 251)
+		require.NoError(t, err)
+		for _, key := range keys {
+			_, err = tempTrie.Put(new(felt.Felt).SetUint64(key), new(felt.Felt).SetUint64(key*10))
+			require.NoError(t, err)
+		}
+		require.NoError(t, tempTrie.Commit())
+		return tempTrie
+	}
+	tempTrie := newTrie(1, 5, 1, 9, 3)
+	// leaves of other tries are not returned
+	newTrie(2, 4, 7)
+
+	keys, values, err := tempTrie.Leaves(new(felt.Felt).SetUint64(2), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []*felt.Felt{new(felt.Felt).SetUint64(3), new(felt.Felt).SetUint64(5)}, keys)
+	assert.Equal(t, []*felt.Felt{new(felt.Felt).SetUint64(30), new(felt.Felt).SetUint64(50)}, values)
+
+	keys, _, err = tempTrie.Leaves(new(felt.Felt).SetUint64(6), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*felt.Felt{new(felt.Felt).SetUint64(9)}, keys)
+
+	keys, _, err = tempTrie.Leaves(new(felt.Felt).SetUint64(10), 10)
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
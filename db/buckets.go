@@ -33,7 +33,19 @@ const (
 	BlockCommitments
 	Temporary // used temporarily for migrations
 	SchemaIntermediateState
-	L1HandlerTxnHashByMsgHash // maps l1 handler msg hash to l1 handler txn hash
+	L1HandlerTxnHashByMsgHash                         // maps l1 handler msg hash to l1 handler txn hash
+	ClassBlobsByHash                                  // maps content hashes to reference counted, compressed class programs
+	TransactionBlockNumbersAndIndicesBySenderAndNonce // maps sender address and nonce to block number and index
+	ContractAddressesByClassHash                      // maps class hash and address of each contract currently instantiating it to nothing
+	L1VerifiedHeight                                  // Latest L2 block number whose hash and state root match the ones finalised on L1
+	L1MessagesHeight                                  // Latest L1 block number whose messaging events were indexed
+	L1ToL2MessagesByHash                              // maps message hash to the message sent to L2 and its status
+	L2ToL1MessagesByHash                              // maps message hash to the message sent to L1 and its status
+	L1ToL2MessageHashesByAddress                      // maps the sender or recipient address and hash of each message sent to L2 to nothing
+	L2ToL1MessageHashesByAddress                      // maps the sender or recipient address and hash of each message sent to L1 to nothing
+	L1ToL2MessageHashesByL1TxnHash                    // maps L1 transaction hash and hash of each message it sent to L2 to nothing
+	L1StateUpdatesByBlockNumber                       // maps the last L2 block number settled by each LogStateUpdate to the L1 transaction that emitted it
+	SnapSyncPivot                                     // Lowest and highest block numbers the state being snap synced was downloaded at
 )
 
 // Key flattens a prefix and series of byte arrays into a single []byte.
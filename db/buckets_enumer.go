@@ -7,11 +7,11 @@ import (
 	"strings"
 )
 
-const _BucketName = "StateTriePeerContractClassHashContractStorageClassContractNonceChainHeightBlockHeaderNumbersByHashBlockHeadersByNumberTransactionBlockNumbersAndIndicesByHashTransactionsByBlockNumberAndIndexReceiptsByBlockNumberAndIndexStateUpdatesByBlockNumberClassesTrieContractStorageHistoryContractNonceHistoryContractClassHashHistoryContractDeploymentHeightL1HeightSchemaVersionUnusedBlockCommitmentsTemporarySchemaIntermediateStateL1HandlerTxnHashByMsgHash"
+const _BucketName = "StateTriePeerContractClassHashContractStorageClassContractNonceChainHeightBlockHeaderNumbersByHashBlockHeadersByNumberTransactionBlockNumbersAndIndicesByHashTransactionsByBlockNumberAndIndexReceiptsByBlockNumberAndIndexStateUpdatesByBlockNumberClassesTrieContractStorageHistoryContractNonceHistoryContractClassHashHistoryContractDeploymentHeightL1HeightSchemaVersionUnusedBlockCommitmentsTemporarySchemaIntermediateStateL1HandlerTxnHashByMsgHashClassBlobsByHashTransactionBlockNumbersAndIndicesBySenderAndNonceContractAddressesByClassHashL1VerifiedHeightL1MessagesHeightL1ToL2MessagesByHashL2ToL1MessagesByHashL1ToL2MessageHashesByAddressL2ToL1MessageHashesByAddressL1ToL2MessageHashesByL1TxnHashL1StateUpdatesByBlockNumberSnapSyncPivot"
 
-var _BucketIndex = [...]uint16{0, 9, 13, 30, 45, 50, 63, 74, 98, 118, 157, 190, 219, 244, 255, 277, 297, 321, 345, 353, 366, 372, 388, 397, 420, 445}
+var _BucketIndex = [...]uint16{0, 9, 13, 30, 45, 50, 63, 74, 98, 118, 157, 190, 219, 244, 255, 277, 297, 321, 345, 353, 366, 372, 388, 397, 420, 445, 461, 510, 538, 554, 570, 590, 610, 638, 666, 696, 723, 736}
 
-const _BucketLowerName = "statetriepeercontractclasshashcontractstorageclasscontractnoncechainheightblockheadernumbersbyhashblockheadersbynumbertransactionblocknumbersandindicesbyhashtransactionsbyblocknumberandindexreceiptsbyblocknumberandindexstateupdatesbyblocknumberclassestriecontractstoragehistorycontractnoncehistorycontractclasshashhistorycontractdeploymentheightl1heightschemaversionunusedblockcommitmentstemporaryschemaintermediatestatel1handlertxnhashbymsghash"
+const _BucketLowerName = "statetriepeercontractclasshashcontractstorageclasscontractnoncechainheightblockheadernumbersbyhashblockheadersbynumbertransactionblocknumbersandindicesbyhashtransactionsbyblocknumberandindexreceiptsbyblocknumberandindexstateupdatesbyblocknumberclassestriecontractstoragehistorycontractnoncehistorycontractclasshashhistorycontractdeploymentheightl1heightschemaversionunusedblockcommitmentstemporaryschemaintermediatestatel1handlertxnhashbymsghashclassblobsbyhashtransactionblocknumbersandindicesbysenderandnoncecontractaddressesbyclasshashl1verifiedheightl1messagesheightl1tol2messagesbyhashl2tol1messagesbyhashl1tol2messagehashesbyaddressl2tol1messagehashesbyaddressl1tol2messagehashesbyl1txnhashl1stateupdatesbyblocknumbersnapsyncpivot"
 
 func (i Bucket) String() string {
 	if i >= Bucket(len(_BucketIndex)-1) {
@@ -49,9 +49,21 @@ func _BucketNoOp() {
 	_ = x[Temporary-(22)]
 	_ = x[SchemaIntermediateState-(23)]
 	_ = x[L1HandlerTxnHashByMsgHash-(24)]
+	_ = x[ClassBlobsByHash-(25)]
+	_ = x[TransactionBlockNumbersAndIndicesBySenderAndNonce-(26)]
+	_ = x[ContractAddressesByClassHash-(27)]
+	_ = x[L1VerifiedHeight-(28)]
+	_ = x[L1MessagesHeight-(29)]
+	_ = x[L1ToL2MessagesByHash-(30)]
+	_ = x[L2ToL1MessagesByHash-(31)]
+	_ = x[L1ToL2MessageHashesByAddress-(32)]
+	_ = x[L2ToL1MessageHashesByAddress-(33)]
+	_ = x[L1ToL2MessageHashesByL1TxnHash-(34)]
+	_ = x[L1StateUpdatesByBlockNumber-(35)]
+	_ = x[SnapSyncPivot-(36)]
 }
 
-var _BucketValues = []Bucket{StateTrie, Peer, ContractClassHash, ContractStorage, Class, ContractNonce, ChainHeight, BlockHeaderNumbersByHash, BlockHeadersByNumber, TransactionBlockNumbersAndIndicesByHash, TransactionsByBlockNumberAndIndex, ReceiptsByBlockNumberAndIndex, StateUpdatesByBlockNumber, ClassesTrie, ContractStorageHistory, ContractNonceHistory, ContractClassHashHistory, ContractDeploymentHeight, L1Height, SchemaVersion, Unused, BlockCommitments, Temporary, SchemaIntermediateState, L1HandlerTxnHashByMsgHash}
+var _BucketValues = []Bucket{StateTrie, Peer, ContractClassHash, ContractStorage, Class, ContractNonce, ChainHeight, BlockHeaderNumbersByHash, BlockHeadersByNumber, TransactionBlockNumbersAndIndicesByHash, TransactionsByBlockNumberAndIndex, ReceiptsByBlockNumberAndIndex, StateUpdatesByBlockNumber, ClassesTrie, ContractStorageHistory, ContractNonceHistory, ContractClassHashHistory, ContractDeploymentHeight, L1Height, SchemaVersion, Unused, BlockCommitments, Temporary, SchemaIntermediateState, L1HandlerTxnHashByMsgHash, ClassBlobsByHash, TransactionBlockNumbersAndIndicesBySenderAndNonce, ContractAddressesByClassHash, L1VerifiedHeight, L1MessagesHeight, L1ToL2MessagesByHash, L2ToL1MessagesByHash, L1ToL2MessageHashesByAddress, L2ToL1MessageHashesByAddress, L1ToL2MessageHashesByL1TxnHash, L1StateUpdatesByBlockNumber, SnapSyncPivot}
 
 var _BucketNameToValueMap = map[string]Bucket{
 	_BucketName[0:9]:          StateTrie,
@@ -104,6 +116,30 @@ var _BucketNameToValueMap = map[string]Bucket{
 	_BucketLowerName[397:420]: SchemaIntermediateState,
 	_BucketName[420:445]:      L1HandlerTxnHashByMsgHash,
 	_BucketLowerName[420:445]: L1HandlerTxnHashByMsgHash,
+	_BucketName[445:461]:      ClassBlobsByHash,
+	_BucketLowerName[445:461]: ClassBlobsByHash,
+	_BucketName[461:510]:      TransactionBlockNumbersAndIndicesBySenderAndNonce,
+	_BucketLowerName[461:510]: TransactionBlockNumbersAndIndicesBySenderAndNonce,
+	_BucketName[510:538]:      ContractAddressesByClassHash,
+	_BucketLowerName[510:538]: ContractAddressesByClassHash,
+	_BucketName[538:554]:      L1VerifiedHeight,
+	_BucketLowerName[538:554]: L1VerifiedHeight,
+	_BucketName[554:570]:      L1MessagesHeight,
+	_BucketLowerName[554:570]: L1MessagesHeight,
+	_BucketName[570:590]:      L1ToL2MessagesByHash,
+	_BucketLowerName[570:590]: L1ToL2MessagesByHash,
+	_BucketName[590:610]:      L2ToL1MessagesByHash,
+	_BucketLowerName[590:610]: L2ToL1MessagesByHash,
+	_BucketName[610:638]:      L1ToL2MessageHashesByAddress,
+	_BucketLowerName[610:638]: L1ToL2MessageHashesByAddress,
+	_BucketName[638:666]:      L2ToL1MessageHashesByAddress,
+	_BucketLowerName[638:666]: L2ToL1MessageHashesByAddress,
+	_BucketName[666:696]:      L1ToL2MessageHashesByL1TxnHash,
+	_BucketLowerName[666:696]: L1ToL2MessageHashesByL1TxnHash,
+	_BucketName[696:723]:      L1StateUpdatesByBlockNumber,
+	_BucketLowerName[696:723]: L1StateUpdatesByBlockNumber,
+	_BucketName[723:736]:      SnapSyncPivot,
+	_BucketLowerName[723:736]: SnapSyncPivot,
 }
 
 var _BucketNames = []string{
@@ -132,6 +168,18 @@ var _BucketNames = []string{
 	_BucketName[388:397],
 	_BucketName[397:420],
 	_BucketName[420:445],
+	_BucketName[445:461],
+	_BucketName[461:510],
+	_BucketName[510:538],
+	_BucketName[538:554],
+	_BucketName[554:570],
+	_BucketName[570:590],
+	_BucketName[590:610],
+	_BucketName[610:638],
+	_BucketName[638:666],
+	_BucketName[666:696],
+	_BucketName[696:723],
+	_BucketName[723:736],
 }
 
 // BucketString retrieves an enum value from the enum constants string name.
@@ -119,6 +119,12 @@ func (d *DB) Impl() any {
 	return d.pebble
 }
 
+// Compact compacts every key of the database, reclaiming the space taken by deleted and overwritten values.
+func (d *DB) Compact() error {
+	// keys start with a bucket prefix, which is never maxByte
+	return d.pebble.Compact([]byte{0}, []byte{maxByte}, true)
+}
+
 type Item struct {
 	Count uint
 	Size  utils.DataSize
@@ -508,3 +508,24 @@ func TestCalculatePrefixSize(t *testing.T) {
 		})
 	})
 }
+
+func TestCompact(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+	require.NoError(t, testDB.(*pebble.DB).Compact())
+
+	require.NoError(t, testDB.Update(func(txn db.Transaction) error {
+		for i := range byte(10) {
+			require.NoError(t, txn.Set([]byte{0, i}, []byte{i}))
+		}
+		return txn.Delete([]byte{0, 0})
+	}))
+	require.NoError(t, testDB.(*pebble.DB).Compact())
+
+	require.NoError(t, testDB.View(func(txn db.Transaction) error {
+		require.ErrorIs(t, txn.Get([]byte{0, 0}, noop), db.ErrKeyNotFound)
+		return txn.Get([]byte{0, 9}, func(value []byte) error {
+			assert.Equal(t, []byte{9}, value)
+			return nil
+		})
+	}))
+}
@@ -0,0 +1,122 @@
+package jsonrpc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ClientIDKey the key used to retrieve the identity of the authenticated client from the context passed to a
+// handler or middleware.
+type ClientIDKey struct{}
+
+// ClientIDFromContext returns the identity AuthMiddleware authenticated the client with. For API keys, it is derived
+// from the key so that the key itself doesn't leak into logs or metrics. For JWTs, it is the subject of the token.
+func ClientIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(ClientIDKey{}).(string)
+	return id, ok
+}
+
+// AuthMiddleware rejects requests that don't carry one of apiKeys or a JWT signed with jwtSecret using HS256.
+// Credentials are read from the X-API-Key header or, as a bearer token, from the Authorization header. A JWT
+// with an exp or nbf claim is only accepted within the time they define. Requests that weren't received over a
+// transport, or that were received over IPC, are trusted.
+func AuthMiddleware(apiKeys []string, jwtSecret []byte) Middleware {
+	return func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, req *Request) (any, http.Header, *Error) {
+			peer, ok := PeerFromContext(ctx)
+			if !ok || peer.Transport == ipcTransport {
+				return next(ctx, req)
+			}
+
+			token := peer.Header.Get("X-API-Key")
+			if bearer, found := strings.CutPrefix(peer.Header.Get("Authorization"), "Bearer "); found {
+				token = bearer
+			}
+			if token == "" {
+				return nil, nil, Err(Unauthorized, "missing credentials")
+			}
+
+			for _, key := range apiKeys {
+				if subtle.ConstantTimeCompare([]byte(token), []byte(key)) == 1 {
+					digest := sha256.Sum256([]byte(key))
+					return next(context.WithValue(ctx, ClientIDKey{}, "key-"+hex.EncodeToString(digest[:4])), req)
+				}
+			}
+
+			if len(jwtSecret) > 0 {
+				subject, err := verifyJWT(token, jwtSecret, time.Now())
+				if err == nil {
+					return next(context.WithValue(ctx, ClientIDKey{}, "jwt-"+subject), req)
+				}
+				return nil, nil, Err(Unauthorized, err.Error())
+			}
+			return nil, nil, Err(Unauthorized, "invalid credentials")
+		}
+	}
+}
+
+type jwtClaims struct {
+	Subject   string `json:"sub"`
+	ExpiresAt *int64 `json:"exp"`
+	NotBefore *int64 `json:"nbf"`
+}
+
+// verifyJWT checks the HS256 signature and the validity period of token and returns its subject.
+func verifyJWT(token string, secret []byte, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 { //nolint:mnd
+		return "", errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return "", err
+	}
+	if header.Alg != "HS256" {
+		return "", errors.New("unsupported token algorithm")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("malformed token signature")
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return "", errors.New("invalid token signature")
+	}
+
+	var claims jwtClaims
+	if err = decodeJWTPart(parts[1], &claims); err != nil {
+		return "", err
+	}
+	if claims.ExpiresAt != nil && now.Unix() >= *claims.ExpiresAt {
+		return "", errors.New("token expired")
+	}
+	if claims.NotBefore != nil && now.Unix() < *claims.NotBefore {
+		return "", errors.New("token not valid yet")
+	}
+	return claims.Subject, nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return errors.New("malformed token")
+	}
+	if err = json.Unmarshal(decoded, v); err != nil {
+		return errors.New("malformed token")
+	}
+	return nil
+}
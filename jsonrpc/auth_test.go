@@ -0,0 +1,105 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signJWT(alg, claims string, secret []byte) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	unsigned := encode([]byte(`{"alg":"`+alg+`","typ":"JWT"}`)) + "." + encode([]byte(claims))
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + encode(mac.Sum(nil))
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	secret := []byte("secret")
+	var clientID string
+	server := newEchoServer(t, jsonrpc.AuthMiddleware([]string{"key"}, secret),
+		func(next jsonrpc.RequestHandler) jsonrpc.RequestHandler {
+			return func(ctx context.Context, req *jsonrpc.Request) (any, http.Header, *jsonrpc.Error) {
+				clientID, _ = jsonrpc.ClientIDFromContext(ctx)
+				return next(ctx, req)
+			}
+		})
+
+	future, past := time.Now().Add(time.Hour).Unix(), time.Now().Add(-time.Hour).Unix()
+	tests := map[string]struct {
+		header   http.Header
+		clientID string
+		err      string
+	}{
+		"no credentials": {
+			header: http.Header{},
+			err:    "missing credentials",
+		},
+		"api key": {
+			header:   http.Header{"X-Api-Key": {"key"}},
+			clientID: "key-2c70e12b",
+		},
+		"api key as bearer token": {
+			header:   http.Header{"Authorization": {"Bearer key"}},
+			clientID: "key-2c70e12b",
+		},
+		"unknown api key": {
+			header: http.Header{"X-Api-Key": {"other"}},
+			err:    "malformed token",
+		},
+		"jwt": {
+			header:   http.Header{"Authorization": {"Bearer " + signJWT("HS256", `{"sub":"alice"}`, secret)}},
+			clientID: "jwt-alice",
+		},
+		"jwt within its validity period": {
+			header: http.Header{"Authorization": {
+				"Bearer " + signJWT("HS256", `{"sub":"alice","nbf":`+strconv.FormatInt(past, 10)+`,"exp":`+strconv.FormatInt(future, 10)+`}`, secret),
+			}},
+			clientID: "jwt-alice",
+		},
+		"expired jwt": {
+			header: http.Header{"Authorization": {"Bearer " + signJWT("HS256", `{"sub":"alice","exp":`+strconv.FormatInt(past, 10)+`}`, secret)}},
+			err:    "token expired",
+		},
+		"jwt not valid yet": {
+			header: http.Header{"Authorization": {"Bearer " + signJWT("HS256", `{"sub":"alice","nbf":`+strconv.FormatInt(future, 10)+`}`, secret)}},
+			err:    "token not valid yet",
+		},
+		"jwt signed with another secret": {
+			header: http.Header{"Authorization": {"Bearer " + signJWT("HS256", `{"sub":"alice"}`, []byte("other"))}},
+			err:    "invalid token signature",
+		},
+		"jwt with another algorithm": {
+			header: http.Header{"Authorization": {"Bearer " + signJWT("none", `{"sub":"alice"}`, secret)}},
+			err:    "unsupported token algorithm",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clientID = ""
+			ctx := context.WithValue(context.Background(), jsonrpc.PeerKey{}, &jsonrpc.Peer{Transport: "http", Header: test.header})
+			res := call(t, ctx, server, "juno_version")
+			if test.err != "" {
+				assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32001,"message":"Unauthorized","data":"`+test.err+`"},"id":1}`, res)
+				return
+			}
+			assert.Equal(t, `{"jsonrpc":"2.0","result":"juno_version","id":1}`, res)
+			assert.Equal(t, test.clientID, clientID)
+		})
+	}
+
+	t.Run("requests that weren't received over a transport are trusted", func(t *testing.T) {
+		res := call(t, context.Background(), server, "juno_version")
+		require.Equal(t, `{"jsonrpc":"2.0","result":"juno_version","id":1}`, res)
+	})
+}
@@ -1,6 +1,7 @@
 package jsonrpc
 
 import (
+	"context"
 	"maps"
 	"net/http"
 
@@ -47,7 +48,8 @@ func (h *HTTP) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
 
 	req.Body = http.MaxBytesReader(writer, req.Body, MaxRequestBodySize)
 	h.listener.OnNewRequest("any")
-	resp, header, err := h.rpc.HandleReader(req.Context(), req.Body)
+	ctx := context.WithValue(req.Context(), PeerKey{}, peerFromHTTPRequest("http", req))
+	resp, header, err := h.rpc.HandleReader(ctx, req.Body)
 
 	writer.Header().Set("Content-Type", "application/json")
 	maps.Copy(writer.Header(), header) // overwrites duplicate headers
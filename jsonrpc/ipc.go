@@ -0,0 +1,127 @@
+package jsonrpc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/utils"
+)
+
+const ipcTransport = "ipc"
+
+// IPC serves JSON-RPC over stream connections, such as those accepted on a Unix domain socket, where requests and
+// responses are separated by newlines. Like over websockets, clients can subscribe and receive notifications on
+// the same connection.
+type IPC struct {
+	rpc        *Server
+	log        utils.SimpleLogger
+	connParams *IPCConnParams
+	listener   NewRequestListener
+}
+
+func NewIPC(rpc *Server, log utils.SimpleLogger) *IPC {
+	return &IPC{
+		rpc:        rpc,
+		log:        log,
+		connParams: DefaultIPCConnParams(),
+		listener:   &SelectiveListener{},
+	}
+}
+
+// WithConnParams applies the provided params.
+func (i *IPC) WithConnParams(p *IPCConnParams) *IPC {
+	i.connParams = p
+	return i
+}
+
+// WithListener registers a NewRequestListener
+func (i *IPC) WithListener(listener NewRequestListener) *IPC {
+	i.listener = listener
+	return i
+}
+
+// ServeConn processes the requests sent over conn until the client closes it or ctx is done, and closes it.
+// Access to the socket is expected to be controlled by its file permissions, so requests received over IPC are
+// trusted by AuthMiddleware.
+func (i *IPC) ServeConn(ctx context.Context, conn net.Conn) {
+	peer := &Peer{Transport: ipcTransport}
+	if addr := conn.RemoteAddr(); addr != nil {
+		peer.RemoteAddr = addr.String()
+	}
+	ctx, cancel := context.WithCancel(context.WithValue(ctx, PeerKey{}, peer))
+	defer cancel()
+	// Closing the connection unblocks the read of the next request.
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	defer stop()
+
+	ipcc := &ipcConn{conn: conn, params: i.connParams}
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, bufferSize), int(i.connParams.ReadLimit))
+	for scanner.Scan() {
+		request := bytes.TrimSpace(scanner.Bytes())
+		if len(request) == 0 {
+			continue
+		}
+
+		ipcc.r = bytes.NewReader(request)
+		i.listener.OnNewRequest("any")
+		if err := i.rpc.HandleReadWriter(ctx, ipcc); err != nil {
+			i.log.Warnw("Closing IPC connection", "err", err)
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		i.log.Warnw("Closing IPC connection", "err", err)
+	}
+
+	if err := conn.Close(); err != nil && ctx.Err() == nil {
+		i.log.Debugw("Failed to close IPC connection", "err", err)
+	}
+}
+
+type IPCConnParams struct {
+	// Maximum request size allowed.
+	ReadLimit int64
+	// Maximum time to write a message.
+	WriteDuration time.Duration
+}
+
+func DefaultIPCConnParams() *IPCConnParams {
+	return &IPCConnParams{
+		ReadLimit:     32 * utils.Megabyte,
+		WriteDuration: 5 * time.Second,
+	}
+}
+
+type ipcConn struct {
+	// r holds the request being handled
+	r      io.Reader
+	conn   net.Conn
+	params *IPCConnParams
+
+	// mu serialises responses and subscription notifications
+	mu sync.Mutex
+}
+
+func (c *ipcConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// Write sends p followed by a newline, and returns the number of bytes of p sent.
+func (c *ipcConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.conn.SetWriteDeadline(time.Now().Add(c.params.WriteDuration)); err != nil {
+		return 0, err
+	}
+	n, err := c.conn.Write(append(p[:len(p):len(p)], '\n'))
+	return min(n, len(p)), err
+}
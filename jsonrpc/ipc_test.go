@@ -0,0 +1,119 @@
+package jsonrpc_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/sourcegraph/conc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The caller is responsible for closing the connection.
+func testIPCConnection(t *testing.T, ctx context.Context, server *jsonrpc.Server, listener jsonrpc.NewRequestListener) net.Conn {
+	t.Helper()
+
+	socket, err := net.Listen("unix", filepath.Join(t.TempDir(), "juno.ipc"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, socket.Close())
+	})
+	ipc := jsonrpc.NewIPC(server, utils.NewNopZapLogger()).WithListener(listener)
+
+	wg := conc.NewWaitGroup()
+	t.Cleanup(wg.Wait)
+	wg.Go(func() {
+		serverConn, err := socket.Accept()
+		require.NoError(t, err)
+		ipc.ServeConn(ctx, serverConn)
+	})
+
+	conn, err := net.Dial("unix", socket.Addr().String())
+	require.NoError(t, err)
+	return conn
+}
+
+func TestIPC(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := jsonrpc.NewServer(1, utils.NewNopZapLogger()).WithMiddleware(jsonrpc.AuthMiddleware([]string{"key"}, nil))
+	require.NoError(t, server.RegisterMethods(jsonrpc.Method{
+		Name:   "test_echo",
+		Params: []jsonrpc.Parameter{{Name: "msg"}},
+		Handler: func(ctx context.Context, msg string) (string, *jsonrpc.Error) {
+			peer, ok := jsonrpc.PeerFromContext(ctx)
+			require.True(t, ok)
+			assert.Equal(t, "ipc", peer.Transport)
+			return msg, nil
+		},
+	}))
+	listener := CountingEventListener{}
+	conn := testIPCConnection(t, ctx, server, &listener)
+	reader := bufio.NewReader(conn)
+
+	// Requests are separated by newlines, and don't need credentials.
+	_, err := conn.Write([]byte(`{"jsonrpc" : "2.0", "method" : "test_echo", "params" : [ "abc" ], "id" : 1}` + "\n\n" +
+		`{"jsonrpc" : "2.0", "method" : "test_echo", "params" : [ "def" ], "id" : 2}` + "\n"))
+	require.NoError(t, err)
+
+	for _, want := range []string{`{"jsonrpc":"2.0","result":"abc","id":1}`, `{"jsonrpc":"2.0","result":"def","id":2}`} {
+		got, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, want+"\n", got)
+	}
+	assert.Len(t, listener.OnNewRequestLogs, 2)
+
+	require.NoError(t, conn.Close())
+}
+
+func TestIPCSendFromHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wg := conc.NewWaitGroup()
+	t.Cleanup(wg.Wait)
+	msg := `{"jsonrpc":"2.0","method":"test_notification"}`
+	server := jsonrpc.NewServer(1, utils.NewNopZapLogger())
+	require.NoError(t, server.RegisterMethods(jsonrpc.Method{
+		Name: "test",
+		Handler: func(ctx context.Context) (int, *jsonrpc.Error) {
+			conn, ok := jsonrpc.ConnFromContext(ctx)
+			require.True(t, ok)
+			wg.Go(func() {
+				_, err := conn.Write([]byte(msg))
+				require.NoError(t, err)
+			})
+			return 0, nil
+		},
+	}))
+	conn := testIPCConnection(t, ctx, server, &CountingEventListener{})
+	reader := bufio.NewReader(conn)
+
+	_, err := conn.Write([]byte(`{"jsonrpc" : "2.0", "method" : "test", "params":[], "id" : 1}` + "\n"))
+	require.NoError(t, err)
+
+	got := make([]string, 0, 2)
+	for range 2 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		got = append(got, line)
+	}
+	assert.ElementsMatch(t, []string{`{"jsonrpc":"2.0","result":0,"id":1}` + "\n", msg + "\n"}, got)
+
+	require.NoError(t, conn.Close())
+}
+
+func TestIPCShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	conn := testIPCConnection(t, ctx, jsonrpc.NewServer(1, utils.NewNopZapLogger()), &CountingEventListener{})
+
+	cancel()
+	_, err := conn.Read(make([]byte, 1))
+	require.Error(t, err)
+}
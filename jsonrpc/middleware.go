@@ -0,0 +1,81 @@
+package jsonrpc
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// RequestHandler processes a request and returns its result, or the error to send to the client instead.
+type RequestHandler func(ctx context.Context, req *Request) (any, http.Header, *Error)
+
+// Middleware wraps the handling of every request made to a Server. It can reject a request by returning an error
+// without calling next, rewrite it by passing a modified request to next, or annotate it by passing next a
+// context carrying additional values.
+type Middleware func(next RequestHandler) RequestHandler
+
+// Peer describes the client a request was received from.
+type Peer struct {
+	// Transport is the name of the transport the request was received over, such as "http" or "websocket".
+	Transport  string
+	RemoteAddr string
+	// Header holds the headers of the HTTP request, or of the upgrade request for websockets.
+	Header http.Header
+}
+
+// PeerKey the key used to retrieve the Peer from the context passed to a handler or middleware.
+// Like ConnKey, it is exported to allow transports and tests to set it manually.
+type PeerKey struct{}
+
+// PeerFromContext returns the client the request being handled was received from.
+func PeerFromContext(ctx context.Context) (*Peer, bool) {
+	p, ok := ctx.Value(PeerKey{}).(*Peer)
+	return p, ok
+}
+
+func peerFromHTTPRequest(transport string, req *http.Request) *Peer {
+	return &Peer{
+		Transport:  transport,
+		RemoteAddr: req.RemoteAddr,
+		Header:     req.Header,
+	}
+}
+
+// WithMiddleware wraps the handling of requests with the given middlewares. Middlewares registered first see
+// requests first.
+func (s *Server) WithMiddleware(middlewares ...Middleware) *Server {
+	s.middlewares = append(s.middlewares, middlewares...)
+
+	s.handler = s.dispatch
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		s.handler = s.middlewares[i](s.handler)
+	}
+	return s
+}
+
+// MethodFilterMiddleware rejects requests for methods that are in denied, or that aren't in allowed unless it is
+// empty. A pattern ending with * matches every method starting with what precedes it, for example juno_admin_*.
+// Rejected methods are reported as not found.
+func MethodFilterMiddleware(allowed, denied []string) Middleware {
+	return func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, req *Request) (any, http.Header, *Error) {
+			if matchesAny(denied, req.Method) || (len(allowed) > 0 && !matchesAny(allowed, req.Method)) {
+				return nil, nil, Err(MethodNotFound, nil)
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+func matchesAny(patterns []string, method string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(method, prefix) {
+				return true
+			}
+		} else if pattern == method {
+			return true
+		}
+	}
+	return false
+}
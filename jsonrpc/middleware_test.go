@@ -0,0 +1,112 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newEchoServer returns a server whose methods return the name they were called with.
+func newEchoServer(t *testing.T, middlewares ...jsonrpc.Middleware) *jsonrpc.Server {
+	t.Helper()
+
+	server := jsonrpc.NewServer(1, utils.NewNopZapLogger()).WithMiddleware(middlewares...)
+	for _, name := range []string{"starknet_chainId", "juno_admin_peerScores", "juno_version"} {
+		require.NoError(t, server.RegisterMethods(jsonrpc.Method{
+			Name: name,
+			Handler: func() (string, *jsonrpc.Error) {
+				return name, nil
+			},
+		}))
+	}
+	return server
+}
+
+func call(t *testing.T, ctx context.Context, server *jsonrpc.Server, method string) string {
+	t.Helper()
+
+	res, _, err := server.HandleReader(ctx, strings.NewReader(`{"jsonrpc":"2.0","method":"`+method+`","id":1}`))
+	require.NoError(t, err)
+	return string(res)
+}
+
+func TestMiddleware(t *testing.T) {
+	type key struct{}
+	var seen []string
+	record := func(name string) jsonrpc.Middleware {
+		return func(next jsonrpc.RequestHandler) jsonrpc.RequestHandler {
+			return func(ctx context.Context, req *jsonrpc.Request) (any, http.Header, *jsonrpc.Error) {
+				seen = append(seen, name+":"+req.Method)
+				return next(ctx, req)
+			}
+		}
+	}
+	rewrite := func(next jsonrpc.RequestHandler) jsonrpc.RequestHandler {
+		return func(ctx context.Context, req *jsonrpc.Request) (any, http.Header, *jsonrpc.Error) {
+			if req.Method == "juno_chainId" {
+				req.Method = "starknet_chainId"
+			}
+			return next(context.WithValue(ctx, key{}, "annotated"), req)
+		}
+	}
+	reject := func(next jsonrpc.RequestHandler) jsonrpc.RequestHandler {
+		return func(ctx context.Context, req *jsonrpc.Request) (any, http.Header, *jsonrpc.Error) {
+			if ctx.Value(key{}) != "annotated" {
+				return nil, nil, jsonrpc.Err(jsonrpc.InternalError, "not annotated")
+			}
+			return next(ctx, req)
+		}
+	}
+
+	server := newEchoServer(t, record("first"), rewrite, record("second"), reject)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":"starknet_chainId","id":1}`, call(t, context.Background(), server, "juno_chainId"))
+	assert.Equal(t, []string{"first:juno_chainId", "second:starknet_chainId"}, seen)
+
+	t.Run("rejected requests aren't dispatched", func(t *testing.T) {
+		server := newEchoServer(t, reject)
+		assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32603,"message":"Internal error","data":"not annotated"},"id":1}`,
+			call(t, context.Background(), server, "starknet_chainId"))
+	})
+}
+
+func TestMethodFilterMiddleware(t *testing.T) {
+	const notFound = `{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method Not Found"},"id":1}`
+
+	tests := map[string]struct {
+		allowed, denied []string
+		want            map[string]string
+	}{
+		"denied methods": {
+			denied: []string{"juno_admin_*", "juno_version"},
+			want: map[string]string{
+				"starknet_chainId":      `{"jsonrpc":"2.0","result":"starknet_chainId","id":1}`,
+				"juno_admin_peerScores": notFound,
+				"juno_version":          notFound,
+			},
+		},
+		"allowed methods": {
+			allowed: []string{"starknet_*", "juno_admin_*"},
+			denied:  []string{"juno_admin_peerScores"},
+			want: map[string]string{
+				"starknet_chainId":      `{"jsonrpc":"2.0","result":"starknet_chainId","id":1}`,
+				"juno_admin_peerScores": notFound,
+				"juno_version":          notFound,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := newEchoServer(t, jsonrpc.MethodFilterMiddleware(test.allowed, test.denied))
+			for method, want := range test.want {
+				assert.Equal(t, want, call(t, context.Background(), server, method), method)
+			}
+		})
+	}
+}
@@ -0,0 +1,379 @@
+package jsonrpc
+
+import (
+	"encoding"
+	"encoding/json"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// DiscoverMethod is the name of the method returning the OpenRPC document of a server.
+	DiscoverMethod = "rpc.discover"
+	openRPCVersion = "1.3.2"
+)
+
+var (
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	invalidComponentChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
+// OpenRPCDocument describes the methods of a server as defined by https://spec.open-rpc.org
+type OpenRPCDocument struct {
+	OpenRPC    string            `json:"openrpc"`
+	Info       OpenRPCInfo       `json:"info"`
+	Methods    []OpenRPCMethod   `json:"methods"`
+	Components OpenRPCComponents `json:"components"`
+}
+
+type OpenRPCInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type OpenRPCMethod struct {
+	Name   string              `json:"name"`
+	Params []ContentDescriptor `json:"params"`
+	Result *ContentDescriptor  `json:"result"`
+}
+
+type ContentDescriptor struct {
+	Name     string  `json:"name"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type OpenRPCComponents struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is a JSON schema, restricted to the keywords needed to describe the types of method handlers.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinLength            *uint64            `json:"minLength,omitempty"`
+	MaxLength            *uint64            `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MinItems             *uint64            `json:"minItems,omitempty"`
+	MaxItems             *uint64            `json:"maxItems,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+// RegisterDiscover registers the rpc.discover method, which returns an OpenRPC document describing every other
+// method registered on the server. It is generated the first time it is requested, see OpenRPCDocument.
+func (s *Server) RegisterDiscover(info OpenRPCInfo, schemas map[reflect.Type]*Schema) error {
+	var (
+		once sync.Once
+		doc  *OpenRPCDocument
+	)
+	return s.RegisterMethods(Method{
+		Name: DiscoverMethod,
+		Handler: func() (*OpenRPCDocument, *Error) {
+			once.Do(func() {
+				doc = s.OpenRPCDocument(info, schemas)
+			})
+			return doc, nil
+		},
+	})
+}
+
+// OpenRPCDocument describes the methods registered on the server. The schemas of their params and results are
+// derived from the types the handlers take and return: struct fields are named after their json tag and, like
+// the validator, the documented constraints follow the validate tag. Fields are required unless they are
+// omitted when empty, or if validate has a required rule.
+//
+// Types that implement json.Marshaler or json.Unmarshaler can't be described by reflection, and are allowed any
+// value unless schemas has their schema. Types that implement encoding.TextMarshaler are described as strings.
+// Named structs and types listed in schemas are documented once, in the components of the document.
+func (s *Server) OpenRPCDocument(info OpenRPCInfo, schemas map[reflect.Type]*Schema) *OpenRPCDocument {
+	b := &schemaBuilder{
+		overrides:  schemas,
+		components: make(map[string]*Schema),
+		names:      make(map[reflect.Type]string),
+	}
+
+	names := make([]string, 0, len(s.methods))
+	for name := range s.methods {
+		if name != DiscoverMethod {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	methods := make([]OpenRPCMethod, 0, len(names))
+	for _, name := range names {
+		methods = append(methods, b.method(s.methods[name]))
+	}
+	return &OpenRPCDocument{
+		OpenRPC:    openRPCVersion,
+		Info:       info,
+		Methods:    methods,
+		Components: OpenRPCComponents{Schemas: b.components},
+	}
+}
+
+type schemaBuilder struct {
+	overrides  map[reflect.Type]*Schema
+	components map[string]*Schema
+	// names maps types to the name of their schema in components
+	names map[reflect.Type]string
+}
+
+func (b *schemaBuilder) method(method Method) OpenRPCMethod {
+	handlerT := reflect.TypeOf(method.Handler)
+	offset := 0
+	if method.needsContext {
+		offset = 1
+	}
+
+	params := make([]ContentDescriptor, 0, len(method.Params))
+	for i, param := range method.Params {
+		params = append(params, ContentDescriptor{
+			Name:     param.Name,
+			Required: !param.Optional,
+			Schema:   b.schemaOf(handlerT.In(i + offset)),
+		})
+	}
+	return OpenRPCMethod{
+		Name:   method.Name,
+		Params: params,
+		Result: &ContentDescriptor{
+			Name:   "result",
+			Schema: b.schemaOf(handlerT.Out(0)),
+		},
+	}
+}
+
+func (b *schemaBuilder) schemaOf(t reflect.Type) *Schema { //nolint:gocyclo
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if name, ok := b.names[t]; ok {
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	if override, ok := b.overrides[t]; ok {
+		if t.Name() == "" {
+			return override
+		}
+		return b.component(t, func() *Schema { return override })
+	}
+
+	switch {
+	case implements(t, jsonMarshalerType):
+		return &Schema{}
+	case implements(t, textMarshalerType):
+		return &Schema{Type: "string"}
+	case implements(t, jsonUnmarshalerType):
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			// encoding/json marshals byte slices as base64 strings
+			return &Schema{Type: "string"}
+		}
+		return &Schema{Type: "array", Items: b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		return b.component(t, func() *Schema { return b.structSchema(t) })
+	default:
+		return &Schema{}
+	}
+}
+
+// component adds the schema of t to the components, and returns a reference to it. The name of t is reserved
+// before the schema is built, so that recursive types reference themselves.
+func (b *schemaBuilder) component(t reflect.Type, build func() *Schema) *Schema {
+	name := invalidComponentChars.ReplaceAllString(t.Name(), "_")
+	if _, taken := b.components[name]; taken {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+	b.names[t] = name
+	b.components[name] = &Schema{}
+	b.components[name] = build()
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	b.addFields(schema, t)
+	return schema
+}
+
+// addFields adds the fields of t to the properties of schema. Like encoding/json, it promotes the fields of
+// embedded structs, unless a less nested field has the same name.
+func (b *schemaBuilder) addFields(schema *Schema, t reflect.Type) {
+	var embedded []reflect.Type
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			fieldT := field.Type
+			if fieldT.Kind() == reflect.Pointer {
+				fieldT = fieldT.Elem()
+			}
+			if fieldT.Kind() == reflect.Struct && !implements(fieldT, jsonMarshalerType) {
+				embedded = append(embedded, fieldT)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema, required := withValidation(b.schemaOf(field.Type), field.Tag.Get("validate"))
+		if required || !slices.Contains(strings.Split(opts, ","), "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = fieldSchema
+	}
+
+	for _, embeddedT := range embedded {
+		promoted := &Schema{Properties: make(map[string]*Schema)}
+		b.addFields(promoted, embeddedT)
+		for name, fieldSchema := range promoted.Properties {
+			if _, ok := schema.Properties[name]; !ok {
+				schema.Properties[name] = fieldSchema
+				if slices.Contains(promoted.Required, name) {
+					schema.Required = append(schema.Required, name)
+				}
+			}
+		}
+	}
+	slices.Sort(schema.Required)
+}
+
+// withValidation returns a copy of schema constrained by the rules of a validate tag, and whether the tag requires
+// the value. Only the rules that have a JSON schema equivalent are applied, and only to inline schemas since
+// keywords next to a $ref are ignored. Conditional rules such as required_if aren't applied either.
+func withValidation(schema *Schema, tag string) (*Schema, bool) {
+	if tag == "" {
+		return schema, false
+	}
+
+	constrained := *schema
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		key, param, _ := strings.Cut(rule, "=")
+		switch key {
+		case "dive":
+			// the remaining rules apply to the elements
+			return &constrained, required
+		case "required":
+			required = true
+		case "oneof":
+			if constrained.Ref == "" {
+				constrained.Enum = enumValues(constrained.Type, strings.Fields(param))
+			}
+		case "len", "min", "max", "gte", "lte", "gt", "lt":
+			if constrained.Ref == "" {
+				applyBound(&constrained, key, param)
+			}
+		}
+	}
+	return &constrained, required
+}
+
+// applyBound sets the bound that a min, max, len, gt, gte, lt or lte rule puts on the length of strings and
+// arrays, or on the value of numbers.
+func applyBound(schema *Schema, key, param string) {
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch schema.Type {
+	case "integer", "number":
+		switch key {
+		case "len":
+			schema.Minimum, schema.Maximum = &bound, &bound
+		case "min", "gte":
+			schema.Minimum = &bound
+		case "max", "lte":
+			schema.Maximum = &bound
+		case "gt":
+			schema.ExclusiveMinimum = &bound
+		case "lt":
+			schema.ExclusiveMaximum = &bound
+		}
+	case "string", "array":
+		minLength, maxLength := &schema.MinLength, &schema.MaxLength
+		if schema.Type == "array" {
+			minLength, maxLength = &schema.MinItems, &schema.MaxItems
+		}
+		length := uint64(bound)
+		switch key {
+		case "len":
+			*minLength, *maxLength = &length, &length
+		case "min", "gte":
+			*minLength = &length
+		case "max", "lte":
+			*maxLength = &length
+		case "gt":
+			length++
+			*minLength = &length
+		case "lt":
+			length--
+			*maxLength = &length
+		}
+	}
+}
+
+func enumValues(schemaType string, values []string) []any {
+	enum := make([]any, 0, len(values))
+	for _, value := range values {
+		if schemaType == "integer" || schemaType == "number" {
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				enum = append(enum, number)
+			}
+			continue
+		}
+		enum = append(enum, value)
+	}
+	return enum
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
@@ -0,0 +1,130 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type page struct {
+	Token string `json:"token,omitempty"`
+	Size  uint64 `json:"size" validate:"min=1,max=1024"`
+}
+
+type status uint8
+
+func (s status) MarshalText() ([]byte, error) {
+	return []byte("OK"), nil
+}
+
+type node struct {
+	Value    string  `json:"value" validate:"oneof=a b"`
+	Children []*node `json:"children,omitempty" validate:"max=2"`
+}
+
+type filter struct {
+	page
+	Keys   []string `json:"keys,omitempty" validate:"required,min=1,dive,len=3"`
+	Status status   `json:"status"`
+	Tree   *node    `json:"tree,omitempty"`
+	Token  int      `json:"token"`
+}
+
+func TestOpenRPCDocument(t *testing.T) {
+	server := jsonrpc.NewServer(1, utils.NewNopZapLogger())
+	require.NoError(t, server.RegisterMethods(jsonrpc.Method{
+		Name:   "test_filter",
+		Params: []jsonrpc.Parameter{{Name: "filter"}, {Name: "limit", Optional: true}},
+		Handler: func(_ context.Context, f filter, limit *int) ([]string, *jsonrpc.Error) {
+			return nil, nil
+		},
+	}, jsonrpc.Method{
+		Name: "test_status",
+		Handler: func() (*status, *jsonrpc.Error) {
+			return nil, nil
+		},
+	}))
+
+	info := jsonrpc.OpenRPCInfo{Title: "Test API", Version: "1.0.0"}
+	statusSchema := &jsonrpc.Schema{Type: "string", Enum: []any{"OK"}}
+	doc := server.OpenRPCDocument(info, map[reflect.Type]*jsonrpc.Schema{reflect.TypeFor[status](): statusSchema})
+
+	assert.Equal(t, info, doc.Info)
+	require.Len(t, doc.Methods, 2)
+
+	filterMethod := doc.Methods[0]
+	assert.Equal(t, "test_filter", filterMethod.Name)
+	assert.Equal(t, []jsonrpc.ContentDescriptor{
+		{Name: "filter", Required: true, Schema: &jsonrpc.Schema{Ref: "#/components/schemas/filter"}},
+		{Name: "limit", Schema: &jsonrpc.Schema{Type: "integer"}},
+	}, filterMethod.Params)
+	assert.Equal(t, &jsonrpc.ContentDescriptor{
+		Name:   "result",
+		Schema: &jsonrpc.Schema{Type: "array", Items: &jsonrpc.Schema{Type: "string"}},
+	}, filterMethod.Result)
+
+	statusMethod := doc.Methods[1]
+	assert.Equal(t, "test_status", statusMethod.Name)
+	assert.Empty(t, statusMethod.Params)
+	assert.Equal(t, &jsonrpc.Schema{Ref: "#/components/schemas/status"}, statusMethod.Result.Schema)
+
+	minSize, maxSize := float64(1), float64(1024)
+	minKeys, maxChildren := uint64(1), uint64(2)
+	assert.Equal(t, map[string]*jsonrpc.Schema{
+		"filter": {
+			Type: "object",
+			Properties: map[string]*jsonrpc.Schema{
+				"size":   {Type: "integer", Minimum: &minSize, Maximum: &maxSize},
+				"keys":   {Type: "array", Items: &jsonrpc.Schema{Type: "string"}, MinItems: &minKeys},
+				"status": {Ref: "#/components/schemas/status"},
+				"tree":   {Ref: "#/components/schemas/node"},
+				"token":  {Type: "integer"},
+			},
+			Required: []string{"keys", "size", "status", "token"},
+		},
+		"node": {
+			Type: "object",
+			Properties: map[string]*jsonrpc.Schema{
+				"value": {Type: "string", Enum: []any{"a", "b"}},
+				"children": {
+					Type:     "array",
+					Items:    &jsonrpc.Schema{Ref: "#/components/schemas/node"},
+					MaxItems: &maxChildren,
+				},
+			},
+			Required: []string{"value"},
+		},
+		"status": statusSchema,
+	}, doc.Components.Schemas)
+}
+
+func TestDiscover(t *testing.T) {
+	server := newEchoServer(t)
+	info := jsonrpc.OpenRPCInfo{Title: "Test API", Version: "1.0.0"}
+	require.NoError(t, server.RegisterDiscover(info, nil))
+
+	res, _, err := server.HandleReader(context.Background(),
+		strings.NewReader(`{"jsonrpc":"2.0","method":"rpc.discover","id":1}`))
+	require.NoError(t, err)
+
+	var response struct {
+		Result jsonrpc.OpenRPCDocument `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(res, &response))
+	assert.Equal(t, "1.3.2", response.Result.OpenRPC)
+	assert.Equal(t, info, response.Result.Info)
+
+	names := make([]string, 0, len(response.Result.Methods))
+	for _, method := range response.Result.Methods {
+		names = append(names, method.Name)
+		assert.Equal(t, "string", method.Result.Schema.Type)
+	}
+	assert.Equal(t, []string{"juno_admin_peerScores", "juno_version", "starknet_chainId"}, names)
+}
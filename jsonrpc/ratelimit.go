@@ -0,0 +1,108 @@
+package jsonrpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimit allows Rate requests per second on average, and bursts of up to Burst requests.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitByClient limits the requests of each client authenticated by AuthMiddleware, which must see requests
+// first. Requests of unauthenticated clients aren't limited.
+func RateLimitByClient(limit RateLimit) Middleware {
+	return newRateLimiter(limit, time.Now).middleware(ClientIDFromContext)
+}
+
+// RateLimitByIP limits the requests received from each IP address. Requests that weren't received over a transport,
+// or that were received over IPC, aren't limited.
+func RateLimitByIP(limit RateLimit) Middleware {
+	return newRateLimiter(limit, time.Now).middleware(func(ctx context.Context) (string, bool) {
+		peer, ok := PeerFromContext(ctx)
+		if !ok || peer.Transport == ipcTransport {
+			return "", false
+		}
+		host, _, err := net.SplitHostPort(peer.RemoteAddr)
+		if err != nil {
+			return peer.RemoteAddr, true
+		}
+		return host, true
+	})
+}
+
+// rateLimiter holds a token bucket per client. Buckets that are full are dropped periodically, since they
+// behave the same as new ones.
+type rateLimiter struct {
+	limit RateLimit
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+const bucketSweepInterval = time.Minute
+
+func newRateLimiter(limit RateLimit, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		limit:     limit,
+		now:       now,
+		buckets:   make(map[string]*tokenBucket),
+		lastSweep: now(),
+	}
+}
+
+func (l *rateLimiter) middleware(clientKey func(ctx context.Context) (string, bool)) Middleware {
+	return func(next RequestHandler) RequestHandler {
+		return func(ctx context.Context, req *Request) (any, http.Header, *Error) {
+			if key, ok := clientKey(ctx); ok && !l.allow(key) {
+				return nil, nil, Err(LimitExceeded, "rate limit exceeded")
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// allow takes a token from the bucket of key, and reports whether there was one.
+func (l *rateLimiter) allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= bucketSweepInterval {
+		for k, bucket := range l.buckets {
+			if l.refill(bucket, now) >= float64(l.limit.Burst) {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	bucket, found := l.buckets[key]
+	if !found {
+		bucket = &tokenBucket{tokens: float64(l.limit.Burst), last: now}
+		l.buckets[key] = bucket
+	}
+	if l.refill(bucket, now) < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+func (l *rateLimiter) refill(bucket *tokenBucket, now time.Time) float64 {
+	bucket.tokens = min(float64(l.limit.Burst), bucket.tokens+now.Sub(bucket.last).Seconds()*l.limit.Rate)
+	bucket.last = now
+	return bucket.tokens
+}
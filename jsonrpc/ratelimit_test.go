@@ -0,0 +1,46 @@
+package jsonrpc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitByIP(t *testing.T) {
+	const (
+		ok      = `{"jsonrpc":"2.0","result":"juno_version","id":1}`
+		limited = `{"jsonrpc":"2.0","error":{"code":-32005,"message":"Limit Exceeded","data":"rate limit exceeded"},"id":1}`
+	)
+
+	server := newEchoServer(t, jsonrpc.RateLimitByIP(jsonrpc.RateLimit{Rate: 0, Burst: 2}))
+	peerCtx := func(addr string) context.Context {
+		return context.WithValue(context.Background(), jsonrpc.PeerKey{}, &jsonrpc.Peer{Transport: "http", RemoteAddr: addr})
+	}
+
+	first, second := peerCtx("10.0.0.1:1234"), peerCtx("10.0.0.2:1234")
+	assert.Equal(t, ok, call(t, first, server, "juno_version"))
+	assert.Equal(t, ok, call(t, peerCtx("10.0.0.1:4321"), server, "juno_version"))
+	assert.Equal(t, limited, call(t, first, server, "juno_version"))
+	// every address has its own bucket
+	assert.Equal(t, ok, call(t, second, server, "juno_version"))
+	// requests that weren't received over a transport aren't limited
+	assert.Equal(t, ok, call(t, context.Background(), server, "juno_version"))
+}
+
+func TestRateLimitByClient(t *testing.T) {
+	server := newEchoServer(t,
+		jsonrpc.AuthMiddleware([]string{"key1", "key2"}, nil),
+		jsonrpc.RateLimitByClient(jsonrpc.RateLimit{Rate: 0, Burst: 1}),
+	)
+	withKey := func(key string) context.Context {
+		return context.WithValue(context.Background(), jsonrpc.PeerKey{}, &jsonrpc.Peer{
+			Transport: "http", RemoteAddr: "10.0.0.1:1234", Header: map[string][]string{"X-Api-Key": {key}},
+		})
+	}
+
+	assert.Contains(t, call(t, withKey("key1"), server, "juno_version"), "result")
+	assert.Contains(t, call(t, withKey("key1"), server, "juno_version"), "Limit Exceeded")
+	assert.Contains(t, call(t, withKey("key2"), server, "juno_version"), "result")
+}
@@ -25,6 +25,9 @@ const (
 	MethodNotFound = -32601 // The method does not exist / is not available.
 	InvalidParams  = -32602 // Invalid method parameter(s).
 	InternalError  = -32603 // Internal JSON-RPC error.
+	Unauthorized   = -32001 // The request lacks valid credentials.
+	LimitExceeded  = -32005 // The client sent more requests than it is allowed to.
+	Timeout        = -32002 // The request took longer than the method allows.
 )
 
 var (
@@ -71,6 +74,12 @@ func Err(code int, data any) *Error {
 		return &Error{Code: MethodNotFound, Message: "Method Not Found", Data: data}
 	case InvalidParams:
 		return &Error{Code: InvalidParams, Message: "Invalid Params", Data: data}
+	case Unauthorized:
+		return &Error{Code: Unauthorized, Message: "Unauthorized", Data: data}
+	case LimitExceeded:
+		return &Error{Code: LimitExceeded, Message: "Limit Exceeded", Data: data}
+	case Timeout:
+		return &Error{Code: Timeout, Message: "Request Timeout", Data: data}
 	default:
 		return &Error{Code: InternalError, Message: "Internal error", Data: data}
 	}
@@ -111,6 +120,10 @@ type Method struct {
 	Name    string
 	Params  []Parameter
 	Handler any
+	// Timeout, if set, is how long the handler may run before its context is cancelled and the client gets a
+	// Timeout error. The context is also cancelled once the handler returns, so methods starting work that
+	// outlives the request, such as subscriptions, must not set it.
+	Timeout time.Duration
 
 	// The method takes a context as its first parameter.
 	// Set upon successful registration.
@@ -123,6 +136,10 @@ type Server struct {
 	pool      *pool.Pool
 	log       utils.SimpleLogger
 	listener  EventListener
+
+	middlewares []Middleware
+	// handler is dispatch wrapped by the middlewares
+	handler RequestHandler
 }
 
 type Validator interface {
@@ -137,6 +154,7 @@ func NewServer(poolMaxGoroutines int, log utils.SimpleLogger) *Server {
 		pool:     pool.New().WithMaxGoroutines(poolMaxGoroutines),
 		listener: &SelectiveListener{},
 	}
+	s.handler = s.dispatch
 
 	return s
 }
@@ -449,31 +467,60 @@ func (s *Server) handleRequest(ctx context.Context, req *Request) (*response, ht
 		ID:      req.ID,
 	}
 
+	result, header, rpcErr := s.handler(ctx, req)
+	if header == nil {
+		header = http.Header{}
+	}
+	if rpcErr != nil {
+		res.Error = rpcErr
+		return res, header, nil
+	}
+	if res.ID == nil { // notification
+		return nil, header, nil
+	}
+	res.Result = result
+
+	return res, header, nil
+}
+
+// dispatch calls the handler of the requested method.
+func (s *Server) dispatch(ctx context.Context, req *Request) (any, http.Header, *Error) {
+	header := http.Header{}
 	calledMethod, found := s.methods[req.Method]
 	if !found {
-		res.Error = Err(MethodNotFound, nil)
 		s.log.Tracew("Method not found in request", "method", req.Method)
-		return res, header, nil
+		return nil, header, Err(MethodNotFound, nil)
+	}
+
+	if calledMethod.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, calledMethod.Timeout)
+		defer cancel()
 	}
 
 	handlerTimer := time.Now()
 	s.listener.OnNewRequest(req.Method)
 	args, err := s.buildArguments(ctx, req.Params, calledMethod)
 	if err != nil {
-		res.Error = Err(InvalidParams, err.Error())
 		s.log.Tracew("Error building arguments for RPC call", "err", err)
-		return res, header, nil
+		return nil, header, Err(InvalidParams, err.Error())
 	}
 	defer func() {
 		s.listener.OnRequestHandled(req.Method, time.Since(handlerTimer))
 	}()
 
 	tuple := reflect.ValueOf(calledMethod.Handler).Call(args)
-	if res.ID == nil { // notification
+	if req.ID == nil { // notification
 		s.log.Tracew("Notification received, no response expected")
 		return nil, header, nil
 	}
 
+	if calledMethod.Timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		rpcErr := Err(Timeout, fmt.Sprintf("%s did not complete within %s", req.Method, calledMethod.Timeout))
+		s.listener.OnRequestFailed(req.Method, rpcErr)
+		return nil, header, rpcErr
+	}
+
 	errorIndex := 1
 	if len(tuple) == 3 {
 		errorIndex = 2
@@ -481,18 +528,17 @@ func (s *Server) handleRequest(ctx context.Context, req *Request) (*response, ht
 	}
 
 	if errAny := tuple[errorIndex].Interface(); !utils.IsNil(errAny) {
-		res.Error = errAny.(*Error)
-		if res.Error.Code == InternalError {
-			s.listener.OnRequestFailed(req.Method, res.Error)
+		rpcErr := errAny.(*Error)
+		if rpcErr.Code == InternalError {
+			s.listener.OnRequestFailed(req.Method, rpcErr)
 			reqJSON, _ := json.Marshal(req)
-			errJSON, _ := json.Marshal(res.Error)
+			errJSON, _ := json.Marshal(rpcErr)
 			s.log.Debugw("Failed handing RPC request", "req", string(reqJSON), "res", string(errJSON))
 		}
-		return res, header, nil
+		return nil, header, rpcErr
 	}
-	res.Result = tuple[0].Interface()
 
-	return res, header, nil
+	return tuple[0].Interface(), header, nil
 }
 
 //nolint:gocyclo
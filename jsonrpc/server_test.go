@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/NethermindEth/juno/jsonrpc"
 	"github.com/NethermindEth/juno/utils"
@@ -665,3 +666,28 @@ func write(t *testing.T, c io.Writer, data string) {
 	_, err := c.Write([]byte(data))
 	require.NoError(t, err)
 }
+
+func TestMethodTimeout(t *testing.T) {
+	server := jsonrpc.NewServer(1, utils.NewNopZapLogger())
+	require.NoError(t, server.RegisterMethods(jsonrpc.Method{
+		Name:    "wait",
+		Params:  []jsonrpc.Parameter{{Name: "done"}},
+		Timeout: 10 * time.Millisecond,
+		Handler: func(ctx context.Context, done bool) (bool, *jsonrpc.Error) {
+			if done {
+				return true, nil
+			}
+			<-ctx.Done()
+			return false, jsonrpc.Err(jsonrpc.InternalError, ctx.Err().Error())
+		},
+	}))
+
+	res, _, err := server.HandleReader(context.Background(), strings.NewReader(`{"jsonrpc":"2.0","method":"wait","params":[true],"id":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","result":true,"id":1}`, string(res))
+
+	res, _, err = server.HandleReader(context.Background(), strings.NewReader(`{"jsonrpc":"2.0","method":"wait","params":[false],"id":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, `{"jsonrpc":"2.0","error":{"code":-32002,"message":"Request Timeout","data":"wait did not complete within 10ms"},"id":1}`,
+		string(res))
+}
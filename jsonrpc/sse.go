@@ -0,0 +1,254 @@
+package jsonrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/utils"
+)
+
+const sseKeepAliveInterval = 15 * time.Second
+
+var errSSEStreamClosed = errors.New("SSE stream closed")
+
+// SSESubscriptions describes the subscriptions that can be opened over server-sent events.
+type SSESubscriptions interface {
+	// Subscribes reports whether method opens a subscription.
+	Subscribes(method string) bool
+	// EventID returns the ID to send a notification of a subscription opened with method with. Clients that
+	// reconnect send the ID of the last event they received, so notifications without one can't be resumed from.
+	EventID(method string, notification []byte) (string, bool)
+	// Resume rewrites the params of a subscription request so that it resumes after the event with lastEventID.
+	Resume(req *Request, lastEventID string) error
+}
+
+// SSE opens a subscription for each HTTP GET request, and streams its notifications as server-sent events until
+// the client disconnects. The method and the JSON-encoded params of the subscription are given by the query.
+// The first event is the response to the subscription request, and the stream ends after it if it is an error.
+type SSE struct {
+	rpc           *Server
+	log           utils.SimpleLogger
+	subscriptions SSESubscriptions
+	listener      NewRequestListener
+	connParams    *SSEConnParams
+
+	shutdown <-chan struct{}
+}
+
+func NewSSE(rpc *Server, subscriptions SSESubscriptions, shutdown <-chan struct{}, log utils.SimpleLogger) *SSE {
+	return &SSE{
+		rpc:           rpc,
+		log:           log,
+		subscriptions: subscriptions,
+		listener:      &SelectiveListener{},
+		connParams:    DefaultSSEConnParams(),
+		shutdown:      shutdown,
+	}
+}
+
+// WithConnParams applies the provided params.
+func (s *SSE) WithConnParams(p *SSEConnParams) *SSE {
+	s.connParams = p
+	return s
+}
+
+// WithListener registers a NewRequestListener
+func (s *SSE) WithListener(listener NewRequestListener) *SSE {
+	s.listener = listener
+	return s
+}
+
+// ServeHTTP processes a subscription request and streams the notifications of the subscription.
+// The connection's entire "lifetime" is spent in this function.
+func (s *SSE) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.WithValue(r.Context(), PeerKey{}, peerFromHTTPRequest("sse", r)))
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	conn := &sseConn{
+		w:             w,
+		flusher:       flusher,
+		controller:    http.NewResponseController(w),
+		params:        s.connParams,
+		subscriptions: s.subscriptions,
+	}
+	defer conn.close()
+
+	s.listener.OnNewRequest("any")
+	req, rpcErr := s.subscriptionRequest(r)
+	if rpcErr != nil {
+		s.writeError(conn, rpcErr)
+		return
+	}
+	encodedReq, err := json.Marshal(req)
+	if err != nil {
+		s.writeError(conn, Err(InternalError, err.Error()))
+		return
+	}
+	conn.r, conn.method = bytes.NewReader(encodedReq), req.Method
+	if err = s.rpc.HandleReadWriter(ctx, conn); err != nil || conn.failed {
+		return
+	}
+
+	keepAlive := time.NewTicker(sseKeepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.shutdown:
+			return
+		case <-keepAlive.C:
+			if err = conn.keepAlive(); err != nil {
+				s.log.Debugw("Closing SSE stream", "err", err)
+				return
+			}
+		}
+	}
+}
+
+// subscriptionRequest builds the request for the subscription described by the query of r. If the client is
+// reconnecting, the subscription resumes after the last event it received.
+func (s *SSE) subscriptionRequest(r *http.Request) (*Request, *Error) {
+	query := r.URL.Query()
+	req := &Request{
+		Version: "2.0",
+		Method:  query.Get("method"),
+		ID:      1,
+	}
+	if !s.subscriptions.Subscribes(req.Method) {
+		return nil, Err(MethodNotFound, nil)
+	}
+
+	if params := query.Get("params"); params != "" {
+		if err := json.Unmarshal([]byte(params), &req.Params); err != nil {
+			return nil, Err(InvalidParams, err.Error())
+		}
+	}
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		if err := s.subscriptions.Resume(req, lastEventID); err != nil {
+			return nil, Err(InvalidParams, err.Error())
+		}
+	}
+	return req, nil
+}
+
+func (s *SSE) writeError(conn *sseConn, rpcErr *Error) {
+	resp, err := json.Marshal(&response{Version: "2.0", Error: rpcErr, ID: 1})
+	if err != nil {
+		s.log.Errorw("Failed to encode SSE error", "err", err)
+		return
+	}
+	if _, err = conn.Write(resp); err != nil {
+		s.log.Debugw("Failed to write SSE error", "err", err)
+	}
+}
+
+type SSEConnParams struct {
+	// Maximum time to write an event.
+	WriteDuration time.Duration
+}
+
+func DefaultSSEConnParams() *SSEConnParams {
+	return &SSEConnParams{
+		WriteDuration: 5 * time.Second,
+	}
+}
+
+type sseConn struct {
+	// r holds the subscription request
+	r             *bytes.Reader
+	w             http.ResponseWriter
+	controller    *http.ResponseController
+	params        *SSEConnParams
+	flusher       http.Flusher
+	subscriptions SSESubscriptions
+	// method is the method the subscription was opened with
+	method string
+
+	// mu serialises the response, the notifications and the keep-alive comments
+	mu sync.Mutex
+	// responded is set once the response to the subscription request was written, and failed if it is an error
+	responded bool
+	failed    bool
+	// closed is set once the stream ended, since w can't be used afterwards
+	closed bool
+}
+
+func (c *sseConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+// Write sends p as the data of an event, and returns the number of bytes of p sent.
+func (c *sseConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return 0, errSSEStreamClosed
+	}
+	if !c.responded {
+		c.responded = true
+		var resp struct {
+			Error json.RawMessage `json:"error"`
+		}
+		c.failed = json.Unmarshal(p, &resp) != nil || len(resp.Error) > 0
+	}
+
+	var event bytes.Buffer
+	if id, ok := c.subscriptions.EventID(c.method, p); ok {
+		event.WriteString("id: " + id + "\n")
+	}
+	event.WriteString("data: ")
+	event.Write(p)
+	event.WriteString("\n\n")
+	if err := c.write(event.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// keepAlive sends a comment, which clients ignore, so that proxies don't close idle streams.
+func (c *sseConn) keepAlive() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.write([]byte(": keep-alive\n\n"))
+}
+
+// write sends data to the client, failing if the client doesn't read it within the write duration.
+func (c *sseConn) write(data []byte) error {
+	if err := c.controller.SetWriteDeadline(time.Now().Add(c.params.WriteDuration)); err != nil {
+		return err
+	}
+	if _, err := c.w.Write(data); err != nil {
+		return err
+	}
+	c.flusher.Flush()
+	return nil
+}
+
+func (c *sseConn) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	// the connection may serve further requests, which mustn't inherit the deadline of the stream
+	_ = c.controller.SetWriteDeadline(time.Time{})
+}
@@ -0,0 +1,154 @@
+package jsonrpc_test
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/sourcegraph/conc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSubscriptions resumes test_subscribe from the number following the last event ID.
+type testSubscriptions struct{}
+
+func (testSubscriptions) Subscribes(method string) bool {
+	return method == "test_subscribe"
+}
+
+func (testSubscriptions) EventID(_ string, notification []byte) (string, bool) {
+	id, found := strings.CutPrefix(string(notification), "notification ")
+	return id, found
+}
+
+func (testSubscriptions) Resume(req *jsonrpc.Request, lastEventID string) error {
+	req.Params = []any{lastEventID + "+1"}
+	return nil
+}
+
+func readEvent(t *testing.T, reader *bufio.Reader) string {
+	t.Helper()
+
+	var event strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		if line == "\n" {
+			return event.String()
+		}
+		event.WriteString(line)
+	}
+}
+
+func TestSSE(t *testing.T) {
+	wg := conc.NewWaitGroup()
+	t.Cleanup(wg.Wait)
+
+	server := jsonrpc.NewServer(1, utils.NewNopZapLogger())
+	require.NoError(t, server.RegisterMethods(jsonrpc.Method{
+		Name:   "test_subscribe",
+		Params: []jsonrpc.Parameter{{Name: "from", Optional: true}},
+		Handler: func(ctx context.Context, from string) (int, *jsonrpc.Error) {
+			if from == "invalid" {
+				return 0, jsonrpc.Err(jsonrpc.InvalidParams, "invalid from")
+			}
+			conn, ok := jsonrpc.ConnFromContext(ctx)
+			require.True(t, ok)
+			wg.Go(func() {
+				_, err := conn.Write([]byte("notification " + from))
+				require.NoError(t, err)
+			})
+			return 1, nil
+		},
+	}, jsonrpc.Method{
+		Name: "test_call",
+		Handler: func() (int, *jsonrpc.Error) {
+			return 0, nil
+		},
+	}))
+	listener := CountingEventListener{}
+	sse := jsonrpc.NewSSE(server, testSubscriptions{}, nil, utils.NewNopZapLogger()).WithListener(&listener)
+	srv := httptest.NewServer(sse)
+	t.Cleanup(srv.Close)
+
+	subscribe := func(t *testing.T, ctx context.Context, query url.Values, lastEventID string) (*http.Response, *bufio.Reader) {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"?"+query.Encode(), http.NoBody)
+		require.NoError(t, err)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, resp.Body.Close())
+		})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		return resp, bufio.NewReader(resp.Body)
+	}
+
+	t.Run("notifications are streamed after the response", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		_, reader := subscribe(t, ctx, url.Values{"method": {"test_subscribe"}, "params": {`["a"]`}}, "")
+		assert.Equal(t, `data: {"jsonrpc":"2.0","result":1,"id":1}`+"\n", readEvent(t, reader))
+		assert.Equal(t, "id: a\ndata: notification a\n", readEvent(t, reader))
+	})
+
+	t.Run("subscription is resumed after the last event", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		_, reader := subscribe(t, ctx, url.Values{"method": {"test_subscribe"}, "params": {`["a"]`}}, "b")
+		assert.Equal(t, `data: {"jsonrpc":"2.0","result":1,"id":1}`+"\n", readEvent(t, reader))
+		assert.Equal(t, "id: b+1\ndata: notification b+1\n", readEvent(t, reader))
+	})
+
+	for name, test := range map[string]struct {
+		query url.Values
+		want  string
+	}{
+		"method is not a subscription": {
+			query: url.Values{"method": {"test_call"}},
+			want:  `data: {"jsonrpc":"2.0","error":{"code":-32601,"message":"Method Not Found"},"id":1}`,
+		},
+		"params are not json": {
+			query: url.Values{"method": {"test_subscribe"}, "params": {"a"}},
+			want: `data: {"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid Params",` +
+				`"data":"invalid character 'a' looking for beginning of value"},"id":1}`,
+		},
+		"subscription fails": {
+			query: url.Values{"method": {"test_subscribe"}, "params": {`["invalid"]`}},
+			want:  `data: {"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid Params","data":"invalid from"},"id":1}`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp, reader := subscribe(t, context.Background(), test.query, "")
+			assert.Equal(t, test.want+"\n", readEvent(t, reader))
+
+			// the stream ends after the error
+			_, err := reader.ReadByte()
+			require.Error(t, err)
+			require.NoError(t, resp.Body.Close())
+		})
+	}
+
+	assert.Len(t, listener.OnNewRequestLogs, 5)
+}
+
+func TestSSEMethodNotAllowed(t *testing.T) {
+	sse := jsonrpc.NewSSE(jsonrpc.NewServer(1, utils.NewNopZapLogger()), testSubscriptions{}, nil, utils.NewNopZapLogger())
+	rr := httptest.NewRecorder()
+	sse.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", http.NoBody))
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
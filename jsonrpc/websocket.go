@@ -57,7 +57,7 @@ func (ws *Websocket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// TODO include connection information, such as the remote address, in the logs.
 
-	ctx, cancel := context.WithCancel(r.Context())
+	ctx, cancel := context.WithCancel(context.WithValue(r.Context(), PeerKey{}, peerFromHTTPRequest("websocket", r)))
 	defer cancel()
 	go func() {
 		select {
@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/NethermindEth/juno/l1/contract"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -17,10 +19,12 @@ import (
 )
 
 type EthSubscriber struct {
-	ethClient *ethclient.Client
-	client    *rpc.Client
-	filterer  *contract.StarknetFilterer
-	listener  EventListener
+	ethClient           *ethclient.Client
+	client              *rpc.Client
+	filterer            *contract.StarknetFilterer
+	coreContractAddress common.Address
+	contractABI         *abi.ABI
+	listener            EventListener
 }
 
 var _ Subscriber = (*EthSubscriber)(nil)
@@ -38,11 +42,17 @@ func NewEthSubscriber(ethClientAddress string, coreContractAddress common.Addres
 	if err != nil {
 		return nil, err
 	}
+	contractABI, err := contract.StarknetMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
 	return &EthSubscriber{
-		ethClient: ethClient,
-		client:    client,
-		filterer:  filterer,
-		listener:  SelectiveListener{},
+		ethClient:           ethClient,
+		client:              client,
+		filterer:            filterer,
+		coreContractAddress: coreContractAddress,
+		contractABI:         contractABI,
+		listener:            SelectiveListener{},
 	}, nil
 }
 
@@ -97,3 +107,25 @@ func (s *EthSubscriber) TransactionReceipt(ctx context.Context, txHash common.Ha
 
 	return receipt, nil
 }
+
+// FilterMessageLogs returns the messaging events emitted by the core contract in the given range of
+// blocks, in the order they were emitted.
+func (s *EthSubscriber) FilterMessageLogs(ctx context.Context, from, to uint64) ([]types.Log, error) {
+	topics := make([]common.Hash, len(messageEvents))
+	for i, name := range messageEvents {
+		topics[i] = s.contractABI.Events[name].ID
+	}
+
+	reqTimer := time.Now()
+	logs, err := s.ethClient.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{s.coreContractAddress},
+		Topics:    [][]common.Hash{topics},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get messaging logs of Ethereum blocks %d to %d: %w", from, to, err)
+	}
+	s.listener.OnL1Call("eth_getLogs", time.Since(reqTimer))
+	return logs, nil
+}
@@ -9,11 +9,20 @@ import (
 type EventListener interface {
 	OnNewL1Head(head *core.L1Head)
 	OnL1Call(method string, took time.Duration)
+	// OnL1Disagreement is called when the Ethereum endpoint with the given index disagrees with the
+	// quorum of endpoints, kind is one of the Disagreement* constants.
+	OnL1Disagreement(endpoint int, kind string)
+	OnL1HeadVerified(head *core.L1Head)
+	// OnL1HeadMismatch is called when the local header at the height of the L1 head doesn't match it.
+	OnL1HeadMismatch(head *core.L1Head, local *core.Header)
 }
 
 type SelectiveListener struct {
-	OnNewL1HeadCb func(head *core.L1Head)
-	OnL1CallCb    func(method string, took time.Duration)
+	OnNewL1HeadCb      func(head *core.L1Head)
+	OnL1CallCb         func(method string, took time.Duration)
+	OnL1DisagreementCb func(endpoint int, kind string)
+	OnL1HeadVerifiedCb func(head *core.L1Head)
+	OnL1HeadMismatchCb func(head *core.L1Head, local *core.Header)
 }
 
 func (l SelectiveListener) OnNewL1Head(head *core.L1Head) {
@@ -27,3 +36,21 @@ func (l SelectiveListener) OnL1Call(method string, took time.Duration) {
 		l.OnL1CallCb(method, took)
 	}
 }
+
+func (l SelectiveListener) OnL1Disagreement(endpoint int, kind string) {
+	if l.OnL1DisagreementCb != nil {
+		l.OnL1DisagreementCb(endpoint, kind)
+	}
+}
+
+func (l SelectiveListener) OnL1HeadVerified(head *core.L1Head) {
+	if l.OnL1HeadVerifiedCb != nil {
+		l.OnL1HeadVerifiedCb(head)
+	}
+}
+
+func (l SelectiveListener) OnL1HeadMismatch(head *core.L1Head, local *core.Header) {
+	if l.OnL1HeadMismatchCb != nil {
+		l.OnL1HeadMismatchCb(head, local)
+	}
+}
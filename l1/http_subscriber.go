@@ -0,0 +1,267 @@
+package l1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/juno/l1/contract"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+const (
+	logStateUpdateEvent = "LogStateUpdate"
+	// maxCheckpoints bounds how far back a reorg is detected. Ethereum blocks are final long before
+	// that many polls have been made.
+	maxCheckpoints = 128
+)
+
+// HTTPSubscriber is a Subscriber for Ethereum nodes that are only reachable over HTTP. Instead of
+// subscribing to LogStateUpdate events, it polls eth_getLogs for the blocks added since the last poll.
+type HTTPSubscriber struct {
+	*EthSubscriber
+	pollInterval  time.Duration
+	maxBlockRange uint64
+}
+
+var _ Subscriber = (*HTTPSubscriber)(nil)
+
+func NewHTTPSubscriber(ethClientAddress string, coreContractAddress common.Address) (*HTTPSubscriber, error) {
+	ethSubscriber, err := NewEthSubscriber(ethClientAddress, coreContractAddress)
+	if err != nil {
+		return nil, err
+	}
+	return &HTTPSubscriber{
+		EthSubscriber: ethSubscriber,
+		pollInterval:  12 * time.Second,
+		maxBlockRange: 2000,
+	}, nil
+}
+
+// WithPollInterval sets the time to wait between two eth_getLogs polls.
+func (s *HTTPSubscriber) WithPollInterval(interval time.Duration) *HTTPSubscriber {
+	s.pollInterval = interval
+	return s
+}
+
+// WithMaxBlockRange sets the maximum number of blocks requested by a single eth_getLogs call.
+// Most providers reject larger ranges.
+func (s *HTTPSubscriber) WithMaxBlockRange(blocks uint64) *HTTPSubscriber {
+	s.maxBlockRange = blocks
+	return s
+}
+
+// WatchLogStateUpdate polls for LogStateUpdate events starting from the finalised block, so that the
+// updates that are not final yet when the subscription starts are not missed. Updates that were sent
+// from blocks that are later reorged out are sent again with Raw.Removed set, like a log subscription
+// would do.
+func (s *HTTPSubscriber) WatchLogStateUpdate(ctx context.Context, sink chan<- *contract.StarknetLogStateUpdate) (event.Subscription, error) {
+	finalisedHeight, err := s.FinalisedHeight(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	poller := &logPoller{
+		subscriber: s,
+		start:      finalisedHeight,
+		next:       finalisedHeight,
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		ticker := time.NewTicker(s.pollInterval)
+		defer ticker.Stop()
+		for {
+			if err := poller.poll(ctx, quit, sink); err != nil {
+				return err
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return nil
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// checkpoint is the hash of the last block of a range that has been polled.
+type checkpoint struct {
+	number uint64
+	hash   common.Hash
+}
+
+type logPoller struct {
+	subscriber *HTTPSubscriber
+	// start is the first block polled
+	start uint64
+	// next is the first block that has not been polled yet
+	next        uint64
+	checkpoints []checkpoint
+	// sent holds the updates that have been sent after the oldest checkpoint
+	sent []*contract.StarknetLogStateUpdate
+}
+
+var errPollerQuit = errors.New("poller quit")
+
+func (p *logPoller) poll(ctx context.Context, quit <-chan struct{}, sink chan<- *contract.StarknetLogStateUpdate) error {
+	err := p.handleReorg(ctx, quit, sink)
+	if err == nil {
+		err = p.pollNewBlocks(ctx, quit, sink)
+	}
+	if errors.Is(err, errPollerQuit) {
+		return nil
+	}
+	return err
+}
+
+// handleReorg checks that the polled blocks are still canonical. If they are not, the updates sent
+// from the reorged blocks are sent again as removed and polling resumes after the common ancestor.
+func (p *logPoller) handleReorg(ctx context.Context, quit <-chan struct{}, sink chan<- *contract.StarknetLogStateUpdate) error {
+	reorged := false
+	for len(p.checkpoints) > 0 {
+		last := p.checkpoints[len(p.checkpoints)-1]
+		header, err := p.header(ctx, new(big.Int).SetUint64(last.number))
+		if err != nil && !errors.Is(err, ethereum.NotFound) {
+			return err
+		}
+		if header != nil && header.Hash() == last.hash {
+			break
+		}
+		p.checkpoints = p.checkpoints[:len(p.checkpoints)-1]
+		reorged = true
+	}
+	if !reorged {
+		return nil
+	}
+
+	p.next = p.start
+	if len(p.checkpoints) > 0 {
+		p.next = p.checkpoints[len(p.checkpoints)-1].number + 1
+	}
+
+	for len(p.sent) > 0 {
+		update := p.sent[len(p.sent)-1]
+		if update.Raw.BlockNumber < p.next {
+			break
+		}
+		p.sent = p.sent[:len(p.sent)-1]
+
+		removed := *update
+		removed.Raw.Removed = true
+		if err := p.send(ctx, quit, sink, &removed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *logPoller) pollNewBlocks(ctx context.Context, quit <-chan struct{}, sink chan<- *contract.StarknetLogStateUpdate) error {
+	head, err := p.header(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	headNumber := head.Number.Uint64()
+	for p.next <= headNumber {
+		to := min(p.next+p.subscriber.maxBlockRange-1, headNumber)
+		toHeader := head
+		if to != headNumber {
+			if toHeader, err = p.header(ctx, new(big.Int).SetUint64(to)); err != nil {
+				return err
+			}
+		}
+
+		updates, err := p.logStateUpdates(ctx, p.next, to)
+		if err != nil {
+			return err
+		}
+		// a reorg between reading the header and the logs would make the checkpoint refer to another chain
+		// than the logs, so the range is polled again unless the header is unchanged
+		toHeaderAfter, err := p.header(ctx, new(big.Int).SetUint64(to))
+		if err != nil {
+			return err
+		}
+		if toHeaderAfter.Hash() != toHeader.Hash() {
+			return nil
+		}
+
+		for _, update := range updates {
+			if err := p.send(ctx, quit, sink, update); err != nil {
+				return err
+			}
+			p.sent = append(p.sent, update)
+		}
+
+		p.addCheckpoint(checkpoint{number: to, hash: toHeader.Hash()})
+		p.next = to + 1
+	}
+	return nil
+}
+
+func (p *logPoller) addCheckpoint(cp checkpoint) {
+	p.checkpoints = append(p.checkpoints, cp)
+	if len(p.checkpoints) <= maxCheckpoints {
+		return
+	}
+
+	p.checkpoints = p.checkpoints[1:]
+	// updates from before the oldest checkpoint can't be detected as reorged anymore
+	oldest := p.checkpoints[0].number
+	for len(p.sent) > 0 && p.sent[0].Raw.BlockNumber <= oldest {
+		p.sent = p.sent[1:]
+	}
+}
+
+func (p *logPoller) send(ctx context.Context, quit <-chan struct{}, sink chan<- *contract.StarknetLogStateUpdate,
+	update *contract.StarknetLogStateUpdate,
+) error {
+	select {
+	case sink <- update:
+		return nil
+	case <-ctx.Done():
+		return errPollerQuit
+	case <-quit:
+		return errPollerQuit
+	}
+}
+
+func (p *logPoller) header(ctx context.Context, number *big.Int) (*types.Header, error) {
+	reqTimer := time.Now()
+	header, err := p.subscriber.ethClient.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("get Ethereum block header: %w", err)
+	}
+	p.subscriber.listener.OnL1Call("eth_getBlockByNumber", time.Since(reqTimer))
+	return header, nil
+}
+
+func (p *logPoller) logStateUpdates(ctx context.Context, from, to uint64) ([]*contract.StarknetLogStateUpdate, error) {
+	reqTimer := time.Now()
+	logs, err := p.subscriber.ethClient.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{p.subscriber.coreContractAddress},
+		Topics:    [][]common.Hash{{p.subscriber.contractABI.Events[logStateUpdateEvent].ID}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get LogStateUpdate logs of Ethereum blocks %d to %d: %w", from, to, err)
+	}
+	p.subscriber.listener.OnL1Call("eth_getLogs", time.Since(reqTimer))
+
+	updates := make([]*contract.StarknetLogStateUpdate, 0, len(logs))
+	for _, log := range logs {
+		update := new(contract.StarknetLogStateUpdate)
+		if err := p.subscriber.contractABI.UnpackIntoInterface(update, logStateUpdateEvent, log.Data); err != nil {
+			return nil, fmt.Errorf("unpack LogStateUpdate: %w", err)
+		}
+		update.Raw = log
+		updates = append(updates, update)
+	}
+	return updates, nil
+}
@@ -0,0 +1,215 @@
+package l1_test
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/l1"
+	"github.com/NethermindEth/juno/l1/contract"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEthChain serves the blocks and LogStateUpdate logs of a chain that can be reorged.
+type fakeEthChain struct {
+	mu        sync.Mutex
+	t         *testing.T
+	fork      byte
+	headers   []*types.Header
+	logs      map[uint64]*types.Log
+	finalised uint64
+	// beforeGetLogs is called once, by the next eth_getLogs call
+	beforeGetLogs func()
+}
+
+type filterQuery struct {
+	FromBlock hexutil.Uint64 `json:"fromBlock"`
+	ToBlock   hexutil.Uint64 `json:"toBlock"`
+}
+
+func newFakeEthChain(t *testing.T, length int, finalised uint64) *fakeEthChain {
+	c := &fakeEthChain{t: t, logs: make(map[uint64]*types.Log), finalised: finalised}
+	c.extend(length)
+	return c
+}
+
+func (c *fakeEthChain) extend(length int) {
+	for len(c.headers) < length {
+		c.headers = append(c.headers, &types.Header{
+			Number:     big.NewInt(int64(len(c.headers))),
+			Difficulty: big.NewInt(0),
+			Extra:      []byte{c.fork},
+		})
+	}
+}
+
+// reorg replaces the blocks from the given height on with the blocks of a new fork.
+func (c *fakeEthChain) reorg(from uint64, length int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.fork++
+	c.headers = c.headers[:from]
+	for number := range c.logs {
+		if number >= from {
+			delete(c.logs, number)
+		}
+	}
+	c.extend(length)
+}
+
+func (c *fakeEthChain) addStateUpdate(l1Block, l2Block uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	contractABI, err := contract.StarknetMetaData.GetAbi()
+	require.NoError(c.t, err)
+	event := contractABI.Events["LogStateUpdate"]
+	data, err := event.Inputs.Pack(big.NewInt(1), new(big.Int).SetUint64(l2Block), big.NewInt(2))
+	require.NoError(c.t, err)
+
+	c.logs[l1Block] = &types.Log{
+		Topics:      []common.Hash{event.ID},
+		Data:        data,
+		BlockNumber: l1Block,
+		BlockHash:   c.headers[l1Block].Hash(),
+	}
+}
+
+func (c *fakeEthChain) GetBlockByNumber(ctx context.Context, number string, fullTx bool) (*types.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch number {
+	case "latest":
+		return c.headers[len(c.headers)-1], nil
+	case "finalized": //nolint:misspell
+		return c.headers[c.finalised], nil
+	}
+	n, err := hexutil.DecodeUint64(number)
+	if err != nil {
+		return nil, err
+	}
+	if n >= uint64(len(c.headers)) {
+		return nil, nil
+	}
+	return c.headers[n], nil
+}
+
+func (c *fakeEthChain) GetLogs(ctx context.Context, query filterQuery) ([]*types.Log, error) {
+	c.mu.Lock()
+	beforeGetLogs := c.beforeGetLogs
+	c.beforeGetLogs = nil
+	c.mu.Unlock()
+	if beforeGetLogs != nil {
+		beforeGetLogs()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	logs := []*types.Log{}
+	for number := uint64(query.FromBlock); number <= uint64(query.ToBlock); number++ {
+		if log, ok := c.logs[number]; ok {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func watchHTTPSubscriber(t *testing.T, chain *fakeEthChain, maxBlockRange uint64) func() *contract.StarknetLogStateUpdate {
+	t.Helper()
+
+	srv := rpc.NewServer()
+	require.NoError(t, srv.RegisterName("eth", chain))
+	httpServer := httptest.NewServer(srv)
+	t.Cleanup(httpServer.Close)
+
+	subscriber, err := l1.NewHTTPSubscriber(httpServer.URL, common.Address{})
+	require.NoError(t, err)
+	subscriber.WithPollInterval(10 * time.Millisecond).WithMaxBlockRange(maxBlockRange)
+	t.Cleanup(subscriber.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+
+	sink := make(chan *contract.StarknetLogStateUpdate)
+	sub, err := subscriber.WatchLogStateUpdate(ctx, sink)
+	require.NoError(t, err)
+	t.Cleanup(sub.Unsubscribe)
+
+	return func() *contract.StarknetLogStateUpdate {
+		select {
+		case update := <-sink:
+			return update
+		case err := <-sub.Err():
+			require.FailNow(t, "subscription failed", err)
+		case <-ctx.Done():
+			require.FailNow(t, "timed out waiting for update")
+		}
+		return nil
+	}
+}
+
+func TestHTTPSubscriber(t *testing.T) {
+	chain := newFakeEthChain(t, 10, 2)
+	chain.addStateUpdate(1, 100)
+	chain.addStateUpdate(3, 101)
+	chain.addStateUpdate(5, 102)
+	receive := watchHTTPSubscriber(t, chain, 3)
+
+	// updates before the finalised block are skipped
+	update := receive()
+	require.Equal(t, uint64(3), update.Raw.BlockNumber)
+	require.Equal(t, uint64(101), update.BlockNumber.Uint64())
+	require.False(t, update.Raw.Removed)
+
+	update = receive()
+	require.Equal(t, uint64(5), update.Raw.BlockNumber)
+	require.False(t, update.Raw.Removed)
+
+	chain.reorg(5, 12)
+	chain.addStateUpdate(11, 103)
+
+	update = receive()
+	require.Equal(t, uint64(5), update.Raw.BlockNumber)
+	require.True(t, update.Raw.Removed)
+
+	update = receive()
+	require.Equal(t, uint64(11), update.Raw.BlockNumber)
+	require.Equal(t, uint64(103), update.BlockNumber.Uint64())
+	require.False(t, update.Raw.Removed)
+}
+
+func TestHTTPSubscriberReorgWhilePolling(t *testing.T) {
+	chain := newFakeEthChain(t, 10, 2)
+	chain.addStateUpdate(3, 100)
+	// the logs are read from a fork of the chain whose header was read
+	chain.beforeGetLogs = func() {
+		chain.reorg(3, 10)
+		chain.addStateUpdate(3, 200)
+	}
+	receive := watchHTTPSubscriber(t, chain, 100)
+
+	update := receive()
+	require.Equal(t, uint64(200), update.BlockNumber.Uint64())
+	require.Equal(t, chain.headers[3].Hash(), update.Raw.BlockHash)
+	require.False(t, update.Raw.Removed)
+
+	// the update is not sent again as removed, since the checkpoint is on the same fork
+	chain.mu.Lock()
+	chain.extend(12)
+	chain.mu.Unlock()
+	chain.addStateUpdate(11, 201)
+
+	update = receive()
+	require.Equal(t, uint64(201), update.BlockNumber.Uint64())
+	require.False(t, update.Raw.Removed)
+}
@@ -1,14 +1,18 @@
 package l1
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"slices"
 	"time"
 
 	"github.com/NethermindEth/juno/blockchain"
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
 	"github.com/NethermindEth/juno/l1/contract"
 	"github.com/NethermindEth/juno/service"
 	"github.com/NethermindEth/juno/utils"
@@ -23,6 +27,7 @@ type Subscriber interface {
 	WatchLogStateUpdate(ctx context.Context, sink chan<- *contract.StarknetLogStateUpdate) (event.Subscription, error)
 	ChainID(ctx context.Context) (*big.Int, error)
 	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	FilterMessageLogs(ctx context.Context, from, to uint64) ([]types.Log, error)
 
 	Close()
 }
@@ -34,12 +39,31 @@ type Client struct {
 	network               *utils.Network
 	resubscribeDelay      time.Duration
 	pollFinalisedInterval time.Duration
-	nonFinalisedLogs      map[uint64]*contract.StarknetLogStateUpdate
+	nonFinalisedLogs      map[uint64]*receivedUpdate
 	listener              EventListener
+	haltSync              func()
+	// messagesStartHeight is the L1 block the message index starts from on a fresh database
+	messagesStartHeight uint64
+	// subscriptions counts the subscriptions to state updates, to tell the updates of each apart
+	subscriptions uint64
+	// lastStored is the last state update whose L1 transaction was stored
+	lastStored *receivedUpdate
+	// checkedL1Head is the number of the latest L1 head that was found to match the local chain
+	checkedL1Head *uint64
+	// mismatchedL1Head is the number of the L1 head that doesn't match the local chain, if any. The L1
+	// head isn't advanced any more once it is set.
+	mismatchedL1Head *uint64
 }
 
 var _ service.Service = (*Client)(nil)
 
+// receivedUpdate is a state update along with the subscription it was received over. The updates received
+// over the same subscription are consecutive, while updates may be missed between subscriptions.
+type receivedUpdate struct {
+	*contract.StarknetLogStateUpdate
+	subscription uint64
+}
+
 func NewClient(l1 Subscriber, chain *blockchain.Blockchain, log utils.SimpleLogger) *Client {
 	return &Client{
 		l1:                    l1,
@@ -48,7 +72,7 @@ func NewClient(l1 Subscriber, chain *blockchain.Blockchain, log utils.SimpleLogg
 		network:               chain.Network(),
 		resubscribeDelay:      10 * time.Second,
 		pollFinalisedInterval: time.Minute,
-		nonFinalisedLogs:      make(map[uint64]*contract.StarknetLogStateUpdate, 0),
+		nonFinalisedLogs:      make(map[uint64]*receivedUpdate, 0),
 		listener:              SelectiveListener{},
 	}
 }
@@ -63,6 +87,19 @@ func (c *Client) WithResubscribeDelay(delay time.Duration) *Client {
 	return c
 }
 
+// WithHaltSync sets a function that stops syncing the L2 chain, which is called when the local chain
+// doesn't match the state finalised on L1.
+func (c *Client) WithHaltSync(haltSync func()) *Client {
+	c.haltSync = haltSync
+	return c
+}
+
+// WithMessagesStartHeight sets the L1 block from which messages are indexed when none were indexed yet.
+func (c *Client) WithMessagesStartHeight(height uint64) *Client {
+	c.messagesStartHeight = height
+	return c
+}
+
 // WithPollFinalisedInterval sets the time to wait before checking for an update to the finalised L1 block.
 func (c *Client) WithPollFinalisedInterval(delay time.Duration) *Client {
 	c.pollFinalisedInterval = delay
@@ -77,6 +114,7 @@ func (c *Client) subscribeToUpdates(ctx context.Context, updateChan chan *contra
 		default:
 			updateSub, err := c.l1.WatchLogStateUpdate(ctx, updateChan)
 			if err == nil {
+				c.subscriptions++
 				return updateSub, nil
 			}
 			c.log.Debugw("Failed to subscribe to L1 state updates", "tryAgainIn", c.resubscribeDelay, "err", err)
@@ -136,6 +174,8 @@ func (c *Client) Run(ctx context.Context) error {
 					// in debug logs and panics (to avoid leaking the API key).
 					c.log.Debugw("L1 update subscription failed, resubscribing", "error", err)
 					updateSub.Unsubscribe()
+					// the updates of the failed subscription must be told apart from the ones of the next
+					c.drainUpdates(updateChan)
 
 					updateSub, err = c.subscribeToUpdates(ctx, updateChan)
 					if err != nil {
@@ -143,21 +183,7 @@ func (c *Client) Run(ctx context.Context) error {
 					}
 					defer updateSub.Unsubscribe() //nolint:gocritic
 				case logStateUpdate := <-updateChan:
-					c.log.Debugw("Received L1 LogStateUpdate",
-						"number", logStateUpdate.BlockNumber,
-						"stateRoot", logStateUpdate.GlobalRoot.Text(felt.Base16),
-						"blockHash", logStateUpdate.BlockHash.Text(felt.Base16))
-
-					if logStateUpdate.Raw.Removed {
-						for l1BlockNumber := range c.nonFinalisedLogs {
-							if l1BlockNumber >= logStateUpdate.Raw.BlockNumber {
-								delete(c.nonFinalisedLogs, l1BlockNumber)
-							}
-						}
-						// TODO What if the finalised block is also reorged?
-					} else {
-						c.nonFinalisedLogs[logStateUpdate.Raw.BlockNumber] = logStateUpdate
-					}
+					c.receiveUpdate(logStateUpdate)
 				default:
 					break Outer
 				}
@@ -166,6 +192,45 @@ func (c *Client) Run(ctx context.Context) error {
 			if err := c.setL1Head(ctx); err != nil {
 				return err
 			}
+			if err := c.verifyL1Head(); err != nil {
+				return err
+			}
+			if err := c.indexMessages(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (c *Client) receiveUpdate(logStateUpdate *contract.StarknetLogStateUpdate) {
+	c.log.Debugw("Received L1 LogStateUpdate",
+		"number", logStateUpdate.BlockNumber,
+		"stateRoot", logStateUpdate.GlobalRoot.Text(felt.Base16),
+		"blockHash", logStateUpdate.BlockHash.Text(felt.Base16))
+
+	if logStateUpdate.Raw.Removed {
+		for l1BlockNumber := range c.nonFinalisedLogs {
+			if l1BlockNumber >= logStateUpdate.Raw.BlockNumber {
+				delete(c.nonFinalisedLogs, l1BlockNumber)
+			}
+		}
+		// TODO What if the finalised block is also reorged?
+	} else {
+		c.nonFinalisedLogs[logStateUpdate.Raw.BlockNumber] = &receivedUpdate{
+			StarknetLogStateUpdate: logStateUpdate,
+			subscription:           c.subscriptions,
+		}
+	}
+}
+
+// drainUpdates receives the updates left in the channel by a subscription that was unsubscribed.
+func (c *Client) drainUpdates(updateChan <-chan *contract.StarknetLogStateUpdate) {
+	for {
+		select {
+		case logStateUpdate := <-updateChan:
+			c.receiveUpdate(logStateUpdate)
+		default:
+			return
 		}
 	}
 }
@@ -187,31 +252,46 @@ func (c *Client) finalisedHeight(ctx context.Context) uint64 {
 }
 
 func (c *Client) setL1Head(ctx context.Context) error {
+	// no more blocks are marked as accepted on L1 once the local chain is known not to match it
+	if c.mismatchedL1Head != nil {
+		return nil
+	}
 	finalisedHeight := c.finalisedHeight(ctx)
 
-	// Get max finalised Starknet head.
-	var maxFinalisedNumber uint64
-	var maxFinalisedHead *contract.StarknetLogStateUpdate
-	for l1BlockNumber := range c.nonFinalisedLogs {
+	var finalised []*receivedUpdate
+	for l1BlockNumber, update := range c.nonFinalisedLogs {
 		if l1BlockNumber <= finalisedHeight {
-			if l1BlockNumber >= maxFinalisedNumber {
-				maxFinalisedNumber = l1BlockNumber
-				maxFinalisedHead = c.nonFinalisedLogs[maxFinalisedNumber]
-			}
+			finalised = append(finalised, update)
 			delete(c.nonFinalisedLogs, l1BlockNumber)
 		}
 	}
 
 	// No finalised logs.
-	if maxFinalisedHead == nil {
+	if len(finalised) == 0 {
 		return nil
 	}
 
+	slices.SortFunc(finalised, func(a, b *receivedUpdate) int {
+		return cmp.Compare(a.Raw.BlockNumber, b.Raw.BlockNumber)
+	})
+	if err := c.storeStateUpdates(finalised); err != nil {
+		return err
+	}
+
+	// Get max finalised Starknet head.
+	maxFinalisedHead := finalised[len(finalised)-1]
 	head := &core.L1Head{
 		BlockNumber: maxFinalisedHead.BlockNumber.Uint64(),
 		BlockHash:   new(felt.Felt).SetBigInt(maxFinalisedHead.BlockHash),
 		StateRoot:   new(felt.Felt).SetBigInt(maxFinalisedHead.GlobalRoot),
 	}
+	header, err := c.localHeader(head.BlockNumber)
+	if err != nil {
+		return err
+	}
+	if header != nil && c.mismatched(head, header) {
+		return nil
+	}
 	if err := c.l2Chain.SetL1Head(head); err != nil {
 		return fmt.Errorf("l1 head for block %d and state root %s: %w", head.BlockNumber, head.StateRoot.String(), err)
 	}
@@ -224,6 +304,98 @@ func (c *Client) setL1Head(ctx context.Context) error {
 	return nil
 }
 
+// storeStateUpdates records the L1 transaction of each finalised state update, in L1 order. An update
+// settles the blocks after the ones settled by the previous update, which is only known to be the last
+// stored one if both were received over the same subscription, as updates may be missed in between.
+func (c *Client) storeStateUpdates(updates []*receivedUpdate) error {
+	for _, update := range updates {
+		blockNumber := update.BlockNumber.Uint64()
+		stateUpdate := &core.L1StateUpdate{
+			L1BlockNumber:    update.Raw.BlockNumber,
+			L1BlockHash:      update.Raw.BlockHash,
+			L1TxnHash:        update.Raw.TxHash,
+			FirstBlockNumber: blockNumber,
+			BlockNumber:      blockNumber,
+			BlockHash:        new(felt.Felt).SetBigInt(update.BlockHash),
+			StateRoot:        new(felt.Felt).SetBigInt(update.GlobalRoot),
+		}
+		if previous := c.lastStored; previous != nil && previous.subscription == update.subscription &&
+			previous.BlockNumber.Uint64() < blockNumber {
+			stateUpdate.FirstBlockNumber = previous.BlockNumber.Uint64() + 1
+		}
+		if err := c.l2Chain.StoreL1StateUpdate(stateUpdate); err != nil {
+			return fmt.Errorf("store l1 state update of block %d: %w", blockNumber, err)
+		}
+		c.lastStored = update
+	}
+	return nil
+}
+
+// verifyL1Head compares the hash and state root of the L1 head with the local block at the same height.
+// Heads that the local chain hasn't reached yet are compared once it does.
+func (c *Client) verifyL1Head() error {
+	head, err := c.l2Chain.L1Head()
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil
+		}
+		return fmt.Errorf("get l1 head: %w", err)
+	}
+	if c.checkedL1Head != nil && *c.checkedL1Head == head.BlockNumber {
+		return nil
+	}
+
+	header, err := c.localHeader(head.BlockNumber)
+	if err != nil || header == nil || c.mismatched(head, header) {
+		return err
+	}
+	c.checkedL1Head = &head.BlockNumber
+
+	if err := c.l2Chain.SetL1VerifiedHeight(head.BlockNumber); err != nil {
+		return fmt.Errorf("set l1 verified height to %d: %w", head.BlockNumber, err)
+	}
+	c.listener.OnL1HeadVerified(head)
+	return nil
+}
+
+// localHeader returns the header of the local block at the given height, or nil if the local chain
+// hasn't reached it yet.
+func (c *Client) localHeader(blockNumber uint64) (*core.Header, error) {
+	header, err := c.l2Chain.BlockHeaderByNumber(blockNumber)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get header of block %d: %w", blockNumber, err)
+	}
+	return header, nil
+}
+
+// mismatched reports whether the local block doesn't match the L1 head. A mismatch halts the sync and is
+// only reported once per head.
+func (c *Client) mismatched(head *core.L1Head, header *core.Header) bool {
+	if header.Hash.Equal(head.BlockHash) && header.GlobalStateRoot.Equal(head.StateRoot) {
+		return false
+	}
+	if c.mismatchedL1Head != nil && *c.mismatchedL1Head == head.BlockNumber {
+		return true
+	}
+	c.mismatchedL1Head = &head.BlockNumber
+
+	c.listener.OnL1HeadMismatch(head, header)
+	c.log.Errorw("Local chain does not match the state finalised on L1, the synced data can't be trusted",
+		"blockNumber", head.BlockNumber,
+		"l1BlockHash", head.BlockHash.String(),
+		"l1StateRoot", head.StateRoot.String(),
+		"localBlockHash", header.Hash.String(),
+		"localStateRoot", header.GlobalStateRoot.String())
+	if c.haltSync != nil {
+		c.log.Errorw("Halting sync because of the L1 mismatch")
+		c.haltSync()
+	}
+	return true
+}
+
 func (c *Client) L1() Subscriber {
 	return c.l1
 }
@@ -360,6 +360,12 @@ func TestClient(t *testing.T) {
 					Return(block.finalisedHeight, nil).
 					AnyTimes()
 
+				subscriber.
+					EXPECT().
+					FilterMessageLogs(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, nil).
+					AnyTimes()
+
 				subscriber.
 					EXPECT().
 					ChainID(gomock.Any()).
@@ -439,6 +445,12 @@ func TestUnreliableSubscription(t *testing.T) {
 			Return(block.finalisedHeight, nil).
 			AnyTimes()
 
+		subscriber.
+			EXPECT().
+			FilterMessageLogs(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, nil).
+			AnyTimes()
+
 		subscriber.EXPECT().Close().Times(1)
 
 		// Replace the subscriber.
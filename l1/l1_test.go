@@ -6,20 +6,25 @@ import (
 	"math/big"
 	"net"
 	"net/http"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/clients/feeder"
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
 	"github.com/NethermindEth/juno/db/pebble"
 	"github.com/NethermindEth/juno/l1"
 	"github.com/NethermindEth/juno/l1/contract"
 	"github.com/NethermindEth/juno/mocks"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
 	"github.com/NethermindEth/juno/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -132,6 +137,12 @@ func TestEventListener(t *testing.T) {
 		Return(uint64(0), nil).
 		AnyTimes()
 
+	subscriber.
+		EXPECT().
+		FilterMessageLogs(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, nil).
+		AnyTimes()
+
 	subscriber.
 		EXPECT().
 		ChainID(gomock.Any()).
@@ -257,3 +268,298 @@ func TestEthSubscriber_FinalisedHeight(t *testing.T) {
 		})
 	}
 }
+
+func TestL1HeadVerification(t *testing.T) {
+	t.Parallel()
+
+	network := utils.Sepolia
+	gw := adaptfeeder.New(feeder.NewTestClient(t, &network))
+	block0, err := gw.BlockByNumber(context.Background(), 0)
+	require.NoError(t, err)
+	stateUpdate0, err := gw.StateUpdate(context.Background(), 0)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		stateRoot *felt.Felt
+		verified  bool
+	}{
+		"matching state root": {
+			stateRoot: block0.GlobalStateRoot,
+			verified:  true,
+		},
+		"mismatched state root": {
+			stateRoot: new(felt.Felt).SetUint64(1),
+			verified:  false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			chain := blockchain.New(pebble.NewMemTest(t), &network, nil)
+			require.NoError(t, chain.Store(block0, &core.BlockCommitments{}, stateUpdate0, nil))
+
+			ctrl := gomock.NewController(t)
+			subscriber := mocks.NewMockSubscriber(ctrl)
+			subscriber.
+				EXPECT().
+				WatchLogStateUpdate(gomock.Any(), gomock.Any()).
+				Do(func(_ context.Context, sink chan<- *contract.StarknetLogStateUpdate) {
+					sink <- &contract.StarknetLogStateUpdate{
+						GlobalRoot:  test.stateRoot.BigInt(new(big.Int)),
+						BlockNumber: new(big.Int),
+						BlockHash:   block0.Hash.BigInt(new(big.Int)),
+						Raw:         types.Log{BlockNumber: 1},
+					}
+				}).
+				Return(newFakeSubscription(), nil)
+			subscriber.EXPECT().FinalisedHeight(gomock.Any()).Return(uint64(1), nil).AnyTimes()
+			subscriber.EXPECT().FilterMessageLogs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			subscriber.EXPECT().ChainID(gomock.Any()).Return(network.L1ChainID, nil)
+			subscriber.EXPECT().Close()
+
+			var verified, mismatched, halted bool
+			client := l1.NewClient(subscriber, chain, utils.NewNopZapLogger()).
+				WithResubscribeDelay(0).
+				WithPollFinalisedInterval(time.Nanosecond).
+				WithHaltSync(func() {
+					halted = true
+				}).
+				WithEventListener(l1.SelectiveListener{
+					OnL1HeadVerifiedCb: func(head *core.L1Head) {
+						verified = true
+					},
+					OnL1HeadMismatchCb: func(head *core.L1Head, local *core.Header) {
+						mismatched = true
+						require.Equal(t, block0.Header, local)
+					},
+				})
+
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			require.NoError(t, client.Run(ctx))
+			cancel()
+
+			assert.Equal(t, test.verified, verified)
+			assert.Equal(t, !test.verified, mismatched)
+			assert.Equal(t, !test.verified, halted)
+
+			height, err := chain.L1VerifiedHeight()
+			if test.verified {
+				require.NoError(t, err)
+				assert.Equal(t, uint64(0), height)
+			} else {
+				require.ErrorIs(t, err, db.ErrKeyNotFound)
+			}
+
+			// the L1 head isn't advanced to a block that doesn't match the local chain
+			head, err := chain.L1Head()
+			if test.verified {
+				require.NoError(t, err)
+				assert.Equal(t, block0.Hash, head.BlockHash)
+			} else {
+				require.ErrorIs(t, err, db.ErrKeyNotFound)
+			}
+		})
+	}
+}
+
+func TestIndexMessages(t *testing.T) {
+	network := utils.Mainnet
+	chain := blockchain.New(pebble.NewMemTest(t), &network, nil)
+
+	contractABI, err := contract.StarknetMetaData.GetAbi()
+	require.NoError(t, err)
+
+	// LogMessageToL2 of L1 transaction 0x5780c6fe46f958a7ebf9308e6db16d819ff9e06b1e88f9e718c50cde10898f38
+	var sentLog types.Log
+	require.NoError(t, sentLog.UnmarshalJSON([]byte(`{"address":"0xc662c410c0ecf747543f5ba90660f6abebd9c8c4","blockHash":"0x42b045a05a24a1585aa3f2102e238e782e4ec3220a25358c74a29fe5f5a52f47","blockNumber":"0x13e6075","data":"0x00000000000000000000000000000000000000000000000000000000000000600000000000000000000000000000000000000000000000000000000000195c3c0000000000000000000000000000000000000000000000000000048c273950000000000000000000000000000000000000000000000000000000000000000003000000000000000000000000c3b49b03a6d9d71f8d3fa6582437374e650f3c4603a1bf949fa7424b4bd48661a62ded82bc6f6e3c5f5c6d5904c07e6143187d1b0000000000000000000000000000000000000000000000000000000000000061","logIndex":"0x11e","removed":false,"topics":["0xdb80dd488acf86d17c747445b0eabb5d57c541d3bd7b6b87af987858e5066b2b","0x0000000000000000000000007ad94e71308bb65c6bc9df35cc69cc9f953d69e5","0x038862e1b15526eda31ed6fd26805c40748458db8e420cb3be3bc65c332c023b","0x03593216f3a8b22f4cf375e5486e3d13bfde9d0f26976d20ac6f653c73f7e507"],"transactionHash":"0x5780c6fe46f958a7ebf9308e6db16d819ff9e06b1e88f9e718c50cde10898f38","transactionIndex":"0x42"}`))) //nolint:lll
+	sentMsgHash := common.HexToHash("0xd8824a75a588f0726d7d83b3e9560810c763043e979fdb77b11c1a51a991235d")
+
+	sent, err := contractABI.Unpack("LogMessageToL2", sentLog.Data)
+	require.NoError(t, err)
+	consumedData, err := contractABI.Events["ConsumedMessageToL2"].Inputs.NonIndexed().Pack(sent[0], sent[1])
+	require.NoError(t, err)
+	consumedLog := types.Log{
+		Topics: append([]common.Hash{contractABI.Events["ConsumedMessageToL2"].ID}, sentLog.Topics[1:]...),
+		Data:   consumedData,
+		TxHash: common.HexToHash("0xc0"),
+	}
+
+	l2Sender, l1Recipient := common.HexToHash("0x1"), common.HexToHash("0x2")
+	toL1Data, err := contractABI.Events["LogMessageToL1"].Inputs.NonIndexed().Pack([]*big.Int{big.NewInt(3)})
+	require.NoError(t, err)
+	toL1Log := func(event string, txnHash string) types.Log {
+		return types.Log{
+			Topics: []common.Hash{contractABI.Events[event].ID, l2Sender, l1Recipient},
+			Data:   toL1Data,
+			TxHash: common.HexToHash(txnHash),
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	subscriber := mocks.NewMockSubscriber(ctrl)
+	subscriber.EXPECT().WatchLogStateUpdate(gomock.Any(), gomock.Any()).Return(newFakeSubscription(), nil)
+	subscriber.EXPECT().ChainID(gomock.Any()).Return(network.L1ChainID, nil)
+	subscriber.EXPECT().Close()
+
+	// the finalised height advances once the first range has been indexed
+	var firstRangeIndexed atomic.Bool
+	subscriber.EXPECT().FinalisedHeight(gomock.Any()).DoAndReturn(func(context.Context) (uint64, error) {
+		if firstRangeIndexed.Load() {
+			return 20, nil
+		}
+		return 10, nil
+	}).AnyTimes()
+	subscriber.EXPECT().FilterMessageLogs(gomock.Any(), uint64(10), uint64(10)).DoAndReturn(
+		func(context.Context, uint64, uint64) ([]types.Log, error) {
+			firstRangeIndexed.Store(true)
+			return []types.Log{sentLog, toL1Log("LogMessageToL1", "0xa1"), toL1Log("LogMessageToL1", "0xa2")}, nil
+		})
+	subscriber.EXPECT().FilterMessageLogs(gomock.Any(), uint64(11), uint64(20)).
+		Return([]types.Log{consumedLog, toL1Log("ConsumedMessageToL1", "0xb1")}, nil)
+
+	client := l1.NewClient(subscriber, chain, utils.NewNopZapLogger()).
+		WithResubscribeDelay(0).
+		WithPollFinalisedInterval(time.Millisecond).
+		WithMessagesStartHeight(10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- client.Run(ctx)
+	}()
+	require.Eventually(t, func() bool {
+		height, err := chain.L1MessagesHeight()
+		return err == nil && height == 20
+	}, 5*time.Second, time.Millisecond)
+	cancel()
+	require.NoError(t, <-done)
+
+	toL2, err := chain.L1ToL2Message(sentMsgHash)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0x7ad94e71308bb65c6bc9df35cc69cc9f953d69e5"), toL2.From)
+	assert.Equal(t, core.MessageConsumed, toL2.Status())
+	assert.Equal(t, sentLog.TxHash, *toL2.SentTxnHash)
+	assert.Equal(t, consumedLog.TxHash, *toL2.ConsumedTxnHash)
+	assert.NotNil(t, toL2.Fee)
+
+	byTxn, err := chain.L1ToL2MessagesByL1TxnHash(sentLog.TxHash)
+	require.NoError(t, err)
+	assert.Equal(t, []*core.L1ToL2MessageLog{toL2}, byTxn)
+
+	pending := core.MessagePending
+	toL1, err := chain.L2ToL1Messages(new(felt.Felt).SetBytes(l1Recipient.Bytes()), &pending, common.Hash{}, 10)
+	require.NoError(t, err)
+	require.Len(t, toL1, 1)
+	assert.Equal(t, uint64(2), toL1[0].Logged)
+	assert.Equal(t, uint64(1), toL1[0].Consumed)
+	assert.Equal(t, common.HexToHash("0xb1"), *toL1[0].LastConsumedTxnHash)
+
+	consumed := core.MessageConsumed
+	toL1, err = chain.L2ToL1Messages(new(felt.Felt).SetBytes(l2Sender.Bytes()), &consumed, common.Hash{}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, toL1)
+}
+
+func TestStoreL1StateUpdates(t *testing.T) {
+	network := utils.Mainnet
+	chain := blockchain.New(pebble.NewMemTest(t), &network, nil)
+	require.NoError(t, chain.SetL1Head(&core.L1Head{BlockNumber: 2}))
+
+	update := func(l1Block, l2Block uint64) *contract.StarknetLogStateUpdate {
+		return &contract.StarknetLogStateUpdate{
+			GlobalRoot:  new(big.Int).SetUint64(l2Block * 10),
+			BlockNumber: new(big.Int).SetUint64(l2Block),
+			BlockHash:   new(big.Int).SetUint64(l2Block * 100),
+			Raw: types.Log{
+				BlockNumber: l1Block,
+				BlockHash:   common.BigToHash(new(big.Int).SetUint64(l1Block)),
+				TxHash:      common.BigToHash(new(big.Int).SetUint64(l1Block + 1000)),
+			},
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	subscriber := mocks.NewMockSubscriber(ctrl)
+	// the subscription fails, so the updates between the ones of each subscription may have been missed
+	failedSub := &fakeSubscription{errChan: make(chan error, 1)}
+	failedSub.errChan <- errors.New("connection lost")
+	subscriber.
+		EXPECT().
+		WatchLogStateUpdate(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, sink chan<- *contract.StarknetLogStateUpdate) {
+			sink <- update(11, 5)
+			sink <- update(12, 9)
+		}).
+		Return(failedSub, nil)
+	subscriber.
+		EXPECT().
+		WatchLogStateUpdate(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, sink chan<- *contract.StarknetLogStateUpdate) {
+			sink <- update(14, 15)
+			// not finalised yet
+			sink <- update(15, 18)
+		}).
+		Return(newFakeSubscription(), nil)
+	subscriber.EXPECT().FinalisedHeight(gomock.Any()).Return(uint64(14), nil).AnyTimes()
+	subscriber.EXPECT().FilterMessageLogs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	subscriber.EXPECT().ChainID(gomock.Any()).Return(network.L1ChainID, nil)
+	subscriber.EXPECT().Close()
+
+	client := l1.NewClient(subscriber, chain, utils.NewNopZapLogger()).
+		WithResubscribeDelay(0).
+		WithPollFinalisedInterval(time.Nanosecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	require.NoError(t, client.Run(ctx))
+	cancel()
+
+	head, err := chain.L1Head()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(15), head.BlockNumber)
+
+	// the blocks settled by the first update of each subscription are only known to include its block
+	tests := map[uint64]*core.L1StateUpdate{
+		2: nil,
+		3: nil,
+		5: {
+			L1BlockNumber:    11,
+			L1BlockHash:      common.BigToHash(big.NewInt(11)),
+			L1TxnHash:        common.BigToHash(big.NewInt(1011)),
+			FirstBlockNumber: 5,
+			BlockNumber:      5,
+			BlockHash:        new(felt.Felt).SetUint64(500),
+			StateRoot:        new(felt.Felt).SetUint64(50),
+		},
+		6: {
+			L1BlockNumber:    12,
+			L1BlockHash:      common.BigToHash(big.NewInt(12)),
+			L1TxnHash:        common.BigToHash(big.NewInt(1012)),
+			FirstBlockNumber: 6,
+			BlockNumber:      9,
+			BlockHash:        new(felt.Felt).SetUint64(900),
+			StateRoot:        new(felt.Felt).SetUint64(90),
+		},
+		10: nil,
+		15: {
+			L1BlockNumber:    14,
+			L1BlockHash:      common.BigToHash(big.NewInt(14)),
+			L1TxnHash:        common.BigToHash(big.NewInt(1014)),
+			FirstBlockNumber: 15,
+			BlockNumber:      15,
+			BlockHash:        new(felt.Felt).SetUint64(1500),
+			StateRoot:        new(felt.Felt).SetUint64(150),
+		},
+		18: nil,
+	}
+	for blockNumber, want := range tests {
+		got, err := chain.L1StateUpdateByBlockNumber(blockNumber)
+		if want == nil {
+			require.ErrorIs(t, err, db.ErrKeyNotFound, "block %d", blockNumber)
+			continue
+		}
+		require.NoError(t, err, "block %d", blockNumber)
+		assert.Equal(t, want, got, "block %d", blockNumber)
+	}
+}
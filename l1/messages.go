@@ -0,0 +1,176 @@
+package l1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/l1/contract"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	logMessageToL2Event                 = "LogMessageToL2"
+	consumedMessageToL2Event            = "ConsumedMessageToL2"
+	messageToL2CancellationStartedEvent = "MessageToL2CancellationStarted"
+	messageToL2CancelledEvent           = "MessageToL2Canceled" //nolint:misspell
+	logMessageToL1Event                 = "LogMessageToL1"
+	consumedMessageToL1Event            = "ConsumedMessageToL1"
+
+	// messagesBlockRange bounds the number of L1 blocks whose messaging logs are requested at once.
+	messagesBlockRange = 2000
+	// messagesRangesPerPoll bounds the number of ranges indexed at once, so that backfilling the index
+	// doesn't delay the updates of the L1 head.
+	messagesRangesPerPoll = 50
+)
+
+// messageEvents are the core contract events that change the status of a message.
+var messageEvents = []string{
+	logMessageToL2Event,
+	consumedMessageToL2Event,
+	messageToL2CancellationStartedEvent,
+	messageToL2CancelledEvent,
+	logMessageToL1Event,
+	consumedMessageToL1Event,
+}
+
+// messageData holds the non-indexed fields of the messaging events.
+type messageData struct {
+	Payload []*big.Int
+	Nonce   *big.Int
+	Fee     *big.Int
+}
+
+// indexMessages stores the messaging events of the finalised L1 blocks that weren't indexed yet. On a
+// fresh database the index starts from the configured start height and is backfilled over several
+// calls. Only finalised blocks are indexed, so the index doesn't need to handle L1 reorgs.
+func (c *Client) indexMessages(ctx context.Context) error {
+	finalisedHeight, err := c.l1.FinalisedHeight(ctx)
+	if err != nil {
+		c.log.Debugw("Failed to retrieve L1 finalised height, skipping message indexing", "error", err)
+		return nil
+	}
+
+	from := c.messagesStartHeight
+	indexedHeight, err := c.l2Chain.L1MessagesHeight()
+	if err == nil {
+		from = indexedHeight + 1
+	} else if !errors.Is(err, db.ErrKeyNotFound) {
+		return fmt.Errorf("get l1 messages height: %w", err)
+	}
+
+	for range messagesRangesPerPoll {
+		if from > finalisedHeight {
+			return nil
+		}
+		to := min(from+messagesBlockRange-1, finalisedHeight)
+		logs, err := c.l1.FilterMessageLogs(ctx, from, to)
+		if err != nil {
+			c.log.Debugw("Failed to retrieve L1 messaging logs, retrying later", "from", from, "to", to, "error", err)
+			return nil
+		}
+
+		l1ToL2, l2ToL1, err := decodeMessageLogs(logs)
+		if err != nil {
+			c.log.Warnw("Failed to decode L1 messaging logs, retrying later", "from", from, "to", to, "error", err)
+			return nil
+		}
+		if err := c.l2Chain.StoreL1Messages(to, l1ToL2, l2ToL1); err != nil {
+			return fmt.Errorf("store messages of l1 blocks %d to %d: %w", from, to, err)
+		}
+		if len(l1ToL2)+len(l2ToL1) > 0 {
+			c.log.Debugw("Indexed L1 messages", "from", from, "to", to, "toL2", len(l1ToL2), "toL1", len(l2ToL1))
+		}
+		from = to + 1
+	}
+	return nil
+}
+
+// decodeMessageLogs turns the messaging events of the core contract into the status changes of the
+// messages they refer to.
+func decodeMessageLogs(logs []types.Log) ([]*core.L1ToL2MessageLog, []*core.L2ToL1MessageLog, error) {
+	contractABI, err := contract.StarknetMetaData.GetAbi()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var l1ToL2 []*core.L1ToL2MessageLog
+	var l2ToL1 []*core.L2ToL1MessageLog
+	for i := range logs {
+		log := &logs[i]
+		if len(log.Topics) == 0 {
+			continue
+		}
+		event, err := contractABI.EventByID(log.Topics[0])
+		if err != nil {
+			continue
+		}
+
+		var data messageData
+		if err := contractABI.UnpackIntoInterface(&data, event.Name, log.Data); err != nil {
+			return nil, nil, fmt.Errorf("unpack %s: %w", event.Name, err)
+		}
+		txnHash := log.TxHash
+
+		switch event.Name {
+		case logMessageToL2Event, consumedMessageToL2Event, messageToL2CancellationStartedEvent, messageToL2CancelledEvent:
+			if len(log.Topics) != 4 { //nolint:mnd
+				return nil, nil, fmt.Errorf("%s has %d topics", event.Name, len(log.Topics))
+			}
+			msg := &core.L1ToL2MessageLog{
+				L1ToL2Message: core.L1ToL2Message{
+					From:     common.BytesToAddress(log.Topics[1].Bytes()),
+					To:       new(felt.Felt).SetBytes(log.Topics[2].Bytes()),
+					Selector: new(felt.Felt).SetBytes(log.Topics[3].Bytes()),
+					Payload:  adaptPayload(data.Payload),
+					Nonce:    new(felt.Felt).SetBigInt(data.Nonce),
+				},
+			}
+			switch event.Name {
+			case logMessageToL2Event:
+				msg.Fee = new(felt.Felt).SetBigInt(data.Fee)
+				msg.SentTxnHash = &txnHash
+			case consumedMessageToL2Event:
+				msg.ConsumedTxnHash = &txnHash
+			case messageToL2CancellationStartedEvent:
+				msg.CancellationStartedTxnHash = &txnHash
+			case messageToL2CancelledEvent:
+				msg.CancelledTxnHash = &txnHash
+			}
+			l1ToL2 = append(l1ToL2, msg)
+		case logMessageToL1Event, consumedMessageToL1Event:
+			if len(log.Topics) != 3 { //nolint:mnd
+				return nil, nil, fmt.Errorf("%s has %d topics", event.Name, len(log.Topics))
+			}
+			msg := &core.L2ToL1MessageLog{
+				L2ToL1Message: core.L2ToL1Message{
+					From:    new(felt.Felt).SetBytes(log.Topics[1].Bytes()),
+					To:      common.BytesToAddress(log.Topics[2].Bytes()),
+					Payload: adaptPayload(data.Payload),
+				},
+			}
+			if event.Name == logMessageToL1Event {
+				msg.Logged = 1
+				msg.LastLoggedTxnHash = &txnHash
+			} else {
+				msg.Consumed = 1
+				msg.LastConsumedTxnHash = &txnHash
+			}
+			l2ToL1 = append(l2ToL1, msg)
+		}
+	}
+	return l1ToL2, l2ToL1, nil
+}
+
+func adaptPayload(payload []*big.Int) []*felt.Felt {
+	felts := make([]*felt.Felt, len(payload))
+	for i, value := range payload {
+		felts[i] = new(felt.Felt).SetBigInt(value)
+	}
+	return felts
+}
@@ -0,0 +1,499 @@
+package l1
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/l1/contract"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	DisagreementChainID     = "chain_id"
+	DisagreementStateUpdate = "state_update"
+	DisagreementMessageLogs = "message_logs"
+	DisagreementReceipt     = "receipt"
+
+	// maxTrackedUpdates bounds the number of state updates whose support is tracked. Updates are
+	// emitted every few minutes, so the oldest ones are long finalised by the time they are dropped.
+	maxTrackedUpdates = 256
+)
+
+// QuorumSubscriber combines the views of several Ethereum endpoints. A state update is only sent once
+// a quorum of endpoints reported it and a height is only considered finalised once a quorum of
+// endpoints finalised it, so that a single compromised or lagging endpoint can't advance the L1 head.
+type QuorumSubscriber struct {
+	subscribers      []Subscriber
+	quorum           int
+	listener         EventListener
+	callTimeout      time.Duration
+	resubscribeDelay time.Duration
+}
+
+var _ Subscriber = (*QuorumSubscriber)(nil)
+
+func NewQuorumSubscriber(subscribers []Subscriber, quorum int) (*QuorumSubscriber, error) {
+	if quorum < 1 || quorum > len(subscribers) {
+		return nil, fmt.Errorf("quorum must be between 1 and the number of Ethereum endpoints (%d), got %d",
+			len(subscribers), quorum)
+	}
+	return &QuorumSubscriber{
+		subscribers:      subscribers,
+		quorum:           quorum,
+		listener:         SelectiveListener{},
+		callTimeout:      30 * time.Second, //nolint:mnd
+		resubscribeDelay: 10 * time.Second, //nolint:mnd
+	}, nil
+}
+
+func (s *QuorumSubscriber) WithEventListener(l EventListener) *QuorumSubscriber {
+	s.listener = l
+	return s
+}
+
+// WithCallTimeout bounds how long a single endpoint can take to answer a query, so that an
+// unresponsive endpoint doesn't hold up the answer of the others.
+func (s *QuorumSubscriber) WithCallTimeout(timeout time.Duration) *QuorumSubscriber {
+	s.callTimeout = timeout
+	return s
+}
+
+// WithResubscribeDelay sets how long to wait before subscribing again to an endpoint whose
+// subscription failed.
+func (s *QuorumSubscriber) WithResubscribeDelay(delay time.Duration) *QuorumSubscriber {
+	s.resubscribeDelay = delay
+	return s
+}
+
+// queryEndpoints calls every endpoint concurrently, each with its own timeout. The result and error
+// of an endpoint are at its index.
+func queryEndpoints[T any](ctx context.Context, s *QuorumSubscriber,
+	query func(context.Context, Subscriber) (T, error),
+) ([]T, []error) {
+	results := make([]T, len(s.subscribers))
+	errs := make([]error, len(s.subscribers))
+	var wg sync.WaitGroup
+	for i, subscriber := range s.subscribers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			callCtx, cancel := context.WithTimeout(ctx, s.callTimeout)
+			defer cancel()
+			results[i], errs[i] = query(callCtx, subscriber)
+		}()
+	}
+	wg.Wait()
+	return results, errs
+}
+
+// FinalisedHeight returns the highest height that a quorum of endpoints consider finalised.
+func (s *QuorumSubscriber) FinalisedHeight(ctx context.Context) (uint64, error) {
+	results, errs := queryEndpoints(ctx, s, func(ctx context.Context, subscriber Subscriber) (uint64, error) {
+		return subscriber.FinalisedHeight(ctx)
+	})
+	heights := make([]uint64, 0, len(s.subscribers))
+	for i, height := range results {
+		if errs[i] == nil {
+			heights = append(heights, height)
+		}
+	}
+	if len(heights) < s.quorum {
+		return 0, fmt.Errorf("only %d of %d Ethereum endpoints returned a finalised height: %w",
+			len(heights), s.quorum, errors.Join(errs...))
+	}
+
+	slices.Sort(heights)
+	return heights[len(heights)-s.quorum], nil
+}
+
+// ChainID returns the chain ID that a quorum of endpoints agree on.
+func (s *QuorumSubscriber) ChainID(ctx context.Context) (*big.Int, error) {
+	chainIDs, errs := queryEndpoints(ctx, s, func(ctx context.Context, subscriber Subscriber) (*big.Int, error) {
+		return subscriber.ChainID(ctx)
+	})
+
+	for _, candidate := range chainIDs {
+		if candidate == nil {
+			continue
+		}
+		agreeing := 0
+		for _, chainID := range chainIDs {
+			if chainID != nil && chainID.Cmp(candidate) == 0 {
+				agreeing++
+			}
+		}
+		if agreeing < s.quorum {
+			continue
+		}
+
+		for endpoint, chainID := range chainIDs {
+			if chainID != nil && chainID.Cmp(candidate) != 0 {
+				s.listener.OnL1Disagreement(endpoint, DisagreementChainID)
+			}
+		}
+		return candidate, nil
+	}
+	return nil, fmt.Errorf("no quorum of %d Ethereum endpoints agree on the chain ID: %w", s.quorum, errors.Join(errs...))
+}
+
+// TransactionReceipt returns the receipt of a transaction that a quorum of endpoints agree on.
+func (s *QuorumSubscriber) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	receipts, errs := queryEndpoints(ctx, s, func(ctx context.Context, subscriber Subscriber) (*types.Receipt, error) {
+		return subscriber.TransactionReceipt(ctx, txHash)
+	})
+	digests := make([]*common.Hash, len(s.subscribers))
+	for i, receipt := range receipts {
+		if errs[i] == nil {
+			digest := receiptDigest(receipt)
+			digests[i] = &digest
+		}
+	}
+
+	if i, ok := s.agreed(digests, DisagreementReceipt); ok {
+		return receipts[i], nil
+	}
+	return nil, fmt.Errorf("no quorum of %d Ethereum endpoints agree on the receipt of transaction %s: %w",
+		s.quorum, txHash, errors.Join(errs...))
+}
+
+// FilterMessageLogs returns the messaging logs that a quorum of endpoints agree on.
+func (s *QuorumSubscriber) FilterMessageLogs(ctx context.Context, from, to uint64) ([]types.Log, error) {
+	results, errs := queryEndpoints(ctx, s, func(ctx context.Context, subscriber Subscriber) ([]types.Log, error) {
+		return subscriber.FilterMessageLogs(ctx, from, to)
+	})
+	digests := make([]*common.Hash, len(s.subscribers))
+	for i, logs := range results {
+		if errs[i] == nil {
+			digest := logsDigest(logs)
+			digests[i] = &digest
+		}
+	}
+
+	if i, ok := s.agreed(digests, DisagreementMessageLogs); ok {
+		return results[i], nil
+	}
+	return nil, fmt.Errorf("no quorum of %d Ethereum endpoints agree on the messaging logs of blocks %d to %d: %w",
+		s.quorum, from, to, errors.Join(errs...))
+}
+
+// agreed returns the index of an endpoint whose digest a quorum of endpoints agree on, and reports the
+// endpoints that returned a different digest as disagreeing. Endpoints that failed have a nil digest.
+func (s *QuorumSubscriber) agreed(digests []*common.Hash, kind string) (int, bool) {
+	for i, candidate := range digests {
+		if candidate == nil {
+			continue
+		}
+		agreeing := 0
+		for _, digest := range digests {
+			if digest != nil && *digest == *candidate {
+				agreeing++
+			}
+		}
+		if agreeing < s.quorum {
+			continue
+		}
+
+		for endpoint, digest := range digests {
+			if digest != nil && *digest != *candidate {
+				s.listener.OnL1Disagreement(endpoint, kind)
+			}
+		}
+		return i, true
+	}
+	return 0, false
+}
+
+// receiptDigest hashes the fields of a receipt that messaging relies on.
+func receiptDigest(receipt *types.Receipt) common.Hash {
+	digest := sha3.NewLegacyKeccak256()
+	digest.Write(receipt.TxHash.Bytes())
+	digest.Write(receipt.BlockHash.Bytes())
+	digest.Write(binary.BigEndian.AppendUint64(nil, receipt.Status))
+	logs := make([]types.Log, 0, len(receipt.Logs))
+	for _, log := range receipt.Logs {
+		logs = append(logs, *log)
+	}
+	digest.Write(logsDigest(logs).Bytes())
+	return common.BytesToHash(digest.Sum(nil))
+}
+
+// logsDigest hashes the content and position of logs.
+func logsDigest(logs []types.Log) common.Hash {
+	digest := sha3.NewLegacyKeccak256()
+	for i := range logs {
+		log := &logs[i]
+		digest.Write(log.BlockHash.Bytes())
+		digest.Write(log.TxHash.Bytes())
+		digest.Write(binary.BigEndian.AppendUint64(nil, uint64(log.Index)))
+		for _, topic := range log.Topics {
+			digest.Write(topic.Bytes())
+		}
+		digest.Write(crypto.Keccak256(log.Data))
+	}
+	return common.BytesToHash(digest.Sum(nil))
+}
+
+func (s *QuorumSubscriber) Close() {
+	for _, subscriber := range s.subscribers {
+		subscriber.Close()
+	}
+}
+
+type endpointUpdate struct {
+	endpoint int
+	update   *contract.StarknetLogStateUpdate
+}
+
+// endpointStatus reports that the subscription of an endpoint failed, or recovered if err is nil.
+type endpointStatus struct {
+	endpoint int
+	err      error
+}
+
+// updateKey identifies a state update. Updates of the same L1 transaction that were included in
+// different L1 blocks are distinct, since only one of them can end up in the canonical chain.
+type updateKey struct {
+	l1BlockHash common.Hash
+	logIndex    uint
+	l2Block     string
+	l2BlockHash string
+	globalRoot  string
+}
+
+func keyOf(update *contract.StarknetLogStateUpdate) updateKey {
+	return updateKey{
+		l1BlockHash: update.Raw.BlockHash,
+		logIndex:    update.Raw.Index,
+		l2Block:     update.BlockNumber.String(),
+		l2BlockHash: update.BlockHash.String(),
+		globalRoot:  update.GlobalRoot.String(),
+	}
+}
+
+type trackedUpdate struct {
+	update    *contract.StarknetLogStateUpdate
+	endpoints map[int]struct{}
+	sent      bool
+}
+
+// WatchLogStateUpdate subscribes to every endpoint and sends an update once a quorum of them reported
+// it. If enough endpoints later report the update as removed, it is sent again as removed. Endpoints
+// whose subscription fails are resubscribed on their own; the subscription only fails once so many
+// endpoints are failing at the same time that a quorum can no longer be reached.
+func (s *QuorumSubscriber) WatchLogStateUpdate(ctx context.Context, sink chan<- *contract.StarknetLogStateUpdate) (event.Subscription, error) {
+	subs := make([]event.Subscription, len(s.subscribers))
+	sinks := make([]chan *contract.StarknetLogStateUpdate, len(s.subscribers))
+	failed := make(map[int]error)
+	for i, subscriber := range s.subscribers {
+		sinks[i] = make(chan *contract.StarknetLogStateUpdate)
+		sub, err := subscriber.WatchLogStateUpdate(ctx, sinks[i])
+		if err != nil {
+			failed[i] = fmt.Errorf("subscribe to Ethereum endpoint %d: %w", i, err)
+			continue
+		}
+		subs[i] = sub
+	}
+	if len(s.subscribers)-len(failed) < s.quorum {
+		for _, sub := range subs {
+			if sub != nil {
+				sub.Unsubscribe()
+			}
+		}
+		return nil, joinFailures(failed)
+	}
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		updates := make(chan endpointUpdate)
+		statuses := make(chan endpointStatus)
+		done := make(chan struct{})
+		var wg sync.WaitGroup
+		defer wg.Wait()
+		defer close(done)
+
+		for i := range s.subscribers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.watchEndpoint(ctx, i, subs[i], sinks[i], updates, statuses, done)
+			}()
+		}
+
+		tracked := make(map[updateKey]*trackedUpdate)
+		for {
+			select {
+			case u := <-updates:
+				for _, update := range s.track(tracked, u) {
+					select {
+					case sink <- update:
+					case <-quit:
+						return nil
+					}
+				}
+			case status := <-statuses:
+				if status.err == nil {
+					delete(failed, status.endpoint)
+					continue
+				}
+				failed[status.endpoint] = status.err
+				if len(s.subscribers)-len(failed) < s.quorum {
+					return joinFailures(failed)
+				}
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// watchEndpoint forwards the updates of an endpoint until done is closed. Whenever the subscription
+// fails, the failure is reported and the endpoint is subscribed to again after the resubscribe delay.
+// A nil subscription means that the endpoint has to be subscribed to first.
+func (s *QuorumSubscriber) watchEndpoint(ctx context.Context, endpoint int, sub event.Subscription,
+	sink chan *contract.StarknetLogStateUpdate, updates chan<- endpointUpdate, statuses chan<- endpointStatus,
+	done <-chan struct{},
+) {
+	report := func(err error) bool {
+		select {
+		case statuses <- endpointStatus{endpoint: endpoint, err: err}:
+			return true
+		case <-done:
+			return false
+		}
+	}
+
+	for {
+		if sub == nil {
+			select {
+			case <-time.After(s.resubscribeDelay):
+			case <-done:
+				return
+			}
+			var err error
+			if sub, err = s.subscribers[endpoint].WatchLogStateUpdate(ctx, sink); err != nil {
+				sub = nil
+				continue
+			}
+			if !report(nil) {
+				sub.Unsubscribe()
+				return
+			}
+		}
+
+		err := forwardUpdates(endpoint, sub, sink, updates, done)
+		sub.Unsubscribe()
+		if err == nil || !report(fmt.Errorf("endpoint %d: %w", endpoint, err)) {
+			return
+		}
+		sub = nil
+	}
+}
+
+// forwardUpdates forwards the updates of a subscription until it fails or done is closed, in which
+// case it returns nil.
+func forwardUpdates(endpoint int, sub event.Subscription, sink <-chan *contract.StarknetLogStateUpdate,
+	updates chan<- endpointUpdate, done <-chan struct{},
+) error {
+	for {
+		select {
+		case update := <-sink:
+			select {
+			case updates <- endpointUpdate{endpoint: endpoint, update: update}:
+			case <-done:
+				return nil
+			}
+		case err, ok := <-sub.Err():
+			if !ok || err == nil {
+				return errors.New("subscription ended")
+			}
+			return err
+		case <-done:
+			return nil
+		}
+	}
+}
+
+func joinFailures(failed map[int]error) error {
+	errs := make([]error, 0, len(failed))
+	for _, err := range failed {
+		errs = append(errs, err)
+	}
+	return fmt.Errorf("%d Ethereum endpoints failed: %w", len(failed), errors.Join(errs...))
+}
+
+// track records the support of an endpoint for an update and returns the updates that must be sent
+// as a result.
+func (s *QuorumSubscriber) track(tracked map[updateKey]*trackedUpdate, u endpointUpdate) []*contract.StarknetLogStateUpdate {
+	key := keyOf(u.update)
+	t, ok := tracked[key]
+	if !ok {
+		if u.update.Raw.Removed {
+			return nil
+		}
+		t = &trackedUpdate{update: u.update, endpoints: make(map[int]struct{})}
+		tracked[key] = t
+		pruneTracked(tracked)
+	}
+
+	if u.update.Raw.Removed {
+		delete(t.endpoints, u.endpoint)
+		if !t.sent || len(t.endpoints) >= s.quorum {
+			return nil
+		}
+		t.sent = false
+		removed := *t.update
+		removed.Raw.Removed = true
+		return []*contract.StarknetLogStateUpdate{&removed}
+	}
+
+	t.endpoints[u.endpoint] = struct{}{}
+	if t.sent {
+		return nil
+	}
+	if len(t.endpoints) < s.quorum {
+		for otherKey, other := range tracked {
+			if other.sent && conflicting(key, otherKey) {
+				s.listener.OnL1Disagreement(u.endpoint, DisagreementStateUpdate)
+				break
+			}
+		}
+		return nil
+	}
+
+	t.sent = true
+	for otherKey, other := range tracked {
+		if conflicting(key, otherKey) {
+			for endpoint := range other.endpoints {
+				s.listener.OnL1Disagreement(endpoint, DisagreementStateUpdate)
+			}
+		}
+	}
+	return []*contract.StarknetLogStateUpdate{t.update}
+}
+
+// conflicting reports whether two updates are for the same L2 block but disagree on its content.
+func conflicting(a, b updateKey) bool {
+	return a.l2Block == b.l2Block && (a.l2BlockHash != b.l2BlockHash || a.globalRoot != b.globalRoot)
+}
+
+func pruneTracked(tracked map[updateKey]*trackedUpdate) {
+	for len(tracked) > maxTrackedUpdates {
+		var oldestKey updateKey
+		var oldest *trackedUpdate
+		for key, t := range tracked {
+			if oldest == nil || t.update.Raw.BlockNumber < oldest.update.Raw.BlockNumber {
+				oldestKey, oldest = key, t
+			}
+		}
+		delete(tracked, oldestKey)
+	}
+}
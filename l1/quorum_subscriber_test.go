@@ -0,0 +1,282 @@
+package l1_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/l1"
+	"github.com/NethermindEth/juno/l1/contract"
+	"github.com/NethermindEth/juno/mocks"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newMockSubscribers(t *testing.T, n int) []*mocks.MockSubscriber {
+	ctrl := gomock.NewController(t)
+	subscribers := make([]*mocks.MockSubscriber, n)
+	for i := range subscribers {
+		subscribers[i] = mocks.NewMockSubscriber(ctrl)
+	}
+	return subscribers
+}
+
+func newQuorumSubscriber(t *testing.T, subscribers []*mocks.MockSubscriber, quorum int) *l1.QuorumSubscriber {
+	s := make([]l1.Subscriber, len(subscribers))
+	for i := range subscribers {
+		s[i] = subscribers[i]
+	}
+	quorumSubscriber, err := l1.NewQuorumSubscriber(s, quorum)
+	require.NoError(t, err)
+	return quorumSubscriber
+}
+
+type disagreements struct {
+	mu     sync.Mutex
+	byKind map[string][]int
+}
+
+func (d *disagreements) listener() l1.EventListener {
+	d.byKind = make(map[string][]int)
+	return l1.SelectiveListener{
+		OnL1DisagreementCb: func(endpoint int, kind string) {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			d.byKind[kind] = append(d.byKind[kind], endpoint)
+		},
+	}
+}
+
+func (d *disagreements) get(kind string) []int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.byKind[kind]
+}
+
+func TestNewQuorumSubscriber(t *testing.T) {
+	subscribers := []l1.Subscriber{nil, nil}
+
+	_, err := l1.NewQuorumSubscriber(subscribers, 0)
+	require.Error(t, err)
+	_, err = l1.NewQuorumSubscriber(subscribers, 3)
+	require.Error(t, err)
+	_, err = l1.NewQuorumSubscriber(subscribers, 2)
+	require.NoError(t, err)
+}
+
+func TestQuorumFinalisedHeight(t *testing.T) {
+	subscribers := newMockSubscribers(t, 3)
+	subscribers[0].EXPECT().FinalisedHeight(gomock.Any()).Return(uint64(30), nil).Times(2)
+	subscribers[1].EXPECT().FinalisedHeight(gomock.Any()).Return(uint64(10), nil).Times(2)
+	subscribers[2].EXPECT().FinalisedHeight(gomock.Any()).Return(uint64(20), nil)
+	subscribers[2].EXPECT().FinalisedHeight(gomock.Any()).Return(uint64(0), errors.New("unavailable"))
+
+	// a single endpoint that is ahead can't advance the finalised height
+	height, err := newQuorumSubscriber(t, subscribers, 2).FinalisedHeight(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), height)
+
+	_, err = newQuorumSubscriber(t, subscribers, 3).FinalisedHeight(context.Background())
+	require.Error(t, err)
+}
+
+func TestQuorumChainID(t *testing.T) {
+	subscribers := newMockSubscribers(t, 3)
+	subscribers[0].EXPECT().ChainID(gomock.Any()).Return(big.NewInt(1), nil).Times(2)
+	subscribers[1].EXPECT().ChainID(gomock.Any()).Return(big.NewInt(5), nil).Times(2)
+	subscribers[2].EXPECT().ChainID(gomock.Any()).Return(big.NewInt(1), nil).Times(2)
+
+	var d disagreements
+	chainID, err := newQuorumSubscriber(t, subscribers, 2).WithEventListener(d.listener()).ChainID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1), chainID)
+	assert.Equal(t, []int{1}, d.get(l1.DisagreementChainID))
+
+	_, err = newQuorumSubscriber(t, subscribers, 3).ChainID(context.Background())
+	require.Error(t, err)
+}
+
+func TestQuorumWatchLogStateUpdate(t *testing.T) {
+	subscribers := newMockSubscribers(t, 3)
+	sinks := make([]chan<- *contract.StarknetLogStateUpdate, len(subscribers))
+	for i, subscriber := range subscribers {
+		subscriber.EXPECT().WatchLogStateUpdate(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, sink chan<- *contract.StarknetLogStateUpdate) (event.Subscription, error) {
+				sinks[i] = sink
+				return newFakeSubscription(), nil
+			})
+	}
+
+	var d disagreements
+	quorumSubscriber := newQuorumSubscriber(t, subscribers, 2).WithEventListener(d.listener())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	sink := make(chan *contract.StarknetLogStateUpdate)
+	sub, err := quorumSubscriber.WatchLogStateUpdate(ctx, sink)
+	require.NoError(t, err)
+	t.Cleanup(sub.Unsubscribe)
+
+	update := func(l2BlockHash int64, removed bool) *contract.StarknetLogStateUpdate {
+		return &contract.StarknetLogStateUpdate{
+			GlobalRoot:  big.NewInt(1),
+			BlockNumber: big.NewInt(100),
+			BlockHash:   big.NewInt(l2BlockHash),
+			Raw: types.Log{
+				BlockNumber: 10,
+				BlockHash:   common.HexToHash("0xabc"),
+				Removed:     removed,
+			},
+		}
+	}
+	receive := func() *contract.StarknetLogStateUpdate {
+		select {
+		case u := <-sink:
+			return u
+		case <-ctx.Done():
+			require.FailNow(t, "timed out waiting for update")
+		}
+		return nil
+	}
+
+	// the first endpoint reports a forged update that no other endpoint confirms
+	sinks[0] <- update(666, false)
+	sinks[1] <- update(1, false)
+	sinks[2] <- update(1, false)
+
+	got := receive()
+	assert.Equal(t, big.NewInt(1), got.BlockHash)
+	assert.False(t, got.Raw.Removed)
+	assert.Equal(t, []int{0}, d.get(l1.DisagreementStateUpdate))
+
+	// the update is removed once it loses the support of the quorum
+	sinks[1] <- update(1, true)
+	got = receive()
+	assert.Equal(t, big.NewInt(1), got.BlockHash)
+	assert.True(t, got.Raw.Removed)
+}
+
+func TestQuorumWatchLogStateUpdateEndpointFailures(t *testing.T) {
+	subscribers := newMockSubscribers(t, 3)
+	sinks := make([]chan<- *contract.StarknetLogStateUpdate, len(subscribers))
+	subs := make([]*fakeSubscription, len(subscribers))
+	var mu sync.Mutex
+	sinkOf := func(i int) chan<- *contract.StarknetLogStateUpdate {
+		mu.Lock()
+		defer mu.Unlock()
+		return sinks[i]
+	}
+	for i, subscriber := range subscribers {
+		subscriber.EXPECT().WatchLogStateUpdate(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, sink chan<- *contract.StarknetLogStateUpdate) (event.Subscription, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				sinks[i] = sink
+				subs[i] = newFakeSubscription()
+				return subs[i], nil
+			})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	sink := make(chan *contract.StarknetLogStateUpdate)
+	sub, err := newQuorumSubscriber(t, subscribers, 2).WithResubscribeDelay(time.Millisecond).WatchLogStateUpdate(ctx, sink)
+	require.NoError(t, err)
+	t.Cleanup(sub.Unsubscribe)
+
+	// a single failing endpoint is resubscribed without failing the subscription
+	resubscribed := make(chan struct{})
+	subscribers[0].EXPECT().WatchLogStateUpdate(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, sink chan<- *contract.StarknetLogStateUpdate) (event.Subscription, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			sinks[0] = sink
+			close(resubscribed)
+			return newFakeSubscription(), nil
+		})
+	subs[0].errChan <- errors.New("connection lost")
+	select {
+	case <-resubscribed:
+	case <-ctx.Done():
+		require.FailNow(t, "timed out waiting for resubscription")
+	}
+
+	update := &contract.StarknetLogStateUpdate{
+		GlobalRoot:  big.NewInt(1),
+		BlockNumber: big.NewInt(100),
+		BlockHash:   big.NewInt(1),
+		Raw:         types.Log{BlockNumber: 10, BlockHash: common.HexToHash("0xabc")},
+	}
+	sinkOf(0) <- update
+	sinkOf(1) <- update
+	select {
+	case got := <-sink:
+		assert.Equal(t, update, got)
+	case <-ctx.Done():
+		require.FailNow(t, "timed out waiting for update")
+	}
+
+	// the subscription fails once a quorum can no longer be reached
+	for _, i := range []int{1, 2} {
+		subscribers[i].EXPECT().WatchLogStateUpdate(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("unavailable")).AnyTimes()
+	}
+	subs[1].errChan <- errors.New("connection lost")
+	subs[2].errChan <- errors.New("connection lost")
+	select {
+	case err := <-sub.Err():
+		require.ErrorContains(t, err, "connection lost")
+	case <-ctx.Done():
+		require.FailNow(t, "timed out waiting for the subscription to fail")
+	}
+}
+
+func TestQuorumFilterMessageLogs(t *testing.T) {
+	logs := []types.Log{{TxHash: common.HexToHash("0x1"), Data: []byte{1}}}
+	forged := []types.Log{{TxHash: common.HexToHash("0x1"), Data: []byte{2}}}
+
+	subscribers := newMockSubscribers(t, 3)
+	subscribers[0].EXPECT().FilterMessageLogs(gomock.Any(), uint64(1), uint64(2)).Return(forged, nil).Times(2)
+	subscribers[1].EXPECT().FilterMessageLogs(gomock.Any(), uint64(1), uint64(2)).Return(logs, nil).Times(2)
+	subscribers[2].EXPECT().FilterMessageLogs(gomock.Any(), uint64(1), uint64(2)).Return(logs, nil)
+	subscribers[2].EXPECT().FilterMessageLogs(gomock.Any(), uint64(1), uint64(2)).Return(nil, errors.New("unavailable"))
+
+	var d disagreements
+	got, err := newQuorumSubscriber(t, subscribers, 2).WithEventListener(d.listener()).FilterMessageLogs(context.Background(), 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, logs, got)
+	assert.Equal(t, []int{0}, d.get(l1.DisagreementMessageLogs))
+
+	_, err = newQuorumSubscriber(t, subscribers, 2).FilterMessageLogs(context.Background(), 1, 2)
+	require.Error(t, err)
+}
+
+func TestQuorumTransactionReceipt(t *testing.T) {
+	txHash := common.HexToHash("0x1")
+	receipt := &types.Receipt{TxHash: txHash, Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{{Data: []byte{1}}}}
+	forged := &types.Receipt{TxHash: txHash, Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{{Data: []byte{2}}}}
+
+	subscribers := newMockSubscribers(t, 3)
+	subscribers[0].EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(forged, nil).Times(2)
+	subscribers[1].EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(receipt, nil).Times(2)
+	subscribers[2].EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(receipt, nil)
+	subscribers[2].EXPECT().TransactionReceipt(gomock.Any(), txHash).Return(nil, errors.New("unavailable"))
+
+	var d disagreements
+	got, err := newQuorumSubscriber(t, subscribers, 2).WithEventListener(d.listener()).TransactionReceipt(context.Background(), txHash)
+	require.NoError(t, err)
+	assert.Equal(t, receipt, got)
+	assert.Equal(t, []int{0}, d.get(l1.DisagreementReceipt))
+
+	_, err = newQuorumSubscriber(t, subscribers, 2).TransactionReceipt(context.Background(), txHash)
+	require.Error(t, err)
+}
@@ -0,0 +1,109 @@
+package migration
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/encoder"
+	"github.com/NethermindEth/juno/utils"
+)
+
+const classCompressorBatchSize = 1_000
+
+// ClassCompressor rewrites classes stored as plain CBOR into the compressed layout while the node
+// is running. Readers accept both layouts, so the database stays usable during the conversion.
+type ClassCompressor struct {
+	database  db.DB
+	log       utils.SimpleLogger
+	batchSize int
+}
+
+func NewClassCompressor(database db.DB, log utils.SimpleLogger) *ClassCompressor {
+	return &ClassCompressor{
+		database:  database,
+		log:       log,
+		batchSize: classCompressorBatchSize,
+	}
+}
+
+// Run converts the [db.Class] bucket and then blocks until the context is cancelled, so that
+// finishing the conversion does not stop the node.
+func (c *ClassCompressor) Run(ctx context.Context) error {
+	if err := c.compressAll(ctx); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return nil
+}
+
+// compressAll visits the [db.Class] bucket in batches, each in its own transaction so that block
+// processing is not held up for the whole conversion.
+func (c *ClassCompressor) compressAll(ctx context.Context) error {
+	var converted int
+	startFrom := db.Class.Key()
+	for startFrom != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		var n int
+		if err := c.database.Update(func(txn db.Transaction) error {
+			var err error
+			startFrom, n, err = c.compressBatch(txn, startFrom)
+			return err
+		}); err != nil {
+			return fmt.Errorf("compress classes: %v", err)
+		}
+		converted += n
+	}
+	if converted > 0 {
+		c.log.Infow("Compressed stored classes", "count", converted)
+	}
+	return nil
+}
+
+// compressBatch converts up to batchSize classes starting at the given key. It returns the key to
+// continue from, which is nil once the bucket has been fully visited, and the number of classes
+// that were rewritten.
+func (c *ClassCompressor) compressBatch(txn db.Transaction, startFrom []byte) ([]byte, int, error) {
+	iterator, err := txn.NewIterator(nil, false)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var converted int
+	remainingInBatch := c.batchSize
+	for iterator.Seek(startFrom); iterator.Valid(); iterator.Next() {
+		key := iterator.Key()
+		if !bytes.HasPrefix(key, db.Class.Key()) {
+			break
+		}
+		if remainingInBatch == 0 {
+			return bytes.Clone(key), converted, iterator.Close()
+		}
+		remainingInBatch--
+
+		value, err := iterator.Value()
+		if err != nil {
+			return nil, 0, utils.RunAndWrapOnError(iterator.Close, err)
+		}
+		if core.IsCompressedClassEncoding(value) {
+			continue
+		}
+
+		var declared core.DeclaredClass
+		if err = encoder.Unmarshal(value, &declared); err != nil {
+			return nil, 0, utils.RunAndWrapOnError(iterator.Close, fmt.Errorf("unmarshal class: %v", err))
+		}
+		classHash := new(felt.Felt).SetBytes(key[len(db.Class.Key()):])
+		if err = core.StoreDeclaredClass(txn, classHash, &declared); err != nil {
+			return nil, 0, utils.RunAndWrapOnError(iterator.Close, err)
+		}
+		converted++
+	}
+	return nil, converted, iterator.Close()
+}
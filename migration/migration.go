@@ -68,6 +68,9 @@ var defaultMigrations = []Migration{
 	NewBucketMigrator(db.Class, migrateCairo1CompiledClass).WithBatchSize(1_000),              //nolint:mnd
 	MigrationFunc(calculateL1MsgHashes),
 	MigrationFunc(removePendingBlock),
+	MigrationFunc(compressClassesInBackground),
+	MigrationFunc(calculateSenderNonceIndex),
+	NewBucketMigrator(db.ContractClassHash, indexContractsByClassHash),
 }
 
 var ErrCallWithNewTransaction = errors.New("call with new transaction")
@@ -510,6 +513,22 @@ func calculateL1MsgHashes(txn db.Transaction, n *utils.Network) error {
 	return processBlocks(txn, processBlockFunc)
 }
 
+// calculateSenderNonceIndex indexes the account transactions of existing blocks by sender address and nonce
+func calculateSenderNonceIndex(txn db.Transaction, _ *utils.Network) error {
+	processBlockFunc := func(blockNumber uint64, txnLock *sync.Mutex) error {
+		txnLock.Lock()
+		txns, err := blockchain.TransactionsByBlockNumber(txn, blockNumber)
+		txnLock.Unlock()
+		if err != nil {
+			return err
+		}
+		txnLock.Lock()
+		defer txnLock.Unlock()
+		return blockchain.StoreSenderNonceIndex(txn, blockNumber, txns)
+	}
+	return processBlocks(txn, processBlockFunc)
+}
+
 func bitset2Key(bs *bitset.BitSet) *trie.Key {
 	bsWords := bs.Words()
 	if len(bsWords) > felt.Limbs {
@@ -760,3 +779,16 @@ func migrateCairo1CompiledClass(txn db.Transaction, key, value []byte, _ *utils.
 
 	return txn.Set(key, value)
 }
+
+// compressClassesInBackground is a no-op: classes are rewritten into the compressed layout by
+// [ClassCompressor] while the node runs. The entry keeps the schema version of later migrations.
+func compressClassesInBackground(_ db.Transaction, _ *utils.Network) error {
+	return nil
+}
+
+// indexContractsByClassHash adds every deployed contract to the reverse index from class hash to
+// the contracts that instantiate it.
+func indexContractsByClassHash(txn db.Transaction, key, value []byte, _ *utils.Network) error {
+	addr := key[len(db.ContractClassHash.Key()):]
+	return txn.Set(db.ContractAddressesByClassHash.Key(value, addr), []byte{})
+}
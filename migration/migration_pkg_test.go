@@ -247,6 +247,68 @@ func TestL1HandlerTxns(t *testing.T) {
 	assert.Equal(t, l1HandlerTxnHash.String(), "0x785c2ada3f53fbc66078d47715c27718f92e6e48b96372b36e5197de69b82b5")
 }
 
+func TestCalculateSenderNonceIndex(t *testing.T) {
+	testdb := pebble.NewMemTest(t)
+	chain := blockchain.New(testdb, &utils.Sepolia, nil)
+	client := feeder.NewTestClient(t, &utils.Sepolia)
+	gw := adaptfeeder.New(client)
+
+	for i := uint64(0); i <= 4; i++ {
+		b, err := gw.BlockByNumber(context.Background(), i)
+		require.NoError(t, err)
+		su, err := gw.StateUpdate(context.Background(), i)
+		require.NoError(t, err)
+		require.NoError(t, chain.Store(b, &core.BlockCommitments{}, su, nil))
+	}
+
+	// Drop the index to simulate a database created before it existed
+	require.NoError(t, testdb.Update(func(txn db.Transaction) error {
+		it, err := txn.NewIterator(db.TransactionBlockNumbersAndIndicesBySenderAndNonce.Key(), true)
+		if err != nil {
+			return err
+		}
+		for it.First(); it.Valid(); it.Next() {
+			if err = txn.Delete(it.Key()); err != nil {
+				return utils.RunAndWrapOnError(it.Close, err)
+			}
+		}
+		return it.Close()
+	}))
+
+	sender := utils.HexToFelt(t, "0x43abaa073c768ebf039c0c4f46db9acc39e9ec165690418060a652aab39e7d8")
+	txns, err := chain.TransactionsBySender(sender, &felt.Zero, 100)
+	require.NoError(t, err)
+	require.Empty(t, txns)
+
+	require.NoError(t, testdb.Update(func(txn db.Transaction) error {
+		return calculateSenderNonceIndex(txn, &utils.Sepolia)
+	}))
+
+	txns, err = chain.TransactionsBySender(sender, &felt.Zero, 100)
+	require.NoError(t, err)
+	assert.Len(t, txns, 11)
+}
+
+func TestIndexContractsByClassHash(t *testing.T) {
+	txn, err := pebble.NewMemTest(t).NewTransaction(true)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, txn.Discard())
+	})
+
+	classHash := utils.HexToFelt(t, "0x123")
+	addr := utils.HexToFelt(t, "0x456")
+	key := db.ContractClassHash.Key(addr.Marshal())
+	value := classHash.Marshal()
+	require.NoError(t, txn.Set(key, value))
+
+	require.NoError(t, indexContractsByClassHash(txn, key, value, &utils.Mainnet))
+
+	addresses, err := core.NewState(txn).ContractsByClassHash(classHash, &felt.Zero, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []*felt.Felt{addr}, addresses)
+}
+
 func TestMigrateTrieRootKeysFromBitsetToTrieKeys(t *testing.T) {
 	memTxn := db.NewMemTransaction()
 
@@ -356,6 +418,47 @@ func TestMigrateCairo1CompiledClass(t *testing.T) {
 	}
 }
 
+func TestCompressClasses(t *testing.T) {
+	testdb := pebble.NewMemTest(t)
+
+	classHash := randFelt(t)
+	key := db.Class.Key(classHash.Marshal())
+	legacy := core.DeclaredClass{
+		At: 42,
+		Class: &core.Cairo1Class{
+			Abi:             "some cairo abi",
+			AbiHash:         randFelt(t),
+			Program:         randSlice(t),
+			ProgramHash:     randFelt(t),
+			SemanticVersion: "0.1.0",
+		},
+	}
+	legacyBytes, err := encoder.Marshal(legacy)
+	require.NoError(t, err)
+	require.NoError(t, testdb.Update(func(txn db.Transaction) error {
+		return txn.Set(key, legacyBytes)
+	}))
+
+	compressor := NewClassCompressor(testdb, utils.NewNopZapLogger())
+	compressor.batchSize = 1
+	// running twice must be a no-op for already compressed classes
+	for range 2 {
+		require.NoError(t, compressor.compressAll(context.Background()))
+	}
+
+	require.NoError(t, testdb.View(func(txn db.Transaction) error {
+		require.NoError(t, txn.Get(key, func(val []byte) error {
+			assert.True(t, core.IsCompressedClassEncoding(val))
+			return nil
+		}))
+
+		got, err := core.NewState(txn).Class(classHash)
+		require.NoError(t, err)
+		assert.Equal(t, &legacy, got)
+		return nil
+	}))
+}
+
 func TestMigrateTrieNodesFromBitsetToTrieKey(t *testing.T) {
 	migrator := migrateTrieNodesFromBitsetToTrieKey(db.ClassesTrie)
 	memTxn := db.NewMemTransaction()
@@ -118,6 +118,51 @@ func (mr *MockReaderMockRecorder) BlockHeaderByNumber(arg0 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockHeaderByNumber", reflect.TypeOf((*MockReader)(nil).BlockHeaderByNumber), arg0)
 }
 
+// ClassDeclaration mocks base method.
+func (m *MockReader) ClassDeclaration(arg0 *felt.Felt) (*blockchain.ClassDeclaration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClassDeclaration", arg0)
+	ret0, _ := ret[0].(*blockchain.ClassDeclaration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClassDeclaration indicates an expected call of ClassDeclaration.
+func (mr *MockReaderMockRecorder) ClassDeclaration(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClassDeclaration", reflect.TypeOf((*MockReader)(nil).ClassDeclaration), arg0)
+}
+
+// ContractInfo mocks base method.
+func (m *MockReader) ContractInfo(arg0 *felt.Felt) (*blockchain.ContractInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContractInfo", arg0)
+	ret0, _ := ret[0].(*blockchain.ContractInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContractInfo indicates an expected call of ContractInfo.
+func (mr *MockReaderMockRecorder) ContractInfo(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContractInfo", reflect.TypeOf((*MockReader)(nil).ContractInfo), arg0)
+}
+
+// ContractsByClassHash mocks base method.
+func (m *MockReader) ContractsByClassHash(arg0, arg1 *felt.Felt, arg2 uint64) ([]*felt.Felt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContractsByClassHash", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*felt.Felt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ContractsByClassHash indicates an expected call of ContractsByClassHash.
+func (mr *MockReaderMockRecorder) ContractsByClassHash(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContractsByClassHash", reflect.TypeOf((*MockReader)(nil).ContractsByClassHash), arg0, arg1, arg2)
+}
+
 // EventFilter mocks base method.
 func (m *MockReader) EventFilter(arg0 *felt.Felt, arg1 [][]felt.Felt) (blockchain.EventFilterer, error) {
 	m.ctrl.T.Helper()
@@ -164,6 +209,22 @@ func (mr *MockReaderMockRecorder) HeadState() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeadState", reflect.TypeOf((*MockReader)(nil).HeadState))
 }
 
+// HeadStateTries mocks base method.
+func (m *MockReader) HeadStateTries() (core.StateTrieReader, func() error, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeadStateTries")
+	ret0, _ := ret[0].(core.StateTrieReader)
+	ret1, _ := ret[1].(func() error)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// HeadStateTries indicates an expected call of HeadStateTries.
+func (mr *MockReaderMockRecorder) HeadStateTries() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeadStateTries", reflect.TypeOf((*MockReader)(nil).HeadStateTries))
+}
+
 // HeadsHeader mocks base method.
 func (m *MockReader) HeadsHeader() (*core.Header, error) {
 	m.ctrl.T.Helper()
@@ -224,6 +285,111 @@ func (mr *MockReaderMockRecorder) L1Head() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "L1Head", reflect.TypeOf((*MockReader)(nil).L1Head))
 }
 
+// L1StateUpdateByBlockNumber mocks base method.
+func (m *MockReader) L1StateUpdateByBlockNumber(arg0 uint64) (*core.L1StateUpdate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "L1StateUpdateByBlockNumber", arg0)
+	ret0, _ := ret[0].(*core.L1StateUpdate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// L1StateUpdateByBlockNumber indicates an expected call of L1StateUpdateByBlockNumber.
+func (mr *MockReaderMockRecorder) L1StateUpdateByBlockNumber(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "L1StateUpdateByBlockNumber", reflect.TypeOf((*MockReader)(nil).L1StateUpdateByBlockNumber), arg0)
+}
+
+// L1ToL2Message mocks base method.
+func (m *MockReader) L1ToL2Message(arg0 common.Hash) (*core.L1ToL2MessageLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "L1ToL2Message", arg0)
+	ret0, _ := ret[0].(*core.L1ToL2MessageLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// L1ToL2Message indicates an expected call of L1ToL2Message.
+func (mr *MockReaderMockRecorder) L1ToL2Message(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "L1ToL2Message", reflect.TypeOf((*MockReader)(nil).L1ToL2Message), arg0)
+}
+
+// L1ToL2Messages mocks base method.
+func (m *MockReader) L1ToL2Messages(arg0 *felt.Felt, arg1 *core.MessageStatus, arg2 common.Hash, arg3 uint64) ([]*core.L1ToL2MessageLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "L1ToL2Messages", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*core.L1ToL2MessageLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// L1ToL2Messages indicates an expected call of L1ToL2Messages.
+func (mr *MockReaderMockRecorder) L1ToL2Messages(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "L1ToL2Messages", reflect.TypeOf((*MockReader)(nil).L1ToL2Messages), arg0, arg1, arg2, arg3)
+}
+
+// L1ToL2MessagesByL1TxnHash mocks base method.
+func (m *MockReader) L1ToL2MessagesByL1TxnHash(arg0 common.Hash) ([]*core.L1ToL2MessageLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "L1ToL2MessagesByL1TxnHash", arg0)
+	ret0, _ := ret[0].([]*core.L1ToL2MessageLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// L1ToL2MessagesByL1TxnHash indicates an expected call of L1ToL2MessagesByL1TxnHash.
+func (mr *MockReaderMockRecorder) L1ToL2MessagesByL1TxnHash(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "L1ToL2MessagesByL1TxnHash", reflect.TypeOf((*MockReader)(nil).L1ToL2MessagesByL1TxnHash), arg0)
+}
+
+// L1VerifiedHeight mocks base method.
+func (m *MockReader) L1VerifiedHeight() (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "L1VerifiedHeight")
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// L1VerifiedHeight indicates an expected call of L1VerifiedHeight.
+func (mr *MockReaderMockRecorder) L1VerifiedHeight() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "L1VerifiedHeight", reflect.TypeOf((*MockReader)(nil).L1VerifiedHeight))
+}
+
+// L2ToL1Message mocks base method.
+func (m *MockReader) L2ToL1Message(arg0 common.Hash) (*core.L2ToL1MessageLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "L2ToL1Message", arg0)
+	ret0, _ := ret[0].(*core.L2ToL1MessageLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// L2ToL1Message indicates an expected call of L2ToL1Message.
+func (mr *MockReaderMockRecorder) L2ToL1Message(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "L2ToL1Message", reflect.TypeOf((*MockReader)(nil).L2ToL1Message), arg0)
+}
+
+// L2ToL1Messages mocks base method.
+func (m *MockReader) L2ToL1Messages(arg0 *felt.Felt, arg1 *core.MessageStatus, arg2 common.Hash, arg3 uint64) ([]*core.L2ToL1MessageLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "L2ToL1Messages", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*core.L2ToL1MessageLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// L2ToL1Messages indicates an expected call of L2ToL1Messages.
+func (mr *MockReaderMockRecorder) L2ToL1Messages(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "L2ToL1Messages", reflect.TypeOf((*MockReader)(nil).L2ToL1Messages), arg0, arg1, arg2, arg3)
+}
+
 // Network mocks base method.
 func (m *MockReader) Network() *utils.Network {
 	m.ctrl.T.Helper()
@@ -360,3 +526,33 @@ func (mr *MockReaderMockRecorder) TransactionByHash(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionByHash", reflect.TypeOf((*MockReader)(nil).TransactionByHash), arg0)
 }
+
+// TransactionBySenderAndNonce mocks base method.
+func (m *MockReader) TransactionBySenderAndNonce(arg0, arg1 *felt.Felt) (*blockchain.SenderTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactionBySenderAndNonce", arg0, arg1)
+	ret0, _ := ret[0].(*blockchain.SenderTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransactionBySenderAndNonce indicates an expected call of TransactionBySenderAndNonce.
+func (mr *MockReaderMockRecorder) TransactionBySenderAndNonce(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionBySenderAndNonce", reflect.TypeOf((*MockReader)(nil).TransactionBySenderAndNonce), arg0, arg1)
+}
+
+// TransactionsBySender mocks base method.
+func (m *MockReader) TransactionsBySender(arg0, arg1 *felt.Felt, arg2 uint64) ([]blockchain.SenderTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransactionsBySender", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]blockchain.SenderTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransactionsBySender indicates an expected call of TransactionsBySender.
+func (mr *MockReaderMockRecorder) TransactionsBySender(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransactionsBySender", reflect.TypeOf((*MockReader)(nil).TransactionsBySender), arg0, arg1, arg2)
+}
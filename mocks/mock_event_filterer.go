@@ -10,6 +10,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	blockchain "github.com/NethermindEth/juno/blockchain"
@@ -55,9 +56,9 @@ func (mr *MockEventFiltererMockRecorder) Close() *gomock.Call {
 }
 
 // Events mocks base method.
-func (m *MockEventFilterer) Events(arg0 *blockchain.ContinuationToken, arg1 uint64) ([]*blockchain.FilteredEvent, *blockchain.ContinuationToken, error) {
+func (m *MockEventFilterer) Events(arg0 context.Context, arg1 *blockchain.ContinuationToken, arg2 uint64) ([]*blockchain.FilteredEvent, *blockchain.ContinuationToken, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Events", arg0, arg1)
+	ret := m.ctrl.Call(m, "Events", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]*blockchain.FilteredEvent)
 	ret1, _ := ret[1].(*blockchain.ContinuationToken)
 	ret2, _ := ret[2].(error)
@@ -65,9 +66,9 @@ func (m *MockEventFilterer) Events(arg0 *blockchain.ContinuationToken, arg1 uint
 }
 
 // Events indicates an expected call of Events.
-func (mr *MockEventFiltererMockRecorder) Events(arg0, arg1 any) *gomock.Call {
+func (mr *MockEventFiltererMockRecorder) Events(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockEventFilterer)(nil).Events), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Events", reflect.TypeOf((*MockEventFilterer)(nil).Events), arg0, arg1, arg2)
 }
 
 // SetRangeEndBlockByHash mocks base method.
@@ -71,6 +71,21 @@ func (mr *MockSubscriberMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSubscriber)(nil).Close))
 }
 
+// FilterMessageLogs mocks base method.
+func (m *MockSubscriber) FilterMessageLogs(arg0 context.Context, arg1, arg2 uint64) ([]types.Log, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FilterMessageLogs", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]types.Log)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FilterMessageLogs indicates an expected call of FilterMessageLogs.
+func (mr *MockSubscriberMockRecorder) FilterMessageLogs(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FilterMessageLogs", reflect.TypeOf((*MockSubscriber)(nil).FilterMessageLogs), arg0, arg1, arg2)
+}
+
 // FinalisedHeight mocks base method.
 func (m *MockSubscriber) FinalisedHeight(arg0 context.Context) (uint64, error) {
 	m.ctrl.T.Helper()
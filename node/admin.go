@@ -0,0 +1,127 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/rpc"
+	"github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/NethermindEth/juno/validator"
+	"github.com/mitchellh/mapstructure"
+)
+
+const redacted = "<redacted>"
+
+// secretOptions are the options left out of the config returned by the admin API. The URLs of the Ethereum
+// nodes and the remote database may embed credentials.
+var secretOptions = []string{
+	"rpc-api-keys", "rpc-jwt-secret", "gw-api-key", "p2p-private-key", "admin-api-keys", "admin-jwt-secret", "eth-node",
+	"remote-db",
+}
+
+var errNoFeederSync = errors.New("the node doesn't sync from the feeder gateway")
+
+// nodeAdmin performs the actions of the admin API on the node.
+type nodeAdmin struct {
+	cfg      *Config
+	log      *utils.ZapLogger
+	database db.DB
+	// synchronizer is nil if the node only syncs over p2p
+	synchronizer *sync.Synchronizer
+}
+
+func (a *nodeAdmin) SetLogLevel(level utils.LogLevel) (utils.LogLevel, error) {
+	previous := a.log.Level()
+	if err := a.log.SetLevel(level); err != nil {
+		return previous, err
+	}
+	a.log.Infow("Changed log level", "level", level, "previous", previous)
+	return previous, nil
+}
+
+func (a *nodeAdmin) PauseSync() (bool, error) {
+	if a.synchronizer == nil {
+		return false, errNoFeederSync
+	}
+	return a.synchronizer.Pause(), nil
+}
+
+func (a *nodeAdmin) ResumeSync() (bool, error) {
+	if a.synchronizer == nil {
+		return false, errNoFeederSync
+	}
+	return a.synchronizer.Resume(), nil
+}
+
+func (a *nodeAdmin) RevertBlocks(ctx context.Context, count uint64) (*core.Header, error) {
+	if a.synchronizer == nil {
+		return nil, errNoFeederSync
+	}
+	return a.synchronizer.RevertBlocks(ctx, count)
+}
+
+func (a *nodeAdmin) CompactDB() error {
+	compactor, ok := a.database.(interface{ Compact() error })
+	if !ok {
+		return errors.New("the database doesn't support compaction")
+	}
+
+	a.log.Infow("Compacting the database")
+	if err := compactor.Compact(); err != nil {
+		return err
+	}
+	a.log.Infow("Compacted the database")
+	return nil
+}
+
+// Config returns the options the node runs with, by name, where the values of secrets are redacted.
+func (a *nodeAdmin) Config() (map[string]any, error) {
+	config := make(map[string]any)
+	if err := mapstructure.Decode(a.cfg, &config); err != nil {
+		return nil, err
+	}
+
+	for name, value := range config {
+		// log levels, networks and durations are set by their names
+		switch value := value.(type) {
+		case utils.Network:
+			config[name] = value.String()
+		case fmt.Stringer:
+			config[name] = value.String()
+		}
+	}
+	for _, name := range secretOptions {
+		if value, ok := config[name].(string); ok && value != "" {
+			config[name] = redacted
+		}
+	}
+	return config, nil
+}
+
+// makeAdminServer makes the RPC server of the admin API, which requires the admin credentials.
+func makeAdminServer(cfg *Config, rpcHandler *rpc.Handler, maxGoroutines int, log utils.SimpleLogger,
+	version string,
+) (*jsonrpc.Server, error) {
+	if cfg.AdminAPIKeys == "" && cfg.AdminJWTSecret == "" {
+		return nil, errors.New("the admin API requires API keys or a JWT secret")
+	}
+
+	server := jsonrpc.NewServer(maxGoroutines, log).WithValidator(validator.Validator())
+	methods, path := rpcHandler.AdminMethods()
+	if err := server.RegisterMethods(methods...); err != nil {
+		return nil, err
+	}
+	info := openRPCInfo(version, path)
+	info.Title = "Juno Admin API"
+	info.Description = "The methods controlling the node, served on the admin port"
+	if err := server.RegisterDiscover(info, rpc.OpenRPCSchemas()); err != nil {
+		return nil, err
+	}
+	server.WithMiddleware(jsonrpc.AuthMiddleware(splitList(cfg.AdminAPIKeys), []byte(cfg.AdminJWTSecret)))
+	return server, nil
+}
@@ -0,0 +1,53 @@
+package node
+
+import (
+	"context"
+	stdsync "sync"
+
+	"github.com/NethermindEth/juno/service"
+)
+
+// haltableService is a service that can be stopped while the rest of the node keeps running. Since the
+// node shuts down as soon as any of its services returns, a halted service blocks until shutdown.
+type haltableService struct {
+	service.Service
+	halt     chan struct{}
+	haltOnce stdsync.Once
+}
+
+func newHaltableService(s service.Service) *haltableService {
+	return &haltableService{
+		Service: s,
+		halt:    make(chan struct{}),
+	}
+}
+
+func (s *haltableService) Halt() {
+	s.haltOnce.Do(func() {
+		close(s.halt)
+	})
+}
+
+func (s *haltableService) Run(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-s.halt:
+			cancel()
+		case <-runCtx.Done():
+		}
+	}()
+
+	if err := s.Service.Run(runCtx); err != nil {
+		return err
+	}
+
+	select {
+	case <-s.halt:
+		<-ctx.Done()
+	default:
+	}
+	return nil
+}
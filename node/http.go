@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"net"
 	"net/http"
 	"net/http/pprof"
@@ -16,6 +17,7 @@ import (
 	junogrpc "github.com/NethermindEth/juno/grpc"
 	"github.com/NethermindEth/juno/grpc/gen"
 	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/rpc"
 	"github.com/NethermindEth/juno/service"
 	"github.com/NethermindEth/juno/sync"
 	"github.com/NethermindEth/juno/utils"
@@ -77,21 +79,31 @@ func exactPathServer(path string, handler http.Handler) http.HandlerFunc {
 	}
 }
 
+// makeRPCOverHTTP serves the RPC servers on their paths, and the subscriptions of each server as server-sent
+// events on the path prefixed with /sse.
 func makeRPCOverHTTP(host string, port uint16, servers map[string]*jsonrpc.Server,
 	httpHandlers map[string]http.HandlerFunc, log utils.SimpleLogger, metricsEnabled bool, corsEnabled bool,
 ) *httpService {
-	var listener jsonrpc.NewRequestListener
+	var listener, sseListener jsonrpc.NewRequestListener
 	if metricsEnabled {
 		listener = makeHTTPMetrics()
+		sseListener = makeSSEMetrics()
 	}
 
+	shutdown := make(chan struct{})
+
 	mux := http.NewServeMux()
 	for path, server := range servers {
 		httpHandler := jsonrpc.NewHTTP(server, log)
+		sseHandler := jsonrpc.NewSSE(server, rpc.SSESubscriptions{}, shutdown, log)
 		if listener != nil {
 			httpHandler = httpHandler.WithListener(listener)
+			sseHandler = sseHandler.WithListener(sseListener)
 		}
 		mux.Handle(path, exactPathServer(path, httpHandler))
+
+		ssePrefixedPath := strings.TrimSuffix("/sse"+path, "/")
+		mux.Handle(ssePrefixedPath, exactPathServer(ssePrefixedPath, sseHandler))
 	}
 	for path, handler := range httpHandlers {
 		mux.HandleFunc(path, handler)
@@ -101,7 +113,87 @@ func makeRPCOverHTTP(host string, port uint16, servers map[string]*jsonrpc.Serve
 	if corsEnabled {
 		handler = cors.Default().Handler(handler)
 	}
-	return makeHTTPService(host, port, handler)
+
+	httpServ := makeHTTPService(host, port, handler)
+	httpServ.registerOnShutdown(func() {
+		close(shutdown)
+	})
+	return httpServ
+}
+
+// makeRPCMiddlewares returns the middlewares enforcing the access policy of the RPC servers. Methods are filtered
+// first so that requests for them don't count towards rate limits, and IP addresses are limited before
+// authentication to slow down credential guessing.
+func makeRPCMiddlewares(cfg *Config) []jsonrpc.Middleware {
+	rateLimit := func(rate float64) jsonrpc.RateLimit {
+		return jsonrpc.RateLimit{Rate: rate, Burst: max(1, int(math.Ceil(rate)))}
+	}
+
+	var middlewares []jsonrpc.Middleware
+	if cfg.RPCAllowedMethods != "" || cfg.RPCDeniedMethods != "" {
+		middlewares = append(middlewares, jsonrpc.MethodFilterMiddleware(splitList(cfg.RPCAllowedMethods), splitList(cfg.RPCDeniedMethods)))
+	}
+	if cfg.RPCIPRateLimit > 0 {
+		middlewares = append(middlewares, jsonrpc.RateLimitByIP(rateLimit(cfg.RPCIPRateLimit)))
+	}
+	if cfg.RPCAPIKeys != "" || cfg.RPCJWTSecret != "" {
+		middlewares = append(middlewares, jsonrpc.AuthMiddleware(splitList(cfg.RPCAPIKeys), []byte(cfg.RPCJWTSecret)))
+	}
+	if cfg.RPCKeyRateLimit > 0 {
+		middlewares = append(middlewares, jsonrpc.RateLimitByClient(rateLimit(cfg.RPCKeyRateLimit)))
+	}
+	return middlewares
+}
+
+// splitList splits a comma-separated list, leaving out empty items.
+func splitList(list string) []string {
+	return utils.Filter(strings.Split(list, ","), func(item string) bool {
+		return item != ""
+	})
+}
+
+// setMethodTimeouts parses timeouts, a comma-separated list of method=duration pairs, and sets the timeout of the
+// methods of every set with a listed name.
+func setMethodTimeouts(timeouts string, methodSets ...[]jsonrpc.Method) error {
+	for _, pair := range strings.Split(timeouts, ",") {
+		if pair == "" {
+			continue
+		}
+		name, durationStr, found := strings.Cut(pair, "=")
+		if !found {
+			return fmt.Errorf("invalid method timeout %q, expected method=duration", pair)
+		}
+		timeout, err := time.ParseDuration(durationStr)
+		if err != nil {
+			return fmt.Errorf("invalid timeout of method %s: %w", name, err)
+		}
+		// the context of a method is cancelled once it returns, which would end the subscriptions it starts
+		if rpc.OpensSubscription(name) {
+			return fmt.Errorf("cannot set the timeout of subscription method %s", name)
+		}
+
+		known := false
+		for _, methods := range methodSets {
+			for i := range methods {
+				if methods[i].Name == name {
+					methods[i].Timeout = timeout
+					known = true
+				}
+			}
+		}
+		if !known {
+			return fmt.Errorf("cannot set the timeout of unknown method %s", name)
+		}
+	}
+	return nil
+}
+
+func openRPCInfo(version, path string) jsonrpc.OpenRPCInfo {
+	return jsonrpc.OpenRPCInfo{
+		Title:       "Juno Starknet RPC API",
+		Version:     version,
+		Description: "The methods served by Juno on the " + path + " path",
+	}
 }
 
 func makeRPCOverWebsocket(host string, port uint16, servers map[string]*jsonrpc.Server,
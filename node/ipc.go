@@ -0,0 +1,116 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/service"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/sourcegraph/conc"
+)
+
+type ipcService struct {
+	path    string
+	perm    fs.FileMode
+	handler *jsonrpc.IPC
+}
+
+var _ service.Service = (*ipcService)(nil)
+
+func (s *ipcService) Run(ctx context.Context) error {
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(s.path)
+	stop := context.AfterFunc(ctx, func() {
+		listener.Close()
+	})
+	defer stop()
+
+	var wg conc.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			listener.Close()
+			return err
+		}
+		wg.Go(func() {
+			s.handler.ServeConn(ctx, conn)
+		})
+	}
+}
+
+// listen creates the socket, replacing the one a previous run may have left behind, and restricts who can connect
+// to it with the permissions of the file.
+func (s *ipcService) listen() (net.Listener, error) {
+	info, err := os.Lstat(s.path)
+	switch {
+	case err == nil:
+		if info.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("cannot create IPC socket at %s: file exists", s.path)
+		}
+		if err = os.Remove(s.path); err != nil {
+			return nil, err
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	}
+
+	// the socket is created in a directory only the node can access and moved into place once its permissions
+	// are set, so that it is never reachable with the permissions of the umask
+	dir, err := os.MkdirTemp(filepath.Dir(s.path), ".ipc")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	tmpPath := filepath.Join(dir, "s")
+	listener, err := net.Listen("unix", tmpPath)
+	if err != nil {
+		return nil, err
+	}
+	// the socket is removed by Run, since the listener only knows its temporary path
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err = os.Chmod(tmpPath, s.perm); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if err = os.Rename(tmpPath, s.path); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// makeRPCOverIPC serves server on a Unix domain socket at path, which only the users allowed by perm, an octal
+// file mode, can connect to.
+func makeRPCOverIPC(path, perm string, server *jsonrpc.Server, log utils.SimpleLogger,
+	metricsEnabled bool,
+) (*ipcService, error) {
+	mode, err := strconv.ParseUint(perm, 8, 32)
+	if err != nil || fs.FileMode(mode)&^fs.ModePerm != 0 {
+		return nil, fmt.Errorf("invalid IPC socket permissions %q", perm)
+	}
+
+	handler := jsonrpc.NewIPC(server, log)
+	if metricsEnabled {
+		handler = handler.WithListener(makeIPCMetrics())
+	}
+	return &ipcService{
+		path:    path,
+		perm:    fs.FileMode(mode),
+		handler: handler,
+	}, nil
+}
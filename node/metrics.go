@@ -13,8 +13,11 @@ import (
 	"github.com/NethermindEth/juno/jemalloc"
 	"github.com/NethermindEth/juno/jsonrpc"
 	"github.com/NethermindEth/juno/l1"
+	"github.com/NethermindEth/juno/p2p/reputation"
+	"github.com/NethermindEth/juno/rpc"
 	"github.com/NethermindEth/juno/sync"
 	"github.com/cockroachdb/pebble"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -110,6 +113,59 @@ func makeWSMetrics() jsonrpc.NewRequestListener {
 	}
 }
 
+func makeSSEMetrics() jsonrpc.NewRequestListener {
+	reqCounter := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "rpc",
+		Subsystem: "sse",
+		Name:      "requests",
+	})
+	prometheus.MustRegister(reqCounter)
+
+	return &jsonrpc.SelectiveListener{
+		OnNewRequestCb: func(method string) {
+			reqCounter.Inc()
+		},
+	}
+}
+
+func makeIPCMetrics() jsonrpc.NewRequestListener {
+	reqCounter := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "rpc",
+		Subsystem: "ipc",
+		Name:      "requests",
+	})
+	prometheus.MustRegister(reqCounter)
+
+	return &jsonrpc.SelectiveListener{
+		OnNewRequestCb: func(method string) {
+			reqCounter.Inc()
+		},
+	}
+}
+
+func makeSubscriptionMetrics() rpc.EventListener {
+	queued := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "rpc",
+		Subsystem: "subscriptions",
+		Name:      "queued_notifications",
+	})
+	dropped := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "rpc",
+		Subsystem: "subscriptions",
+		Name:      "dropped_notifications",
+	}, []string{"policy"})
+	prometheus.MustRegister(queued, dropped)
+
+	return &rpc.SelectiveListener{
+		OnQueuedNotificationsChangeCb: func(delta int) {
+			queued.Add(float64(delta))
+		},
+		OnNotificationsDroppedCb: func(policy rpc.OverflowPolicy, count int) {
+			dropped.WithLabelValues(string(policy)).Add(float64(count))
+		},
+	}
+}
+
 func makeRPCMetrics(version, legacyVersion string) (jsonrpc.EventListener, jsonrpc.EventListener) {
 	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "rpc",
@@ -232,6 +288,22 @@ func makeL1Metrics() l1.EventListener {
 		Name:      "request_latency",
 	}, []string{"method"})
 	prometheus.MustRegister(requestLatencies)
+	disagreements := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "l1",
+		Subsystem: "client",
+		Name:      "disagreements",
+	}, []string{"endpoint", "kind"})
+	prometheus.MustRegister(disagreements)
+	verifiedHeight := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "l1",
+		Name:      "verified_height",
+	})
+	prometheus.MustRegister(verifiedHeight)
+	mismatches := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "l1",
+		Name:      "state_root_mismatches",
+	})
+	prometheus.MustRegister(mismatches)
 
 	return l1.SelectiveListener{
 		OnNewL1HeadCb: func(head *core.L1Head) {
@@ -240,6 +312,43 @@ func makeL1Metrics() l1.EventListener {
 		OnL1CallCb: func(method string, took time.Duration) {
 			requestLatencies.WithLabelValues(method).Observe(took.Seconds())
 		},
+		OnL1DisagreementCb: func(endpoint int, kind string) {
+			disagreements.WithLabelValues(strconv.Itoa(endpoint), kind).Inc()
+		},
+		OnL1HeadVerifiedCb: func(head *core.L1Head) {
+			verifiedHeight.Set(float64(head.BlockNumber))
+		},
+		OnL1HeadMismatchCb: func(head *core.L1Head, local *core.Header) {
+			mismatches.Inc()
+		},
+	}
+}
+
+func makeReputationMetrics() reputation.EventListener {
+	scores := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "p2p",
+		Subsystem: "peer",
+		Name:      "score",
+	}, []string{"peer"})
+	prometheus.MustRegister(scores)
+	bans := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "p2p",
+		Subsystem: "peer",
+		Name:      "bans",
+	}, []string{"kind"})
+	prometheus.MustRegister(bans)
+
+	return &reputation.SelectiveListener{
+		OnPeerScoreCb: func(id peer.ID, score float64) {
+			scores.WithLabelValues(id.String()).Set(score)
+		},
+		OnPeerBannedCb: func(id peer.ID, permanently bool) {
+			kind := "temporary"
+			if permanently {
+				kind = "permanent"
+			}
+			bans.WithLabelValues(kind).Inc()
+		},
 	}
 }
 
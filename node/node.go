@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"reflect"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/semver/v3"
@@ -31,6 +32,8 @@ import (
 	"github.com/NethermindEth/juno/utils"
 	"github.com/NethermindEth/juno/validator"
 	"github.com/NethermindEth/juno/vm"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/mitchellh/mapstructure"
 	"github.com/sourcegraph/conc"
 	"google.golang.org/grpc"
@@ -54,13 +57,18 @@ type Config struct {
 	Websocket              bool           `mapstructure:"ws"`
 	WebsocketHost          string         `mapstructure:"ws-host"`
 	WebsocketPort          uint16         `mapstructure:"ws-port"`
+	IPCPath                string         `mapstructure:"ipc-path"`
+	IPCPermissions         string         `mapstructure:"ipc-permissions"`
 	GRPC                   bool           `mapstructure:"grpc"`
 	GRPCHost               string         `mapstructure:"grpc-host"`
 	GRPCPort               uint16         `mapstructure:"grpc-port"`
 	DatabasePath           string         `mapstructure:"db-path"`
 	Network                utils.Network  `mapstructure:"network"`
 	EthNode                string         `mapstructure:"eth-node"`
+	EthNodeQuorum          uint           `mapstructure:"eth-node-quorum"`
+	EthMessagesStartHeight uint64         `mapstructure:"eth-messages-start-height"`
 	DisableL1Verification  bool           `mapstructure:"disable-l1-verification"`
+	HaltSyncOnL1Mismatch   bool           `mapstructure:"halt-sync-on-l1-mismatch"`
 	Pprof                  bool           `mapstructure:"pprof"`
 	PprofHost              string         `mapstructure:"pprof-host"`
 	PprofPort              uint16         `mapstructure:"pprof-port"`
@@ -73,18 +81,31 @@ type Config struct {
 	MetricsHost string `mapstructure:"metrics-host"`
 	MetricsPort uint16 `mapstructure:"metrics-port"`
 
-	P2P           bool   `mapstructure:"p2p"`
-	P2PAddr       string `mapstructure:"p2p-addr"`
-	P2PPublicAddr string `mapstructure:"p2p-public-addr"`
-	P2PPeers      string `mapstructure:"p2p-peers"`
-	P2PFeederNode bool   `mapstructure:"p2p-feeder-node"`
-	P2PPrivateKey string `mapstructure:"p2p-private-key"`
+	P2P            bool   `mapstructure:"p2p"`
+	P2PAddr        string `mapstructure:"p2p-addr"`
+	P2PPublicAddr  string `mapstructure:"p2p-public-addr"`
+	P2PPeers       string `mapstructure:"p2p-peers"`
+	P2PFeederNode  bool   `mapstructure:"p2p-feeder-node"`
+	P2PFeederPeers string `mapstructure:"p2p-feeder-peers"`
+	P2PPrivateKey  string `mapstructure:"p2p-private-key"`
+	P2PSnapSync    bool   `mapstructure:"p2p-snap-sync"`
 
 	MaxVMs          uint `mapstructure:"max-vms"`
 	MaxVMQueue      uint `mapstructure:"max-vm-queue"`
 	RPCMaxBlockScan uint `mapstructure:"rpc-max-block-scan"`
 	RPCCallMaxSteps uint `mapstructure:"rpc-call-max-steps"`
 
+	RPCAPIKeys        string  `mapstructure:"rpc-api-keys"`
+	RPCJWTSecret      string  `mapstructure:"rpc-jwt-secret"`
+	RPCKeyRateLimit   float64 `mapstructure:"rpc-key-rate-limit"`
+	RPCIPRateLimit    float64 `mapstructure:"rpc-ip-rate-limit"`
+	RPCAllowedMethods string  `mapstructure:"rpc-allowed-methods"`
+	RPCDeniedMethods  string  `mapstructure:"rpc-denied-methods"`
+	RPCMethodTimeouts string  `mapstructure:"rpc-method-timeouts"`
+
+	RPCSubQueueSize uint   `mapstructure:"rpc-subscription-queue-size"`
+	RPCSubOverflow  string `mapstructure:"rpc-subscription-overflow-policy"`
+
 	DBCacheSize  uint `mapstructure:"db-cache-size"`
 	DBMaxHandles int  `mapstructure:"db-max-handles"`
 
@@ -92,6 +113,12 @@ type Config struct {
 	GatewayTimeout time.Duration `mapstructure:"gw-timeout"`
 
 	PluginPath string `mapstructure:"plugin-path"`
+
+	Admin          bool   `mapstructure:"admin"`
+	AdminHost      string `mapstructure:"admin-host"`
+	AdminPort      uint16 `mapstructure:"admin-port"`
+	AdminAPIKeys   string `mapstructure:"admin-api-keys"`
+	AdminJWTSecret string `mapstructure:"admin-jwt-secret"`
 }
 
 type Node struct {
@@ -170,12 +197,17 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 		services = append(services, plugin.NewService(p))
 	}
 
+	var syncServices []*haltableService
 	var p2pService *p2p.Service
 	if cfg.P2P {
 		if cfg.Network == utils.Mainnet {
 			return nil, fmt.Errorf("P2P cannot be used on %v network", utils.Mainnet)
 		}
 		log.Warnw("P2P features enabled. Please note P2P is in experimental stage")
+		// the snap sync pivot is anchored to the L1 head, without which snap sync can't start
+		if cfg.P2PSnapSync && cfg.DisableL1Verification {
+			return nil, errors.New("--p2p-snap-sync requires L1 verification to anchor the pivot to the L1 head")
+		}
 
 		if !cfg.P2PFeederNode {
 			// Do not start the feeder synchronisation
@@ -186,12 +218,28 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 		if err != nil {
 			return nil, fmt.Errorf("set up p2p service: %w", err)
 		}
+		if cfg.P2PSnapSync {
+			p2pService.WithSnapSync()
+		}
+		var feederPeers []peer.ID
+		for _, id := range splitList(cfg.P2PFeederPeers) {
+			var feederPeer peer.ID
+			if feederPeer, err = peer.Decode(id); err != nil {
+				return nil, fmt.Errorf("decode feeder peer ID %q: %w", id, err)
+			}
+			feederPeers = append(feederPeers, feederPeer)
+		}
+		p2pService.WithFeederPeers(feederPeers...)
 
-		services = append(services, p2pService)
+		syncServices = append(syncServices, newHaltableService(p2pService))
 	}
 	if synchronizer != nil {
-		services = append(services, synchronizer)
+		syncServices = append(syncServices, newHaltableService(synchronizer))
 	}
+	for _, s := range syncServices {
+		services = append(services, s)
+	}
+	services = append(services, migration.NewClassCompressor(database, log))
 
 	throttledVM := NewThrottledVM(vm.New(false, log), cfg.MaxVMs, int32(cfg.MaxVMQueue))
 
@@ -202,19 +250,45 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 
 	rpcHandler := rpc.New(chain, syncReader, throttledVM, version, log).WithGateway(gatewayClient).WithFeeder(client)
 	rpcHandler = rpcHandler.WithFilterLimit(cfg.RPCMaxBlockScan).WithCallMaxSteps(uint64(cfg.RPCCallMaxSteps))
+	if cfg.RPCSubQueueSize > 0 {
+		rpcHandler.WithSubscriptionQueueSize(int(cfg.RPCSubQueueSize))
+	}
+	if cfg.RPCSubOverflow != "" {
+		overflowPolicy, policyErr := rpc.ParseOverflowPolicy(cfg.RPCSubOverflow)
+		if policyErr != nil {
+			return nil, policyErr
+		}
+		rpcHandler.WithOverflowPolicy(overflowPolicy)
+	}
+	if p2pService != nil {
+		rpcHandler.WithPeerReputation(p2pService.Reputation()).WithPeerManager(p2pService)
+	}
 	services = append(services, rpcHandler)
 	// to improve RPC throughput we double GOMAXPROCS
 	maxGoroutines := 2 * runtime.GOMAXPROCS(0)
 	jsonrpcServer := jsonrpc.NewServer(maxGoroutines, log).WithValidator(validator.Validator())
 	methods, path := rpcHandler.Methods()
+	legacyMethods, legacyPath := rpcHandler.MethodsV0_7()
+	if err = setMethodTimeouts(cfg.RPCMethodTimeouts, methods, legacyMethods); err != nil {
+		return nil, err
+	}
 	if err = jsonrpcServer.RegisterMethods(methods...); err != nil {
 		return nil, err
 	}
 	jsonrpcServerLegacy := jsonrpc.NewServer(maxGoroutines, log).WithValidator(validator.Validator())
-	legacyMethods, legacyPath := rpcHandler.MethodsV0_7()
 	if err = jsonrpcServerLegacy.RegisterMethods(legacyMethods...); err != nil {
 		return nil, err
 	}
+	schemas := rpc.OpenRPCSchemas()
+	if err = jsonrpcServer.RegisterDiscover(openRPCInfo(version, path), schemas); err != nil {
+		return nil, err
+	}
+	if err = jsonrpcServerLegacy.RegisterDiscover(openRPCInfo(version, legacyPath), schemas); err != nil {
+		return nil, err
+	}
+	middlewares := makeRPCMiddlewares(cfg)
+	jsonrpcServer.WithMiddleware(middlewares...)
+	jsonrpcServerLegacy.WithMiddleware(middlewares...)
 	rpcServers := map[string]*jsonrpc.Server{
 		"/":                 jsonrpcServer,
 		path:                jsonrpcServer,
@@ -234,6 +308,22 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 		services = append(services,
 			makeRPCOverWebsocket(cfg.WebsocketHost, cfg.WebsocketPort, rpcServers, log, cfg.Metrics, cfg.RPCCorsEnable))
 	}
+	if cfg.Admin {
+		rpcHandler.WithNodeAdmin(&nodeAdmin{cfg: cfg, log: log, database: database, synchronizer: synchronizer})
+		adminServer, adminErr := makeAdminServer(cfg, rpcHandler, maxGoroutines, log, version)
+		if adminErr != nil {
+			return nil, adminErr
+		}
+		services = append(services, makeRPCOverHTTP(cfg.AdminHost, cfg.AdminPort, map[string]*jsonrpc.Server{"/": adminServer},
+			nil, log, false, false))
+	}
+	if cfg.IPCPath != "" {
+		ipc, ipcErr := makeRPCOverIPC(cfg.IPCPath, cfg.IPCPermissions, jsonrpcServer, log, cfg.Metrics)
+		if ipcErr != nil {
+			return nil, ipcErr
+		}
+		services = append(services, ipc)
+	}
 	var metricsService service.Service
 	if cfg.Metrics {
 		makeJeMallocMetrics()
@@ -245,6 +335,7 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 		rpcMetrics, legacyRPCMetrics := makeRPCMetrics(path, legacyPath)
 		jsonrpcServer.WithListener(rpcMetrics)
 		jsonrpcServerLegacy.WithListener(legacyRPCMetrics)
+		rpcHandler.WithListener(makeSubscriptionMetrics())
 		client.WithListener(makeFeederMetrics())
 		gatewayClient.WithListener(makeGatewayMetrics())
 		metricsService = makeMetrics(cfg.MetricsHost, cfg.MetricsPort)
@@ -256,6 +347,9 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 			p2pService.WithListener(makeSyncMetrics(&sync.NoopSynchronizer{}, chain))
 			p2pService.WithGossipTracer()
 		}
+		if p2pService != nil {
+			p2pService.Reputation().WithListener(makeReputationMetrics())
+		}
 	}
 	if cfg.GRPC {
 		services = append(services, makeGRPC(cfg.GRPCHost, cfg.GRPCPort, database, version))
@@ -281,10 +375,18 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 		}
 
 		var l1Client *l1.Client
-		l1Client, err = newL1Client(cfg.EthNode, cfg.Metrics, n.blockchain, n.log)
+		l1Client, err = newL1Client(cfg.EthNode, cfg.EthNodeQuorum, cfg.Metrics, n.blockchain, n.log)
 		if err != nil {
 			return nil, fmt.Errorf("create L1 client: %w", err)
 		}
+		l1Client.WithMessagesStartHeight(cfg.EthMessagesStartHeight)
+		if cfg.HaltSyncOnL1Mismatch {
+			l1Client.WithHaltSync(func() {
+				for _, s := range syncServices {
+					s.Halt()
+				}
+			})
+		}
 		n.services = append(n.services, l1Client)
 		rpcHandler.WithL1Client(l1Client.L1())
 	}
@@ -299,29 +401,70 @@ func New(cfg *Config, version string) (*Node, error) { //nolint:gocyclo,funlen
 	return n, nil
 }
 
-func newL1Client(ethNode string, includeMetrics bool, chain *blockchain.Blockchain, log utils.SimpleLogger) (*l1.Client, error) {
-	ethNodeURL, err := url.Parse(ethNode)
-	if err != nil {
-		return nil, fmt.Errorf("parse Ethereum node URL: %w", err)
+func newL1Client(ethNodes string, quorum uint, includeMetrics bool, chain *blockchain.Blockchain,
+	log utils.SimpleLogger,
+) (*l1.Client, error) {
+	var listener l1.EventListener = l1.SelectiveListener{}
+	if includeMetrics {
+		listener = makeL1Metrics()
 	}
-	if ethNodeURL.Scheme != "wss" && ethNodeURL.Scheme != "ws" {
-		return nil, errors.New("non-websocket Ethereum node URL (need wss://... or ws://...): " + ethNode)
+
+	urls := strings.Split(ethNodes, ",")
+	if quorum == 0 {
+		quorum = uint(len(urls)/2 + 1)
+	}
+	if quorum > uint(len(urls)) {
+		return nil, fmt.Errorf("quorum of %d Ethereum nodes exceeds the number of endpoints (%d)", quorum, len(urls))
 	}
 
 	network := chain.Network()
+	subscribers := make([]l1.Subscriber, 0, len(urls))
+	closeSubscribers := func() {
+		for _, subscriber := range subscribers {
+			subscriber.Close()
+		}
+	}
+	for _, ethNode := range urls {
+		ethSubscriber, err := newEthSubscriber(strings.TrimSpace(ethNode), network.CoreContractAddress)
+		if err != nil {
+			closeSubscribers()
+			return nil, err
+		}
+		subscribers = append(subscribers, ethSubscriber)
+	}
 
-	var ethSubscriber *l1.EthSubscriber
-	ethSubscriber, err = l1.NewEthSubscriber(ethNode, network.CoreContractAddress)
-	if err != nil {
-		return nil, fmt.Errorf("set up ethSubscriber: %w", err)
+	ethSubscriber := subscribers[0]
+	if len(subscribers) > 1 {
+		quorumSubscriber, err := l1.NewQuorumSubscriber(subscribers, int(quorum))
+		if err != nil {
+			closeSubscribers()
+			return nil, err
+		}
+		ethSubscriber = quorumSubscriber.WithEventListener(listener)
 	}
 
-	l1Client := l1.NewClient(ethSubscriber, chain, log)
+	return l1.NewClient(ethSubscriber, chain, log).WithEventListener(listener), nil
+}
 
-	if includeMetrics {
-		l1Client.WithEventListener(makeL1Metrics())
+func newEthSubscriber(ethNode string, coreContractAddress common.Address) (l1.Subscriber, error) {
+	ethNodeURL, err := url.Parse(ethNode)
+	if err != nil {
+		return nil, fmt.Errorf("parse Ethereum node URL: %w", err)
+	}
+
+	var ethSubscriber l1.Subscriber
+	switch ethNodeURL.Scheme {
+	case "ws", "wss":
+		ethSubscriber, err = l1.NewEthSubscriber(ethNode, coreContractAddress)
+	case "http", "https":
+		ethSubscriber, err = l1.NewHTTPSubscriber(ethNode, coreContractAddress)
+	default:
+		return nil, errors.New("unsupported Ethereum node URL (need ws(s)://... or http(s)://...): " + ethNode)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("set up ethSubscriber: %w", err)
 	}
-	return l1Client, nil
+	return ethSubscriber, nil
 }
 
 // Run starts Juno node by opening the DB, initialising services.
@@ -2,6 +2,8 @@ package node_test
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -38,6 +40,14 @@ func TestNewNode(t *testing.T) {
 		P2P:                   true,
 		P2PAddr:               "",
 		P2PPeers:              "",
+		RPCMethodTimeouts:     "starknet_getEvents=10s,starknet_simulateTransactions=1m",
+		IPCPath:               filepath.Join(t.TempDir(), "juno.ipc"),
+		IPCPermissions:        "0660",
+		RPCSubQueueSize:       16,
+		RPCSubOverflow:        "coalesce-heads",
+		Admin:                 true,
+		AdminPort:             0,
+		AdminAPIKeys:          "admin-key",
 	}
 
 	n, err := node.New(config, "v0.3")
@@ -48,6 +58,68 @@ func TestNewNode(t *testing.T) {
 	n.Run(ctx)
 }
 
+func TestNewNodeWithInvalidMethodTimeouts(t *testing.T) {
+	for timeouts, errString := range map[string]string{
+		"starknet_getEvents":           `invalid method timeout "starknet_getEvents", expected method=duration`,
+		"starknet_getEvents=often":     `invalid timeout of method starknet_getEvents: time: invalid duration "often"`,
+		"starknet_getEventz=10s":       "cannot set the timeout of unknown method starknet_getEventz",
+		"starknet_subscribeEvents=10s": "cannot set the timeout of subscription method starknet_subscribeEvents",
+	} {
+		t.Run(timeouts, func(t *testing.T) {
+			_, err := node.New(&node.Config{
+				DatabasePath:      t.TempDir(),
+				Network:           utils.Sepolia,
+				RPCMethodTimeouts: timeouts,
+			}, "v0.3")
+			require.EqualError(t, err, errString)
+		})
+	}
+}
+
+func TestNewNodeWithInvalidIPCPermissions(t *testing.T) {
+	for _, perm := range []string{"", "rw-------", "0800", "4755"} {
+		t.Run(perm, func(t *testing.T) {
+			_, err := node.New(&node.Config{
+				DatabasePath:   t.TempDir(),
+				Network:        utils.Sepolia,
+				IPCPath:        filepath.Join(t.TempDir(), "juno.ipc"),
+				IPCPermissions: perm,
+			}, "v0.3")
+			require.EqualError(t, err, fmt.Sprintf("invalid IPC socket permissions %q", perm))
+		})
+	}
+}
+
+func TestNewNodeWithInvalidSubscriptionOverflowPolicy(t *testing.T) {
+	_, err := node.New(&node.Config{
+		DatabasePath:   t.TempDir(),
+		Network:        utils.Sepolia,
+		RPCSubOverflow: "block",
+	}, "v0.3")
+	require.EqualError(t, err,
+		`unknown subscription overflow policy "block", expected one of "disconnect", "drop-oldest" or "coalesce-heads"`)
+}
+
+func TestNewNodeWithAdminWithoutCredentials(t *testing.T) {
+	_, err := node.New(&node.Config{
+		DatabasePath: t.TempDir(),
+		Network:      utils.Sepolia,
+		Admin:        true,
+	}, "v0.3")
+	require.EqualError(t, err, "the admin API requires API keys or a JWT secret")
+}
+
+func TestNewNodeWithSnapSyncWithoutL1Verification(t *testing.T) {
+	_, err := node.New(&node.Config{
+		DatabasePath:          t.TempDir(),
+		Network:               utils.Sepolia,
+		P2P:                   true,
+		P2PSnapSync:           true,
+		DisableL1Verification: true,
+	}, "v0.3")
+	require.EqualError(t, err, "--p2p-snap-sync requires L1 verification to anchor the pivot to the L1 head")
+}
+
 func TestNetworkVerificationOnNonEmptyDB(t *testing.T) {
 	network := utils.Integration
 	tests := map[string]struct {
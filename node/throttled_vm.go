@@ -1,6 +1,8 @@
 package node
 
 import (
+	"context"
+
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/utils"
@@ -19,27 +21,27 @@ func NewThrottledVM(res vm.VM, concurrenyBudget uint, maxQueueLen int32) *Thrott
 	}
 }
 
-func (tvm *ThrottledVM) Call(callInfo *vm.CallInfo, blockInfo *vm.BlockInfo, state core.StateReader,
+func (tvm *ThrottledVM) Call(ctx context.Context, callInfo *vm.CallInfo, blockInfo *vm.BlockInfo, state core.StateReader,
 	network *utils.Network, maxSteps uint64,
 ) ([]*felt.Felt, error) {
 	var ret []*felt.Felt
-	return ret, tvm.Do(func(vm *vm.VM) error {
+	return ret, tvm.Do(ctx, func(vm *vm.VM) error {
 		var err error
-		ret, err = (*vm).Call(callInfo, blockInfo, state, network, maxSteps)
+		ret, err = (*vm).Call(ctx, callInfo, blockInfo, state, network, maxSteps)
 		return err
 	})
 }
 
-func (tvm *ThrottledVM) Execute(txns []core.Transaction, declaredClasses []core.Class, paidFeesOnL1 []*felt.Felt,
+func (tvm *ThrottledVM) Execute(ctx context.Context, txns []core.Transaction, declaredClasses []core.Class, paidFeesOnL1 []*felt.Felt,
 	blockInfo *vm.BlockInfo, state core.StateReader, network *utils.Network, skipChargeFee, skipValidate, errOnRevert bool,
 ) ([]*felt.Felt, []core.GasConsumed, []vm.TransactionTrace, uint64, error) {
 	var ret []*felt.Felt
 	var traces []vm.TransactionTrace
 	var daGas []core.GasConsumed
 	var numSteps uint64
-	return ret, daGas, traces, numSteps, tvm.Do(func(vm *vm.VM) error {
+	return ret, daGas, traces, numSteps, tvm.Do(ctx, func(vm *vm.VM) error {
 		var err error
-		ret, daGas, traces, numSteps, err = (*vm).Execute(txns, declaredClasses, paidFeesOnL1, blockInfo, state, network,
+		ret, daGas, traces, numSteps, err = (*vm).Execute(ctx, txns, declaredClasses, paidFeesOnL1, blockInfo, state, network,
 			skipChargeFee, skipValidate, errOnRevert)
 		return err
 	})
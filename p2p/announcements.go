@@ -0,0 +1,188 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/NethermindEth/juno/adapters/core2p2p"
+	"github.com/NethermindEth/juno/adapters/p2p2core"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/p2p/gen"
+	p2pSync "github.com/NethermindEth/juno/p2p/sync"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"google.golang.org/protobuf/proto"
+)
+
+// headerHashVersion is the first Starknet version whose block hash only depends on the header.
+var headerHashVersion = semver.MustParse("0.13.2")
+
+// runBlockAnnouncements joins the block announcements topic of the network. Feeder nodes publish the
+// header of every block they store, other nodes request the announced blocks from their peers right away.
+// Announcements are signed with the key of the publishing peer, pubsub drops the ones that aren't, and
+// only the ones published by known feeder nodes are accepted.
+func (s *Service) runBlockAnnouncements(ctx context.Context) error {
+	topicName := p2pSync.BlockAnnouncementsTopic(s.network)
+	if err := s.pubsub.RegisterTopicValidator(topicName, s.validateBlockAnnouncement); err != nil {
+		return err
+	}
+
+	topic, err := s.pubsub.Join(topicName)
+	if err != nil {
+		return err
+	}
+
+	if s.feederNode {
+		go s.publishBlockAnnouncements(ctx, topic)
+		return nil
+	}
+
+	sub, err := topic.Subscribe()
+	if err != nil {
+		return errors.Join(err, topic.Close())
+	}
+	go s.receiveBlockAnnouncements(ctx, topic, sub)
+	return nil
+}
+
+func (s *Service) publishBlockAnnouncements(ctx context.Context, topic *pubsub.Topic) {
+	defer s.callAndLogErr(topic.Close, "Failed to close block announcements topic")
+
+	storedSub := s.blockchain.SubscribeStoredBlocks()
+	defer storedSub.Unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case block, ok := <-storedSub.Recv():
+			if !ok {
+				return
+			}
+
+			announcement, err := s.blockAnnouncement(block.Header)
+			if err != nil {
+				s.log.Warnw("Failed to create block announcement", "number", block.Number, "err", err)
+				continue
+			} else if announcement == nil {
+				continue
+			}
+
+			if err = topic.Publish(ctx, announcement); err != nil {
+				s.log.Debugw("Failed to publish block announcement", "number", block.Number, "err", err)
+			}
+		}
+	}
+}
+
+// blockAnnouncement returns the encoded p2p header of the given block. Headers from before Starknet 0.13.1
+// lack the prices a p2p header requires, so nil is returned for them.
+func (s *Service) blockAnnouncement(header *core.Header) ([]byte, error) {
+	if header.GasPriceSTRK == nil || header.L1DataGasPrice == nil {
+		return nil, nil
+	}
+
+	commitments, err := s.blockchain.BlockCommitmentsByNumber(header.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	stateUpdate, err := s.blockchain.StateUpdateByNumber(header.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(core2p2p.AdaptHeader(header, commitments, stateUpdate.StateDiff.Hash(), stateUpdate.StateDiff.Length()))
+}
+
+func (s *Service) receiveBlockAnnouncements(ctx context.Context, topic *pubsub.Topic, sub *pubsub.Subscription) {
+	defer s.callAndLogErr(topic.Close, "Failed to close block announcements topic")
+	defer sub.Cancel()
+
+	for {
+		msg, err := sub.Next(ctx)
+		if err != nil {
+			return
+		}
+
+		header := msg.ValidatorData.(*gen.SignedBlockHeader)
+		s.log.Debugw("Received block announcement", "number", header.Number, "from", msg.ReceivedFrom)
+		s.onBlockAnnouncement(header.Number)
+	}
+}
+
+// validateBlockAnnouncement rejects malformed announcements and the ones whose hash doesn't match the
+// header, and ignores the ones that weren't published by a known feeder node or are of blocks the node
+// already has, so that they are not propagated any further.
+func (s *Service) validateBlockAnnouncement(_ context.Context, _ peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+	header := new(gen.SignedBlockHeader)
+	if err := proto.Unmarshal(msg.Data, header); err != nil {
+		return pubsub.ValidationReject
+	}
+	if header.BlockHash == nil || header.ParentHash == nil || header.StateRoot == nil {
+		return pubsub.ValidationReject
+	}
+	msg.ValidatorData = header
+
+	if msg.ReceivedFrom == s.host.ID() {
+		return pubsub.ValidationAccept
+	}
+	// other feeder nodes may be known to the peer that forwarded the announcement, so it isn't penalised
+	if _, ok := s.feederPeers[msg.GetFrom()]; !ok {
+		return pubsub.ValidationIgnore
+	}
+	if !announcedHashMatches(header) {
+		return pubsub.ValidationReject
+	}
+
+	head, err := s.blockchain.HeadsHeader()
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return pubsub.ValidationAccept
+		}
+		return pubsub.ValidationIgnore
+	}
+
+	if header.Number <= head.Number {
+		return pubsub.ValidationIgnore
+	} else if header.Number == head.Number+1 && !p2p2core.AdaptHash(header.ParentHash).Equal(head.Hash) {
+		return pubsub.ValidationIgnore
+	}
+	return pubsub.ValidationAccept
+}
+
+// announcedHashMatches reports whether the hash of an announced header matches its content. The hashes of
+// blocks before Starknet 0.13.2 can't be computed from the header alone, they are verified when the block is
+// fetched, and so are the ones core.HeaderHash can't compute from a p2p header.
+func announcedHashMatches(header *gen.SignedBlockHeader) bool {
+	version, err := core.ParseBlockVersion(header.ProtocolVersion)
+	if err != nil {
+		return false
+	}
+	if version.LessThan(headerHashVersion) {
+		return true
+	}
+
+	if header.SequencerAddress == nil || header.Transactions.GetRoot() == nil || header.Events.GetRoot() == nil ||
+		header.Receipts == nil || header.StateDiffCommitment.GetRoot() == nil || header.GasPriceFri == nil ||
+		header.GasPriceWei == nil || header.DataGasPriceFri == nil || header.DataGasPriceWei == nil {
+		return false
+	}
+	if header.L1DataAvailabilityMode != gen.L1DataAvailabilityMode_Calldata &&
+		header.L1DataAvailabilityMode != gen.L1DataAvailabilityMode_Blob {
+		return false
+	}
+
+	hash, err := core.HeaderHash(p2p2core.AdaptBlockHeader(header, nil), &core.BlockCommitments{
+		TransactionCommitment: p2p2core.AdaptHash(header.Transactions.Root),
+		EventCommitment:       p2p2core.AdaptHash(header.Events.Root),
+		ReceiptCommitment:     p2p2core.AdaptHash(header.Receipts),
+		StateDiffCommitment:   p2p2core.AdaptHash(header.StateDiffCommitment.Root),
+	}, header.StateDiffCommitment.StateDiffLength)
+	if err != nil {
+		return errors.Is(err, core.ErrUnverifiableHeader)
+	}
+	return hash.Equal(p2p2core.AdaptHash(header.BlockHash))
+}
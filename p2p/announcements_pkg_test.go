@@ -0,0 +1,131 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/adapters/core2p2p"
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db/pebble"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	pubsubpb "github.com/libp2p/go-libp2p-pubsub/pb"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBlockAnnouncements(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	network := &utils.Sepolia
+	gw := adaptfeeder.New(feeder.NewTestClient(t, network))
+	block0, err := gw.BlockByNumber(ctx, 0)
+	require.NoError(t, err)
+	stateUpdate0, err := gw.StateUpdate(ctx, 0)
+	require.NoError(t, err)
+	// p2p headers carry the prices introduced in Starknet 0.13.1
+	block0.GasPriceSTRK = new(felt.Felt).SetUint64(1)
+	block0.L1DataGasPrice = &core.GasPrice{PriceInWei: new(felt.Felt).SetUint64(2), PriceInFri: new(felt.Felt).SetUint64(3)}
+
+	mn, err := mocknet.FullMeshConnected(2)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, mn.Close()) })
+	hosts := mn.Hosts()
+
+	newService := func(i int, feederNode bool) (*Service, *blockchain.Blockchain) {
+		database := pebble.NewMemTest(t)
+		chain := blockchain.New(database, network, nil)
+		s, err := NewWithHost(hosts[i], "", feederNode, chain, network, utils.NewNopZapLogger(), database)
+		require.NoError(t, err)
+		s.pubsub, err = pubsub.NewGossipSub(ctx, hosts[i])
+		require.NoError(t, err)
+		return s, chain
+	}
+	publisher, publisherChain := newService(0, true)
+	receiver, receiverChain := newService(1, false)
+	receiver.WithFeederPeers(hosts[0].ID())
+
+	announced := make(chan uint64, 1)
+	receiver.onBlockAnnouncement = func(blockNumber uint64) {
+		announced <- blockNumber
+	}
+	require.NoError(t, publisher.runBlockAnnouncements(ctx))
+	require.NoError(t, receiver.runBlockAnnouncements(ctx))
+
+	topic := "/starknet/SN_SEPOLIA/block_announcements/0.1.0-rc.0"
+	require.Eventually(t, func() bool {
+		return len(publisher.pubsub.ListPeers(topic)) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	t.Run("stored blocks are announced", func(t *testing.T) {
+		require.NoError(t, publisherChain.Store(block0, &core.BlockCommitments{}, stateUpdate0, nil))
+
+		select {
+		case blockNumber := <-announced:
+			require.Equal(t, uint64(0), blockNumber)
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "block was not announced")
+		}
+	})
+
+	t.Run("malformed announcements are rejected", func(t *testing.T) {
+		msg := &pubsub.Message{Message: &pubsubpb.Message{Data: []byte("not a header")}, ReceivedFrom: hosts[0].ID()}
+		require.Equal(t, pubsub.ValidationReject, receiver.validateBlockAnnouncement(ctx, hosts[0].ID(), msg))
+
+		data, err := proto.Marshal(core2p2p.AdaptHeader(block0.Header, &core.BlockCommitments{},
+			stateUpdate0.StateDiff.Hash(), stateUpdate0.StateDiff.Length()))
+		require.NoError(t, err)
+		msg = &pubsub.Message{Message: &pubsubpb.Message{Data: data, From: []byte(hosts[0].ID())}, ReceivedFrom: hosts[0].ID()}
+		require.Equal(t, pubsub.ValidationAccept, receiver.validateBlockAnnouncement(ctx, hosts[0].ID(), msg))
+	})
+
+	t.Run("announcements of unknown publishers are ignored", func(t *testing.T) {
+		data, err := proto.Marshal(core2p2p.AdaptHeader(block0.Header, &core.BlockCommitments{},
+			stateUpdate0.StateDiff.Hash(), stateUpdate0.StateDiff.Length()))
+		require.NoError(t, err)
+		msg := &pubsub.Message{Message: &pubsubpb.Message{Data: data, From: []byte(hosts[1].ID())}, ReceivedFrom: hosts[0].ID()}
+		require.Equal(t, pubsub.ValidationIgnore, receiver.validateBlockAnnouncement(ctx, hosts[0].ID(), msg))
+	})
+
+	t.Run("announcements whose hash doesn't match the header are rejected", func(t *testing.T) {
+		header := *block0.Header
+		header.ProtocolVersion = "0.13.2"
+		header.SequencerAddress = new(felt.Felt).SetUint64(4)
+		commitments := &core.BlockCommitments{
+			TransactionCommitment: new(felt.Felt).SetUint64(5),
+			EventCommitment:       new(felt.Felt).SetUint64(6),
+			ReceiptCommitment:     new(felt.Felt).SetUint64(7),
+			StateDiffCommitment:   new(felt.Felt).SetUint64(8),
+		}
+		var err error
+		header.Hash, err = core.HeaderHash(&header, commitments, 9)
+		require.NoError(t, err)
+
+		announcement := func(header *core.Header) *pubsub.Message {
+			data, err := proto.Marshal(core2p2p.AdaptHeader(header, commitments, commitments.StateDiffCommitment, 9))
+			require.NoError(t, err)
+			return &pubsub.Message{Message: &pubsubpb.Message{Data: data, From: []byte(hosts[0].ID())}, ReceivedFrom: hosts[0].ID()}
+		}
+		require.Equal(t, pubsub.ValidationAccept, receiver.validateBlockAnnouncement(ctx, hosts[0].ID(), announcement(&header)))
+
+		header.Hash = new(felt.Felt).SetUint64(1)
+		require.Equal(t, pubsub.ValidationReject, receiver.validateBlockAnnouncement(ctx, hosts[0].ID(), announcement(&header)))
+	})
+
+	t.Run("announcements of known blocks are ignored", func(t *testing.T) {
+		require.NoError(t, receiverChain.Store(block0, &core.BlockCommitments{}, stateUpdate0, nil))
+
+		data, err := proto.Marshal(core2p2p.AdaptHeader(block0.Header, &core.BlockCommitments{},
+			stateUpdate0.StateDiff.Hash(), stateUpdate0.StateDiff.Length()))
+		require.NoError(t, err)
+		msg := &pubsub.Message{Message: &pubsubpb.Message{Data: data, From: []byte(hosts[0].ID())}, ReceivedFrom: hosts[0].ID()}
+		require.Equal(t, pubsub.ValidationIgnore, receiver.validateBlockAnnouncement(ctx, hosts[0].ID(), msg))
+	})
+}
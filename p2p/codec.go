@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/NethermindEth/juno/p2p/reputation"
 	"github.com/fxamacker/cbor/v2"
 	"github.com/multiformats/go-multiaddr"
 )
@@ -41,3 +42,52 @@ func decodeAddrs(b []byte) ([]multiaddr.Multiaddr, error) {
 
 	return addrs, nil
 }
+
+// peerRecord is what is stored for a peer in the [db.Peer] bucket. Older versions stored the encoded
+// addresses only.
+type peerRecord struct {
+	Addrs      [][]byte
+	Reputation reputation.Record
+}
+
+// encodePeer encodes the addresses and the reputation of a peer
+func encodePeer(addrs []multiaddr.Multiaddr, rep reputation.Record) ([]byte, error) {
+	record := peerRecord{
+		Addrs:      make([][]byte, len(addrs)),
+		Reputation: rep,
+	}
+	for i, addr := range addrs {
+		record.Addrs[i] = addr.Bytes()
+	}
+
+	var buf bytes.Buffer
+	if err := cbor.NewEncoder(&buf).Encode(record); err != nil {
+		return nil, fmt.Errorf("encode peer: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodePeer decodes the addresses and the reputation of a peer, peers stored by older versions have
+// no reputation.
+func decodePeer(b []byte) ([]multiaddr.Multiaddr, reputation.Record, error) {
+	var record peerRecord
+	if err := cbor.NewDecoder(bytes.NewReader(b)).Decode(&record); err != nil {
+		addrs, legacyErr := decodeAddrs(b)
+		if legacyErr != nil {
+			return nil, reputation.Record{}, fmt.Errorf("decode peer: %w", err)
+		}
+		return addrs, reputation.Record{}, nil
+	}
+
+	addrs := make([]multiaddr.Multiaddr, 0, len(record.Addrs))
+	for _, addrBytes := range record.Addrs {
+		addr, err := multiaddr.NewMultiaddrBytes(addrBytes)
+		if err != nil {
+			return nil, reputation.Record{}, fmt.Errorf("parse multiaddr: %w", err)
+		}
+		addrs = append(addrs, addr)
+	}
+
+	return addrs, record.Reputation, nil
+}
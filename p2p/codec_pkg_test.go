@@ -0,0 +1,42 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/p2p/reputation"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecodePeer(t *testing.T) {
+	addrs := []multiaddr.Multiaddr{
+		multiaddr.StringCast("/ip4/127.0.0.1/tcp/7777"),
+		multiaddr.StringCast("/ip4/10.0.0.1/tcp/7778"),
+	}
+
+	t.Run("addresses and reputation", func(t *testing.T) {
+		record := reputation.Record{Score: -12.5, Bans: 2, BannedUntil: 1_700_000_000}
+		encoded, err := encodePeer(addrs, record)
+		require.NoError(t, err)
+
+		decodedAddrs, decodedRecord, err := decodePeer(encoded)
+		require.NoError(t, err)
+		require.Equal(t, addrs, decodedAddrs)
+		require.Equal(t, record, decodedRecord)
+	})
+
+	t.Run("addresses stored by older versions", func(t *testing.T) {
+		encoded, err := EncodeAddrs(addrs)
+		require.NoError(t, err)
+
+		decodedAddrs, decodedRecord, err := decodePeer(encoded)
+		require.NoError(t, err)
+		require.Equal(t, addrs, decodedAddrs)
+		require.Equal(t, reputation.Record{}, decodedRecord)
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		_, _, err := decodePeer([]byte{0xff, 0x01})
+		require.Error(t, err)
+	})
+}
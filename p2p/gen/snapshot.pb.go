@@ -0,0 +1,1284 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.0
+// 	protoc        (unknown)
+// source: snapshot.proto
+
+package gen
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// A node of a Patricia trie on the path from the root to the first or last leaf of a range.
+type PatriciaNode struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Node:
+	//
+	//	*PatriciaNode_Edge_
+	//	*PatriciaNode_Binary_
+	Node          isPatriciaNode_Node `protobuf_oneof:"node"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatriciaNode) Reset() {
+	*x = PatriciaNode{}
+	mi := &file_snapshot_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatriciaNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatriciaNode) ProtoMessage() {}
+
+func (x *PatriciaNode) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatriciaNode.ProtoReflect.Descriptor instead.
+func (*PatriciaNode) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PatriciaNode) GetNode() isPatriciaNode_Node {
+	if x != nil {
+		return x.Node
+	}
+	return nil
+}
+
+func (x *PatriciaNode) GetEdge() *PatriciaNode_Edge {
+	if x != nil {
+		if x, ok := x.Node.(*PatriciaNode_Edge_); ok {
+			return x.Edge
+		}
+	}
+	return nil
+}
+
+func (x *PatriciaNode) GetBinary() *PatriciaNode_Binary {
+	if x != nil {
+		if x, ok := x.Node.(*PatriciaNode_Binary_); ok {
+			return x.Binary
+		}
+	}
+	return nil
+}
+
+type isPatriciaNode_Node interface {
+	isPatriciaNode_Node()
+}
+
+type PatriciaNode_Edge_ struct {
+	Edge *PatriciaNode_Edge `protobuf:"bytes,1,opt,name=edge,proto3,oneof"`
+}
+
+type PatriciaNode_Binary_ struct {
+	Binary *PatriciaNode_Binary `protobuf:"bytes,2,opt,name=binary,proto3,oneof"`
+}
+
+func (*PatriciaNode_Edge_) isPatriciaNode_Node() {}
+
+func (*PatriciaNode_Binary_) isPatriciaNode_Node() {}
+
+// Proves that a range of leaves holds every leaf of the trie between its first and last keys.
+type PatriciaRangeProof struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nodes         []*PatriciaNode        `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatriciaRangeProof) Reset() {
+	*x = PatriciaRangeProof{}
+	mi := &file_snapshot_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatriciaRangeProof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatriciaRangeProof) ProtoMessage() {}
+
+func (x *PatriciaRangeProof) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatriciaRangeProof.ProtoReflect.Descriptor instead.
+func (*PatriciaRangeProof) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PatriciaRangeProof) GetNodes() []*PatriciaNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type ContractState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       *Address               `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	ClassHash     *Hash                  `protobuf:"bytes,2,opt,name=class_hash,json=classHash,proto3" json:"class_hash,omitempty"`
+	Nonce         *Felt252               `protobuf:"bytes,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	StorageRoot   *Hash                  `protobuf:"bytes,4,opt,name=storage_root,json=storageRoot,proto3" json:"storage_root,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContractState) Reset() {
+	*x = ContractState{}
+	mi := &file_snapshot_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContractState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContractState) ProtoMessage() {}
+
+func (x *ContractState) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContractState.ProtoReflect.Descriptor instead.
+func (*ContractState) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ContractState) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *ContractState) GetClassHash() *Hash {
+	if x != nil {
+		return x.ClassHash
+	}
+	return nil
+}
+
+func (x *ContractState) GetNonce() *Felt252 {
+	if x != nil {
+		return x.Nonce
+	}
+	return nil
+}
+
+func (x *ContractState) GetStorageRoot() *Hash {
+	if x != nil {
+		return x.StorageRoot
+	}
+	return nil
+}
+
+// Peers only serve the state of their latest block. A request for any other state is answered with a Fin alone.
+// Ranges are sent in order, each one starting right after the last key of the previous one, or at start for the
+// first one. The last range may end after end, so that it proves that no key between its first key and end is
+// missing. The last range is empty if no key follows its start. Peers may send fewer ranges than needed to reach end,
+// the remaining ones are requested again from the key following the last one received.
+type ContractRangeRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	StateRoot      *Hash                  `protobuf:"bytes,1,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
+	Start          *Address               `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End            *Address               `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	ChunksPerProof uint32                 `protobuf:"varint,4,opt,name=chunks_per_proof,json=chunksPerProof,proto3" json:"chunks_per_proof,omitempty"` // the number of contracts in each range
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ContractRangeRequest) Reset() {
+	*x = ContractRangeRequest{}
+	mi := &file_snapshot_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContractRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContractRangeRequest) ProtoMessage() {}
+
+func (x *ContractRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContractRangeRequest.ProtoReflect.Descriptor instead.
+func (*ContractRangeRequest) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ContractRangeRequest) GetStateRoot() *Hash {
+	if x != nil {
+		return x.StateRoot
+	}
+	return nil
+}
+
+func (x *ContractRangeRequest) GetStart() *Address {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *ContractRangeRequest) GetEnd() *Address {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *ContractRangeRequest) GetChunksPerProof() uint32 {
+	if x != nil {
+		return x.ChunksPerProof
+	}
+	return 0
+}
+
+type ContractRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContractsRoot *Hash                  `protobuf:"bytes,1,opt,name=contracts_root,json=contractsRoot,proto3" json:"contracts_root,omitempty"`
+	ClassesRoot   *Hash                  `protobuf:"bytes,2,opt,name=classes_root,json=classesRoot,proto3" json:"classes_root,omitempty"`
+	States        []*ContractState       `protobuf:"bytes,3,rep,name=states,proto3" json:"states,omitempty"`
+	RangeProof    *PatriciaRangeProof    `protobuf:"bytes,4,opt,name=range_proof,json=rangeProof,proto3" json:"range_proof,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContractRange) Reset() {
+	*x = ContractRange{}
+	mi := &file_snapshot_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContractRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContractRange) ProtoMessage() {}
+
+func (x *ContractRange) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContractRange.ProtoReflect.Descriptor instead.
+func (*ContractRange) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ContractRange) GetContractsRoot() *Hash {
+	if x != nil {
+		return x.ContractsRoot
+	}
+	return nil
+}
+
+func (x *ContractRange) GetClassesRoot() *Hash {
+	if x != nil {
+		return x.ClassesRoot
+	}
+	return nil
+}
+
+func (x *ContractRange) GetStates() []*ContractState {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *ContractRange) GetRangeProof() *PatriciaRangeProof {
+	if x != nil {
+		return x.RangeProof
+	}
+	return nil
+}
+
+type ContractRangeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to ContractRangeMessage:
+	//
+	//	*ContractRangeResponse_Range
+	//	*ContractRangeResponse_Fin
+	ContractRangeMessage isContractRangeResponse_ContractRangeMessage `protobuf_oneof:"contract_range_message"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *ContractRangeResponse) Reset() {
+	*x = ContractRangeResponse{}
+	mi := &file_snapshot_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContractRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContractRangeResponse) ProtoMessage() {}
+
+func (x *ContractRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContractRangeResponse.ProtoReflect.Descriptor instead.
+func (*ContractRangeResponse) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ContractRangeResponse) GetContractRangeMessage() isContractRangeResponse_ContractRangeMessage {
+	if x != nil {
+		return x.ContractRangeMessage
+	}
+	return nil
+}
+
+func (x *ContractRangeResponse) GetRange() *ContractRange {
+	if x != nil {
+		if x, ok := x.ContractRangeMessage.(*ContractRangeResponse_Range); ok {
+			return x.Range
+		}
+	}
+	return nil
+}
+
+func (x *ContractRangeResponse) GetFin() *Fin {
+	if x != nil {
+		if x, ok := x.ContractRangeMessage.(*ContractRangeResponse_Fin); ok {
+			return x.Fin
+		}
+	}
+	return nil
+}
+
+type isContractRangeResponse_ContractRangeMessage interface {
+	isContractRangeResponse_ContractRangeMessage()
+}
+
+type ContractRangeResponse_Range struct {
+	Range *ContractRange `protobuf:"bytes,1,opt,name=range,proto3,oneof"`
+}
+
+type ContractRangeResponse_Fin struct {
+	Fin *Fin `protobuf:"bytes,2,opt,name=fin,proto3,oneof"`
+}
+
+func (*ContractRangeResponse_Range) isContractRangeResponse_ContractRangeMessage() {}
+
+func (*ContractRangeResponse_Fin) isContractRangeResponse_ContractRangeMessage() {}
+
+// Only Cairo 1 classes are committed to in the classes trie.
+type ClassRangeRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	StateRoot      *Hash                  `protobuf:"bytes,1,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
+	Start          *Hash                  `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`
+	End            *Hash                  `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`
+	ChunksPerProof uint32                 `protobuf:"varint,4,opt,name=chunks_per_proof,json=chunksPerProof,proto3" json:"chunks_per_proof,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ClassRangeRequest) Reset() {
+	*x = ClassRangeRequest{}
+	mi := &file_snapshot_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClassRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClassRangeRequest) ProtoMessage() {}
+
+func (x *ClassRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClassRangeRequest.ProtoReflect.Descriptor instead.
+func (*ClassRangeRequest) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ClassRangeRequest) GetStateRoot() *Hash {
+	if x != nil {
+		return x.StateRoot
+	}
+	return nil
+}
+
+func (x *ClassRangeRequest) GetStart() *Hash {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *ClassRangeRequest) GetEnd() *Hash {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *ClassRangeRequest) GetChunksPerProof() uint32 {
+	if x != nil {
+		return x.ChunksPerProof
+	}
+	return 0
+}
+
+type ClassRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ContractsRoot *Hash                  `protobuf:"bytes,1,opt,name=contracts_root,json=contractsRoot,proto3" json:"contracts_root,omitempty"`
+	ClassesRoot   *Hash                  `protobuf:"bytes,2,opt,name=classes_root,json=classesRoot,proto3" json:"classes_root,omitempty"`
+	Classes       []*DeclaredClass       `protobuf:"bytes,3,rep,name=classes,proto3" json:"classes,omitempty"`
+	RangeProof    *PatriciaRangeProof    `protobuf:"bytes,4,opt,name=range_proof,json=rangeProof,proto3" json:"range_proof,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClassRange) Reset() {
+	*x = ClassRange{}
+	mi := &file_snapshot_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClassRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClassRange) ProtoMessage() {}
+
+func (x *ClassRange) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClassRange.ProtoReflect.Descriptor instead.
+func (*ClassRange) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ClassRange) GetContractsRoot() *Hash {
+	if x != nil {
+		return x.ContractsRoot
+	}
+	return nil
+}
+
+func (x *ClassRange) GetClassesRoot() *Hash {
+	if x != nil {
+		return x.ClassesRoot
+	}
+	return nil
+}
+
+func (x *ClassRange) GetClasses() []*DeclaredClass {
+	if x != nil {
+		return x.Classes
+	}
+	return nil
+}
+
+func (x *ClassRange) GetRangeProof() *PatriciaRangeProof {
+	if x != nil {
+		return x.RangeProof
+	}
+	return nil
+}
+
+type ClassRangeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to ClassRangeMessage:
+	//
+	//	*ClassRangeResponse_Range
+	//	*ClassRangeResponse_Fin
+	ClassRangeMessage isClassRangeResponse_ClassRangeMessage `protobuf_oneof:"class_range_message"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ClassRangeResponse) Reset() {
+	*x = ClassRangeResponse{}
+	mi := &file_snapshot_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClassRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClassRangeResponse) ProtoMessage() {}
+
+func (x *ClassRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClassRangeResponse.ProtoReflect.Descriptor instead.
+func (*ClassRangeResponse) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ClassRangeResponse) GetClassRangeMessage() isClassRangeResponse_ClassRangeMessage {
+	if x != nil {
+		return x.ClassRangeMessage
+	}
+	return nil
+}
+
+func (x *ClassRangeResponse) GetRange() *ClassRange {
+	if x != nil {
+		if x, ok := x.ClassRangeMessage.(*ClassRangeResponse_Range); ok {
+			return x.Range
+		}
+	}
+	return nil
+}
+
+func (x *ClassRangeResponse) GetFin() *Fin {
+	if x != nil {
+		if x, ok := x.ClassRangeMessage.(*ClassRangeResponse_Fin); ok {
+			return x.Fin
+		}
+	}
+	return nil
+}
+
+type isClassRangeResponse_ClassRangeMessage interface {
+	isClassRangeResponse_ClassRangeMessage()
+}
+
+type ClassRangeResponse_Range struct {
+	Range *ClassRange `protobuf:"bytes,1,opt,name=range,proto3,oneof"`
+}
+
+type ClassRangeResponse_Fin struct {
+	Fin *Fin `protobuf:"bytes,2,opt,name=fin,proto3,oneof"`
+}
+
+func (*ClassRangeResponse_Range) isClassRangeResponse_ClassRangeMessage() {}
+
+func (*ClassRangeResponse_Fin) isClassRangeResponse_ClassRangeMessage() {}
+
+type ContractStorageRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	StateRoot      *Hash                  `protobuf:"bytes,1,opt,name=state_root,json=stateRoot,proto3" json:"state_root,omitempty"`
+	Address        *Address               `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Start          *Felt252               `protobuf:"bytes,3,opt,name=start,proto3" json:"start,omitempty"`
+	End            *Felt252               `protobuf:"bytes,4,opt,name=end,proto3" json:"end,omitempty"`
+	ChunksPerProof uint32                 `protobuf:"varint,5,opt,name=chunks_per_proof,json=chunksPerProof,proto3" json:"chunks_per_proof,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ContractStorageRequest) Reset() {
+	*x = ContractStorageRequest{}
+	mi := &file_snapshot_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContractStorageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContractStorageRequest) ProtoMessage() {}
+
+func (x *ContractStorageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContractStorageRequest.ProtoReflect.Descriptor instead.
+func (*ContractStorageRequest) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ContractStorageRequest) GetStateRoot() *Hash {
+	if x != nil {
+		return x.StateRoot
+	}
+	return nil
+}
+
+func (x *ContractStorageRequest) GetAddress() *Address {
+	if x != nil {
+		return x.Address
+	}
+	return nil
+}
+
+func (x *ContractStorageRequest) GetStart() *Felt252 {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *ContractStorageRequest) GetEnd() *Felt252 {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *ContractStorageRequest) GetChunksPerProof() uint32 {
+	if x != nil {
+		return x.ChunksPerProof
+	}
+	return 0
+}
+
+type ContractStorage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []*ContractStoredValue `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	RangeProof    *PatriciaRangeProof    `protobuf:"bytes,2,opt,name=range_proof,json=rangeProof,proto3" json:"range_proof,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ContractStorage) Reset() {
+	*x = ContractStorage{}
+	mi := &file_snapshot_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContractStorage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContractStorage) ProtoMessage() {}
+
+func (x *ContractStorage) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContractStorage.ProtoReflect.Descriptor instead.
+func (*ContractStorage) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ContractStorage) GetValues() []*ContractStoredValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *ContractStorage) GetRangeProof() *PatriciaRangeProof {
+	if x != nil {
+		return x.RangeProof
+	}
+	return nil
+}
+
+type ContractStorageResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to ContractStorageMessage:
+	//
+	//	*ContractStorageResponse_Storage
+	//	*ContractStorageResponse_Fin
+	ContractStorageMessage isContractStorageResponse_ContractStorageMessage `protobuf_oneof:"contract_storage_message"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *ContractStorageResponse) Reset() {
+	*x = ContractStorageResponse{}
+	mi := &file_snapshot_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ContractStorageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ContractStorageResponse) ProtoMessage() {}
+
+func (x *ContractStorageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ContractStorageResponse.ProtoReflect.Descriptor instead.
+func (*ContractStorageResponse) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ContractStorageResponse) GetContractStorageMessage() isContractStorageResponse_ContractStorageMessage {
+	if x != nil {
+		return x.ContractStorageMessage
+	}
+	return nil
+}
+
+func (x *ContractStorageResponse) GetStorage() *ContractStorage {
+	if x != nil {
+		if x, ok := x.ContractStorageMessage.(*ContractStorageResponse_Storage); ok {
+			return x.Storage
+		}
+	}
+	return nil
+}
+
+func (x *ContractStorageResponse) GetFin() *Fin {
+	if x != nil {
+		if x, ok := x.ContractStorageMessage.(*ContractStorageResponse_Fin); ok {
+			return x.Fin
+		}
+	}
+	return nil
+}
+
+type isContractStorageResponse_ContractStorageMessage interface {
+	isContractStorageResponse_ContractStorageMessage()
+}
+
+type ContractStorageResponse_Storage struct {
+	Storage *ContractStorage `protobuf:"bytes,1,opt,name=storage,proto3,oneof"`
+}
+
+type ContractStorageResponse_Fin struct {
+	Fin *Fin `protobuf:"bytes,2,opt,name=fin,proto3,oneof"`
+}
+
+func (*ContractStorageResponse_Storage) isContractStorageResponse_ContractStorageMessage() {}
+
+func (*ContractStorageResponse_Fin) isContractStorageResponse_ContractStorageMessage() {}
+
+// Answered with a ClassesResponse per class the peer knows, in the order of the request, followed by a Fin.
+type ClassHashesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClassHashes   []*Hash                `protobuf:"bytes,1,rep,name=class_hashes,json=classHashes,proto3" json:"class_hashes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClassHashesRequest) Reset() {
+	*x = ClassHashesRequest{}
+	mi := &file_snapshot_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClassHashesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClassHashesRequest) ProtoMessage() {}
+
+func (x *ClassHashesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClassHashesRequest.ProtoReflect.Descriptor instead.
+func (*ClassHashesRequest) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ClassHashesRequest) GetClassHashes() []*Hash {
+	if x != nil {
+		return x.ClassHashes
+	}
+	return nil
+}
+
+type PatriciaNode_Edge struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Length        uint32                 `protobuf:"varint,1,opt,name=length,proto3" json:"length,omitempty"`
+	Path          *Felt252               `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"` // as many bits of the path as length, the remaining bits are 0
+	Child         *Hash                  `protobuf:"bytes,3,opt,name=child,proto3" json:"child,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatriciaNode_Edge) Reset() {
+	*x = PatriciaNode_Edge{}
+	mi := &file_snapshot_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatriciaNode_Edge) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatriciaNode_Edge) ProtoMessage() {}
+
+func (x *PatriciaNode_Edge) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatriciaNode_Edge.ProtoReflect.Descriptor instead.
+func (*PatriciaNode_Edge) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *PatriciaNode_Edge) GetLength() uint32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *PatriciaNode_Edge) GetPath() *Felt252 {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *PatriciaNode_Edge) GetChild() *Hash {
+	if x != nil {
+		return x.Child
+	}
+	return nil
+}
+
+type PatriciaNode_Binary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Left          *Hash                  `protobuf:"bytes,1,opt,name=left,proto3" json:"left,omitempty"`
+	Right         *Hash                  `protobuf:"bytes,2,opt,name=right,proto3" json:"right,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PatriciaNode_Binary) Reset() {
+	*x = PatriciaNode_Binary{}
+	mi := &file_snapshot_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PatriciaNode_Binary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PatriciaNode_Binary) ProtoMessage() {}
+
+func (x *PatriciaNode_Binary) ProtoReflect() protoreflect.Message {
+	mi := &file_snapshot_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PatriciaNode_Binary.ProtoReflect.Descriptor instead.
+func (*PatriciaNode_Binary) Descriptor() ([]byte, []int) {
+	return file_snapshot_proto_rawDescGZIP(), []int{0, 1}
+}
+
+func (x *PatriciaNode_Binary) GetLeft() *Hash {
+	if x != nil {
+		return x.Left
+	}
+	return nil
+}
+
+func (x *PatriciaNode_Binary) GetRight() *Hash {
+	if x != nil {
+		return x.Right
+	}
+	return nil
+}
+
+var File_snapshot_proto protoreflect.FileDescriptor
+
+var file_snapshot_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x73, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x1a, 0x0c, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x0b,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8d, 0x02, 0x0a, 0x0c,
+	0x50, 0x61, 0x74, 0x72, 0x69, 0x63, 0x69, 0x61, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x28, 0x0a, 0x04,
+	0x65, 0x64, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x50, 0x61, 0x74,
+	0x72, 0x69, 0x63, 0x69, 0x61, 0x4e, 0x6f, 0x64, 0x65, 0x2e, 0x45, 0x64, 0x67, 0x65, 0x48, 0x00,
+	0x52, 0x04, 0x65, 0x64, 0x67, 0x65, 0x12, 0x2e, 0x0a, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x50, 0x61, 0x74, 0x72, 0x69, 0x63, 0x69,
+	0x61, 0x4e, 0x6f, 0x64, 0x65, 0x2e, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x48, 0x00, 0x52, 0x06,
+	0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x1a, 0x59, 0x0a, 0x04, 0x45, 0x64, 0x67, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x06,
+	0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x1c, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x46, 0x65, 0x6c, 0x74, 0x32, 0x35, 0x32, 0x52, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x12, 0x1b, 0x0a, 0x05, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x52, 0x05, 0x63, 0x68, 0x69, 0x6c,
+	0x64, 0x1a, 0x40, 0x0a, 0x06, 0x42, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x19, 0x0a, 0x04, 0x6c,
+	0x65, 0x66, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68,
+	0x52, 0x04, 0x6c, 0x65, 0x66, 0x74, 0x12, 0x1b, 0x0a, 0x05, 0x72, 0x69, 0x67, 0x68, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x52, 0x05, 0x72, 0x69,
+	0x67, 0x68, 0x74, 0x42, 0x06, 0x0a, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x22, 0x39, 0x0a, 0x12, 0x50,
+	0x61, 0x74, 0x72, 0x69, 0x63, 0x69, 0x61, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x12, 0x23, 0x0a, 0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0d, 0x2e, 0x50, 0x61, 0x74, 0x72, 0x69, 0x63, 0x69, 0x61, 0x4e, 0x6f, 0x64, 0x65, 0x52,
+	0x05, 0x6e, 0x6f, 0x64, 0x65, 0x73, 0x22, 0xa3, 0x01, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x74, 0x72,
+	0x61, 0x63, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x41, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x24, 0x0a, 0x0a,
+	0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x52, 0x09, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x1e, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x08, 0x2e, 0x46, 0x65, 0x6c, 0x74, 0x32, 0x35, 0x32, 0x52, 0x05, 0x6e, 0x6f, 0x6e,
+	0x63, 0x65, 0x12, 0x28, 0x0a, 0x0c, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x72, 0x6f,
+	0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x52,
+	0x0b, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x22, 0xa2, 0x01, 0x0a,
+	0x14, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x72,
+	0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68,
+	0x52, 0x09, 0x73, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1e, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x41, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x03, 0x65,
+	0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x08, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x63, 0x68, 0x75, 0x6e, 0x6b,
+	0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x0e, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x50, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x22, 0xc5, 0x01, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x12, 0x2c, 0x0a, 0x0e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x73,
+	0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61,
+	0x73, 0x68, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x73, 0x52, 0x6f, 0x6f,
+	0x74, 0x12, 0x28, 0x0a, 0x0c, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x5f, 0x72, 0x6f, 0x6f,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x52, 0x0b,
+	0x63, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x26, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x0b, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x50, 0x61, 0x74, 0x72, 0x69,
+	0x63, 0x69, 0x61, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x0a, 0x72,
+	0x61, 0x6e, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x73, 0x0a, 0x15, 0x43, 0x6f, 0x6e,
+	0x74, 0x72, 0x61, 0x63, 0x74, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x26, 0x0a, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0e, 0x2e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x52, 0x61, 0x6e, 0x67,
+	0x65, 0x48, 0x00, 0x52, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x03, 0x66, 0x69,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x04, 0x2e, 0x46, 0x69, 0x6e, 0x48, 0x00, 0x52,
+	0x03, 0x66, 0x69, 0x6e, 0x42, 0x18, 0x0a, 0x16, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74,
+	0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x99,
+	0x01, 0x0a, 0x11, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x72, 0x6f,
+	0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x52,
+	0x09, 0x73, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x1b, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x17, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x52, 0x03, 0x65, 0x6e, 0x64,
+	0x12, 0x28, 0x0a, 0x10, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x70,
+	0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x63, 0x68, 0x75, 0x6e,
+	0x6b, 0x73, 0x50, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0xc4, 0x01, 0x0a, 0x0a, 0x43,
+	0x6c, 0x61, 0x73, 0x73, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x2c, 0x0a, 0x0e, 0x63, 0x6f, 0x6e,
+	0x74, 0x72, 0x61, 0x63, 0x74, 0x73, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73, 0x68, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61,
+	0x63, 0x74, 0x73, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x28, 0x0a, 0x0c, 0x63, 0x6c, 0x61, 0x73, 0x73,
+	0x65, 0x73, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e,
+	0x48, 0x61, 0x73, 0x68, 0x52, 0x0b, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x52, 0x6f, 0x6f,
+	0x74, 0x12, 0x28, 0x0a, 0x07, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x44, 0x65, 0x63, 0x6c, 0x61, 0x72, 0x65, 0x64, 0x43, 0x6c, 0x61,
+	0x73, 0x73, 0x52, 0x07, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x0b, 0x72,
+	0x61, 0x6e, 0x67, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x50, 0x61, 0x74, 0x72, 0x69, 0x63, 0x69, 0x61, 0x52, 0x61, 0x6e, 0x67, 0x65,
+	0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x0a, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x6f,
+	0x66, 0x22, 0x6a, 0x0a, 0x12, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x23, 0x0a, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x48, 0x00, 0x52, 0x05, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x03,
+	0x66, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x04, 0x2e, 0x46, 0x69, 0x6e, 0x48,
+	0x00, 0x52, 0x03, 0x66, 0x69, 0x6e, 0x42, 0x15, 0x0a, 0x13, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f,
+	0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xc8, 0x01,
+	0x0a, 0x16, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x24, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x5f, 0x72, 0x6f, 0x6f, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48,
+	0x61, 0x73, 0x68, 0x52, 0x09, 0x73, 0x74, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x6f, 0x74, 0x12, 0x22,
+	0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x08, 0x2e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x12, 0x1e, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x08, 0x2e, 0x46, 0x65, 0x6c, 0x74, 0x32, 0x35, 0x32, 0x52, 0x05, 0x73, 0x74, 0x61,
+	0x72, 0x74, 0x12, 0x1a, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x08, 0x2e, 0x46, 0x65, 0x6c, 0x74, 0x32, 0x35, 0x32, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x28,
+	0x0a, 0x10, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x70, 0x72, 0x6f,
+	0x6f, 0x66, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73,
+	0x50, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22, 0x75, 0x0a, 0x0f, 0x43, 0x6f, 0x6e, 0x74,
+	0x72, 0x61, 0x63, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x65, 0x64, 0x56, 0x61, 0x6c, 0x75,
+	0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x0b, 0x72, 0x61, 0x6e,
+	0x67, 0x65, 0x5f, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x50, 0x61, 0x74, 0x72, 0x69, 0x63, 0x69, 0x61, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x72,
+	0x6f, 0x6f, 0x66, 0x52, 0x0a, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x22,
+	0x7d, 0x0a, 0x17, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x61,
+	0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x73, 0x74,
+	0x6f, 0x72, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x53, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52,
+	0x07, 0x73, 0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x03, 0x66, 0x69, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x04, 0x2e, 0x46, 0x69, 0x6e, 0x48, 0x00, 0x52, 0x03, 0x66,
+	0x69, 0x6e, 0x42, 0x1a, 0x0a, 0x18, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x5f, 0x73,
+	0x74, 0x6f, 0x72, 0x61, 0x67, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x3e,
+	0x0a, 0x12, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x0c, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x5f, 0x68, 0x61,
+	0x73, 0x68, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x05, 0x2e, 0x48, 0x61, 0x73,
+	0x68, 0x52, 0x0b, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x42, 0x27,
+	0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4e, 0x65, 0x74,
+	0x68, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x64, 0x45, 0x74, 0x68, 0x2f, 0x6a, 0x75, 0x6e, 0x6f, 0x2f,
+	0x70, 0x32, 0x70, 0x2f, 0x67, 0x65, 0x6e, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_snapshot_proto_rawDescOnce sync.Once
+	file_snapshot_proto_rawDescData = file_snapshot_proto_rawDesc
+)
+
+func file_snapshot_proto_rawDescGZIP() []byte {
+	file_snapshot_proto_rawDescOnce.Do(func() {
+		file_snapshot_proto_rawDescData = protoimpl.X.CompressGZIP(file_snapshot_proto_rawDescData)
+	})
+	return file_snapshot_proto_rawDescData
+}
+
+var file_snapshot_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_snapshot_proto_goTypes = []any{
+	(*PatriciaNode)(nil),            // 0: PatriciaNode
+	(*PatriciaRangeProof)(nil),      // 1: PatriciaRangeProof
+	(*ContractState)(nil),           // 2: ContractState
+	(*ContractRangeRequest)(nil),    // 3: ContractRangeRequest
+	(*ContractRange)(nil),           // 4: ContractRange
+	(*ContractRangeResponse)(nil),   // 5: ContractRangeResponse
+	(*ClassRangeRequest)(nil),       // 6: ClassRangeRequest
+	(*ClassRange)(nil),              // 7: ClassRange
+	(*ClassRangeResponse)(nil),      // 8: ClassRangeResponse
+	(*ContractStorageRequest)(nil),  // 9: ContractStorageRequest
+	(*ContractStorage)(nil),         // 10: ContractStorage
+	(*ContractStorageResponse)(nil), // 11: ContractStorageResponse
+	(*ClassHashesRequest)(nil),      // 12: ClassHashesRequest
+	(*PatriciaNode_Edge)(nil),       // 13: PatriciaNode.Edge
+	(*PatriciaNode_Binary)(nil),     // 14: PatriciaNode.Binary
+	(*Address)(nil),                 // 15: Address
+	(*Hash)(nil),                    // 16: Hash
+	(*Felt252)(nil),                 // 17: Felt252
+	(*Fin)(nil),                     // 18: Fin
+	(*DeclaredClass)(nil),           // 19: DeclaredClass
+	(*ContractStoredValue)(nil),     // 20: ContractStoredValue
+}
+var file_snapshot_proto_depIdxs = []int32{
+	13, // 0: PatriciaNode.edge:type_name -> PatriciaNode.Edge
+	14, // 1: PatriciaNode.binary:type_name -> PatriciaNode.Binary
+	0,  // 2: PatriciaRangeProof.nodes:type_name -> PatriciaNode
+	15, // 3: ContractState.address:type_name -> Address
+	16, // 4: ContractState.class_hash:type_name -> Hash
+	17, // 5: ContractState.nonce:type_name -> Felt252
+	16, // 6: ContractState.storage_root:type_name -> Hash
+	16, // 7: ContractRangeRequest.state_root:type_name -> Hash
+	15, // 8: ContractRangeRequest.start:type_name -> Address
+	15, // 9: ContractRangeRequest.end:type_name -> Address
+	16, // 10: ContractRange.contracts_root:type_name -> Hash
+	16, // 11: ContractRange.classes_root:type_name -> Hash
+	2,  // 12: ContractRange.states:type_name -> ContractState
+	1,  // 13: ContractRange.range_proof:type_name -> PatriciaRangeProof
+	4,  // 14: ContractRangeResponse.range:type_name -> ContractRange
+	18, // 15: ContractRangeResponse.fin:type_name -> Fin
+	16, // 16: ClassRangeRequest.state_root:type_name -> Hash
+	16, // 17: ClassRangeRequest.start:type_name -> Hash
+	16, // 18: ClassRangeRequest.end:type_name -> Hash
+	16, // 19: ClassRange.contracts_root:type_name -> Hash
+	16, // 20: ClassRange.classes_root:type_name -> Hash
+	19, // 21: ClassRange.classes:type_name -> DeclaredClass
+	1,  // 22: ClassRange.range_proof:type_name -> PatriciaRangeProof
+	7,  // 23: ClassRangeResponse.range:type_name -> ClassRange
+	18, // 24: ClassRangeResponse.fin:type_name -> Fin
+	16, // 25: ContractStorageRequest.state_root:type_name -> Hash
+	15, // 26: ContractStorageRequest.address:type_name -> Address
+	17, // 27: ContractStorageRequest.start:type_name -> Felt252
+	17, // 28: ContractStorageRequest.end:type_name -> Felt252
+	20, // 29: ContractStorage.values:type_name -> ContractStoredValue
+	1,  // 30: ContractStorage.range_proof:type_name -> PatriciaRangeProof
+	10, // 31: ContractStorageResponse.storage:type_name -> ContractStorage
+	18, // 32: ContractStorageResponse.fin:type_name -> Fin
+	16, // 33: ClassHashesRequest.class_hashes:type_name -> Hash
+	17, // 34: PatriciaNode.Edge.path:type_name -> Felt252
+	16, // 35: PatriciaNode.Edge.child:type_name -> Hash
+	16, // 36: PatriciaNode.Binary.left:type_name -> Hash
+	16, // 37: PatriciaNode.Binary.right:type_name -> Hash
+	38, // [38:38] is the sub-list for method output_type
+	38, // [38:38] is the sub-list for method input_type
+	38, // [38:38] is the sub-list for extension type_name
+	38, // [38:38] is the sub-list for extension extendee
+	0,  // [0:38] is the sub-list for field type_name
+}
+
+func init() { file_snapshot_proto_init() }
+func file_snapshot_proto_init() {
+	if File_snapshot_proto != nil {
+		return
+	}
+	file_common_proto_init()
+	file_state_proto_init()
+	file_snapshot_proto_msgTypes[0].OneofWrappers = []any{
+		(*PatriciaNode_Edge_)(nil),
+		(*PatriciaNode_Binary_)(nil),
+	}
+	file_snapshot_proto_msgTypes[5].OneofWrappers = []any{
+		(*ContractRangeResponse_Range)(nil),
+		(*ContractRangeResponse_Fin)(nil),
+	}
+	file_snapshot_proto_msgTypes[8].OneofWrappers = []any{
+		(*ClassRangeResponse_Range)(nil),
+		(*ClassRangeResponse_Fin)(nil),
+	}
+	file_snapshot_proto_msgTypes[11].OneofWrappers = []any{
+		(*ContractStorageResponse_Storage)(nil),
+		(*ContractStorageResponse_Fin)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_snapshot_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_snapshot_proto_goTypes,
+		DependencyIndexes: file_snapshot_proto_depIdxs,
+		MessageInfos:      file_snapshot_proto_msgTypes,
+	}.Build()
+	File_snapshot_proto = out.File
+	file_snapshot_proto_rawDesc = nil
+	file_snapshot_proto_goTypes = nil
+	file_snapshot_proto_depIdxs = nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/NethermindEth/juno/blockchain"
 	"github.com/NethermindEth/juno/db"
 	p2pPeers "github.com/NethermindEth/juno/p2p/peers"
+	"github.com/NethermindEth/juno/p2p/reputation"
 	p2pSync "github.com/NethermindEth/juno/p2p/sync"
 	junoSync "github.com/NethermindEth/juno/sync"
 	"github.com/NethermindEth/juno/utils"
@@ -24,6 +26,7 @@ import (
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
 	"github.com/libp2p/go-libp2p/core/protocol"
 	"github.com/multiformats/go-multiaddr"
 	"google.golang.org/protobuf/proto"
@@ -45,11 +48,16 @@ type Service struct {
 	dht    *dht.IpfsDHT
 	pubsub *pubsub.PubSub
 
-	synchroniser *p2pSync.Service
-	gossipTracer *gossipTracer
+	synchroniser        *p2pSync.Service
+	gossipTracer        *gossipTracer
+	blockchain          *blockchain.Blockchain
+	onBlockAnnouncement func(blockNumber uint64)
+	reputation          *reputation.Tracker
 
 	feederNode bool
-	database   db.DB
+	// feederPeers are the peers whose block announcements are trusted
+	feederPeers map[peer.ID]struct{}
+	database    db.DB
 }
 
 func New(addr, publicAddr, version, peers, privKeyStr string, feederNode bool, bc *blockchain.Blockchain, snNetwork *utils.Network,
@@ -77,6 +85,12 @@ func New(addr, publicAddr, version, peers, privKeyStr string, feederNode bool, b
 		return nil, err
 	}
 
+	storedPeers, records, err := loadPeers(database)
+	if err != nil {
+		log.Warnw("Failed to load peers", "err", err)
+	}
+	tracker := reputation.New(records, log)
+
 	// The address Factory is used when the public ip is passed to the node.
 	// In this case the node will NOT try to listen to the public IP because
 	// it is not possible to listen to a public IP. Instead, the node will
@@ -103,6 +117,8 @@ func New(addr, publicAddr, version, peers, privKeyStr string, feederNode bool, b
 		libp2p.EnableHolePunching(),
 		// Try to open a port in the NAT router to accept incoming connections.
 		libp2p.NATPortMap(),
+		// Refuse connections to and from banned peers.
+		libp2p.ConnectionGater(tracker),
 	)
 	if err != nil {
 		return nil, err
@@ -110,22 +126,25 @@ func New(addr, publicAddr, version, peers, privKeyStr string, feederNode bool, b
 	// Todo: try to understand what will happen if user passes a multiaddr with p2p public and a private key which doesn't match.
 	// For example, a user passes the following multiaddr: --p2p-addr=/ip4/0.0.0.0/tcp/7778/p2p/(SomePublicKey) and also passes a
 	// --p2p-private-key="SomePrivateKey". However, the private public key pair don't match, in this case what will happen?
-	return NewWithHost(p2pHost, peers, feederNode, bc, snNetwork, log, database)
+	return newWithHost(p2pHost, storedPeers, tracker, peers, feederNode, bc, snNetwork, log, database)
 }
 
+// NewWithHost creates a service on an existing host. Since the connection gater of a host can't be set once
+// it is created, banned peers are only disconnected when they are banned rather than refused.
 func NewWithHost(p2phost host.Host, peers string, feederNode bool, bc *blockchain.Blockchain, snNetwork *utils.Network,
 	log utils.SimpleLogger, database db.DB,
 ) (*Service, error) {
-	var (
-		peersAddrInfoS []peer.AddrInfo
-		err            error
-	)
-
-	peersAddrInfoS, err = loadPeers(database)
+	storedPeers, records, err := loadPeers(database)
 	if err != nil {
 		log.Warnw("Failed to load peers", "err", err)
 	}
+	return newWithHost(p2phost, storedPeers, reputation.New(records, log), peers, feederNode, bc, snNetwork, log, database)
+}
 
+func newWithHost(p2phost host.Host, peersAddrInfoS []peer.AddrInfo, tracker *reputation.Tracker, peers string, feederNode bool,
+	bc *blockchain.Blockchain, snNetwork *utils.Network, log utils.SimpleLogger, database db.DB,
+) (*Service, error) {
+	var err error
 	if peers != "" {
 		splitted := strings.Split(peers, ",")
 		for _, peerStr := range splitted {
@@ -146,7 +165,7 @@ func NewWithHost(p2phost host.Host, peers string, feederNode bool, bc *blockchai
 
 	// todo: reconsider initialising synchroniser here because if node is a feedernode we shouldn't not create an instance of it.
 
-	synchroniser := p2pSync.New(bc, p2phost, snNetwork, log)
+	synchroniser := p2pSync.New(bc, p2phost, snNetwork, tracker, log)
 	s := &Service{
 		synchroniser: synchroniser,
 		log:          log,
@@ -156,10 +175,23 @@ func NewWithHost(p2phost host.Host, peers string, feederNode bool, bc *blockchai
 		feederNode:   feederNode,
 		handler:      p2pPeers.NewHandler(bc, log),
 		database:     database,
+		blockchain:   bc,
+		reputation:   tracker,
 	}
+	s.onBlockAnnouncement = synchroniser.Announce
 	return s, nil
 }
 
+// WithFeederPeers sets the feeder nodes whose block announcements are trusted, the announcements of other
+// peers are ignored.
+func (s *Service) WithFeederPeers(ids ...peer.ID) *Service {
+	s.feederPeers = make(map[peer.ID]struct{}, len(ids))
+	for _, id := range ids {
+		s.feederPeers[id] = struct{}{}
+	}
+	return s
+}
+
 func makeDHT(p2phost host.Host, addrInfos []peer.AddrInfo) (*dht.IpfsDHT, error) {
 	return dht.New(context.Background(), p2phost,
 		dht.ProtocolPrefix(p2pSync.Prefix),
@@ -235,6 +267,10 @@ func (s *Service) Run(ctx context.Context) error {
 
 	s.setProtocolHandlers()
 
+	if err = s.runBlockAnnouncements(ctx); err != nil {
+		return err
+	}
+
 	if !s.feederNode {
 		s.synchroniser.Run(ctx)
 	}
@@ -255,6 +291,10 @@ func (s *Service) setProtocolHandlers() {
 	s.SetProtocolHandler(p2pSync.TransactionsPID(), s.handler.TransactionsHandler)
 	s.SetProtocolHandler(p2pSync.ClassesPID(), s.handler.ClassesHandler)
 	s.SetProtocolHandler(p2pSync.StateDiffPID(), s.handler.StateDiffHandler)
+	s.SetProtocolHandler(p2pSync.SnapContractsPID(), s.handler.ContractRangeHandler)
+	s.SetProtocolHandler(p2pSync.SnapClassesPID(), s.handler.ClassRangeHandler)
+	s.SetProtocolHandler(p2pSync.SnapStoragePID(), s.handler.ContractStorageHandler)
+	s.SetProtocolHandler(p2pSync.ClassHashesPID(), s.handler.ClassHashesHandler)
 }
 
 func (s *Service) callAndLogErr(f func() error, msg string) {
@@ -312,11 +352,56 @@ func (s *Service) WithListener(l junoSync.EventListener) {
 	s.synchroniser.WithListener(l)
 }
 
+// Reputation returns the tracker scoring the peers of the service.
+func (s *Service) Reputation() *reputation.Tracker {
+	return s.reputation
+}
+
+// Peers returns the peers the host is connected to, sorted by ID.
+func (s *Service) Peers() []peer.AddrInfo {
+	ids := s.host.Network().Peers()
+	peers := make([]peer.AddrInfo, len(ids))
+	for i, id := range ids {
+		peers[i] = s.host.Peerstore().PeerInfo(id)
+	}
+	slices.SortFunc(peers, func(a, b peer.AddrInfo) int {
+		return strings.Compare(string(a.ID), string(b.ID))
+	})
+	return peers
+}
+
+// AddPeer connects to the peer at addr, a multiaddr ending with the ID of the peer, and keeps its addresses so that
+// they are persisted with the other peers.
+func (s *Service) AddPeer(ctx context.Context, addr string) error {
+	addrInfo, err := peer.AddrInfoFromString(addr)
+	if err != nil {
+		return fmt.Errorf("addr info from %q: %w", addr, err)
+	}
+	if err = s.host.Connect(ctx, *addrInfo); err != nil {
+		return err
+	}
+	s.host.Peerstore().AddAddrs(addrInfo.ID, addrInfo.Addrs, peerstore.PermanentAddrTTL)
+	return nil
+}
+
+// BanPeer bans the peer permanently and disconnects from it.
+func (s *Service) BanPeer(id peer.ID) error {
+	s.reputation.Ban(id)
+	s.host.Peerstore().RemovePeer(id)
+	s.host.Peerstore().ClearAddrs(id)
+	return s.host.Network().ClosePeer(id)
+}
+
+// WithSnapSync makes the service download the state at a recent block when the chain is empty.
+func (s *Service) WithSnapSync() {
+	s.synchroniser.WithSnapSync()
+}
+
 func (s *Service) WithGossipTracer() {
 	s.gossipTracer = NewGossipTracer(s.host)
 }
 
-// persistPeers stores the given peers in the peers database
+// persistPeers stores the known peers and the reputation of every peer that has one in the peers database
 func (s *Service) persistPeers() error {
 	txn, err := s.database.NewTransaction(true)
 	if err != nil {
@@ -327,15 +412,29 @@ func (s *Service) persistPeers() error {
 	peers := utils.Filter(store.Peers(), func(peerID peer.ID) bool {
 		return peerID != s.host.ID()
 	})
+	records := s.reputation.Records()
 	for _, peerID := range peers {
 		peerInfo := store.PeerInfo(peerID)
 
-		encodedAddrs, err := EncodeAddrs(peerInfo.Addrs)
+		encodedPeer, err := encodePeer(peerInfo.Addrs, records[peerID])
+		if err != nil {
+			return fmt.Errorf("encode peer %s: %w", peerID, err)
+		}
+
+		if err := txn.Set(db.Peer.Key([]byte(peerID)), encodedPeer); err != nil {
+			return fmt.Errorf("set data for peer %s: %w", peerID, err)
+		}
+		delete(records, peerID)
+	}
+
+	// Peers that were removed from the peerstore, banned ones for instance, are stored without addresses
+	for peerID, record := range records {
+		encodedPeer, err := encodePeer(nil, record)
 		if err != nil {
-			return fmt.Errorf("encode addresses for peer %s: %w", peerID, err)
+			return fmt.Errorf("encode peer %s: %w", peerID, err)
 		}
 
-		if err := txn.Set(db.Peer.Key([]byte(peerID)), encodedAddrs); err != nil {
+		if err := txn.Set(db.Peer.Key([]byte(peerID)), encodedPeer); err != nil {
 			return fmt.Errorf("set data for peer %s: %w", peerID, err)
 		}
 	}
@@ -349,9 +448,11 @@ func (s *Service) persistPeers() error {
 	return nil
 }
 
-// loadPeers loads the previously stored peers from the database
-func loadPeers(database db.DB) ([]peer.AddrInfo, error) {
+// loadPeers loads the previously stored peers and their reputation from the database. Peers without
+// addresses or that are banned aren't returned as bootstrap peers.
+func loadPeers(database db.DB) ([]peer.AddrInfo, map[peer.ID]reputation.Record, error) {
 	var peers []peer.AddrInfo
+	records := make(map[peer.ID]reputation.Record)
 
 	err := database.View(func(txn db.Transaction) error {
 		it, err := txn.NewIterator(db.Peer.Key(), true)
@@ -372,21 +473,26 @@ func loadPeers(database db.DB) ([]peer.AddrInfo, error) {
 				return fmt.Errorf("get value: %w", err)
 			}
 
-			addrs, err := decodeAddrs(val)
+			addrs, record, err := decodePeer(val)
 			if err != nil {
-				return fmt.Errorf("decode addresses for peer %s: %w", peerID, err)
+				return fmt.Errorf("decode peer %s: %w", peerID, err)
 			}
 
-			peers = append(peers, peer.AddrInfo{ID: peerID, Addrs: addrs})
+			if record != (reputation.Record{}) {
+				records[peerID] = record
+			}
+			if len(addrs) > 0 && !record.BannedForever && record.BannedUntil <= time.Now().Unix() {
+				peers = append(peers, peer.AddrInfo{ID: peerID, Addrs: addrs})
+			}
 		}
 
 		return nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("load peers: %w", err)
+		return nil, nil, fmt.Errorf("load peers: %w", err)
 	}
 
-	return peers, nil
+	return peers, records, nil
 }
 
 func makeAgentName(version string) string {
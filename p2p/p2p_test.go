@@ -1,14 +1,18 @@
 package p2p_test
 
 import (
+	"context"
 	"testing"
 
+	"github.com/NethermindEth/juno/blockchain"
 	"github.com/NethermindEth/juno/db"
 	"github.com/NethermindEth/juno/db/pebble"
 	"github.com/NethermindEth/juno/p2p"
 	"github.com/NethermindEth/juno/utils"
 	"github.com/libp2p/go-libp2p/core/peer"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
 	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -64,3 +68,28 @@ func TestLoadAndPersistPeers(t *testing.T) {
 	)
 	require.NoError(t, err)
 }
+
+func TestAddAndBanPeer(t *testing.T) {
+	mn, err := mocknet.FullMeshLinked(2)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, mn.Close()) })
+	hosts := mn.Hosts()
+
+	database := pebble.NewMemTest(t)
+	chain := blockchain.New(database, &utils.Sepolia, nil)
+	service, err := p2p.NewWithHost(hosts[0], "", true, chain, &utils.Sepolia, utils.NewNopZapLogger(), database)
+	require.NoError(t, err)
+	assert.Empty(t, service.Peers())
+
+	other := hosts[1].ID()
+	require.Error(t, service.AddPeer(context.Background(), "not a multiaddr"))
+	require.NoError(t, service.AddPeer(context.Background(), hosts[1].Addrs()[0].String()+"/p2p/"+other.String()))
+	peers := service.Peers()
+	require.Len(t, peers, 1)
+	assert.Equal(t, other, peers[0].ID)
+	assert.Equal(t, hosts[1].Addrs(), peers[0].Addrs)
+
+	require.NoError(t, service.BanPeer(other))
+	assert.Empty(t, service.Peers())
+	assert.True(t, service.Reputation().Banned(other))
+}
@@ -0,0 +1,350 @@
+package peers
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+
+	"github.com/NethermindEth/juno/adapters/core2p2p"
+	"github.com/NethermindEth/juno/adapters/p2p2core"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/p2p/gen"
+	"github.com/libp2p/go-libp2p/core/network"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// maxLeavesPerRange bounds the number of leaves in each range sent to a peer.
+	maxLeavesPerRange = 1024
+	// maxRangesPerResponse bounds the number of ranges sent for a single request, peers request the rest again.
+	maxRangesPerResponse = 16
+	// maxClassHashesPerRequest bounds the number of classes sent for a single request.
+	maxClassHashesPerRequest = 128
+)
+
+func (h *Handler) ContractRangeHandler(stream network.Stream) {
+	streamHandler(h.ctx, &h.wg, stream, h.onContractRangeRequest, h.log)
+}
+
+func (h *Handler) ClassRangeHandler(stream network.Stream) {
+	streamHandler(h.ctx, &h.wg, stream, h.onClassRangeRequest, h.log)
+}
+
+func (h *Handler) ContractStorageHandler(stream network.Stream) {
+	streamHandler(h.ctx, &h.wg, stream, h.onContractStorageRequest, h.log)
+}
+
+func (h *Handler) ClassHashesHandler(stream network.Stream) {
+	streamHandler(h.ctx, &h.wg, stream, h.onClassHashesRequest, h.log)
+}
+
+// headState holds the latest state along with the roots of its tries.
+type headState struct {
+	core.StateTrieReader
+	contractsRoot *felt.Felt
+	classesRoot   *felt.Felt
+}
+
+// processHeadStateRequest calls getMsgs with the latest state if its root is stateRoot. Only a Fin is sent
+// otherwise, so that the peer picks a more recent state.
+func (h *Handler) processHeadStateRequest(stateRoot *gen.Hash, finMsg proto.Message,
+	getMsgs func(state *headState, yield func(proto.Message) bool) error,
+) (iter.Seq[proto.Message], error) {
+	if stateRoot == nil {
+		return nil, errors.New("missing state root")
+	}
+
+	return func(yield func(proto.Message) bool) {
+		state, closer, err := h.bcReader.HeadStateTries()
+		if err != nil {
+			if !errors.Is(err, db.ErrKeyNotFound) {
+				h.log.Errorw("Failed to get head state", "err", err)
+			}
+			yield(finMsg)
+			return
+		}
+		defer func() {
+			if closeErr := closer(); closeErr != nil {
+				h.log.Errorw("Failed to close state reader", "err", closeErr)
+			}
+		}()
+
+		head, err := newHeadState(state)
+		if err != nil {
+			h.log.Errorw("Failed to get state roots", "err", err)
+			yield(finMsg)
+			return
+		}
+
+		if core.StateCommitment(head.contractsRoot, head.classesRoot).Equal(p2p2core.AdaptHash(stateRoot)) {
+			sent := true
+			err = getMsgs(head, func(msg proto.Message) bool {
+				sent = yield(msg)
+				return sent
+			})
+			if err != nil {
+				h.log.Errorw("Failed to generate state ranges", "err", err)
+			}
+			if !sent {
+				// note that in this case we won't send finMsg
+				return
+			}
+		}
+		yield(finMsg)
+	}, nil
+}
+
+func newHeadState(state core.StateTrieReader) (*headState, error) {
+	contracts, err := state.ContractsTrie()
+	if err != nil {
+		return nil, err
+	}
+	contractsRoot, err := contracts.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	classes, err := state.ClassesTrie()
+	if err != nil {
+		return nil, err
+	}
+	classesRoot, err := classes.Root()
+	if err != nil {
+		return nil, err
+	}
+
+	return &headState{StateTrieReader: state, contractsRoot: contractsRoot, classesRoot: classesRoot}, nil
+}
+
+// proveRanges splits the leaves of t from start onwards into ranges of up to limit leaves and passes each one to
+// yield with its proof, until a range ends at or after end. Each range is proven from the key following the last
+// key of the previous one, so that together they prove that no leaf is missing.
+func proveRanges(t *trie.Trie, start, end *felt.Felt, limit uint32,
+	yield func(keys, values []*felt.Felt, proof *trie.ProofNodeSet) bool,
+) error {
+	if limit == 0 || limit > maxLeavesPerRange {
+		limit = maxLeavesPerRange
+	}
+
+	from := start
+	for range maxRangesPerResponse {
+		keys, values, err := t.Leaves(from, int(limit))
+		if err != nil {
+			return err
+		}
+
+		last := from
+		if len(keys) > 0 {
+			last = keys[len(keys)-1]
+		}
+		proof := trie.NewProofNodeSet()
+		if err = t.GetRangeProof(from, last, proof); err != nil {
+			return err
+		}
+
+		if !yield(keys, values, proof) || len(keys) < int(limit) || last.Cmp(end) >= 0 {
+			return nil
+		}
+		from = new(felt.Felt).Add(last, new(felt.Felt).SetUint64(1))
+	}
+	return nil
+}
+
+func (h *Handler) onContractRangeRequest(req *gen.ContractRangeRequest) (iter.Seq[proto.Message], error) {
+	finMsg := &gen.ContractRangeResponse{
+		ContractRangeMessage: &gen.ContractRangeResponse_Fin{},
+	}
+	if req.Start == nil || req.End == nil {
+		return nil, errors.New("missing range bounds")
+	}
+
+	return h.processHeadStateRequest(req.StateRoot, finMsg, func(state *headState, yield func(proto.Message) bool) error {
+		contracts, err := state.ContractsTrie()
+		if err != nil {
+			return err
+		}
+
+		var rangeErr error
+		err = proveRanges(contracts, p2p2core.AdaptAddress(req.Start), p2p2core.AdaptAddress(req.End), req.ChunksPerProof,
+			func(addresses, _ []*felt.Felt, proof *trie.ProofNodeSet) bool {
+				states := make([]*gen.ContractState, 0, len(addresses))
+				for _, addr := range addresses {
+					var contractState *gen.ContractState
+					if contractState, rangeErr = adaptContractState(state, addr); rangeErr != nil {
+						return false
+					}
+					states = append(states, contractState)
+				}
+
+				return yield(&gen.ContractRangeResponse{
+					ContractRangeMessage: &gen.ContractRangeResponse_Range{
+						Range: &gen.ContractRange{
+							ContractsRoot: core2p2p.AdaptHash(state.contractsRoot),
+							ClassesRoot:   core2p2p.AdaptHash(state.classesRoot),
+							States:        states,
+							RangeProof:    core2p2p.AdaptProof(proof),
+						},
+					},
+				})
+			})
+		return errors.Join(err, rangeErr)
+	})
+}
+
+func adaptContractState(state core.StateTrieReader, addr *felt.Felt) (*gen.ContractState, error) {
+	classHash, err := state.ContractClassHash(addr)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := state.ContractNonce(addr)
+	if err != nil {
+		return nil, err
+	}
+	storage, err := state.ContractStorageTrie(addr)
+	if err != nil {
+		return nil, err
+	}
+	storageRoot, err := storage.Root()
+	if err != nil {
+		return nil, err
+	}
+	return core2p2p.AdaptContractState(addr, classHash, nonce, storageRoot), nil
+}
+
+func (h *Handler) onClassRangeRequest(req *gen.ClassRangeRequest) (iter.Seq[proto.Message], error) {
+	finMsg := &gen.ClassRangeResponse{
+		ClassRangeMessage: &gen.ClassRangeResponse_Fin{},
+	}
+	if req.Start == nil || req.End == nil {
+		return nil, errors.New("missing range bounds")
+	}
+
+	return h.processHeadStateRequest(req.StateRoot, finMsg, func(state *headState, yield func(proto.Message) bool) error {
+		classes, err := state.ClassesTrie()
+		if err != nil {
+			return err
+		}
+
+		var rangeErr error
+		err = proveRanges(classes, p2p2core.AdaptHash(req.Start), p2p2core.AdaptHash(req.End), req.ChunksPerProof,
+			func(classHashes, _ []*felt.Felt, proof *trie.ProofNodeSet) bool {
+				declaredClasses := make([]*gen.DeclaredClass, 0, len(classHashes))
+				for _, classHash := range classHashes {
+					var declared *core.DeclaredClass
+					if declared, rangeErr = state.Class(classHash); rangeErr != nil {
+						return false
+					}
+					cairo1, ok := declared.Class.(*core.Cairo1Class)
+					if !ok || cairo1.Compiled == nil {
+						rangeErr = fmt.Errorf("class %s in the classes trie has no compiled class", classHash)
+						return false
+					}
+
+					declaredClasses = append(declaredClasses, &gen.DeclaredClass{
+						ClassHash:         core2p2p.AdaptHash(classHash),
+						CompiledClassHash: core2p2p.AdaptHash(cairo1.Compiled.Hash()),
+					})
+				}
+
+				return yield(&gen.ClassRangeResponse{
+					ClassRangeMessage: &gen.ClassRangeResponse_Range{
+						Range: &gen.ClassRange{
+							ContractsRoot: core2p2p.AdaptHash(state.contractsRoot),
+							ClassesRoot:   core2p2p.AdaptHash(state.classesRoot),
+							Classes:       declaredClasses,
+							RangeProof:    core2p2p.AdaptProof(proof),
+						},
+					},
+				})
+			})
+		return errors.Join(err, rangeErr)
+	})
+}
+
+func (h *Handler) onContractStorageRequest(req *gen.ContractStorageRequest) (iter.Seq[proto.Message], error) {
+	finMsg := &gen.ContractStorageResponse{
+		ContractStorageMessage: &gen.ContractStorageResponse_Fin{},
+	}
+	if req.Address == nil || req.Start == nil || req.End == nil {
+		return nil, errors.New("missing contract address or range bounds")
+	}
+
+	return h.processHeadStateRequest(req.StateRoot, finMsg, func(state *headState, yield func(proto.Message) bool) error {
+		storage, err := state.ContractStorageTrie(p2p2core.AdaptAddress(req.Address))
+		if err != nil {
+			return err
+		}
+
+		return proveRanges(storage, p2p2core.AdaptFelt(req.Start), p2p2core.AdaptFelt(req.End), req.ChunksPerProof,
+			func(keys, values []*felt.Felt, proof *trie.ProofNodeSet) bool {
+				storedValues := make([]*gen.ContractStoredValue, 0, len(keys))
+				for i, key := range keys {
+					storedValues = append(storedValues, &gen.ContractStoredValue{
+						Key:   core2p2p.AdaptFelt(key),
+						Value: core2p2p.AdaptFelt(values[i]),
+					})
+				}
+
+				return yield(&gen.ContractStorageResponse{
+					ContractStorageMessage: &gen.ContractStorageResponse_Storage{
+						Storage: &gen.ContractStorage{
+							Values:     storedValues,
+							RangeProof: core2p2p.AdaptProof(proof),
+						},
+					},
+				})
+			})
+	})
+}
+
+func (h *Handler) onClassHashesRequest(req *gen.ClassHashesRequest) (iter.Seq[proto.Message], error) {
+	finMsg := &gen.ClassesResponse{
+		ClassMessage: &gen.ClassesResponse_Fin{},
+	}
+	if len(req.ClassHashes) > maxClassHashesPerRequest {
+		return nil, fmt.Errorf("requested %d classes, at most %d are allowed", len(req.ClassHashes), maxClassHashesPerRequest)
+	}
+
+	type yieldFunc = func(proto.Message) bool
+	return func(yield yieldFunc) {
+		state, closer, err := h.bcReader.HeadState()
+		if err != nil {
+			if !errors.Is(err, db.ErrKeyNotFound) {
+				h.log.Errorw("Failed to get head state", "err", err)
+			}
+			yield(finMsg)
+			return
+		}
+		defer func() {
+			if closeErr := closer(); closeErr != nil {
+				h.log.Errorw("Failed to close state reader", "err", closeErr)
+			}
+		}()
+
+		for _, classHash := range req.ClassHashes {
+			declared, err := state.Class(p2p2core.AdaptHash(classHash))
+			if err != nil {
+				if !errors.Is(err, db.ErrKeyNotFound) {
+					h.log.Errorw("Failed to get class", "err", err)
+					break
+				}
+				continue
+			}
+
+			msg := &gen.ClassesResponse{
+				ClassMessage: &gen.ClassesResponse_Class{
+					Class: core2p2p.AdaptClass(declared.Class),
+				},
+			}
+			if !yield(msg) {
+				// if caller is not interested in remaining data (example: connection to a peer is closed) exit
+				// note that in this case we won't send finMsg
+				return
+			}
+		}
+		yield(finMsg)
+	}, nil
+}
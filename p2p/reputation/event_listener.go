@@ -0,0 +1,25 @@
+package reputation
+
+import "github.com/libp2p/go-libp2p/core/peer"
+
+type EventListener interface {
+	OnPeerScore(id peer.ID, score float64)
+	OnPeerBanned(id peer.ID, permanently bool)
+}
+
+type SelectiveListener struct {
+	OnPeerScoreCb  func(id peer.ID, score float64)
+	OnPeerBannedCb func(id peer.ID, permanently bool)
+}
+
+func (l *SelectiveListener) OnPeerScore(id peer.ID, score float64) {
+	if l.OnPeerScoreCb != nil {
+		l.OnPeerScoreCb(id, score)
+	}
+}
+
+func (l *SelectiveListener) OnPeerBanned(id peer.ID, permanently bool) {
+	if l.OnPeerBannedCb != nil {
+		l.OnPeerBannedCb(id, permanently)
+	}
+}
@@ -0,0 +1,203 @@
+package reputation
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/utils"
+	"github.com/libp2p/go-libp2p/core/connmgr"
+	"github.com/libp2p/go-libp2p/core/control"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+)
+
+const (
+	// InvalidDataPenalty is subtracted from the score of a peer that sent data failing validation.
+	InvalidDataPenalty = 40
+	// TimeoutPenalty is subtracted from the score of a peer that didn't respond or stopped responding.
+	TimeoutPenalty = 10
+	// BytesPerPoint is the number of useful bytes a peer has to serve to gain a point.
+	BytesPerPoint = utils.Megabyte
+
+	maxScore = 100
+	// banThreshold is the score at which a peer is banned, its score is reset once banned.
+	banThreshold = -50
+	// TemporaryBanDuration is how long a peer is banned for the first banThreshold crossings.
+	TemporaryBanDuration = time.Hour
+	// MaxTemporaryBans is the number of temporary bans after which a peer is banned permanently.
+	MaxTemporaryBans = 3
+)
+
+var _ connmgr.ConnectionGater = (*Tracker)(nil)
+
+// Record is the reputation of a peer.
+type Record struct {
+	Score float64
+	// Bans is the number of times the peer was banned
+	Bans uint64
+	// BannedUntil is the unix time in seconds until which the peer is banned, 0 if it isn't
+	BannedUntil int64
+	// BannedForever is set once the peer exceeded MaxTemporaryBans
+	BannedForever bool
+}
+
+// PeerScore is the reputation of the peer with the given ID.
+type PeerScore struct {
+	ID peer.ID
+	Record
+}
+
+// Tracker scores peers by the quality of their responses and bans the ones whose score drops too low.
+// It gates the connections of the host it is set on, so that banned peers can't connect.
+type Tracker struct {
+	mu       sync.Mutex // protects records
+	records  map[peer.ID]*Record
+	now      func() time.Time
+	listener EventListener
+	log      utils.SimpleLogger
+}
+
+// New creates a tracker with the given records, usually loaded from the database.
+func New(records map[peer.ID]Record, log utils.SimpleLogger) *Tracker {
+	t := &Tracker{
+		records:  make(map[peer.ID]*Record, len(records)),
+		now:      time.Now,
+		listener: &SelectiveListener{},
+		log:      log,
+	}
+	for id, record := range records {
+		t.records[id] = &record
+	}
+	return t
+}
+
+func (t *Tracker) WithListener(listener EventListener) *Tracker {
+	t.listener = listener
+	return t
+}
+
+func (t *Tracker) RecordInvalidData(id peer.ID) {
+	t.adjust(id, -InvalidDataPenalty, "invalid data")
+}
+
+func (t *Tracker) RecordTimeout(id peer.ID) {
+	t.adjust(id, -TimeoutPenalty, "timeout")
+}
+
+func (t *Tracker) RecordUsefulBytes(id peer.ID, n int) {
+	t.adjust(id, float64(n)/BytesPerPoint, "")
+}
+
+func (t *Tracker) adjust(id peer.ID, delta float64, reason string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record, ok := t.records[id]
+	if !ok {
+		record = new(Record)
+		t.records[id] = record
+	}
+	if t.banned(record) {
+		return
+	}
+
+	record.Score = min(record.Score+delta, maxScore)
+	if record.Score <= banThreshold {
+		record.Score = 0
+		record.Bans++
+		if record.Bans > MaxTemporaryBans {
+			record.BannedForever = true
+		} else {
+			record.BannedUntil = t.now().Add(TemporaryBanDuration).Unix()
+		}
+		t.log.Infow("Banned peer", "peer", id, "reason", reason, "bans", record.Bans, "permanently", record.BannedForever)
+		t.listener.OnPeerBanned(id, record.BannedForever)
+	}
+	t.listener.OnPeerScore(id, record.Score)
+}
+
+// Ban bans the peer permanently, whatever its score.
+func (t *Tracker) Ban(id peer.ID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record, ok := t.records[id]
+	if !ok {
+		record = new(Record)
+		t.records[id] = record
+	}
+	if record.BannedForever {
+		return
+	}
+
+	record.Score = 0
+	record.Bans++
+	record.BannedForever = true
+	t.log.Infow("Banned peer", "peer", id, "reason", "manual", "bans", record.Bans, "permanently", true)
+	t.listener.OnPeerBanned(id, true)
+	t.listener.OnPeerScore(id, record.Score)
+}
+
+// Banned returns whether the peer is currently banned.
+func (t *Tracker) Banned(id peer.ID) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	record, ok := t.records[id]
+	return ok && t.banned(record)
+}
+
+func (t *Tracker) banned(record *Record) bool {
+	return record.BannedForever || t.now().Unix() < record.BannedUntil
+}
+
+// Scores returns the reputation of every peer that has one, sorted by ID.
+func (t *Tracker) Scores() []PeerScore {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	scores := make([]PeerScore, 0, len(t.records))
+	for id, record := range t.records {
+		scores = append(scores, PeerScore{ID: id, Record: *record})
+	}
+	slices.SortFunc(scores, func(a, b PeerScore) int {
+		return strings.Compare(string(a.ID), string(b.ID))
+	})
+	return scores
+}
+
+// Records returns a copy of the reputation of every peer that has one.
+func (t *Tracker) Records() map[peer.ID]Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	records := make(map[peer.ID]Record, len(t.records))
+	for id, record := range t.records {
+		records[id] = *record
+	}
+	return records
+}
+
+func (t *Tracker) InterceptPeerDial(id peer.ID) bool {
+	return !t.Banned(id)
+}
+
+func (t *Tracker) InterceptAddrDial(id peer.ID, _ multiaddr.Multiaddr) bool {
+	return !t.Banned(id)
+}
+
+func (t *Tracker) InterceptAccept(network.ConnMultiaddrs) bool {
+	// the peer is only known once the connection is secured
+	return true
+}
+
+func (t *Tracker) InterceptSecured(_ network.Direction, id peer.ID, _ network.ConnMultiaddrs) bool {
+	return !t.Banned(id)
+}
+
+func (t *Tracker) InterceptUpgraded(network.Conn) (bool, control.DisconnectReason) {
+	return true, 0
+}
@@ -0,0 +1,102 @@
+package reputation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/utils"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	good, bad := peer.ID("good"), peer.ID("bad")
+
+	var bans []bool
+	scores := make(map[peer.ID]float64)
+	tracker := New(nil, utils.NewNopZapLogger()).WithListener(&SelectiveListener{
+		OnPeerScoreCb: func(id peer.ID, score float64) {
+			scores[id] = score
+		},
+		OnPeerBannedCb: func(id peer.ID, permanently bool) {
+			assert.Equal(t, bad, id)
+			bans = append(bans, permanently)
+		},
+	})
+	tracker.now = func() time.Time { return now }
+
+	t.Run("useful bytes raise the score up to the maximum", func(t *testing.T) {
+		tracker.RecordUsefulBytes(good, 3*BytesPerPoint)
+		assert.Equal(t, float64(3), scores[good])
+
+		tracker.RecordUsefulBytes(good, 1000*BytesPerPoint)
+		assert.Equal(t, float64(maxScore), scores[good])
+		assert.False(t, tracker.Banned(good))
+	})
+
+	t.Run("invalid data and timeouts lead to a temporary ban", func(t *testing.T) {
+		tracker.RecordInvalidData(bad)
+		assert.Equal(t, float64(-InvalidDataPenalty), scores[bad])
+		assert.False(t, tracker.Banned(bad))
+
+		tracker.RecordTimeout(bad)
+		assert.True(t, tracker.Banned(bad))
+		assert.Equal(t, []bool{false}, bans)
+		assert.Equal(t, float64(0), scores[bad])
+
+		// banned peers can't connect
+		assert.False(t, tracker.InterceptPeerDial(bad))
+		assert.False(t, tracker.InterceptSecured(0, bad, nil))
+		assert.True(t, tracker.InterceptSecured(0, good, nil))
+
+		// the score of a banned peer doesn't change
+		tracker.RecordUsefulBytes(bad, 10*BytesPerPoint)
+		assert.Equal(t, float64(0), scores[bad])
+
+		now = now.Add(TemporaryBanDuration)
+		assert.False(t, tracker.Banned(bad))
+	})
+
+	t.Run("repeated bans are permanent", func(t *testing.T) {
+		for range MaxTemporaryBans {
+			tracker.RecordInvalidData(bad)
+			tracker.RecordInvalidData(bad)
+			now = now.Add(TemporaryBanDuration)
+		}
+		assert.Equal(t, []bool{false, false, false, true}, bans)
+
+		now = now.Add(365 * 24 * time.Hour)
+		assert.True(t, tracker.Banned(bad))
+	})
+
+	t.Run("records survive a restart", func(t *testing.T) {
+		records := tracker.Records()
+		require.Len(t, records, 2)
+		assert.Equal(t, Record{Score: maxScore}, records[good])
+		assert.Equal(t, Record{Bans: MaxTemporaryBans + 1, BannedUntil: records[bad].BannedUntil, BannedForever: true}, records[bad])
+
+		restarted := New(records, utils.NewNopZapLogger())
+		assert.True(t, restarted.Banned(bad))
+		assert.Equal(t, []PeerScore{{ID: bad, Record: records[bad]}, {ID: good, Record: records[good]}}, restarted.Scores())
+	})
+}
+
+func TestTrackerBan(t *testing.T) {
+	id := peer.ID("peer")
+
+	var bans []bool
+	tracker := New(nil, utils.NewNopZapLogger()).WithListener(&SelectiveListener{
+		OnPeerBannedCb: func(_ peer.ID, permanently bool) {
+			bans = append(bans, permanently)
+		},
+	})
+	tracker.RecordUsefulBytes(id, 10*BytesPerPoint)
+
+	tracker.Ban(id)
+	tracker.Ban(id)
+	assert.True(t, tracker.Banned(id))
+	assert.Equal(t, []bool{true}, bans)
+	assert.Equal(t, Record{Bans: 1, BannedForever: true}, tracker.Records()[id])
+}
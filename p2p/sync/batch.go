@@ -0,0 +1,283 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/p2p/gen"
+	"github.com/sourcegraph/conc/pool"
+	"google.golang.org/protobuf/proto"
+)
+
+// errInvalidResponse is returned when a peer sends messages that don't follow the protocol, as opposed
+// to failing to send them.
+var errInvalidResponse = errors.New("invalid response")
+
+// blockParts holds the p2p messages that make up a block.
+type blockParts struct {
+	header        *gen.SignedBlockHeader
+	txs           []*gen.Transaction
+	receipts      []*gen.Receipt
+	events        []*gen.Event
+	classes       []*gen.Class
+	contractDiffs []*gen.ContractDiff
+}
+
+// fetchBatch requests up to limit blocks starting at start from the peer behind client. Fewer blocks are
+// returned if the peer doesn't have all of them. Every part is requested for the whole batch at once, the
+// responses are split into blocks with the counts committed to in the headers.
+func fetchBatch(ctx context.Context, client *Client, start, limit uint64) ([]*blockParts, error) {
+	headers, err := fetchHeaders(ctx, client, iteration(start, limit))
+	if err != nil {
+		return nil, err
+	}
+	if len(headers) == 0 {
+		return nil, nil
+	}
+
+	var (
+		txs        []*gen.TransactionWithReceipt
+		events     []*gen.Event
+		stateDiffs []*gen.StateDiffsResponse
+		classes    []*gen.Class
+	)
+	it := iteration(start, uint64(len(headers)))
+	p := pool.New().WithErrors().WithContext(ctx)
+	p.Go(func(ctx context.Context) (err error) {
+		txs, err = fetchTransactions(ctx, client, it)
+		return err
+	})
+	p.Go(func(ctx context.Context) (err error) {
+		events, err = fetchEvents(ctx, client, it)
+		return err
+	})
+	p.Go(func(ctx context.Context) (err error) {
+		stateDiffs, err = fetchStateDiffs(ctx, client, it)
+		return err
+	})
+	p.Go(func(ctx context.Context) (err error) {
+		classes, err = fetchClasses(ctx, client, it)
+		return err
+	})
+	if err := p.Wait(); err != nil {
+		return nil, err
+	}
+
+	blocks, err := splitBlockParts(headers, txs, events, stateDiffs, classes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidResponse, err)
+	}
+	return blocks, nil
+}
+
+func iteration(start, limit uint64) *gen.Iteration {
+	return &gen.Iteration{
+		Start:     &gen.Iteration_BlockNumber{BlockNumber: start},
+		Direction: gen.Iteration_Forward,
+		Limit:     limit,
+		Step:      1,
+	}
+}
+
+func fetchHeaders(ctx context.Context, client *Client, it *gen.Iteration) ([]*gen.SignedBlockHeader, error) {
+	headersIt, err := client.RequestBlockHeaders(ctx, &gen.BlockHeadersRequest{Iteration: it})
+	if err != nil {
+		return nil, err
+	}
+
+	expected := it.GetBlockNumber()
+	var headers []*gen.SignedBlockHeader
+	for res := range headersIt {
+		switch v := res.HeaderMessage.(type) {
+		case *gen.BlockHeadersResponse_Header:
+			if v.Header.GetNumber() != expected {
+				return nil, fmt.Errorf("%w: expected header of block %d, got %d", errInvalidResponse, expected, v.Header.GetNumber())
+			}
+			headers = append(headers, v.Header)
+			expected++
+		case *gen.BlockHeadersResponse_Fin:
+			return headers, nil
+		default:
+			return nil, fmt.Errorf("%w: unexpected HeaderMessage %T", errInvalidResponse, v)
+		}
+	}
+	return nil, fmt.Errorf("headers stream ended before Fin")
+}
+
+func fetchTransactions(ctx context.Context, client *Client, it *gen.Iteration) ([]*gen.TransactionWithReceipt, error) {
+	txsIt, err := client.RequestTransactions(ctx, &gen.TransactionsRequest{Iteration: it})
+	if err != nil {
+		return nil, err
+	}
+
+	var txs []*gen.TransactionWithReceipt
+	for res := range txsIt {
+		switch v := res.TransactionMessage.(type) {
+		case *gen.TransactionsResponse_TransactionWithReceipt:
+			txs = append(txs, v.TransactionWithReceipt)
+		case *gen.TransactionsResponse_Fin:
+			return txs, nil
+		default:
+			return nil, fmt.Errorf("%w: unexpected TransactionMessage %T", errInvalidResponse, v)
+		}
+	}
+	return nil, fmt.Errorf("transactions stream ended before Fin")
+}
+
+func fetchEvents(ctx context.Context, client *Client, it *gen.Iteration) ([]*gen.Event, error) {
+	eventsIt, err := client.RequestEvents(ctx, &gen.EventsRequest{Iteration: it})
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*gen.Event
+	for res := range eventsIt {
+		switch v := res.EventMessage.(type) {
+		case *gen.EventsResponse_Event:
+			events = append(events, v.Event)
+		case *gen.EventsResponse_Fin:
+			return events, nil
+		default:
+			return nil, fmt.Errorf("%w: unexpected EventMessage %T", errInvalidResponse, v)
+		}
+	}
+	return nil, fmt.Errorf("events stream ended before Fin")
+}
+
+func fetchStateDiffs(ctx context.Context, client *Client, it *gen.Iteration) ([]*gen.StateDiffsResponse, error) {
+	stateDiffsIt, err := client.RequestStateDiffs(ctx, &gen.StateDiffsRequest{Iteration: it})
+	if err != nil {
+		return nil, err
+	}
+
+	var stateDiffs []*gen.StateDiffsResponse
+	for res := range stateDiffsIt {
+		switch v := res.StateDiffMessage.(type) {
+		case *gen.StateDiffsResponse_ContractDiff, *gen.StateDiffsResponse_DeclaredClass:
+			stateDiffs = append(stateDiffs, res)
+		case *gen.StateDiffsResponse_Fin:
+			return stateDiffs, nil
+		default:
+			return nil, fmt.Errorf("%w: unexpected StateDiffMessage %T", errInvalidResponse, v)
+		}
+	}
+	return nil, fmt.Errorf("state diffs stream ended before Fin")
+}
+
+func fetchClasses(ctx context.Context, client *Client, it *gen.Iteration) ([]*gen.Class, error) {
+	classesIt, err := client.RequestClasses(ctx, &gen.ClassesRequest{Iteration: it})
+	if err != nil {
+		return nil, err
+	}
+
+	var classes []*gen.Class
+	for res := range classesIt {
+		switch v := res.ClassMessage.(type) {
+		case *gen.ClassesResponse_Class:
+			classes = append(classes, v.Class)
+		case *gen.ClassesResponse_Fin:
+			return classes, nil
+		default:
+			return nil, fmt.Errorf("%w: unexpected ClassMessage %T", errInvalidResponse, v)
+		}
+	}
+	return nil, fmt.Errorf("classes stream ended before Fin")
+}
+
+// splitBlockParts assigns the messages received for a batch to the blocks of the given headers. Peers send
+// all the messages of a block before the ones of the next block, so the transaction, event and state diff
+// counts of the headers are enough to split them. Classes are split by the number of classes each state
+// diff declares.
+func splitBlockParts(headers []*gen.SignedBlockHeader, txs []*gen.TransactionWithReceipt, events []*gen.Event,
+	stateDiffs []*gen.StateDiffsResponse, classes []*gen.Class,
+) ([]*blockParts, error) {
+	blocks := make([]*blockParts, len(headers))
+	for i, header := range headers {
+		block := &blockParts{header: header}
+		number := header.GetNumber()
+
+		txCount := header.GetTransactions().GetNLeaves()
+		if uint64(len(txs)) < txCount {
+			return nil, fmt.Errorf("block %d: expected %d transactions, got %d", number, txCount, len(txs))
+		}
+		for _, tx := range txs[:txCount] {
+			block.txs = append(block.txs, tx.Transaction)
+			block.receipts = append(block.receipts, tx.Receipt)
+		}
+		txs = txs[txCount:]
+
+		eventCount := header.GetEvents().GetNLeaves()
+		if uint64(len(events)) < eventCount {
+			return nil, fmt.Errorf("block %d: expected %d events, got %d", number, eventCount, len(events))
+		}
+		block.events, events = events[:eventCount], events[eventCount:]
+
+		var length, declaredClasses uint64
+		stateDiffLength := header.GetStateDiffCommitment().GetStateDiffLength()
+		for length < stateDiffLength {
+			if len(stateDiffs) == 0 {
+				return nil, fmt.Errorf("block %d: expected a state diff of length %d, got %d", number, stateDiffLength, length)
+			}
+
+			switch v := stateDiffs[0].StateDiffMessage.(type) {
+			case *gen.StateDiffsResponse_ContractDiff:
+				length += contractDiffLength(v.ContractDiff)
+				block.contractDiffs = append(block.contractDiffs, v.ContractDiff)
+			case *gen.StateDiffsResponse_DeclaredClass:
+				length++
+				declaredClasses++
+			}
+			stateDiffs = stateDiffs[1:]
+		}
+		if length != stateDiffLength {
+			return nil, fmt.Errorf("block %d: expected a state diff of length %d, got %d", number, stateDiffLength, length)
+		}
+
+		if uint64(len(classes)) < declaredClasses {
+			return nil, fmt.Errorf("block %d: expected %d classes, got %d", number, declaredClasses, len(classes))
+		}
+		block.classes, classes = classes[:declaredClasses], classes[declaredClasses:]
+
+		blocks[i] = block
+	}
+
+	if len(txs) > 0 || len(events) > 0 || len(stateDiffs) > 0 || len(classes) > 0 {
+		return nil, fmt.Errorf("received more data than the headers of blocks %d to %d commit to",
+			headers[0].GetNumber(), headers[len(headers)-1].GetNumber())
+	}
+	return blocks, nil
+}
+
+// size returns the encoded size of the messages of the block.
+func (b *blockParts) size() int {
+	size := proto.Size(b.header)
+	for _, tx := range b.txs {
+		size += proto.Size(tx)
+	}
+	for _, receipt := range b.receipts {
+		size += proto.Size(receipt)
+	}
+	for _, event := range b.events {
+		size += proto.Size(event)
+	}
+	for _, class := range b.classes {
+		size += proto.Size(class)
+	}
+	for _, diff := range b.contractDiffs {
+		size += proto.Size(diff)
+	}
+	return size
+}
+
+// contractDiffLength returns the number of state diff entries a contract diff accounts for.
+func contractDiffLength(diff *gen.ContractDiff) uint64 {
+	length := uint64(len(diff.Values))
+	if diff.Nonce != nil {
+		length++
+	}
+	if diff.ClassHash != nil {
+		length++
+	}
+	return length
+}
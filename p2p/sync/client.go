@@ -123,3 +123,21 @@ func (c *Client) RequestTransactions(ctx context.Context, req *gen.TransactionsR
 	return requestAndReceiveStream[*gen.TransactionsRequest, *gen.TransactionsResponse](
 		ctx, c.newStream, TransactionsPID(), req, c.log)
 }
+
+func (c *Client) RequestContractRange(ctx context.Context, req *gen.ContractRangeRequest) (iter.Seq[*gen.ContractRangeResponse], error) {
+	return requestAndReceiveStream[*gen.ContractRangeRequest, *gen.ContractRangeResponse](
+		ctx, c.newStream, SnapContractsPID(), req, c.log)
+}
+
+func (c *Client) RequestClassRange(ctx context.Context, req *gen.ClassRangeRequest) (iter.Seq[*gen.ClassRangeResponse], error) {
+	return requestAndReceiveStream[*gen.ClassRangeRequest, *gen.ClassRangeResponse](ctx, c.newStream, SnapClassesPID(), req, c.log)
+}
+
+func (c *Client) RequestContractStorage(ctx context.Context, req *gen.ContractStorageRequest) (iter.Seq[*gen.ContractStorageResponse], error) {
+	return requestAndReceiveStream[*gen.ContractStorageRequest, *gen.ContractStorageResponse](
+		ctx, c.newStream, SnapStoragePID(), req, c.log)
+}
+
+func (c *Client) RequestClassesByHash(ctx context.Context, req *gen.ClassHashesRequest) (iter.Seq[*gen.ClassesResponse], error) {
+	return requestAndReceiveStream[*gen.ClassHashesRequest, *gen.ClassesResponse](ctx, c.newStream, ClassHashesPID(), req, c.log)
+}
@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"github.com/NethermindEth/juno/utils"
 	"github.com/libp2p/go-libp2p/core/protocol"
 )
 
@@ -25,3 +26,24 @@ func ClassesPID() protocol.ID {
 func StateDiffPID() protocol.ID {
 	return Prefix + "/state_diffs/0.1.0-rc.0"
 }
+
+func SnapContractsPID() protocol.ID {
+	return Prefix + "/snap/contracts/0.1.0-rc.0"
+}
+
+func SnapClassesPID() protocol.ID {
+	return Prefix + "/snap/classes/0.1.0-rc.0"
+}
+
+func SnapStoragePID() protocol.ID {
+	return Prefix + "/snap/storage/0.1.0-rc.0"
+}
+
+func ClassHashesPID() protocol.ID {
+	return Prefix + "/class_hashes/0.1.0-rc.0"
+}
+
+// BlockAnnouncementsTopic is the pubsub topic new blocks of the given network are announced on.
+func BlockAnnouncementsTopic(network *utils.Network) string {
+	return Prefix + "/" + network.L2ChainID + "/block_announcements/0.1.0-rc.0"
+}
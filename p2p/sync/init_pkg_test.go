@@ -0,0 +1,5 @@
+package sync
+
+import (
+	_ "github.com/NethermindEth/juno/encoder/registry"
+)
@@ -0,0 +1,275 @@
+package sync
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/sourcegraph/conc"
+)
+
+const (
+	// blocksPerBatch is the number of blocks requested from a peer at once.
+	blocksPerBatch = 32
+	// maxBatchesAhead bounds the number of batches being fetched or waiting to be stored.
+	maxBatchesAhead = 16
+	// throughputWeight is the weight of the latest batch in the throughput of a peer.
+	throughputWeight = 0.5
+)
+
+// batch is a range of consecutive blocks fetched from a single peer.
+type batch struct {
+	start uint64
+	limit uint64
+	// excluded are the peers that failed to serve the batch or don't have its first block
+	excluded map[peer.ID]struct{}
+	// failed is set if a peer failed to serve the batch, as opposed to not having it
+	failed bool
+}
+
+func newBatch(start, limit uint64) *batch {
+	return &batch{start: start, limit: limit, excluded: make(map[peer.ID]struct{})}
+}
+
+type batchResult struct {
+	batch    *batch
+	peer     peer.ID
+	blocks   []*blockParts
+	duration time.Duration
+	err      error
+}
+
+// peerStats tracks how a peer served the batches assigned to it.
+type peerStats struct {
+	// throughput is the exponentially weighted number of blocks per second the peer served, 0 if unknown
+	throughput float64
+	busy       bool
+}
+
+// syncFrom fetches the blocks from start onwards in batches, from several peers concurrently, and stores
+// them in order. A batch that fails or isn't fully served is retried on a different peer. syncFrom returns
+// the number of blocks stored once no peer has the next block, or an error if a batch couldn't be fetched
+// from any peer.
+//
+//nolint:gocyclo
+func (s *Service) syncFrom(ctx context.Context, start uint64) (uint64, error) {
+	var wg conc.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		results   = make(chan batchResult)
+		pending   []*batch
+		fetched   = make(map[uint64]batchResult)
+		inFlight  int
+		next      = start
+		scheduled = start
+		// end is the first block no peer has
+		end uint64 = math.MaxUint64
+	)
+
+	for {
+		// Store the fetched batches that follow the stored blocks
+		for r, ok := fetched[next]; ok; r, ok = fetched[next] {
+			delete(fetched, next)
+
+			stored, err := s.storeBlocks(r.blocks)
+			next += uint64(stored)
+			if stored > 0 {
+				var usefulBytes int
+				for _, block := range r.blocks[:stored] {
+					usefulBytes += block.size()
+				}
+				s.reputation.RecordUsefulBytes(r.peer, usefulBytes)
+			}
+			if err != nil {
+				if !errors.Is(err, errInvalidResponse) {
+					return next - start, err
+				}
+				s.log.Debugw("Failed to store blocks", "peer", r.peer, "err", err)
+				s.recordFailure(r.peer, err)
+
+				retry := newBatch(next, uint64(len(r.blocks)-stored))
+				retry.excluded[r.peer] = struct{}{}
+				retry.failed = true
+				pending = insertBatch(pending, retry)
+			}
+		}
+
+		for len(pending)+inFlight+len(fetched) < maxBatchesAhead && scheduled < end {
+			pending = insertBatch(pending, newBatch(scheduled, blocksPerBatch))
+			scheduled += blocksPerBatch
+		}
+
+		// Assign the pending batches to the fastest idle peers, lowest blocks first
+		candidates := s.connectedPeers()
+		for i := 0; i < len(pending); {
+			b := pending[i]
+			if b.start >= end {
+				pending = slices.Delete(pending, i, i+1)
+				continue
+			}
+
+			id, ok := s.pickPeer(candidates, b.excluded)
+			if !ok {
+				if !allExcluded(candidates, b.excluded) {
+					// wait for a peer to become idle
+					i++
+					continue
+				}
+
+				if b.failed {
+					return next - start, fmt.Errorf("failed to fetch blocks %d to %d from any peer", b.start, b.start+b.limit-1)
+				}
+				// none of the peers has the batch
+				end = b.start
+				pending = slices.Delete(pending, i, i+1)
+				continue
+			}
+
+			pending = slices.Delete(pending, i, i+1)
+			s.peers[id].busy = true
+			inFlight++
+			wg.Go(func() {
+				client := NewClient(s.peerStream(id), s.network, s.log)
+				begin := time.Now()
+				blocks, err := fetchBatch(ctx, client, b.start, b.limit)
+
+				select {
+				case <-ctx.Done():
+				case results <- batchResult{batch: b, peer: id, blocks: blocks, duration: time.Since(begin), err: err}:
+				}
+			})
+		}
+
+		if inFlight == 0 {
+			if len(pending) > 0 {
+				return next - start, fmt.Errorf("no peers available for blocks %d to %d", pending[0].start,
+					pending[0].start+pending[0].limit-1)
+			}
+			return next - start, nil
+		}
+
+		var r batchResult
+		select {
+		case <-ctx.Done():
+			return next - start, ctx.Err()
+		case r = <-results:
+			inFlight--
+		}
+
+		b := r.batch
+		if stats, ok := s.peers[r.peer]; ok {
+			stats.busy = false
+		}
+		switch {
+		case r.err != nil:
+			s.log.Debugw("Failed to fetch blocks", "peer", r.peer, "start", b.start, "limit", b.limit, "err", r.err)
+			s.recordFailure(r.peer, r.err)
+			b.excluded[r.peer] = struct{}{}
+			b.failed = true
+			pending = insertBatch(pending, b)
+		case len(r.blocks) == 0:
+			b.excluded[r.peer] = struct{}{}
+			pending = insertBatch(pending, b)
+		default:
+			s.recordSuccess(r.peer, len(r.blocks), r.duration)
+			fetched[b.start] = r
+
+			if received := uint64(len(r.blocks)); received < b.limit {
+				// the peer doesn't have the rest of the batch
+				rest := newBatch(b.start+received, b.limit-received)
+				maps.Copy(rest.excluded, b.excluded)
+				rest.excluded[r.peer] = struct{}{}
+				rest.failed = b.failed
+				pending = insertBatch(pending, rest)
+			}
+		}
+	}
+}
+
+// pickPeer returns the idle candidate with the highest throughput that isn't excluded. Peers that haven't
+// served a batch yet come first so that their throughput gets measured.
+func (s *Service) pickPeer(candidates []peer.ID, excluded map[peer.ID]struct{}) (peer.ID, bool) {
+	var (
+		best      peer.ID
+		bestStats *peerStats
+	)
+	for _, id := range candidates {
+		if _, ok := excluded[id]; ok {
+			continue
+		}
+
+		stats, ok := s.peers[id]
+		if !ok {
+			stats = new(peerStats)
+			s.peers[id] = stats
+		}
+		if stats.busy {
+			continue
+		}
+
+		if bestStats == nil || untested(stats) && !untested(bestStats) ||
+			untested(stats) == untested(bestStats) && stats.throughput > bestStats.throughput {
+			best, bestStats = id, stats
+		}
+	}
+	return best, bestStats != nil
+}
+
+func untested(stats *peerStats) bool {
+	return stats.throughput == 0
+}
+
+func (s *Service) recordSuccess(id peer.ID, blocks int, duration time.Duration) {
+	stats, ok := s.peers[id]
+	if !ok {
+		return
+	}
+
+	throughput := float64(blocks) / max(duration.Seconds(), time.Millisecond.Seconds())
+	if untested(stats) {
+		stats.throughput = throughput
+	} else {
+		stats.throughput = throughputWeight*throughput + (1-throughputWeight)*stats.throughput
+	}
+	s.log.Debugw("Fetched blocks", "peer", id, "blocks", blocks, "duration", duration, "throughput", stats.throughput)
+}
+
+// recordFailure lowers the reputation of a peer that failed to serve a batch and drops it once it is banned.
+// Peers that sent invalid data are penalised more than the ones that stopped responding.
+func (s *Service) recordFailure(id peer.ID, err error) {
+	if errors.Is(err, errInvalidResponse) {
+		s.reputation.RecordInvalidData(id)
+	} else {
+		s.reputation.RecordTimeout(id)
+	}
+
+	if s.reputation.Banned(id) {
+		s.removePeer(id)
+	}
+}
+
+func allExcluded(candidates []peer.ID, excluded map[peer.ID]struct{}) bool {
+	for _, id := range candidates {
+		if _, ok := excluded[id]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// insertBatch inserts b into batches, which are sorted by their first block.
+func insertBatch(batches []*batch, b *batch) []*batch {
+	i, _ := slices.BinarySearchFunc(batches, b, func(x, y *batch) int {
+		return cmp.Compare(x.start, y.start)
+	})
+	return slices.Insert(batches, i, b)
+}
@@ -0,0 +1,314 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/adapters/core2p2p"
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/p2p/gen"
+	"github.com/NethermindEth/juno/p2p/peers"
+	"github.com/NethermindEth/juno/p2p/reputation"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSplitBlockParts(t *testing.T) {
+	header := func(number, txs, events, stateDiffLength uint64) *gen.SignedBlockHeader {
+		return &gen.SignedBlockHeader{
+			Number:              number,
+			Transactions:        &gen.Patricia{NLeaves: txs},
+			Events:              &gen.Patricia{NLeaves: events},
+			StateDiffCommitment: &gen.StateDiffCommitment{StateDiffLength: stateDiffLength},
+		}
+	}
+	contractDiff := func(values int, nonce, classHash bool) *gen.StateDiffsResponse {
+		diff := &gen.ContractDiff{Values: make([]*gen.ContractStoredValue, values)}
+		if nonce {
+			diff.Nonce = &gen.Felt252{}
+		}
+		if classHash {
+			diff.ClassHash = &gen.Hash{}
+		}
+		return &gen.StateDiffsResponse{StateDiffMessage: &gen.StateDiffsResponse_ContractDiff{ContractDiff: diff}}
+	}
+	declaredClass := &gen.StateDiffsResponse{StateDiffMessage: &gen.StateDiffsResponse_DeclaredClass{}}
+
+	headers := []*gen.SignedBlockHeader{header(5, 2, 1, 5), header(6, 0, 0, 0), header(7, 1, 3, 2)}
+	txs := []*gen.TransactionWithReceipt{{}, {}, {}}
+	events := []*gen.Event{{}, {}, {}, {}}
+	stateDiffs := []*gen.StateDiffsResponse{
+		contractDiff(2, true, false), declaredClass, declaredClass, // block 5
+		contractDiff(0, true, true), // block 7
+	}
+	classes := []*gen.Class{{}, {}}
+
+	t.Run("messages are split with the header counts", func(t *testing.T) {
+		blocks, err := splitBlockParts(headers, txs, events, stateDiffs, classes)
+		require.NoError(t, err)
+		require.Len(t, blocks, 3)
+
+		for i, expected := range []struct {
+			txs, events, contractDiffs, classes int
+		}{{2, 1, 1, 2}, {0, 0, 0, 0}, {1, 3, 1, 0}} {
+			assert.Equal(t, headers[i], blocks[i].header)
+			assert.Len(t, blocks[i].txs, expected.txs)
+			assert.Len(t, blocks[i].receipts, expected.txs)
+			assert.Len(t, blocks[i].events, expected.events)
+			assert.Len(t, blocks[i].contractDiffs, expected.contractDiffs)
+			assert.Len(t, blocks[i].classes, expected.classes)
+		}
+	})
+
+	t.Run("missing messages", func(t *testing.T) {
+		_, err := splitBlockParts(headers, txs[:2], events, stateDiffs, classes)
+		require.ErrorContains(t, err, "block 7: expected 1 transactions, got 0")
+
+		_, err = splitBlockParts(headers, txs, events, stateDiffs[:3], classes)
+		require.ErrorContains(t, err, "block 7: expected a state diff of length 2, got 0")
+
+		_, err = splitBlockParts(headers, txs, events, stateDiffs, classes[:1])
+		require.ErrorContains(t, err, "block 5: expected 2 classes, got 1")
+	})
+
+	t.Run("extra messages", func(t *testing.T) {
+		_, err := splitBlockParts(headers, txs, append(events, &gen.Event{}), stateDiffs, classes)
+		require.ErrorContains(t, err, "received more data than the headers of blocks 5 to 7 commit to")
+	})
+
+	t.Run("contract diff across the state diff length", func(t *testing.T) {
+		_, err := splitBlockParts(headers[:1], txs[:2], events[:1], []*gen.StateDiffsResponse{contractDiff(6, false, false)}, nil)
+		require.ErrorContains(t, err, "block 5: expected a state diff of length 5, got 6")
+	})
+}
+
+func TestPickPeer(t *testing.T) {
+	s := &Service{log: utils.NewNopZapLogger(), peers: make(map[peer.ID]*peerStats)}
+	slow, fast, fresh := peer.ID("slow"), peer.ID("fast"), peer.ID("fresh")
+	candidates := []peer.ID{slow, fast}
+
+	// Peers are tracked when first considered
+	_, ok := s.pickPeer(candidates, nil)
+	require.True(t, ok)
+	s.recordSuccess(slow, 10, 10*time.Second)
+	s.recordSuccess(fast, 10, time.Second)
+
+	id, ok := s.pickPeer(candidates, nil)
+	require.True(t, ok)
+	assert.Equal(t, fast, id)
+
+	t.Run("excluded and busy peers are skipped", func(t *testing.T) {
+		id, ok := s.pickPeer(candidates, map[peer.ID]struct{}{fast: {}})
+		require.True(t, ok)
+		assert.Equal(t, slow, id)
+
+		s.peers[slow].busy = true
+		_, ok = s.pickPeer(candidates, map[peer.ID]struct{}{fast: {}})
+		assert.False(t, ok)
+		assert.False(t, allExcluded(candidates, map[peer.ID]struct{}{fast: {}}))
+		assert.True(t, allExcluded(candidates, map[peer.ID]struct{}{fast: {}, slow: {}}))
+		s.peers[slow].busy = false
+	})
+
+	t.Run("untested peers come first", func(t *testing.T) {
+		id, ok := s.pickPeer(append(candidates, fresh), nil)
+		require.True(t, ok)
+		assert.Equal(t, fresh, id)
+	})
+
+	t.Run("throughput is weighted", func(t *testing.T) {
+		s.recordSuccess(fast, 1, time.Second)
+		assert.InDelta(t, 5.5, s.peers[fast].throughput, 1e-9)
+
+		id, ok := s.pickPeer(candidates, nil)
+		require.True(t, ok)
+		assert.Equal(t, fast, id)
+	})
+}
+
+func TestInsertBatch(t *testing.T) {
+	var batches []*batch
+	for _, start := range []uint64{64, 0, 32, 16} {
+		batches = insertBatch(batches, newBatch(start, 16))
+	}
+
+	starts := make([]uint64, len(batches))
+	for i, b := range batches {
+		starts[i] = b.start
+	}
+	assert.Equal(t, []uint64{0, 16, 32, 64}, starts)
+}
+
+func TestSyncFrom(t *testing.T) {
+	const (
+		headNumber = 6
+		// start is the first block that doesn't declare classes
+		start = 3
+	)
+
+	gw := adaptfeeder.New(feeder.NewTestClient(t, &utils.Sepolia))
+	source := blockchain.New(pebble.NewMemTest(t), &utils.Sepolia, nil)
+	for number := range uint64(headNumber + 1) {
+		su, err := gw.StateUpdate(context.Background(), number)
+		require.NoError(t, err)
+		block, err := gw.BlockByNumber(context.Background(), number)
+		require.NoError(t, err)
+		// p2p headers always carry the STRK and data gas prices, which the first blocks don't have
+		if block.GasPriceSTRK == nil || block.GasPriceSTRK.IsZero() {
+			block.GasPriceSTRK = new(felt.Felt).SetUint64(1)
+		}
+		if block.L1DataGasPrice == nil {
+			block.L1DataGasPrice = &core.GasPrice{PriceInWei: new(felt.Felt).SetUint64(1), PriceInFri: new(felt.Felt).SetUint64(1)}
+		}
+
+		commitments, err := source.SanityCheckNewHeight(block, su, nil)
+		require.NoError(t, err)
+		require.NoError(t, source.Store(block, commitments, su, nil))
+	}
+	head, err := source.Head()
+	require.NoError(t, err)
+
+	newHost := func(t *testing.T) host.Host {
+		h, err := libp2p.New(libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, h.Close())
+		})
+		return h
+	}
+
+	// serve makes server serve the blocks of source, with the given headers handler
+	serve := func(t *testing.T, server host.Host, headersHandler func(*peers.Handler) network.StreamHandler) {
+		handler := peers.NewHandler(source, utils.NewNopZapLogger())
+		t.Cleanup(handler.Close)
+		for pid, handle := range map[protocol.ID]network.StreamHandler{
+			HeadersPID():      headersHandler(handler),
+			EventsPID():       handler.EventsHandler,
+			TransactionsPID(): handler.TransactionsHandler,
+			ClassesPID():      handler.ClassesHandler,
+			StateDiffPID():    handler.StateDiffHandler,
+		} {
+			server.SetStreamHandler(pid, handle)
+		}
+	}
+
+	// misbehavingHeaders returns a headers handler that calls misbehave with the requested headers of source, if
+	// it has any, and only sends Fin otherwise so that the end of the chain is found.
+	misbehavingHeaders := func(misbehave func(stream network.Stream, headers []*gen.BlockHeadersResponse)) network.StreamHandler {
+		return func(stream network.Stream) {
+			reqBytes, err := io.ReadAll(stream)
+			require.NoError(t, err)
+			req := new(gen.BlockHeadersRequest)
+			require.NoError(t, proto.Unmarshal(reqBytes, req))
+
+			var headers []*gen.BlockHeadersResponse
+			start := req.Iteration.GetBlockNumber()
+			for number := start; number < start+req.Iteration.Limit && number <= headNumber; number++ {
+				header, err := source.BlockHeaderByNumber(number)
+				require.NoError(t, err)
+				commitments, err := source.BlockCommitmentsByNumber(number)
+				require.NoError(t, err)
+				su, err := source.StateUpdateByNumber(number)
+				require.NoError(t, err)
+				headers = append(headers, &gen.BlockHeadersResponse{HeaderMessage: &gen.BlockHeadersResponse_Header{
+					Header: core2p2p.AdaptHeader(header, commitments, su.StateDiff.Hash(), su.StateDiff.Length()),
+				}})
+			}
+			if len(headers) > 0 {
+				misbehave(stream, headers)
+				return
+			}
+
+			fin := &gen.BlockHeadersResponse{HeaderMessage: &gen.BlockHeadersResponse_Fin{}}
+			_, err = protodelim.MarshalTo(stream, fin)
+			require.NoError(t, err)
+			require.NoError(t, stream.Close())
+		}
+	}
+
+	tests := map[string]struct {
+		misbehave func(stream network.Stream, headers []*gen.BlockHeadersResponse)
+		penalty   float64
+	}{
+		"peer that fails": {
+			misbehave: func(stream network.Stream, _ []*gen.BlockHeadersResponse) {
+				require.NoError(t, stream.Reset())
+			},
+			penalty: reputation.TimeoutPenalty,
+		},
+		"peer that returns blocks out of order": {
+			misbehave: func(stream network.Stream, headers []*gen.BlockHeadersResponse) {
+				slices.Reverse(headers)
+				for _, header := range headers {
+					if _, err := protodelim.MarshalTo(stream, header); err != nil {
+						return
+					}
+				}
+				_, _ = protodelim.MarshalTo(stream, &gen.BlockHeadersResponse{HeaderMessage: &gen.BlockHeadersResponse_Fin{}})
+				_ = stream.Close()
+			},
+			penalty: reputation.InvalidDataPenalty,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			log := utils.NewNopZapLogger()
+			// the classes declared in the first blocks can't be served, so they are copied from the source chain
+			target := blockchain.New(pebble.NewMemTest(t), &utils.Sepolia, nil)
+			for number := range uint64(start) {
+				block, err := source.BlockByNumber(number)
+				require.NoError(t, err)
+				commitments, err := source.BlockCommitmentsByNumber(number)
+				require.NoError(t, err)
+				su, err := source.StateUpdateByNumber(number)
+				require.NoError(t, err)
+				require.NoError(t, target.Store(block, commitments, su, nil))
+			}
+
+			clientHost, goodHost, badHost := newHost(t), newHost(t), newHost(t)
+			serve(t, goodHost, func(handler *peers.Handler) network.StreamHandler { return handler.HeadersHandler })
+			serve(t, badHost, func(*peers.Handler) network.StreamHandler { return misbehavingHeaders(test.misbehave) })
+			for _, server := range []host.Host{goodHost, badHost} {
+				clientHost.Peerstore().AddAddrs(server.ID(), server.Addrs(), peerstore.PermanentAddrTTL)
+			}
+
+			tracker := reputation.New(nil, log)
+			s := New(target, clientHost, &utils.Sepolia, tracker, log)
+			// the misbehaving peer is the fastest one, so that the first batch is assigned to it
+			s.peers[goodHost.ID()] = &peerStats{throughput: 1}
+			s.peers[badHost.ID()] = &peerStats{throughput: 2}
+
+			stored, err := s.syncFrom(context.Background(), start)
+			// the misbehaving peer may have the blocks after the head, so the end of the chain isn't known
+			require.ErrorContains(t, err, fmt.Sprintf("failed to fetch blocks %d to %d from any peer", headNumber+1,
+				start+blocksPerBatch-1))
+			assert.Equal(t, uint64(headNumber-start+1), stored)
+
+			targetHead, err := target.Head()
+			require.NoError(t, err)
+			assert.Equal(t, head.Header, targetHead.Header)
+
+			records := tracker.Records()
+			assert.InDelta(t, -test.penalty, records[badHost.ID()].Score, 1e-9)
+			assert.GreaterOrEqual(t, records[goodHost.ID()].Score, 0.0)
+		})
+	}
+}
@@ -0,0 +1,680 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+
+	"github.com/NethermindEth/juno/adapters/core2p2p"
+	"github.com/NethermindEth/juno/adapters/p2p2core"
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/core/trie"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/p2p/gen"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// leavesPerRange is the number of leaves requested in each range of a trie.
+	leavesPerRange = 1024
+	// classesPerRequest is the number of class definitions requested at once.
+	classesPerRequest = 128
+)
+
+var (
+	// errStalePivot is returned when a peer no longer serves the state at the pivot, usually because it stored a
+	// new block.
+	errStalePivot = errors.New("peer no longer serves the state at the pivot")
+	// errStateDiscarded is returned when the state written so far fails to verify and has to be downloaded again.
+	errStateDiscarded = errors.New("snap synced state discarded")
+)
+
+var (
+	// maxTrieKey is the largest key of the tries committing to the state, which have a height of 251.
+	maxTrieKey = new(felt.Felt).SetBytes(append([]byte{0x07}, bytes.Repeat([]byte{0xff}, felt.Bytes-1)...))
+	one        = new(felt.Felt).SetUint64(1)
+)
+
+// pivot is the block the state is downloaded at.
+type pivot struct {
+	number uint64
+	hash   *felt.Felt
+	root   *felt.Felt
+}
+
+// snapSyncer downloads the state at the latest block of a peer, the pivot, rather than replaying every state diff
+// since genesis. Whenever the peer fails or moves on to a new block, the download continues where it stopped at
+// the latest block of a peer. The state is then brought to the last pivot by applying the state diffs of the
+// blocks since the first one, and the last pivot becomes the head of the chain.
+type snapSyncer struct {
+	*Service
+
+	peer   peer.ID
+	client *Client
+	pivot  *pivot
+	// anchor is the L1 head the blocks up to the pivot are checked against, since the pivot comes from a peer
+	anchor *core.L1Head
+	// lowest and highest are the pivots the state was written at, including by interrupted snap syncs
+	lowest, highest uint64
+	excluded        map[peer.ID]struct{}
+
+	// nextClass and nextContract are the keys the next ranges start at, nil once the tries are downloaded
+	nextClass, nextContract *felt.Felt
+	// storageOf is the contract whose storage is being downloaded, from nextSlot onwards
+	storageOf, nextSlot *felt.Felt
+	// classHashes are the classes whose definitions haven't been downloaded yet
+	classHashes map[felt.Felt]struct{}
+	// nextBlock is the next block to apply the state diff of
+	nextBlock *uint64
+	head      *blockBody
+}
+
+// snapSyncState downloads the state at a recent block and makes that block the head of the empty chain, after
+// which the blocks that follow it are synced one by one. The definitions of Cairo 0 classes that no contract uses
+// aren't downloaded, since nothing in the state refers to them.
+func (s *Service) snapSyncState(ctx context.Context) error {
+	ss, err := s.newSnapSyncer()
+	if err != nil {
+		return err
+	}
+
+	if err = ss.repivot(ctx); err != nil {
+		return err
+	}
+	for err = ss.syncSteps(ctx); errors.Is(err, errStateDiscarded); err = ss.syncSteps(ctx) {
+		s.log.Warnw("Snap syncing state again", "err", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.log.Infow("Snap synced state", "number", ss.head.block.Number, "hash", ss.head.block.Hash.ShortString(),
+		"root", ss.head.block.GlobalStateRoot.ShortString())
+	return nil
+}
+
+func (s *Service) newSnapSyncer() (*snapSyncer, error) {
+	ss := &snapSyncer{
+		Service:      s,
+		excluded:     make(map[peer.ID]struct{}),
+		nextClass:    &felt.Zero,
+		nextContract: &felt.Zero,
+		classHashes:  make(map[felt.Felt]struct{}),
+	}
+
+	var err error
+	ss.lowest, ss.highest, err = s.blockchain.SnapSyncPivots()
+	switch {
+	case errors.Is(err, db.ErrKeyNotFound):
+		ss.lowest = math.MaxUint64
+	case err != nil:
+		return nil, err
+	default:
+		s.log.Infow("Resuming snap sync", "lowestPivot", ss.lowest, "highestPivot", ss.highest)
+	}
+	return ss, nil
+}
+
+func (ss *snapSyncer) syncSteps(ctx context.Context) error {
+	for _, step := range []func(context.Context) error{ss.syncClasses, ss.syncContracts, ss.syncClassDefinitions, ss.heal} {
+		if err := ss.retry(ctx, step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retry runs step until it succeeds, moving to the latest block of a peer whenever it fails. If the state was
+// discarded, the steps have to start over instead.
+func (ss *snapSyncer) retry(ctx context.Context, step func(context.Context) error) error {
+	for {
+		stepErr := step(ctx)
+		if stepErr == nil || ctx.Err() != nil {
+			return stepErr
+		}
+
+		if !errors.Is(stepErr, errStalePivot) {
+			ss.log.Debugw("Failed to snap sync from peer", "peer", ss.peer, "err", stepErr)
+			ss.recordFailure(ss.peer, stepErr)
+			ss.excluded[ss.peer] = struct{}{}
+		}
+		if err := ss.repivot(ctx); err != nil {
+			return err
+		}
+		if errors.Is(stepErr, errStateDiscarded) {
+			return stepErr
+		}
+	}
+}
+
+// repivot makes the latest block of a peer the pivot. Since the state may already hold values from the highest
+// pivot, the state diffs of the blocks up to the new pivot only bring it up to date if it isn't lower. The pivot
+// can't precede the L1 head either, whose block the chain up to the pivot must go through.
+func (ss *snapSyncer) repivot(ctx context.Context) error {
+	anchor, err := ss.blockchain.L1Head()
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return errors.New("no L1 head to anchor the snap sync pivot to")
+	} else if err != nil {
+		return err
+	}
+	ss.anchor = anchor
+
+	hint := max(ss.highest, anchor.BlockNumber)
+	if ss.pivot != nil {
+		hint = max(hint, ss.pivot.number)
+	}
+
+	for _, id := range ss.connectedPeers() {
+		if _, ok := ss.excluded[id]; ok {
+			continue
+		}
+
+		client := NewClient(ss.peerStream(id), ss.network, ss.log)
+		header, err := fetchHead(ctx, client, hint)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			ss.recordFailure(id, err)
+			ss.excluded[id] = struct{}{}
+			continue
+		}
+		if header == nil || header.Number < ss.highest || header.Number < anchor.BlockNumber {
+			continue
+		}
+
+		ss.peer, ss.client = id, client
+		ss.pivot = &pivot{
+			number: header.Number,
+			hash:   p2p2core.AdaptHash(header.BlockHash),
+			root:   p2p2core.AdaptHash(header.StateRoot),
+		}
+		ss.lowest, ss.highest = min(ss.lowest, header.Number), header.Number
+		ss.log.Infow("Snap syncing state", "pivot", ss.pivot.number, "root", ss.pivot.root.ShortString(), "peer", id)
+		return nil
+	}
+	return fmt.Errorf("no peer has a state at or after block %d", max(ss.highest, anchor.BlockNumber))
+}
+
+// fetchHead returns the header of the latest block of the peer behind client, or nil if it has none. The peer is
+// expected to have the block numbered hint.
+func fetchHead(ctx context.Context, client *Client, hint uint64) (*gen.SignedBlockHeader, error) {
+	fetch := func(blockNumber uint64) (*gen.SignedBlockHeader, error) {
+		headers, err := fetchHeaders(ctx, client, iteration(blockNumber, 1))
+		if err != nil || len(headers) == 0 {
+			return nil, err
+		}
+		return headers[0], nil
+	}
+
+	head, err := fetch(hint)
+	if err != nil {
+		return nil, err
+	} else if head == nil {
+		if hint == 0 {
+			return nil, nil
+		}
+		return fetchHead(ctx, client, 0)
+	}
+
+	// Look for a block the peer doesn't have, then for the last one it has
+	lo, step := hint, uint64(1)
+	for {
+		header, err := fetch(lo + step)
+		if err != nil {
+			return nil, err
+		} else if header == nil {
+			break
+		}
+		lo, head = lo+step, header
+		step *= 2
+	}
+
+	for hi := lo + step; hi-lo > 1; {
+		mid := lo + (hi-lo)/2
+		header, err := fetch(mid)
+		if err != nil {
+			return nil, err
+		} else if header == nil {
+			hi = mid
+		} else {
+			lo, head = mid, header
+		}
+	}
+	return head, nil
+}
+
+// verifyRange checks that keys and values are every leaf of the trie with the given root from the key from up to
+// the last key. It returns the key the next range starts at, or nil if no leaf follows the range.
+func verifyRange(root, from *felt.Felt, keys, values []*felt.Felt, proof *gen.PatriciaRangeProof,
+	hash crypto.HashFn,
+) (*felt.Felt, error) {
+	proofNodes, err := p2p2core.AdaptProof(proof, hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidResponse, err)
+	}
+
+	hasMore, err := trie.VerifyRangeProof(root, from, keys, values, proofNodes, hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidResponse, err)
+	}
+	if !hasMore {
+		return nil, nil
+	}
+	return new(felt.Felt).Add(keys[len(keys)-1], one), nil
+}
+
+// verifyRoots checks that the roots of the tries a range comes from commit to the state at the pivot.
+func (ss *snapSyncer) verifyRoots(contractsRoot, classesRoot *gen.Hash) (*felt.Felt, *felt.Felt, error) {
+	contracts, classes := p2p2core.AdaptHash(contractsRoot), p2p2core.AdaptHash(classesRoot)
+	if contracts == nil || classes == nil {
+		return nil, nil, fmt.Errorf("%w: missing trie roots", errInvalidResponse)
+	}
+	if !core.StateCommitment(contracts, classes).Equal(ss.pivot.root) {
+		return nil, nil, fmt.Errorf("%w: trie roots don't match state root %s", errInvalidResponse, ss.pivot.root)
+	}
+	return contracts, classes, nil
+}
+
+// syncRanges requests the ranges of a trie from *next onwards and stores them until no leaf follows, store
+// moving *next past the range it stored. Since storing a range may involve further requests, each response is
+// read in full first.
+func syncRanges[ResT, RangeT any](ctx context.Context, next **felt.Felt,
+	request func(ctx context.Context, from *felt.Felt) (iter.Seq[ResT], error),
+	getRange func(ResT) (RangeT, bool, error), store func(ctx context.Context, r RangeT) error,
+) error {
+	for *next != nil {
+		responses, err := request(ctx, *next)
+		if err != nil {
+			return err
+		}
+
+		ranges, err := receiveRanges(responses, getRange)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range ranges {
+			if err = store(ctx, r); err != nil {
+				return err
+			}
+			if *next == nil {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func receiveRanges[ResT, RangeT any](responses iter.Seq[ResT], getRange func(ResT) (RangeT, bool, error)) ([]RangeT, error) {
+	var ranges []RangeT
+	for res := range responses {
+		r, fin, err := getRange(res)
+		if err != nil {
+			return nil, err
+		}
+		if fin {
+			if len(ranges) == 0 {
+				return nil, errStalePivot
+			}
+			return ranges, nil
+		}
+		ranges = append(ranges, r)
+	}
+	return nil, errors.New("range stream ended before Fin")
+}
+
+func (ss *snapSyncer) syncClasses(ctx context.Context) error {
+	request := func(ctx context.Context, from *felt.Felt) (iter.Seq[*gen.ClassRangeResponse], error) {
+		return ss.client.RequestClassRange(ctx, &gen.ClassRangeRequest{
+			StateRoot:      core2p2p.AdaptHash(ss.pivot.root),
+			Start:          core2p2p.AdaptHash(from),
+			End:            core2p2p.AdaptHash(maxTrieKey),
+			ChunksPerProof: leavesPerRange,
+		})
+	}
+	return syncRanges(ctx, &ss.nextClass, request, func(res *gen.ClassRangeResponse) (*gen.ClassRange, bool, error) {
+		switch v := res.ClassRangeMessage.(type) {
+		case *gen.ClassRangeResponse_Range:
+			return v.Range, false, nil
+		case *gen.ClassRangeResponse_Fin:
+			return nil, true, nil
+		default:
+			return nil, false, fmt.Errorf("%w: unexpected ClassRangeMessage %T", errInvalidResponse, v)
+		}
+	}, ss.storeClassRange)
+}
+
+func (ss *snapSyncer) storeClassRange(_ context.Context, r *gen.ClassRange) error {
+	_, classesRoot, err := ss.verifyRoots(r.ContractsRoot, r.ClassesRoot)
+	if err != nil {
+		return err
+	}
+	if classesRoot.IsZero() {
+		ss.nextClass = nil
+		return nil
+	}
+
+	classHashes := make([]*felt.Felt, 0, len(r.Classes))
+	leaves := make([]*felt.Felt, 0, len(r.Classes))
+	compiledClassHashes := make(map[felt.Felt]*felt.Felt, len(r.Classes))
+	for _, class := range r.Classes {
+		if class.ClassHash == nil || class.CompiledClassHash == nil {
+			return fmt.Errorf("%w: class without hash or compiled class hash", errInvalidResponse)
+		}
+
+		classHash, compiledClassHash := p2p2core.AdaptHash(class.ClassHash), p2p2core.AdaptHash(class.CompiledClassHash)
+		classHashes = append(classHashes, classHash)
+		leaves = append(leaves, core.ClassCommitment(compiledClassHash))
+		compiledClassHashes[*classHash] = compiledClassHash
+	}
+
+	next, err := verifyRange(classesRoot, ss.nextClass, classHashes, leaves, r.RangeProof, crypto.Poseidon)
+	if err != nil {
+		return err
+	}
+
+	err = ss.blockchain.StoreSnapState(ss.pivot.number, func(state *core.State) error {
+		return state.PutCompiledClassHashes(compiledClassHashes)
+	})
+	if err != nil {
+		return err
+	}
+
+	for classHash := range compiledClassHashes {
+		ss.classHashes[classHash] = struct{}{}
+	}
+	ss.nextClass = next
+	return nil
+}
+
+func (ss *snapSyncer) syncContracts(ctx context.Context) error {
+	request := func(ctx context.Context, from *felt.Felt) (iter.Seq[*gen.ContractRangeResponse], error) {
+		return ss.client.RequestContractRange(ctx, &gen.ContractRangeRequest{
+			StateRoot:      core2p2p.AdaptHash(ss.pivot.root),
+			Start:          core2p2p.AdaptAddress(from),
+			End:            core2p2p.AdaptAddress(maxTrieKey),
+			ChunksPerProof: leavesPerRange,
+		})
+	}
+	return syncRanges(ctx, &ss.nextContract, request, func(res *gen.ContractRangeResponse) (*gen.ContractRange, bool, error) {
+		switch v := res.ContractRangeMessage.(type) {
+		case *gen.ContractRangeResponse_Range:
+			return v.Range, false, nil
+		case *gen.ContractRangeResponse_Fin:
+			return nil, true, nil
+		default:
+			return nil, false, fmt.Errorf("%w: unexpected ContractRangeMessage %T", errInvalidResponse, v)
+		}
+	}, ss.storeContractRange)
+}
+
+func (ss *snapSyncer) storeContractRange(ctx context.Context, r *gen.ContractRange) error {
+	contractsRoot, _, err := ss.verifyRoots(r.ContractsRoot, r.ClassesRoot)
+	if err != nil {
+		return err
+	}
+	if contractsRoot.IsZero() {
+		ss.nextContract = nil
+		return nil
+	}
+
+	addresses := make([]*felt.Felt, 0, len(r.States))
+	leaves := make([]*felt.Felt, 0, len(r.States))
+	for _, state := range r.States {
+		if state.Address == nil || state.ClassHash == nil || state.Nonce == nil || state.StorageRoot == nil {
+			return fmt.Errorf("%w: incomplete contract state", errInvalidResponse)
+		}
+		addresses = append(addresses, p2p2core.AdaptAddress(state.Address))
+		leaves = append(leaves, core.ContractCommitment(p2p2core.AdaptHash(state.StorageRoot),
+			p2p2core.AdaptHash(state.ClassHash), p2p2core.AdaptFelt(state.Nonce)))
+	}
+
+	next, err := verifyRange(contractsRoot, ss.nextContract, addresses, leaves, r.RangeProof, crypto.Pedersen)
+	if err != nil {
+		return err
+	}
+
+	for i, state := range r.States {
+		classHash := p2p2core.AdaptHash(state.ClassHash)
+		err = ss.storeContract(ctx, addresses[i], classHash, p2p2core.AdaptFelt(state.Nonce), p2p2core.AdaptHash(state.StorageRoot))
+		if err != nil {
+			return err
+		}
+
+		ss.classHashes[*classHash] = struct{}{}
+		ss.nextContract = new(felt.Felt).Add(addresses[i], one)
+	}
+	ss.nextContract = next
+	return nil
+}
+
+// storeContract writes the class hash and nonce of a contract, then downloads its storage. The storage is
+// downloaded from where it stopped if the download of the contract was interrupted.
+func (ss *snapSyncer) storeContract(ctx context.Context, addr, classHash, nonce, storageRoot *felt.Felt) error {
+	if ss.storageOf == nil || !ss.storageOf.Equal(addr) {
+		ss.storageOf, ss.nextSlot = addr, &felt.Zero
+	}
+
+	err := ss.blockchain.StoreSnapState(ss.pivot.number, func(state *core.State) error {
+		return state.PutContract(addr, classHash, nonce, nil, ss.pivot.number)
+	})
+	if err != nil {
+		return err
+	}
+
+	if storageRoot.IsZero() {
+		return nil
+	}
+
+	request := func(ctx context.Context, from *felt.Felt) (iter.Seq[*gen.ContractStorageResponse], error) {
+		return ss.client.RequestContractStorage(ctx, &gen.ContractStorageRequest{
+			StateRoot:      core2p2p.AdaptHash(ss.pivot.root),
+			Address:        core2p2p.AdaptAddress(addr),
+			Start:          core2p2p.AdaptFelt(from),
+			End:            core2p2p.AdaptFelt(maxTrieKey),
+			ChunksPerProof: leavesPerRange,
+		})
+	}
+	return syncRanges(ctx, &ss.nextSlot, request, func(res *gen.ContractStorageResponse) (*gen.ContractStorage, bool, error) {
+		switch v := res.ContractStorageMessage.(type) {
+		case *gen.ContractStorageResponse_Storage:
+			return v.Storage, false, nil
+		case *gen.ContractStorageResponse_Fin:
+			return nil, true, nil
+		default:
+			return nil, false, fmt.Errorf("%w: unexpected ContractStorageMessage %T", errInvalidResponse, v)
+		}
+	}, func(_ context.Context, r *gen.ContractStorage) error {
+		return ss.storeStorageRange(addr, storageRoot, r)
+	})
+}
+
+func (ss *snapSyncer) storeStorageRange(addr, storageRoot *felt.Felt, r *gen.ContractStorage) error {
+	keys := make([]*felt.Felt, 0, len(r.Values))
+	values := make([]*felt.Felt, 0, len(r.Values))
+	slots := make(map[felt.Felt]*felt.Felt, len(r.Values))
+	for _, stored := range r.Values {
+		if stored.Key == nil || stored.Value == nil {
+			return fmt.Errorf("%w: storage slot without key or value", errInvalidResponse)
+		}
+		key, value := p2p2core.AdaptFelt(stored.Key), p2p2core.AdaptFelt(stored.Value)
+		keys = append(keys, key)
+		values = append(values, value)
+		slots[*key] = value
+	}
+
+	next, err := verifyRange(storageRoot, ss.nextSlot, keys, values, r.RangeProof, crypto.Pedersen)
+	if err != nil {
+		return err
+	}
+
+	err = ss.blockchain.StoreSnapState(ss.pivot.number, func(state *core.State) error {
+		return state.PutContract(addr, nil, nil, slots, ss.pivot.number)
+	})
+	if err != nil {
+		return err
+	}
+	ss.nextSlot = next
+	return nil
+}
+
+// syncClassDefinitions downloads the definitions of the classes found in the tries.
+func (ss *snapSyncer) syncClassDefinitions(ctx context.Context) error {
+	for len(ss.classHashes) > 0 {
+		requested := make([]*gen.Hash, 0, classesPerRequest)
+		for classHash := range ss.classHashes {
+			requested = append(requested, core2p2p.AdaptHash(&classHash))
+			if len(requested) == classesPerRequest {
+				break
+			}
+		}
+
+		responses, err := ss.client.RequestClassesByHash(ctx, &gen.ClassHashesRequest{ClassHashes: requested})
+		if err != nil {
+			return err
+		}
+
+		classes := make(map[felt.Felt]core.Class, len(requested))
+		fin := false
+		for res := range responses {
+			switch v := res.ClassMessage.(type) {
+			case *gen.ClassesResponse_Class:
+				class := p2p2core.AdaptClass(v.Class)
+				classHash, err := class.Hash()
+				if err != nil {
+					return fmt.Errorf("%w: class hash calculation error: %v", errInvalidResponse, err)
+				}
+				if _, ok := ss.classHashes[*classHash]; !ok {
+					return fmt.Errorf("%w: unexpected class %s", errInvalidResponse, classHash)
+				}
+				classes[*classHash] = class
+			case *gen.ClassesResponse_Fin:
+				fin = true
+			default:
+				return fmt.Errorf("%w: unexpected ClassMessage %T", errInvalidResponse, v)
+			}
+			if fin {
+				break
+			}
+		}
+		if !fin {
+			return errors.New("classes stream ended before Fin")
+		}
+
+		err = ss.blockchain.StoreSnapState(ss.pivot.number, func(state *core.State) error {
+			for classHash, class := range classes {
+				if err := state.PutClass(&classHash, class, ss.pivot.number); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for classHash := range classes {
+			delete(ss.classHashes, classHash)
+		}
+		if len(classes) < len(requested) {
+			return fmt.Errorf("peer is missing %d classes", len(requested)-len(classes))
+		}
+	}
+	return nil
+}
+
+// heal applies the state diffs of the blocks from the lowest pivot, or the L1 head if it is lower, to the pivot,
+// then stores the pivot as the head of the chain. The block before is fetched too, for the state root the first
+// state update starts from. Since the state written so far can't be trusted if these blocks don't verify, it is
+// discarded when they don't.
+func (ss *snapSyncer) heal(ctx context.Context) error {
+	err := ss.replayBlocks(ctx)
+	if errors.Is(err, blockchain.ErrStateRootMismatch) {
+		err = fmt.Errorf("%w: %w", errInvalidResponse, err)
+	}
+	if errors.Is(err, errInvalidResponse) {
+		return ss.discardState(err)
+	}
+	return err
+}
+
+func (ss *snapSyncer) replayBlocks(ctx context.Context) error {
+	if ss.nextBlock == nil {
+		start := min(ss.lowest, ss.anchor.BlockNumber)
+		if start > 0 {
+			start--
+		}
+		ss.nextBlock = &start
+	}
+
+	for *ss.nextBlock <= ss.pivot.number {
+		blocks, err := fetchBatch(ctx, ss.client, *ss.nextBlock, min(blocksPerBatch, ss.pivot.number-*ss.nextBlock+1))
+		if err != nil {
+			return err
+		}
+		if len(blocks) == 0 {
+			return fmt.Errorf("peer doesn't have block %d", *ss.nextBlock)
+		}
+
+		for _, parts := range blocks {
+			if err = ss.applyBlock(parts); err != nil {
+				return err
+			}
+			*ss.nextBlock++
+		}
+	}
+
+	return ss.blockchain.StoreSnapHead(ss.head.block, ss.head.commitments, ss.head.stateUpdate)
+}
+
+func (ss *snapSyncer) applyBlock(parts *blockParts) error {
+	prevBlockRoot := &felt.Zero
+	if ss.head != nil {
+		prevBlockRoot = ss.head.block.GlobalStateRoot
+	}
+
+	b, err := ss.adaptAndSanityCheckBlock(parts, prevBlockRoot)
+	if err != nil {
+		return err
+	}
+	if ss.head != nil && !b.block.ParentHash.Equal(ss.head.block.Hash) {
+		return fmt.Errorf("%w: block %d doesn't follow block %d", errInvalidResponse, b.block.Number, ss.head.block.Number)
+	}
+	if b.block.Number == ss.pivot.number && !b.block.Hash.Equal(ss.pivot.hash) {
+		return fmt.Errorf("%w: block %d doesn't match the pivot", errInvalidResponse, b.block.Number)
+	}
+	if b.block.Number == ss.anchor.BlockNumber &&
+		(!b.block.Hash.Equal(ss.anchor.BlockHash) || !b.block.GlobalStateRoot.Equal(ss.anchor.StateRoot)) {
+		return fmt.Errorf("%w: block %d doesn't match the L1 head", errInvalidResponse, b.block.Number)
+	}
+
+	err = ss.blockchain.StoreSnapState(ss.pivot.number, func(state *core.State) error {
+		return state.ApplyStateDiff(b.block.Number, b.stateUpdate.StateDiff, b.newClasses)
+	})
+	if err != nil {
+		return err
+	}
+	ss.head = b
+	return nil
+}
+
+// discardState deletes the state written so far and resets the progress of the snap sync, so that it starts over.
+func (ss *snapSyncer) discardState(cause error) error {
+	if err := ss.blockchain.DiscardSnapState(); err != nil {
+		return err
+	}
+
+	ss.lowest, ss.highest = math.MaxUint64, 0
+	ss.nextClass, ss.nextContract = &felt.Zero, &felt.Zero
+	ss.storageOf, ss.nextSlot = nil, nil
+	ss.classHashes = make(map[felt.Felt]struct{})
+	ss.nextBlock, ss.head = nil, nil
+	return fmt.Errorf("%w: %w", errStateDiscarded, cause)
+}
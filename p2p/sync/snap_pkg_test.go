@@ -0,0 +1,212 @@
+package sync
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/db/pebble"
+	"github.com/NethermindEth/juno/p2p/peers"
+	"github.com/NethermindEth/juno/p2p/reputation"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapSync(t *testing.T) {
+	const (
+		headNumber   = 6
+		l1HeadNumber = 5
+	)
+
+	client := feeder.NewTestClient(t, &utils.Sepolia)
+	gw := adaptfeeder.New(client)
+	log := utils.NewNopZapLogger()
+
+	// The definitions of the classes declared in the first blocks aren't part of the test data
+	source := blockchain.New(pebble.NewMemTest(t), &utils.Sepolia, nil)
+	var stateUpdates []*core.StateUpdate
+	for i := range uint64(headNumber + 1) {
+		su, err := gw.StateUpdate(context.Background(), i)
+		require.NoError(t, err)
+		block, err := gw.BlockByNumber(context.Background(), i)
+		require.NoError(t, err)
+		stateUpdates = append(stateUpdates, su)
+		// p2p headers always carry the STRK and data gas prices, which the first blocks don't have
+		if block.GasPriceSTRK == nil || block.GasPriceSTRK.IsZero() {
+			block.GasPriceSTRK = new(felt.Felt).SetUint64(1)
+		}
+		if block.L1DataGasPrice == nil {
+			block.L1DataGasPrice = &core.GasPrice{PriceInWei: new(felt.Felt).SetUint64(1), PriceInFri: new(felt.Felt).SetUint64(1)}
+		}
+
+		commitments, err := source.SanityCheckNewHeight(block, su, nil)
+		require.NoError(t, err)
+		require.NoError(t, source.Store(block, commitments, su, nil))
+	}
+	head, err := source.Head()
+	require.NoError(t, err)
+	l1Block, err := source.BlockByNumber(l1HeadNumber)
+	require.NoError(t, err)
+	l1Head := &core.L1Head{BlockNumber: l1HeadNumber, BlockHash: l1Block.Hash, StateRoot: l1Block.GlobalStateRoot}
+
+	// newSnapSyncerWith returns a snap syncer writing to target, with a single peer serving the source chain,
+	// along with the reputation tracker of the syncer and the ID of the peer
+	newSnapSyncerWith := func(t *testing.T, target *blockchain.Blockchain) (*snapSyncer, *reputation.Tracker, peer.ID) {
+		newHost := func() host.Host {
+			h, err := libp2p.New(libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				require.NoError(t, h.Close())
+			})
+			return h
+		}
+		serverHost, clientHost := newHost(), newHost()
+		clientHost.Peerstore().AddAddrs(serverHost.ID(), serverHost.Addrs(), peerstore.PermanentAddrTTL)
+
+		handler := peers.NewHandler(source, log)
+		t.Cleanup(handler.Close)
+		for pid, handle := range map[protocol.ID]network.StreamHandler{
+			HeadersPID():       handler.HeadersHandler,
+			EventsPID():        handler.EventsHandler,
+			TransactionsPID():  handler.TransactionsHandler,
+			ClassesPID():       handler.ClassesHandler,
+			StateDiffPID():     handler.StateDiffHandler,
+			SnapContractsPID(): handler.ContractRangeHandler,
+			SnapClassesPID():   handler.ClassRangeHandler,
+			SnapStoragePID():   handler.ContractStorageHandler,
+			ClassHashesPID():   handler.ClassHashesHandler,
+		} {
+			serverHost.SetStreamHandler(pid, handle)
+		}
+
+		tracker := reputation.New(nil, log)
+		ss, err := New(target, clientHost, &utils.Sepolia, tracker, log).newSnapSyncer()
+		require.NoError(t, err)
+		return ss, tracker, serverHost.ID()
+	}
+
+	// newSnapSyncer returns a snap syncer writing to target whose pivot is anchored to the L1 head
+	newSnapSyncer := func(t *testing.T, target *blockchain.Blockchain) *snapSyncer {
+		require.NoError(t, target.SetL1Head(l1Head))
+		ss, _, _ := newSnapSyncerWith(t, target)
+		require.NoError(t, ss.repivot(context.Background()))
+		assert.Equal(t, &pivot{number: headNumber, hash: head.Hash, root: head.GlobalStateRoot}, ss.pivot)
+		return ss
+	}
+
+	t.Run("ranges of the tries rebuild the state at the pivot", func(t *testing.T) {
+		target := blockchain.New(pebble.NewMemTest(t), &utils.Sepolia, nil)
+		ss := newSnapSyncer(t, target)
+		require.NoError(t, ss.syncClasses(context.Background()))
+		require.NoError(t, ss.syncContracts(context.Background()))
+
+		require.NoError(t, target.StoreSnapState(headNumber, func(state *core.State) error {
+			root, err := state.Root()
+			require.NoError(t, err)
+			assert.Equal(t, head.GlobalStateRoot, root)
+			return nil
+		}))
+
+		for _, su := range stateUpdates {
+			for _, classHash := range su.StateDiff.DeployedContracts {
+				assert.Contains(t, ss.classHashes, *classHash)
+			}
+		}
+	})
+
+	t.Run("ranges are verified against the pivot", func(t *testing.T) {
+		target := blockchain.New(pebble.NewMemTest(t), &utils.Sepolia, nil)
+		ss := newSnapSyncer(t, target)
+		ss.pivot.root = new(felt.Felt).SetUint64(1)
+		require.ErrorIs(t, ss.syncContracts(context.Background()), errStalePivot)
+	})
+
+	t.Run("state diffs bring the state written at earlier pivots to the head", func(t *testing.T) {
+		const lowestPivot = 4
+
+		target := blockchain.New(pebble.NewMemTest(t), &utils.Sepolia, nil)
+		require.NoError(t, target.StoreSnapState(lowestPivot, func(state *core.State) error {
+			for i, su := range stateUpdates[:lowestPivot+1] {
+				if err := state.ApplyStateDiff(uint64(i), su.StateDiff, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+
+		ss := newSnapSyncer(t, target)
+		assert.Equal(t, uint64(lowestPivot), ss.lowest)
+		require.NoError(t, ss.heal(context.Background()))
+
+		targetHead, err := target.Head()
+		require.NoError(t, err)
+		assert.Equal(t, head.Header, targetHead.Header)
+		_, _, err = target.SnapSyncPivots()
+		require.Error(t, err)
+	})
+	t.Run("pivot is anchored to the L1 head", func(t *testing.T) {
+		target := blockchain.New(pebble.NewMemTest(t), &utils.Sepolia, nil)
+		ss, _, _ := newSnapSyncerWith(t, target)
+		require.ErrorContains(t, ss.repivot(context.Background()), "no L1 head")
+
+		require.NoError(t, target.SetL1Head(&core.L1Head{BlockNumber: headNumber + 1, BlockHash: head.Hash, StateRoot: head.GlobalStateRoot}))
+		require.ErrorContains(t, ss.repivot(context.Background()), "no peer has a state at or after block 7")
+	})
+
+	t.Run("state is discarded if the blocks up to the pivot don't match the L1 head", func(t *testing.T) {
+		target := blockchain.New(pebble.NewMemTest(t), &utils.Sepolia, nil)
+		require.NoError(t, target.SetL1Head(&core.L1Head{BlockNumber: l1HeadNumber, BlockHash: head.Hash, StateRoot: head.GlobalStateRoot}))
+		ss, tracker, server := newSnapSyncerWith(t, target)
+		require.NoError(t, ss.repivot(context.Background()))
+		require.NoError(t, ss.syncClasses(context.Background()))
+		require.NoError(t, ss.syncContracts(context.Background()))
+
+		err := ss.heal(context.Background())
+		require.ErrorIs(t, err, errStateDiscarded)
+		require.ErrorIs(t, err, errInvalidResponse)
+
+		_, _, err = target.SnapSyncPivots()
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+		require.NoError(t, target.StoreSnapState(headNumber, func(state *core.State) error {
+			root, err := state.Root()
+			require.NoError(t, err)
+			assert.True(t, root.IsZero())
+			return nil
+		}))
+		assert.Equal(t, &felt.Zero, ss.nextContract)
+		assert.Nil(t, ss.head)
+
+		// the peer is penalised, leaving no other peer to snap sync from
+		require.ErrorContains(t, ss.retry(context.Background(), ss.heal), "no peer has a state")
+		assert.Less(t, tracker.Records()[server].Score, 0.0)
+	})
+
+	t.Run("state is discarded if it doesn't match the pivot", func(t *testing.T) {
+		target := blockchain.New(pebble.NewMemTest(t), &utils.Sepolia, nil)
+		ss := newSnapSyncer(t, target)
+		// a leaf the range proofs left out
+		require.NoError(t, target.StoreSnapState(headNumber, func(state *core.State) error {
+			return state.PutContract(new(felt.Felt).SetUint64(1), new(felt.Felt).SetUint64(2), &felt.Zero, nil, headNumber)
+		}))
+		require.NoError(t, ss.syncClasses(context.Background()))
+		require.NoError(t, ss.syncContracts(context.Background()))
+
+		err := ss.heal(context.Background())
+		require.ErrorIs(t, err, errStateDiscarded)
+		require.ErrorIs(t, err, blockchain.ErrStateRootMismatch)
+		_, _, err = target.SnapSyncPivots()
+		require.ErrorIs(t, err, db.ErrKeyNotFound)
+	})
+}
@@ -4,8 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
-	"reflect"
 	"time"
 
 	"github.com/NethermindEth/juno/adapters/p2p2core"
@@ -13,10 +11,9 @@ import (
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/db"
-	"github.com/NethermindEth/juno/p2p/gen"
+	"github.com/NethermindEth/juno/p2p/reputation"
 	junoSync "github.com/NethermindEth/juno/sync"
 	"github.com/NethermindEth/juno/utils"
-	"github.com/NethermindEth/juno/utils/pipeline"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -24,123 +21,143 @@ import (
 	"go.uber.org/zap"
 )
 
+// headPollInterval is how long the service waits for a block announcement once it has caught up
+// with its peers before requesting the next block anyway.
+const headPollInterval = 10 * time.Second
+
 type Service struct {
 	host    host.Host
 	network *utils.Network
-	client  *Client // todo: merge all the functionality of Client with p2p SyncService
 
-	blockchain *blockchain.Blockchain
-	listener   junoSync.EventListener
-	log        utils.SimpleLogger
+	blockchain    *blockchain.Blockchain
+	listener      junoSync.EventListener
+	log           utils.SimpleLogger
+	announcements chan uint64
+	reputation    *reputation.Tracker
+	// peers is only accessed by the goroutine running the service
+	peers map[peer.ID]*peerStats
+	// snapSync is set if the state is downloaded at a recent block when the chain is empty
+	snapSync bool
 }
 
-func New(bc *blockchain.Blockchain, h host.Host, n *utils.Network, log utils.SimpleLogger) *Service {
+func New(bc *blockchain.Blockchain, h host.Host, n *utils.Network, tracker *reputation.Tracker, log utils.SimpleLogger) *Service {
 	return &Service{
-		host:       h,
-		network:    n,
-		blockchain: bc,
-		log:        log,
-		listener:   &junoSync.SelectiveListener{},
+		host:          h,
+		network:       n,
+		blockchain:    bc,
+		log:           log,
+		reputation:    tracker,
+		listener:      &junoSync.SelectiveListener{},
+		announcements: make(chan uint64, 1),
+		peers:         make(map[peer.ID]*peerStats),
 	}
 }
 
-func (s *Service) Run(ctx context.Context) {
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
-	s.client = NewClient(s.randomPeerStream, s.network, s.log)
-
-	for i := 0; ; i++ {
-		if err := ctx.Err(); err != nil {
-			break
-		}
-		s.log.Debugw("Continuous iteration", "i", i)
+// Announce notifies the service that a peer has the block with the given number, so that it is
+// requested immediately instead of after the next poll.
+func (s *Service) Announce(blockNumber uint64) {
+	select {
+	case s.announcements <- blockNumber:
+	default:
+		// an announcement is already pending, the service will fetch up to the latest block anyway
+	}
+}
 
-		iterCtx, cancelIteration := context.WithCancel(ctx)
+func (s *Service) Run(ctx context.Context) {
+	for ctx.Err() == nil {
 		nextHeight, err := s.getNextHeight()
 		if err != nil {
 			s.logError("Failed to get current height", err)
-			cancelIteration()
 			continue
 		}
 
-		s.log.Infow("Start Pipeline", "Current height", nextHeight-1, "Start", nextHeight)
-
-		// todo change iteration to fetch several objects uint64(min(blockBehind, maxBlocks))
-		blockNumber := uint64(nextHeight)
-		if err := s.processBlock(iterCtx, blockNumber); err != nil {
-			s.logError("Failed to process block", fmt.Errorf("blockNumber: %d, err: %w", blockNumber, err))
-			cancelIteration()
+		if nextHeight == 0 && s.snapSync {
+			if err = s.snapSyncState(ctx); err != nil {
+				s.logError("Failed to snap sync state", err)
+				s.waitForAnnouncement(ctx, 0)
+			}
 			continue
 		}
 
-		cancelIteration()
+		s.log.Debugw("Start syncing", "Current height", int(nextHeight)-1, "Start", nextHeight)
+
+		stored, err := s.syncFrom(ctx, nextHeight)
+		if err != nil {
+			s.logError("Failed to sync blocks", err)
+		}
+		if stored == 0 {
+			// No block was received, wait until a peer announces a new one
+			s.waitForAnnouncement(ctx, nextHeight)
+		}
 	}
 }
 
-func (s *Service) getNextHeight() (int, error) {
+// waitForAnnouncement blocks until a block at or above blockNumber is announced or headPollInterval elapses.
+func (s *Service) waitForAnnouncement(ctx context.Context, blockNumber uint64) {
+	timer := time.NewTimer(headPollInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			return
+		case announced := <-s.announcements:
+			if announced >= blockNumber {
+				s.log.Debugw("Received block announcement", "number", announced)
+				return
+			}
+		}
+	}
+}
+
+func (s *Service) getNextHeight() (uint64, error) {
 	curHeight, err := s.blockchain.Height()
 	if err == nil {
-		return int(curHeight) + 1, nil
+		return curHeight + 1, nil
 	} else if errors.Is(err, db.ErrKeyNotFound) {
 		return 0, nil
 	}
 	return 0, err
 }
 
-func (s *Service) processBlock(ctx context.Context, blockNumber uint64) error {
-	headersAndSigsCh, err := s.genHeadersAndSigs(ctx, blockNumber)
-	if err != nil {
-		return fmt.Errorf("failed to get block headers parts: %w", err)
-	}
-
-	txsCh, err := s.genTransactions(ctx, blockNumber)
-	if err != nil {
-		return fmt.Errorf("failed to get transactions: %w", err)
-	}
+// storeBlocks adapts, sanity checks and stores the given consecutive blocks. It returns the number of
+// blocks stored before the first one that failed. Errors caused by the content of the blocks wrap
+// errInvalidResponse.
+func (s *Service) storeBlocks(blocks []*blockParts) (int, error) {
+	for i, parts := range blocks {
+		blockNumber := parts.header.Number
 
-	eventsCh, err := s.genEvents(ctx, blockNumber)
-	if err != nil {
-		return fmt.Errorf("failed to get events: %w", err)
-	}
-
-	classesCh, err := s.genClasses(ctx, blockNumber)
-	if err != nil {
-		return fmt.Errorf("failed to get classes: %w", err)
-	}
-
-	stateDiffsCh, err := s.genStateDiffs(ctx, blockNumber)
-	if err != nil {
-		return fmt.Errorf("failed to get state diffs: %w", err)
-	}
+		prevBlockRoot := &felt.Zero
+		if blockNumber > 0 {
+			prevHeader, err := s.blockchain.BlockHeaderByNumber(blockNumber - 1)
+			if err != nil {
+				return i, fmt.Errorf("get header of block %d: %w", blockNumber-1, err)
+			}
+			prevBlockRoot = prevHeader.GlobalStateRoot
+		}
 
-	blocksCh := pipeline.Bridge(ctx, s.processSpecBlockParts(ctx, blockNumber, pipeline.FanIn(ctx,
-		pipeline.Stage(ctx, headersAndSigsCh, specBlockPartsFunc[specBlockHeaderAndSigs]),
-		pipeline.Stage(ctx, classesCh, specBlockPartsFunc[specClasses]),
-		pipeline.Stage(ctx, stateDiffsCh, specBlockPartsFunc[specContractDiffs]),
-		pipeline.Stage(ctx, txsCh, specBlockPartsFunc[specTxWithReceipts]),
-		pipeline.Stage(ctx, eventsCh, specBlockPartsFunc[specEvents]),
-	)))
-
-	for b := range blocksCh {
-		if b.err != nil {
-			return fmt.Errorf("failed to process block: %w", b.err)
+		b, err := s.adaptAndSanityCheckBlock(parts, prevBlockRoot)
+		if err != nil {
+			return i, err
 		}
 
 		storeTimer := time.Now()
 		if err := s.blockchain.Store(b.block, b.commitments, b.stateUpdate, b.newClasses); err != nil {
-			return fmt.Errorf("failed to store block: %w", err)
+			// a block that doesn't follow the local chain or doesn't lead to its state root was sent by the peer,
+			// any other error is a local one
+			if errors.Is(err, blockchain.ErrParentDoesNotMatchHead) || errors.Is(err, core.ErrMismatchedRoot) {
+				return i, fmt.Errorf("%w: failed to store block: %v", errInvalidResponse, err)
+			}
+			return i, fmt.Errorf("failed to store block: %w", err)
 		}
 
 		s.log.Infow("Stored Block", "number", b.block.Number, "hash", b.block.Hash.ShortString(),
 			"root", b.block.GlobalStateRoot.ShortString())
 		s.listener.OnSyncStepDone(junoSync.OpStore, b.block.Number, time.Since(storeTimer))
 	}
-	return nil
-}
-
-func specBlockPartsFunc[T specBlockHeaderAndSigs | specTxWithReceipts | specEvents | specClasses | specContractDiffs](i T) specBlockParts {
-	return specBlockParts(i)
+	return len(blocks), nil
 }
 
 func (s *Service) logError(msg string, err error) {
@@ -165,507 +182,118 @@ type blockBody struct {
 	stateUpdate *core.StateUpdate
 	newClasses  map[felt.Felt]core.Class
 	commitments *core.BlockCommitments
-	err         error
-}
-
-//nolint:gocyclo
-func (s *Service) processSpecBlockParts(
-	ctx context.Context, startingBlockNum uint64, specBlockPartsCh <-chan specBlockParts,
-) <-chan <-chan blockBody {
-	orderedBlockBodiesCh := make(chan (<-chan blockBody))
-
-	go func() {
-		defer close(orderedBlockBodiesCh)
-
-		specBlockHeadersAndSigsM := make(map[uint64]specBlockHeaderAndSigs)
-		specClassesM := make(map[uint64]specClasses)
-		specTransactionsM := make(map[uint64]specTxWithReceipts)
-		specEventsM := make(map[uint64]specEvents)
-		specContractDiffsM := make(map[uint64]specContractDiffs)
-
-		curBlockNum := startingBlockNum
-		for part := range specBlockPartsCh {
-			select {
-			case <-ctx.Done():
-			default:
-				switch p := part.(type) {
-				case specBlockHeaderAndSigs:
-					s.log.Debugw("Received Block Header with signatures", "blockNumber", p.blockNumber())
-					if _, ok := specBlockHeadersAndSigsM[part.blockNumber()]; !ok {
-						specBlockHeadersAndSigsM[part.blockNumber()] = p
-					}
-				case specTxWithReceipts:
-					s.log.Debugw("Received Transactions with receipts", "blockNumber", p.blockNumber(), "txLen", len(p.txs))
-					if _, ok := specTransactionsM[part.blockNumber()]; !ok {
-						specTransactionsM[part.blockNumber()] = p
-					}
-				case specEvents:
-					s.log.Debugw("Received Events", "blockNumber", p.blockNumber(), "len", len(p.events))
-					if _, ok := specEventsM[part.blockNumber()]; !ok {
-						specEventsM[part.blockNumber()] = p
-					}
-				case specClasses:
-					s.log.Debugw("Received Classes", "blockNumber", p.blockNumber())
-					if _, ok := specClassesM[part.blockNumber()]; !ok {
-						specClassesM[part.blockNumber()] = p
-					}
-				case specContractDiffs:
-					s.log.Debugw("Received ContractDiffs", "blockNumber", p.blockNumber())
-					if _, ok := specContractDiffsM[part.blockNumber()]; !ok {
-						specContractDiffsM[part.blockNumber()] = p
-					}
-				default:
-					s.log.Warnw("Unsupported part type", "blockNumber", part.blockNumber(), "type", reflect.TypeOf(p))
-				}
-
-				headerAndSig, okHeader := specBlockHeadersAndSigsM[curBlockNum]
-				txs, okTxs := specTransactionsM[curBlockNum]
-				es, okEvents := specEventsM[curBlockNum]
-				cls, okClasses := specClassesM[curBlockNum]
-				diffs, okDiffs := specContractDiffsM[curBlockNum]
-				if okHeader && okTxs && okEvents && okClasses && okDiffs {
-					s.log.Debugw(fmt.Sprintf("----- Received all block parts from peers for block number %d-----", curBlockNum))
-
-					select {
-					case <-ctx.Done():
-					default:
-						prevBlockRoot := &felt.Zero
-						if curBlockNum > 0 {
-							// First check cache if the header is not present, then get it from the db.
-							if oldHeader, ok := specBlockHeadersAndSigsM[curBlockNum-1]; ok {
-								prevBlockRoot = p2p2core.AdaptHash(oldHeader.header.StateRoot)
-							} else {
-								oldHeader, err := s.blockchain.BlockHeaderByNumber(curBlockNum - 1)
-								if err != nil {
-									s.log.Errorw("Failed to get Header", "number", curBlockNum, "err", err)
-									return
-								}
-								prevBlockRoot = oldHeader.GlobalStateRoot
-							}
-						}
-
-						orderedBlockBodiesCh <- s.adaptAndSanityCheckBlock(ctx, headerAndSig.header, diffs.contractDiffs,
-							cls.classes, txs.txs, txs.receipts, es.events, prevBlockRoot)
-					}
-
-					if curBlockNum > 0 {
-						delete(specBlockHeadersAndSigsM, curBlockNum-1)
-					}
-					delete(specTransactionsM, curBlockNum)
-					delete(specEventsM, curBlockNum)
-					curBlockNum++
-				}
-			}
-		}
-	}()
-	return orderedBlockBodiesCh
-}
-
-//nolint:gocyclo
-func (s *Service) adaptAndSanityCheckBlock(ctx context.Context, header *gen.SignedBlockHeader, contractDiffs []*gen.ContractDiff,
-	classes []*gen.Class, txs []*gen.Transaction, receipts []*gen.Receipt, events []*gen.Event, prevBlockRoot *felt.Felt,
-) <-chan blockBody {
-	bodyCh := make(chan blockBody)
-	go func() {
-		defer close(bodyCh)
-		select {
-		case <-ctx.Done():
-			bodyCh <- blockBody{err: ctx.Err()}
-		default:
-			coreBlock := new(core.Block)
-
-			var coreTxs []core.Transaction
-			for _, tx := range txs {
-				coreTxs = append(coreTxs, p2p2core.AdaptTransaction(tx, s.network))
-			}
-
-			coreBlock.Transactions = coreTxs
-
-			txHashEventsM := make(map[felt.Felt][]*core.Event)
-			for _, event := range events {
-				txH := p2p2core.AdaptHash(event.TransactionHash)
-				txHashEventsM[*txH] = append(txHashEventsM[*txH], p2p2core.AdaptEvent(event))
-			}
-
-			coreReceipts := make([]*core.TransactionReceipt, 0, len(receipts))
-			for i, r := range receipts {
-				coreReceipts = append(coreReceipts, p2p2core.AdaptReceipt(r, coreTxs[i].Hash()))
-			}
-			coreReceipts = utils.Map(coreReceipts, func(r *core.TransactionReceipt) *core.TransactionReceipt {
-				r.Events = txHashEventsM[*r.TransactionHash]
-				return r
-			})
-			coreBlock.Receipts = coreReceipts
-
-			eventsBloom := core.EventsBloom(coreBlock.Receipts)
-			coreBlock.Header = p2p2core.AdaptBlockHeader(header, eventsBloom)
-
-			if int(coreBlock.TransactionCount) != len(coreBlock.Transactions) {
-				s.log.Errorw(
-					"Number of transactions != count",
-					"transactionCount",
-					coreBlock.TransactionCount,
-					"len(transactions)",
-					len(coreBlock.Transactions),
-				)
-				return
-			}
-			if int(coreBlock.EventCount) != len(events) {
-				s.log.Errorw(
-					"Number of events != count",
-					"eventCount",
-					coreBlock.EventCount,
-					"len(events)",
-					len(events),
-				)
-				return
-			}
-
-			newClasses := make(map[felt.Felt]core.Class)
-			for _, cls := range classes {
-				coreC := p2p2core.AdaptClass(cls)
-				h, err := coreC.Hash()
-				if err != nil {
-					bodyCh <- blockBody{err: fmt.Errorf("class hash calculation error: %v", err)}
-					return
-				}
-				newClasses[*h] = coreC
-			}
-
-			// Build State update
-			// Note: Parts of the State Update are created from Blockchain object as the Store and SanityCheck functions require a State
-			// Update but there is no such message in P2P.
-
-			stateReader, stateCloser, err := s.blockchain.StateAtBlockNumber(coreBlock.Number - 1)
-			if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
-				// todo(kirill) change to shutdown
-				panic(err)
-			}
-			defer func() {
-				if stateCloser == nil {
-					return
-				}
-
-				if closeErr := stateCloser(); closeErr != nil {
-					s.log.Errorw("Failed to close state reader", "err", closeErr)
-				}
-			}()
-
-			stateUpdate := &core.StateUpdate{
-				BlockHash: coreBlock.Hash,
-				NewRoot:   coreBlock.GlobalStateRoot,
-				OldRoot:   prevBlockRoot,
-				StateDiff: p2p2core.AdaptStateDiff(stateReader, contractDiffs, classes),
-			}
-
-			commitments, err := s.blockchain.SanityCheckNewHeight(coreBlock, stateUpdate, newClasses)
-			if err != nil {
-				bodyCh <- blockBody{err: fmt.Errorf("sanity check error: %v for block number: %v", err, coreBlock.Number)}
-				return
-			}
-
-			select {
-			case <-ctx.Done():
-			case bodyCh <- blockBody{block: coreBlock, stateUpdate: stateUpdate, newClasses: newClasses, commitments: commitments}:
-			}
-		}
-	}()
-
-	return bodyCh
-}
-
-type specBlockParts interface {
-	blockNumber() uint64
-}
-
-type specBlockHeaderAndSigs struct {
-	header *gen.SignedBlockHeader
 }
 
-func (s specBlockHeaderAndSigs) blockNumber() uint64 {
-	return s.header.Number
-}
+func (s *Service) adaptAndSanityCheckBlock(parts *blockParts, prevBlockRoot *felt.Felt) (*blockBody, error) {
+	coreBlock := new(core.Block)
 
-func (s *Service) genHeadersAndSigs(ctx context.Context, blockNumber uint64) (<-chan specBlockHeaderAndSigs, error) {
-	it := s.createIteratorForBlock(blockNumber)
-	headersIt, err := s.client.RequestBlockHeaders(ctx, &gen.BlockHeadersRequest{Iteration: it})
-	if err != nil {
-		return nil, err
+	var coreTxs []core.Transaction
+	for _, tx := range parts.txs {
+		coreTxs = append(coreTxs, p2p2core.AdaptTransaction(tx, s.network))
 	}
 
-	headersAndSigCh := make(chan specBlockHeaderAndSigs)
-	go func() {
-		defer close(headersAndSigCh)
-
-	loop:
-		for res := range headersIt {
-			headerAndSig := specBlockHeaderAndSigs{}
-			switch v := res.HeaderMessage.(type) {
-			case *gen.BlockHeadersResponse_Header:
-				headerAndSig.header = v.Header
-			case *gen.BlockHeadersResponse_Fin:
-				break loop
-			default:
-				s.log.Warnw("Unexpected HeaderMessage from getBlockHeaders", "v", v)
-				break loop
-			}
-
-			select {
-			case <-ctx.Done():
-				break
-			case headersAndSigCh <- headerAndSig:
-			}
-		}
-	}()
-
-	return headersAndSigCh, nil
-}
+	coreBlock.Transactions = coreTxs
 
-type specClasses struct {
-	number  uint64
-	classes []*gen.Class
-}
-
-func (s specClasses) blockNumber() uint64 {
-	return s.number
-}
-
-func (s *Service) genClasses(ctx context.Context, blockNumber uint64) (<-chan specClasses, error) {
-	it := s.createIteratorForBlock(blockNumber)
-	classesIt, err := s.client.RequestClasses(ctx, &gen.ClassesRequest{Iteration: it})
-	if err != nil {
-		return nil, err
+	txHashEventsM := make(map[felt.Felt][]*core.Event)
+	for _, event := range parts.events {
+		txH := p2p2core.AdaptHash(event.TransactionHash)
+		txHashEventsM[*txH] = append(txHashEventsM[*txH], p2p2core.AdaptEvent(event))
 	}
 
-	classesCh := make(chan specClasses)
-	go func() {
-		defer close(classesCh)
-
-		var classes []*gen.Class
-	loop:
-		for res := range classesIt {
-			switch v := res.ClassMessage.(type) {
-			case *gen.ClassesResponse_Class:
-				classes = append(classes, v.Class)
-			case *gen.ClassesResponse_Fin:
-				break loop
-			default:
-				s.log.Warnw("Unexpected ClassMessage from getClasses", "v", v)
-				break loop
-			}
-		}
-
-		select {
-		case <-ctx.Done():
-		case classesCh <- specClasses{
-			number:  blockNumber,
-			classes: classes,
-		}:
-			s.log.Debugw("Received classes for block", "blockNumber", blockNumber, "lenClasses", len(classes))
-		}
-	}()
-	return classesCh, nil
-}
-
-type specContractDiffs struct {
-	number        uint64
-	contractDiffs []*gen.ContractDiff
-}
+	coreReceipts := make([]*core.TransactionReceipt, 0, len(parts.receipts))
+	for i, r := range parts.receipts {
+		coreReceipts = append(coreReceipts, p2p2core.AdaptReceipt(r, coreTxs[i].Hash()))
+	}
+	coreReceipts = utils.Map(coreReceipts, func(r *core.TransactionReceipt) *core.TransactionReceipt {
+		r.Events = txHashEventsM[*r.TransactionHash]
+		return r
+	})
+	coreBlock.Receipts = coreReceipts
 
-func (s specContractDiffs) blockNumber() uint64 {
-	return s.number
-}
+	eventsBloom := core.EventsBloom(coreBlock.Receipts)
+	coreBlock.Header = p2p2core.AdaptBlockHeader(parts.header, eventsBloom)
 
-func (s *Service) genStateDiffs(ctx context.Context, blockNumber uint64) (<-chan specContractDiffs, error) {
-	it := s.createIteratorForBlock(blockNumber)
-	stateDiffsIt, err := s.client.RequestStateDiffs(ctx, &gen.StateDiffsRequest{Iteration: it})
-	if err != nil {
-		return nil, err
+	if int(coreBlock.TransactionCount) != len(coreBlock.Transactions) {
+		return nil, fmt.Errorf("%w: number of transactions %d != count %d for block number: %d", errInvalidResponse,
+			len(coreBlock.Transactions), coreBlock.TransactionCount, coreBlock.Number)
+	}
+	if int(coreBlock.EventCount) != len(parts.events) {
+		return nil, fmt.Errorf("%w: number of events %d != count %d for block number: %d", errInvalidResponse,
+			len(parts.events), coreBlock.EventCount, coreBlock.Number)
 	}
 
-	stateDiffsCh := make(chan specContractDiffs)
-	go func() {
-		defer close(stateDiffsCh)
-
-		var contractDiffs []*gen.ContractDiff
-
-	loop:
-		for res := range stateDiffsIt {
-			switch v := res.StateDiffMessage.(type) {
-			case *gen.StateDiffsResponse_ContractDiff:
-				contractDiffs = append(contractDiffs, v.ContractDiff)
-			case *gen.StateDiffsResponse_DeclaredClass:
-				s.log.Warnw("Unimplemented message StateDiffsResponse_DeclaredClass")
-			case *gen.StateDiffsResponse_Fin:
-				break loop
-			default:
-				s.log.Warnw("Unexpected ClassMessage from getStateDiffs", "v", v)
-				break loop
-			}
-		}
-
-		select {
-		case <-ctx.Done():
-		case stateDiffsCh <- specContractDiffs{
-			number:        blockNumber,
-			contractDiffs: contractDiffs,
-		}:
+	newClasses := make(map[felt.Felt]core.Class)
+	for _, cls := range parts.classes {
+		coreC := p2p2core.AdaptClass(cls)
+		h, err := coreC.Hash()
+		if err != nil {
+			return nil, fmt.Errorf("%w: class hash calculation error: %v", errInvalidResponse, err)
 		}
-	}()
-	return stateDiffsCh, nil
-}
-
-type specEvents struct {
-	number uint64
-	events []*gen.Event
-}
+		newClasses[*h] = coreC
+	}
 
-func (s specEvents) blockNumber() uint64 {
-	return s.number
-}
+	// Build State update
+	// Note: Parts of the State Update are created from Blockchain object as the Store and SanityCheck functions require a State
+	// Update but there is no such message in P2P.
 
-func (s *Service) genEvents(ctx context.Context, blockNumber uint64) (<-chan specEvents, error) {
-	it := s.createIteratorForBlock(blockNumber)
-	eventsIt, err := s.client.RequestEvents(ctx, &gen.EventsRequest{Iteration: it})
-	if err != nil {
-		return nil, err
+	stateReader, stateCloser, err := s.blockchain.StateAtBlockNumber(coreBlock.Number - 1)
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return nil, fmt.Errorf("get state at block number %d: %w", coreBlock.Number-1, err)
 	}
-
-	eventsCh := make(chan specEvents)
-	go func() {
-		defer close(eventsCh)
-
-		var events []*gen.Event
-
-	loop:
-		for res := range eventsIt {
-			switch v := res.EventMessage.(type) {
-			case *gen.EventsResponse_Event:
-				events = append(events, v.Event)
-			case *gen.EventsResponse_Fin:
-				break loop
-			default:
-				s.log.Warnw("Unexpected EventMessage from getEvents", "v", v)
-				break loop
-			}
+	defer func() {
+		if stateCloser == nil {
+			return
 		}
 
-		select {
-		case <-ctx.Done():
-		case eventsCh <- specEvents{
-			number: blockNumber,
-			events: events,
-		}:
+		if closeErr := stateCloser(); closeErr != nil {
+			s.log.Errorw("Failed to close state reader", "err", closeErr)
 		}
 	}()
-	return eventsCh, nil
-}
 
-type specTxWithReceipts struct {
-	number   uint64
-	txs      []*gen.Transaction
-	receipts []*gen.Receipt
-}
-
-func (s specTxWithReceipts) blockNumber() uint64 {
-	return s.number
-}
+	stateUpdate := &core.StateUpdate{
+		BlockHash: coreBlock.Hash,
+		NewRoot:   coreBlock.GlobalStateRoot,
+		OldRoot:   prevBlockRoot,
+		StateDiff: p2p2core.AdaptStateDiff(stateReader, parts.contractDiffs, parts.classes),
+	}
 
-func (s *Service) genTransactions(ctx context.Context, blockNumber uint64) (<-chan specTxWithReceipts, error) {
-	it := s.createIteratorForBlock(blockNumber)
-	txsIt, err := s.client.RequestTransactions(ctx, &gen.TransactionsRequest{Iteration: it})
+	commitments, err := s.blockchain.SanityCheckNewHeight(coreBlock, stateUpdate, newClasses)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w: sanity check error: %v for block number: %v", errInvalidResponse, err, coreBlock.Number)
 	}
 
-	txsCh := make(chan specTxWithReceipts)
-	go func() {
-		defer close(txsCh)
-
-		var (
-			transactions []*gen.Transaction
-			receipts     []*gen.Receipt
-		)
-
-	loop:
-		for res := range txsIt {
-			switch v := res.TransactionMessage.(type) {
-			case *gen.TransactionsResponse_TransactionWithReceipt:
-				txWithReceipt := v.TransactionWithReceipt
-				transactions = append(transactions, txWithReceipt.Transaction)
-				receipts = append(receipts, txWithReceipt.Receipt)
-			case *gen.TransactionsResponse_Fin:
-				break loop
-			default:
-				s.log.Warnw("Unexpected TransactionMessage from getTransactions", "v", v)
-				break loop
-			}
-		}
-
-		s.log.Debugw("Transactions length", "len", len(transactions))
-		spexTxs := specTxWithReceipts{
-			number:   blockNumber,
-			txs:      transactions,
-			receipts: receipts,
-		}
-		select {
-		case <-ctx.Done():
-			return
-		case txsCh <- spexTxs:
-		}
-	}()
-	return txsCh, nil
+	return &blockBody{block: coreBlock, stateUpdate: stateUpdate, newClasses: newClasses, commitments: commitments}, nil
 }
 
-func (s *Service) randomPeer() peer.ID {
-	store := s.host.Peerstore()
-	// todo do not request same block from all peers
-	peers := utils.Filter(store.Peers(), func(peerID peer.ID) bool {
-		return peerID != s.host.ID()
+// connectedPeers returns the peers known to the host that aren't banned, except the host itself.
+func (s *Service) connectedPeers() []peer.ID {
+	return utils.Filter(s.host.Peerstore().Peers(), func(peerID peer.ID) bool {
+		return peerID != s.host.ID() && !s.reputation.Banned(peerID)
 	})
-	if len(peers) == 0 {
-		return ""
-	}
-
-	p := peers[rand.Intn(len(peers))] //nolint:gosec
-
-	s.log.Debugw("Number of peers", "len", len(peers))
-	s.log.Debugw("Random chosen peer's info", "peerInfo", store.PeerInfo(p))
-
-	return p
 }
 
-var errNoPeers = errors.New("no peers available")
-
-func (s *Service) randomPeerStream(ctx context.Context, pids ...protocol.ID) (network.Stream, error) {
-	randPeer := s.randomPeer()
-	if randPeer == "" {
-		return nil, errNoPeers
-	}
-	stream, err := s.host.NewStream(ctx, randPeer, pids...)
-	if err != nil {
-		s.log.Debugw("Error creating stream", "peer", randPeer, "err", err)
-		s.removePeer(randPeer)
-		return nil, err
+func (s *Service) peerStream(id peer.ID) NewStreamFunc {
+	return func(ctx context.Context, pids ...protocol.ID) (network.Stream, error) {
+		return s.host.NewStream(ctx, id, pids...)
 	}
-	return stream, err
 }
 
 func (s *Service) removePeer(id peer.ID) {
 	s.log.Debugw("Removing peer", "peerID", id)
 	s.host.Peerstore().RemovePeer(id)
 	s.host.Peerstore().ClearAddrs(id)
-}
-
-func (s *Service) createIteratorForBlock(blockNumber uint64) *gen.Iteration {
-	return &gen.Iteration{
-		Start:     &gen.Iteration_BlockNumber{BlockNumber: blockNumber},
-		Direction: gen.Iteration_Forward,
-		Limit:     1,
-		Step:      1,
+	if err := s.host.Network().ClosePeer(id); err != nil {
+		s.log.Debugw("Failed to close connections to peer", "peerID", id, "err", err)
 	}
+	delete(s.peers, id)
 }
 
 func (s *Service) WithListener(l junoSync.EventListener) {
 	s.listener = l
 }
+
+// WithSnapSync makes the service download the state at a recent block from its peers when the chain is empty,
+// instead of replaying every block since genesis.
+func (s *Service) WithSnapSync() {
+	s.snapSync = true
+}
@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"errors"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
+)
+
+const maxSenderTxsLimit = 1024
+
+type SenderTransaction struct {
+	BlockHash        *felt.Felt   `json:"block_hash"`
+	BlockNumber      uint64       `json:"block_number"`
+	TransactionIndex uint64       `json:"transaction_index"`
+	Transaction      *Transaction `json:"transaction"`
+}
+
+func adaptSenderTransaction(t *blockchain.SenderTransaction) SenderTransaction {
+	return SenderTransaction{
+		BlockHash:        t.BlockHash,
+		BlockNumber:      t.BlockNumber,
+		TransactionIndex: t.Index,
+		Transaction:      AdaptTransaction(t.Transaction),
+	}
+}
+
+/****************************************************
+		Account Handlers
+*****************************************************/
+
+// TransactionBySenderAndNonce returns the transaction an account sent with the given nonce.
+// Only invoke, declare and deploy account transactions that carry a nonce are indexed.
+func (h *Handler) TransactionBySenderAndNonce(address, nonce felt.Felt) (*SenderTransaction, *jsonrpc.Error) {
+	txn, err := h.bcReader.TransactionBySenderAndNonce(&address, &nonce)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, ErrSenderTxnNotFound
+		}
+		return nil, ErrInternal.CloneWithData(err)
+	}
+
+	senderTxn := adaptSenderTransaction(txn)
+	return &senderTxn, nil
+}
+
+// TransactionsBySender returns the transactions sent by an account in nonce order, starting from
+// fromNonce. A zero limit returns the maximum page size.
+func (h *Handler) TransactionsBySender(address, fromNonce felt.Felt, limit uint64) ([]SenderTransaction, *jsonrpc.Error) {
+	if limit > maxSenderTxsLimit {
+		return nil, ErrPageSizeTooBig
+	} else if limit == 0 {
+		limit = maxSenderTxsLimit
+	}
+
+	txns, err := h.bcReader.TransactionsBySender(&address, &fromNonce, limit)
+	if err != nil {
+		return nil, ErrInternal.CloneWithData(err)
+	}
+
+	senderTxns := make([]SenderTransaction, len(txns))
+	for i := range txns {
+		senderTxns[i] = adaptSenderTransaction(&txns[i])
+	}
+	return senderTxns, nil
+}
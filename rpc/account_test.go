@@ -0,0 +1,91 @@
+package rpc_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/mocks"
+	"github.com/NethermindEth/juno/rpc"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTransactionsBySender(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	handler := rpc.New(mockReader, nil, nil, "", nil)
+
+	sender := utils.HexToFelt(t, "0x43abaa073c768ebf039c0c4f46db9acc39e9ec165690418060a652aab39e7d8")
+	blockHash := utils.HexToFelt(t, "0x5c627d4aeb51280058bed93c7889bce78114d63baad1be0f0aeb32496d5f19c")
+	invoke := &core.InvokeTransaction{
+		TransactionHash: utils.HexToFelt(t, "0x1"),
+		SenderAddress:   sender,
+		Nonce:           new(felt.Felt).SetUint64(2),
+		Version:         new(core.TransactionVersion).SetUint64(1),
+	}
+	senderTxn := blockchain.SenderTransaction{
+		Transaction: invoke,
+		BlockHash:   blockHash,
+		BlockNumber: 7,
+		Index:       3,
+	}
+	expected := rpc.SenderTransaction{
+		BlockHash:        blockHash,
+		BlockNumber:      7,
+		TransactionIndex: 3,
+		Transaction:      rpc.AdaptTransaction(invoke),
+	}
+
+	t.Run("by sender and nonce", func(t *testing.T) {
+		mockReader.EXPECT().TransactionBySenderAndNonce(sender, invoke.Nonce).Return(&senderTxn, nil)
+
+		txn, rpcErr := handler.TransactionBySenderAndNonce(*sender, *invoke.Nonce)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &expected, txn)
+	})
+
+	t.Run("unknown sender and nonce", func(t *testing.T) {
+		mockReader.EXPECT().TransactionBySenderAndNonce(sender, &felt.Zero).Return(nil, db.ErrKeyNotFound)
+
+		_, rpcErr := handler.TransactionBySenderAndNonce(*sender, felt.Zero)
+		assert.Equal(t, rpc.ErrSenderTxnNotFound, rpcErr)
+	})
+
+	t.Run("page", func(t *testing.T) {
+		mockReader.EXPECT().TransactionsBySender(sender, invoke.Nonce, uint64(1)).
+			Return([]blockchain.SenderTransaction{senderTxn}, nil)
+
+		txns, rpcErr := handler.TransactionsBySender(*sender, *invoke.Nonce, 1)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, []rpc.SenderTransaction{expected}, txns)
+	})
+
+	t.Run("zero limit uses the maximum page size", func(t *testing.T) {
+		mockReader.EXPECT().TransactionsBySender(sender, &felt.Zero, uint64(1024)).Return(nil, nil)
+
+		txns, rpcErr := handler.TransactionsBySender(*sender, felt.Zero, 0)
+		require.Nil(t, rpcErr)
+		assert.Empty(t, txns)
+	})
+
+	t.Run("page size too big", func(t *testing.T) {
+		_, rpcErr := handler.TransactionsBySender(*sender, felt.Zero, 1025)
+		assert.Equal(t, rpc.ErrPageSizeTooBig, rpcErr)
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		mockReader.EXPECT().TransactionsBySender(sender, &felt.Zero, uint64(10)).Return(nil, errors.New("some error"))
+
+		_, rpcErr := handler.TransactionsBySender(*sender, felt.Zero, 10)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, rpc.ErrInternal.Code, rpcErr.Code)
+	})
+}
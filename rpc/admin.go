@@ -0,0 +1,111 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/utils"
+)
+
+// nodeAdmin performs the operational actions of the admin methods on the node.
+type nodeAdmin interface {
+	// SetLogLevel changes the level of the logger of the node and returns the previous one
+	SetLogLevel(level utils.LogLevel) (utils.LogLevel, error)
+	// PauseSync and ResumeSync report whether syncing was running and paused respectively
+	PauseSync() (bool, error)
+	ResumeSync() (bool, error)
+	// RevertBlocks reverts the count most recent blocks and returns the new head
+	RevertBlocks(ctx context.Context, count uint64) (*core.Header, error)
+	CompactDB() error
+	// Config returns the effective configuration of the node, without secrets
+	Config() (map[string]any, error)
+}
+
+type LogLevelUpdate struct {
+	Level         string `json:"level"`
+	PreviousLevel string `json:"previous_level"`
+}
+
+/****************************************************
+		Admin Handlers
+*****************************************************/
+
+// SetLogLevel changes the level the node logs at until it is restarted.
+func (h *Handler) SetLogLevel(level utils.LogLevel) (*LogLevelUpdate, *jsonrpc.Error) {
+	if h.nodeAdmin == nil {
+		return nil, ErrAdminNotEnabled
+	}
+
+	previous, err := h.nodeAdmin.SetLogLevel(level)
+	if err != nil {
+		return nil, ErrAdminActionFailed.CloneWithData(err.Error())
+	}
+	return &LogLevelUpdate{Level: level.String(), PreviousLevel: previous.String()}, nil
+}
+
+// PauseSync stops syncing blocks until ResumeSync is called, and returns whether it was running.
+func (h *Handler) PauseSync() (bool, *jsonrpc.Error) {
+	if h.nodeAdmin == nil {
+		return false, ErrAdminNotEnabled
+	}
+
+	paused, err := h.nodeAdmin.PauseSync()
+	if err != nil {
+		return false, ErrAdminActionFailed.CloneWithData(err.Error())
+	}
+	return paused, nil
+}
+
+// ResumeSync continues syncing blocks after PauseSync, and returns whether it was paused.
+func (h *Handler) ResumeSync() (bool, *jsonrpc.Error) {
+	if h.nodeAdmin == nil {
+		return false, ErrAdminNotEnabled
+	}
+
+	resumed, err := h.nodeAdmin.ResumeSync()
+	if err != nil {
+		return false, ErrAdminActionFailed.CloneWithData(err.Error())
+	}
+	return resumed, nil
+}
+
+// RevertBlocks reverts the count most recent blocks while syncing is paused, and returns the new head. Subscribers
+// are notified of the reverted blocks as of a reorg.
+func (h *Handler) RevertBlocks(ctx context.Context, count uint64) (*BlockHashAndNumber, *jsonrpc.Error) {
+	if h.nodeAdmin == nil {
+		return nil, ErrAdminNotEnabled
+	}
+
+	head, err := h.nodeAdmin.RevertBlocks(ctx, count)
+	if err != nil {
+		return nil, ErrAdminActionFailed.CloneWithData(err.Error())
+	}
+	return &BlockHashAndNumber{Hash: head.Hash, Number: head.Number}, nil
+}
+
+// CompactDB compacts the database, which reclaims the space of the data that was deleted or overwritten, such as
+// the data of reverted blocks.
+func (h *Handler) CompactDB() (bool, *jsonrpc.Error) {
+	if h.nodeAdmin == nil {
+		return false, ErrAdminNotEnabled
+	}
+
+	if err := h.nodeAdmin.CompactDB(); err != nil {
+		return false, ErrAdminActionFailed.CloneWithData(err.Error())
+	}
+	return true, nil
+}
+
+// NodeConfig returns the configuration the node is running with, where secrets are redacted.
+func (h *Handler) NodeConfig() (map[string]any, *jsonrpc.Error) {
+	if h.nodeAdmin == nil {
+		return nil, ErrAdminNotEnabled
+	}
+
+	config, err := h.nodeAdmin.Config()
+	if err != nil {
+		return nil, ErrAdminActionFailed.CloneWithData(err.Error())
+	}
+	return config, nil
+}
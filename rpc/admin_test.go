@@ -0,0 +1,98 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/rpc"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNodeAdmin struct {
+	level  utils.LogLevel
+	paused bool
+	head   *core.Header
+}
+
+func (a *fakeNodeAdmin) SetLogLevel(level utils.LogLevel) (utils.LogLevel, error) {
+	previous := a.level
+	a.level = level
+	return previous, nil
+}
+
+func (a *fakeNodeAdmin) PauseSync() (bool, error) {
+	wasRunning := !a.paused
+	a.paused = true
+	return wasRunning, nil
+}
+
+func (a *fakeNodeAdmin) ResumeSync() (bool, error) {
+	wasPaused := a.paused
+	a.paused = false
+	return wasPaused, nil
+}
+
+func (a *fakeNodeAdmin) RevertBlocks(_ context.Context, count uint64) (*core.Header, error) {
+	if !a.paused {
+		return nil, errors.New("syncing must be paused to revert blocks")
+	}
+	a.head = &core.Header{Number: a.head.Number - count, Hash: new(felt.Felt).SetUint64(a.head.Number - count)}
+	return a.head, nil
+}
+
+func (a *fakeNodeAdmin) CompactDB() error {
+	return errors.New("the database doesn't support compaction")
+}
+
+func (a *fakeNodeAdmin) Config() (map[string]any, error) {
+	return map[string]any{"log-level": a.level.String()}, nil
+}
+
+func TestNodeAdmin(t *testing.T) {
+	handler := rpc.New(nil, nil, nil, "", utils.NewNopZapLogger())
+
+	t.Run("admin disabled", func(t *testing.T) {
+		_, rpcErr := handler.PauseSync()
+		assert.Equal(t, rpc.ErrAdminNotEnabled, rpcErr)
+	})
+
+	admin := &fakeNodeAdmin{level: utils.INFO, head: &core.Header{Number: 5}}
+	handler = handler.WithNodeAdmin(admin)
+
+	t.Run("set log level", func(t *testing.T) {
+		update, rpcErr := handler.SetLogLevel(utils.DEBUG)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.LogLevelUpdate{Level: "debug", PreviousLevel: "info"}, update)
+
+		config, rpcErr := handler.NodeConfig()
+		require.Nil(t, rpcErr)
+		assert.Equal(t, map[string]any{"log-level": "debug"}, config)
+	})
+
+	t.Run("revert blocks", func(t *testing.T) {
+		_, rpcErr := handler.RevertBlocks(context.Background(), 2)
+		assert.Equal(t, rpc.ErrAdminActionFailed.CloneWithData("syncing must be paused to revert blocks"), rpcErr)
+
+		paused, rpcErr := handler.PauseSync()
+		require.Nil(t, rpcErr)
+		assert.True(t, paused)
+
+		head, rpcErr := handler.RevertBlocks(context.Background(), 2)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.BlockHashAndNumber{Hash: new(felt.Felt).SetUint64(3), Number: 3}, head)
+
+		resumed, rpcErr := handler.ResumeSync()
+		require.Nil(t, rpcErr)
+		assert.True(t, resumed)
+	})
+
+	t.Run("compaction failure", func(t *testing.T) {
+		_, rpcErr := handler.CompactDB()
+		assert.Equal(t, rpc.ErrAdminActionFailed.CloneWithData("the database doesn't support compaction"), rpcErr)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/NethermindEth/juno/adapters/sn2core"
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
 	"github.com/NethermindEth/juno/jsonrpc"
 	"github.com/NethermindEth/juno/starknet"
 	"github.com/NethermindEth/juno/starknet/compiler"
@@ -41,6 +42,12 @@ type FunctionCall struct {
 	Calldata           []felt.Felt `json:"calldata"`
 }
 
+type ClassDeclaration struct {
+	BlockHash       *felt.Felt `json:"block_hash"`
+	BlockNumber     uint64     `json:"block_number"`
+	TransactionHash *felt.Felt `json:"transaction_hash"`
+}
+
 func adaptDeclaredClass(declaredClass json.RawMessage) (core.Class, error) {
 	var feederClass starknet.ClassDefinition
 	err := json.Unmarshal(declaredClass, &feederClass)
@@ -95,8 +102,17 @@ func (h *Handler) Class(id BlockID, classHash felt.Felt) (*Class, *jsonrpc.Error
 		return nil, ErrClassHashNotFound
 	}
 
+	rpcClass := adaptCoreClass(declared.Class)
+	if rpcClass == nil {
+		return nil, ErrClassHashNotFound
+	}
+	return rpcClass, nil
+}
+
+// adaptCoreClass returns the definition of class as served by starknet_getClass, or nil for unknown class types.
+func adaptCoreClass(class core.Class) *Class {
 	var rpcClass *Class
-	switch c := declared.Class.(type) {
+	switch c := class.(type) {
 	case *core.Cairo0Class:
 		adaptEntryPoint := func(ep core.EntryPoint) EntryPoint {
 			return EntryPoint{
@@ -138,11 +154,9 @@ func (h *Handler) Class(id BlockID, classHash felt.Felt) (*Class, *jsonrpc.Error
 				L1Handler:   utils.Map(c.EntryPoints.L1Handler, adaptEntryPoint),
 			},
 		}
-	default:
-		return nil, ErrClassHashNotFound
 	}
 
-	return rpcClass, nil
+	return rpcClass
 }
 
 // ClassAt gets the contract class definition in the given block instantiated by the given contract address
@@ -175,3 +189,21 @@ func (h *Handler) ClassHashAt(id BlockID, address felt.Felt) (*felt.Felt, *jsonr
 
 	return classHash, nil
 }
+
+// ClassDeclaration returns the block and transaction that declared the class with the given hash.
+// The transaction hash is null for classes that were not declared by a transaction.
+func (h *Handler) ClassDeclaration(classHash felt.Felt) (*ClassDeclaration, *jsonrpc.Error) {
+	declaration, err := h.bcReader.ClassDeclaration(&classHash)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, ErrClassHashNotFound
+		}
+		return nil, ErrInternal.CloneWithData(err)
+	}
+
+	return &ClassDeclaration{
+		BlockHash:       declaration.BlockHash,
+		BlockNumber:     declaration.BlockNumber,
+		TransactionHash: declaration.DeclareTxnHash,
+	}, nil
+}
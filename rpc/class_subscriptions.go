@@ -0,0 +1,191 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/sourcegraph/conc"
+)
+
+// NewClass is a class declared in a block. The compiled class hash is omitted for Cairo 0 classes, and the class
+// definition unless it was requested.
+type NewClass struct {
+	BlockHash         *felt.Felt `json:"block_hash"`
+	BlockNumber       uint64     `json:"block_number"`
+	ClassHash         *felt.Felt `json:"class_hash"`
+	CompiledClassHash *felt.Felt `json:"compiled_class_hash,omitempty"`
+	Class             *Class     `json:"class,omitempty"`
+}
+
+// Deployment is a contract deployed in a block.
+type Deployment struct {
+	BlockHash       *felt.Felt `json:"block_hash"`
+	BlockNumber     uint64     `json:"block_number"`
+	ContractAddress *felt.Felt `json:"contract_address"`
+	ClassHash       *felt.Felt `json:"class_hash"`
+}
+
+// SubscribeNewClasses notifies each class declared in a new block, with its definition if includeDefinition is set.
+func (h *Handler) SubscribeNewClasses(ctx context.Context, includeDefinition *bool) (*SubscriptionID, *jsonrpc.Error) {
+	withDefinition := includeDefinition != nil && *includeDefinition
+	return h.subscribeStateDiffs(ctx, func(w jsonrpc.Conn, header *core.Header, diff *core.StateDiff, id uint64) error {
+		classes, err := h.newClasses(header, diff, withDefinition)
+		if err != nil {
+			return err
+		}
+		for _, class := range classes {
+			if err = sendStateDiffNotification(w, "juno_subscriptionNewClasses", class, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SubscribeDeployments notifies each contract deployed in a new block, only for the given class hashes unless none
+// are given.
+func (h *Handler) SubscribeDeployments(ctx context.Context, classHashes []felt.Felt) (*SubscriptionID, *jsonrpc.Error) {
+	if len(classHashes) > maxEventFilterKeys {
+		return nil, ErrTooManyClassHashesInFilter
+	}
+	return h.subscribeStateDiffs(ctx, func(w jsonrpc.Conn, header *core.Header, diff *core.StateDiff, id uint64) error {
+		for _, deployment := range deployments(header, diff, classHashes) {
+			if err := sendStateDiffNotification(w, "juno_subscriptionDeployments", deployment, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// subscribeStateDiffs calls notify with the state diff of each new block, and notifies reorgs.
+func (h *Handler) subscribeStateDiffs(ctx context.Context,
+	notify func(w jsonrpc.Conn, header *core.Header, diff *core.StateDiff, id uint64) error,
+) (*SubscriptionID, *jsonrpc.Error) {
+	w, ok := jsonrpc.ConnFromContext(ctx)
+	if !ok {
+		return nil, jsonrpc.Err(jsonrpc.MethodNotFound, nil)
+	}
+
+	id, subscriptionCtx, sub := h.newSubscription(ctx, w)
+	w = sub.queue
+
+	headerSub := h.newHeads.Subscribe()
+	reorgSub := h.reorgs.Subscribe()
+	sub.wg.Go(func() {
+		defer func() {
+			h.unsubscribe(sub, id)
+			headerSub.Unsubscribe()
+			reorgSub.Unsubscribe()
+		}()
+
+		var wg conc.WaitGroup
+		wg.Go(func() {
+			h.processReorgs(subscriptionCtx, reorgSub, w, id)
+		})
+
+		wg.Go(func() {
+			for {
+				select {
+				case <-subscriptionCtx.Done():
+					return
+				case header := <-headerSub.Recv():
+					update, err := h.bcReader.StateUpdateByNumber(header.Number)
+					if err != nil {
+						h.log.Warnw("Error getting state update", "block", header.Number, "err", err)
+						err = sendSubscriptionError(w, header.Number, ErrInternal.CloneWithData(err.Error()), id)
+					} else {
+						err = notify(w, header, update.StateDiff, id)
+					}
+					if err != nil {
+						h.log.Warnw("Error sending state diff notification", "block", header.Number, "err", err)
+						return
+					}
+				}
+			}
+		})
+
+		wg.Wait()
+	})
+
+	return &SubscriptionID{ID: id}, nil
+}
+
+// newClasses returns the classes declared by diff, Cairo 0 classes first, in the order of their declaration, then
+// Cairo 1 classes, ordered by class hash.
+func (h *Handler) newClasses(header *core.Header, diff *core.StateDiff, withDefinition bool) ([]*NewClass, error) {
+	classes := make([]*NewClass, 0, len(diff.DeclaredV0Classes)+len(diff.DeclaredV1Classes))
+	for _, classHash := range diff.DeclaredV0Classes {
+		classes = append(classes, &NewClass{BlockHash: header.Hash, BlockNumber: header.Number, ClassHash: classHash})
+	}
+
+	v1Classes := make([]*NewClass, 0, len(diff.DeclaredV1Classes))
+	for classHash, compiledClassHash := range diff.DeclaredV1Classes {
+		v1Classes = append(v1Classes, &NewClass{
+			BlockHash:         header.Hash,
+			BlockNumber:       header.Number,
+			ClassHash:         &classHash,
+			CompiledClassHash: compiledClassHash,
+		})
+	}
+	slices.SortFunc(v1Classes, func(a, b *NewClass) int { return a.ClassHash.Cmp(b.ClassHash) })
+	classes = append(classes, v1Classes...)
+
+	if !withDefinition || len(classes) == 0 {
+		return classes, nil
+	}
+
+	state, closer, err := h.bcReader.StateAtBlockNumber(header.Number)
+	if err != nil {
+		return nil, err
+	}
+	defer h.callAndLogErr(closer, "Error closing state reader in new classes subscription")
+
+	for _, class := range classes {
+		declared, err := state.Class(class.ClassHash)
+		if err != nil {
+			return nil, err
+		}
+		class.Class = adaptCoreClass(declared.Class)
+	}
+	return classes, nil
+}
+
+// deployments returns the contracts deployed by diff with one of classHashes, or any class if there are none, ordered
+// by address.
+func deployments(header *core.Header, diff *core.StateDiff, classHashes []felt.Felt) []*Deployment {
+	result := make([]*Deployment, 0, len(diff.DeployedContracts))
+	for address, classHash := range diff.DeployedContracts {
+		if len(classHashes) > 0 && !slices.Contains(classHashes, *classHash) {
+			continue
+		}
+		result = append(result, &Deployment{
+			BlockHash:       header.Hash,
+			BlockNumber:     header.Number,
+			ContractAddress: &address,
+			ClassHash:       classHash,
+		})
+	}
+	slices.SortFunc(result, func(a, b *Deployment) int { return a.ContractAddress.Cmp(b.ContractAddress) })
+	return result
+}
+
+func sendStateDiffNotification(w jsonrpc.Conn, method string, result any, id uint64) error {
+	resp, err := json.Marshal(SubscriptionResponse{
+		Version: "2.0",
+		Method:  method,
+		Params: map[string]any{
+			"subscription_id": id,
+			"result":          result,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(resp)
+	return err
+}
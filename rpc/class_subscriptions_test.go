@@ -0,0 +1,144 @@
+package rpc
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/mocks"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSubscribeNewClasses(t *testing.T) {
+	log := utils.NewNopZapLogger()
+
+	header := &core.Header{Number: 1, Hash: new(felt.Felt).SetUint64(11)}
+	update := &core.StateUpdate{StateDiff: &core.StateDiff{
+		DeclaredV0Classes: []*felt.Felt{new(felt.Felt).SetUint64(3)},
+		DeclaredV1Classes: map[felt.Felt]*felt.Felt{
+			*new(felt.Felt).SetUint64(2): new(felt.Felt).SetUint64(22),
+			*new(felt.Felt).SetUint64(1): new(felt.Felt).SetUint64(21),
+		},
+	}}
+
+	t.Run("Without definitions", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockChain := mocks.NewMockReader(mockCtrl)
+		handler := New(mockChain, mocks.NewMockSyncReader(mockCtrl), nil, "", log)
+
+		mockChain.EXPECT().StateUpdateByNumber(uint64(1)).Return(update, nil)
+
+		subCtx, decoder := subscribeOverPipe(t)
+		_, rpcErr := handler.SubscribeNewClasses(subCtx, nil)
+		require.Nil(t, rpcErr)
+		handler.newHeads.Send(header)
+
+		for _, want := range []string{
+			`{"block_hash":"0xb","block_number":1,"class_hash":"0x3"}`,
+			`{"block_hash":"0xb","block_number":1,"class_hash":"0x1","compiled_class_hash":"0x15"}`,
+			`{"block_hash":"0xb","block_number":1,"class_hash":"0x2","compiled_class_hash":"0x16"}`,
+		} {
+			notification := readNotification(t, decoder)
+			assert.Equal(t, "juno_subscriptionNewClasses", notification.Method)
+			assert.JSONEq(t, want, string(notification.Params.Result))
+		}
+	})
+
+	t.Run("With definitions", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockChain := mocks.NewMockReader(mockCtrl)
+		mockState := mocks.NewMockStateHistoryReader(mockCtrl)
+		handler := New(mockChain, mocks.NewMockSyncReader(mockCtrl), nil, "", log)
+
+		classHash := new(felt.Felt).SetUint64(1)
+		mockChain.EXPECT().StateUpdateByNumber(uint64(1)).Return(&core.StateUpdate{StateDiff: &core.StateDiff{
+			DeclaredV1Classes: map[felt.Felt]*felt.Felt{*classHash: new(felt.Felt).SetUint64(21)},
+		}}, nil)
+		mockChain.EXPECT().StateAtBlockNumber(uint64(1)).Return(mockState, func() error { return nil }, nil)
+		class := &core.Cairo1Class{
+			Abi:             "[]",
+			Program:         []*felt.Felt{new(felt.Felt).SetUint64(7)},
+			SemanticVersion: "0.1.0",
+		}
+		class.EntryPoints.Constructor = []core.SierraEntryPoint{}
+		class.EntryPoints.External = []core.SierraEntryPoint{{Index: 0, Selector: new(felt.Felt).SetUint64(5)}}
+		class.EntryPoints.L1Handler = []core.SierraEntryPoint{}
+		mockState.EXPECT().Class(classHash).Return(&core.DeclaredClass{Class: class}, nil)
+
+		subCtx, decoder := subscribeOverPipe(t)
+		_, rpcErr := handler.SubscribeNewClasses(subCtx, utils.Ptr(true))
+		require.Nil(t, rpcErr)
+		handler.newHeads.Send(header)
+
+		notification := readNotification(t, decoder)
+		assert.Equal(t, "juno_subscriptionNewClasses", notification.Method)
+		assert.JSONEq(t, `{"block_hash":"0xb","block_number":1,"class_hash":"0x1","compiled_class_hash":"0x15",`+
+			`"class":{"sierra_program":["0x7"],"contract_class_version":"0.1.0",`+
+			`"entry_points_by_type":{"CONSTRUCTOR":[],"EXTERNAL":[{"function_idx":0,"selector":"0x5"}],"L1_HANDLER":[]},"abi":"[]"}}`,
+			string(notification.Params.Result))
+	})
+
+	t.Run("Error reading the state update of a block", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockChain := mocks.NewMockReader(mockCtrl)
+		handler := New(mockChain, mocks.NewMockSyncReader(mockCtrl), nil, "", log)
+
+		mockChain.EXPECT().StateUpdateByNumber(uint64(1)).Return(nil, db.ErrKeyNotFound)
+
+		subCtx, decoder := subscribeOverPipe(t)
+		_, rpcErr := handler.SubscribeNewClasses(subCtx, nil)
+		require.Nil(t, rpcErr)
+		handler.newHeads.Send(header)
+
+		notification := readNotification(t, decoder)
+		assert.Equal(t, "juno_subscriptionError", notification.Method)
+		assert.JSONEq(t, `{"block_number":1,"error":{"code":-32603,"message":"Internal error","data":"key not found"}}`,
+			string(notification.Params.Result))
+	})
+}
+
+func TestSubscribeDeployments(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockChain := mocks.NewMockReader(mockCtrl)
+	handler := New(mockChain, mocks.NewMockSyncReader(mockCtrl), nil, "", utils.NewNopZapLogger())
+
+	classHash := new(felt.Felt).SetUint64(7)
+	header := &core.Header{Number: 1, Hash: new(felt.Felt).SetUint64(11)}
+	mockChain.EXPECT().StateUpdateByNumber(uint64(1)).Return(&core.StateUpdate{StateDiff: &core.StateDiff{
+		DeployedContracts: map[felt.Felt]*felt.Felt{
+			*new(felt.Felt).SetUint64(3): classHash,
+			*new(felt.Felt).SetUint64(2): new(felt.Felt).SetUint64(8),
+			*new(felt.Felt).SetUint64(1): classHash,
+		},
+	}}, nil)
+
+	subCtx, decoder := subscribeOverPipe(t)
+	_, rpcErr := handler.SubscribeDeployments(subCtx, []felt.Felt{*classHash})
+	require.Nil(t, rpcErr)
+	handler.newHeads.Send(header)
+
+	for _, address := range []string{"0x1", "0x3"} {
+		notification := readNotification(t, decoder)
+		assert.Equal(t, "juno_subscriptionDeployments", notification.Method)
+		assert.JSONEq(t, `{"block_hash":"0xb","block_number":1,"contract_address":"`+address+`","class_hash":"0x7"}`,
+			string(notification.Params.Result))
+	}
+
+	t.Run("Too many class hashes", func(t *testing.T) {
+		subCtx, _ := subscribeOverPipe(t)
+		_, rpcErr := handler.SubscribeDeployments(subCtx, make([]felt.Felt, maxEventFilterKeys+1))
+		assert.Equal(t, ErrTooManyClassHashesInFilter, rpcErr)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/NethermindEth/juno/blockchain"
 	"github.com/NethermindEth/juno/clients/feeder"
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
@@ -218,3 +219,39 @@ func TestClassHashAt(t *testing.T) {
 		assert.Equal(t, expectedClassHash, classHash)
 	})
 }
+
+func TestClassDeclaration(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	handler := rpc.New(mockReader, nil, nil, "", utils.NewNopZapLogger())
+
+	classHash := utils.HexToFelt(t, "0x123")
+
+	t.Run("unknown class", func(t *testing.T) {
+		mockReader.EXPECT().ClassDeclaration(classHash).Return(nil, db.ErrKeyNotFound)
+
+		declaration, rpcErr := handler.ClassDeclaration(*classHash)
+		require.Nil(t, declaration)
+		assert.Equal(t, rpc.ErrClassHashNotFound, rpcErr)
+	})
+
+	t.Run("declared class", func(t *testing.T) {
+		blockHash := utils.HexToFelt(t, "0x1")
+		txnHash := utils.HexToFelt(t, "0x2")
+		mockReader.EXPECT().ClassDeclaration(classHash).Return(&blockchain.ClassDeclaration{
+			BlockNumber:    7,
+			BlockHash:      blockHash,
+			DeclareTxnHash: txnHash,
+		}, nil)
+
+		declaration, rpcErr := handler.ClassDeclaration(*classHash)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.ClassDeclaration{
+			BlockHash:       blockHash,
+			BlockNumber:     7,
+			TransactionHash: txnHash,
+		}, declaration)
+	})
+}
@@ -3,11 +3,25 @@ package rpc
 import (
 	"errors"
 
+	"github.com/NethermindEth/juno/blockchain"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/db"
 	"github.com/NethermindEth/juno/jsonrpc"
 )
 
+type ClassHashChange struct {
+	BlockNumber uint64     `json:"block_number"`
+	ClassHash   *felt.Felt `json:"class_hash"`
+}
+
+type ContractInfo struct {
+	BlockHash       *felt.Felt        `json:"block_hash"`
+	BlockNumber     uint64            `json:"block_number"`
+	TransactionHash *felt.Felt        `json:"transaction_hash"`
+	ClassHash       *felt.Felt        `json:"class_hash"`
+	ClassHistory    []ClassHashChange `json:"class_history"`
+}
+
 /****************************************************
 		Contract Handlers
 *****************************************************/
@@ -60,3 +74,76 @@ func (h *Handler) StorageAt(address, key felt.Felt, id BlockID) (*felt.Felt, *js
 
 	return value, nil
 }
+
+// ContractInfo returns the block and transaction that deployed the contract at the given address,
+// together with every class the contract instantiated since then.
+// The transaction hash is null if the deploying transaction can't be identified.
+func (h *Handler) ContractInfo(address felt.Felt) (*ContractInfo, *jsonrpc.Error) {
+	info, err := h.bcReader.ContractInfo(&address)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, ErrContractNotFound
+		}
+		return nil, ErrInternal.CloneWithData(err)
+	}
+
+	return adaptContractInfo(info), nil
+}
+
+func adaptContractInfo(info *blockchain.ContractInfo) *ContractInfo {
+	classHistory := make([]ClassHashChange, len(info.ClassHistory))
+	for i, change := range info.ClassHistory {
+		classHistory[i] = ClassHashChange{
+			BlockNumber: change.BlockNumber,
+			ClassHash:   change.ClassHash,
+		}
+	}
+
+	return &ContractInfo{
+		BlockHash:       info.DeployBlockHash,
+		BlockNumber:     info.DeployedAt,
+		TransactionHash: info.DeployTxnHash,
+		ClassHash:       info.ClassHash,
+		ClassHistory:    classHistory,
+	}
+}
+
+type ContractsChunk struct {
+	Addresses         []*felt.Felt `json:"addresses"`
+	ContinuationToken string       `json:"continuation_token,omitempty"`
+}
+
+// ContractsByClassHash returns the addresses of the contracts that currently instantiate the given
+// class, in pages of chunkSize addresses. The continuation token of a page resumes the listing.
+func (h *Handler) ContractsByClassHash(classHash felt.Felt, chunkSize uint64, continuationToken string) (*ContractsChunk,
+	*jsonrpc.Error,
+) {
+	if chunkSize == 0 {
+		return nil, jsonrpc.Err(jsonrpc.InvalidParams, "chunk_size must be positive")
+	} else if chunkSize > maxContractsChunkSize {
+		return nil, ErrPageSizeTooBig
+	}
+
+	startAt := new(felt.Felt)
+	if continuationToken != "" {
+		if _, err := startAt.SetString(continuationToken); err != nil {
+			return nil, ErrInvalidContinuationToken
+		}
+	}
+
+	// fetch one more address than requested to know whether there is a next page
+	addresses, err := h.bcReader.ContractsByClassHash(&classHash, startAt, chunkSize+1)
+	if err != nil {
+		return nil, ErrInternal.CloneWithData(err)
+	}
+
+	chunk := &ContractsChunk{Addresses: addresses}
+	if uint64(len(addresses)) > chunkSize {
+		chunk.Addresses = addresses[:chunkSize]
+		chunk.ContinuationToken = addresses[chunkSize].String()
+	}
+	if chunk.Addresses == nil {
+		chunk.Addresses = []*felt.Felt{}
+	}
+	return chunk, nil
+}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/NethermindEth/juno/blockchain"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
 	"github.com/NethermindEth/juno/mocks"
 	"github.com/NethermindEth/juno/rpc"
 	"github.com/NethermindEth/juno/utils"
@@ -172,3 +174,111 @@ func TestStorageAt(t *testing.T) {
 		assert.Equal(t, expectedStorage, storage)
 	})
 }
+
+func TestContractInfo(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	handler := rpc.New(mockReader, nil, nil, "", utils.NewNopZapLogger())
+
+	address := utils.HexToFelt(t, "0x123")
+
+	t.Run("unknown contract", func(t *testing.T) {
+		mockReader.EXPECT().ContractInfo(address).Return(nil, db.ErrKeyNotFound)
+
+		info, rpcErr := handler.ContractInfo(*address)
+		require.Nil(t, info)
+		assert.Equal(t, rpc.ErrContractNotFound, rpcErr)
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		mockReader.EXPECT().ContractInfo(address).Return(nil, errors.New("some error"))
+
+		_, rpcErr := handler.ContractInfo(*address)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, rpc.ErrInternal.Code, rpcErr.Code)
+	})
+
+	t.Run("replaced class", func(t *testing.T) {
+		blockHash := utils.HexToFelt(t, "0x1")
+		txnHash := utils.HexToFelt(t, "0x2")
+		oldClassHash := utils.HexToFelt(t, "0x3")
+		newClassHash := utils.HexToFelt(t, "0x4")
+		mockReader.EXPECT().ContractInfo(address).Return(&blockchain.ContractInfo{
+			DeployedAt:      5,
+			DeployBlockHash: blockHash,
+			DeployTxnHash:   txnHash,
+			ClassHash:       newClassHash,
+			ClassHistory: []blockchain.ClassHashChange{
+				{BlockNumber: 5, ClassHash: oldClassHash},
+				{BlockNumber: 9, ClassHash: newClassHash},
+			},
+		}, nil)
+
+		info, rpcErr := handler.ContractInfo(*address)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.ContractInfo{
+			BlockHash:       blockHash,
+			BlockNumber:     5,
+			TransactionHash: txnHash,
+			ClassHash:       newClassHash,
+			ClassHistory: []rpc.ClassHashChange{
+				{BlockNumber: 5, ClassHash: oldClassHash},
+				{BlockNumber: 9, ClassHash: newClassHash},
+			},
+		}, info)
+	})
+}
+
+func TestContractsByClassHash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	handler := rpc.New(mockReader, nil, nil, "", utils.NewNopZapLogger())
+
+	classHash := utils.HexToFelt(t, "0x123")
+	addresses := []*felt.Felt{utils.HexToFelt(t, "0x1"), utils.HexToFelt(t, "0x2"), utils.HexToFelt(t, "0x3")}
+
+	t.Run("invalid chunk size", func(t *testing.T) {
+		_, rpcErr := handler.ContractsByClassHash(*classHash, 0, "")
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+
+		_, rpcErr = handler.ContractsByClassHash(*classHash, 1025, "")
+		assert.Equal(t, rpc.ErrPageSizeTooBig, rpcErr)
+	})
+
+	t.Run("invalid continuation token", func(t *testing.T) {
+		_, rpcErr := handler.ContractsByClassHash(*classHash, 2, "not a felt")
+		assert.Equal(t, rpc.ErrInvalidContinuationToken, rpcErr)
+	})
+
+	t.Run("first page", func(t *testing.T) {
+		mockReader.EXPECT().ContractsByClassHash(classHash, &felt.Zero, uint64(3)).Return(addresses, nil)
+
+		chunk, rpcErr := handler.ContractsByClassHash(*classHash, 2, "")
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.ContractsChunk{
+			Addresses:         addresses[:2],
+			ContinuationToken: "0x3",
+		}, chunk)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		mockReader.EXPECT().ContractsByClassHash(classHash, addresses[2], uint64(3)).Return(addresses[2:], nil)
+
+		chunk, rpcErr := handler.ContractsByClassHash(*classHash, 2, "0x3")
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.ContractsChunk{Addresses: addresses[2:]}, chunk)
+	})
+
+	t.Run("unused class", func(t *testing.T) {
+		mockReader.EXPECT().ContractsByClassHash(classHash, &felt.Zero, uint64(3)).Return(nil, nil)
+
+		chunk, rpcErr := handler.ContractsByClassHash(*classHash, 2, "")
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.ContractsChunk{Addresses: []*felt.Felt{}}, chunk)
+	})
+}
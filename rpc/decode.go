@@ -0,0 +1,389 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/starknet/abi"
+)
+
+var (
+	executeSelector     = crypto.StarknetKeccak([]byte("__execute__"))
+	constructorSelector = crypto.StarknetKeccak([]byte("constructor"))
+
+	errMalformedMulticall = errors.New("malformed multicall calldata")
+)
+
+// DecodedCall is a call to a contract function, with its calldata decoded according to the ABI of
+// the called contract. When the calldata can't be decoded, the raw calldata and the reason are
+// returned instead.
+type DecodedCall struct {
+	ContractAddress    *felt.Felt   `json:"contract_address"`
+	EntryPointSelector *felt.Felt   `json:"entry_point_selector"`
+	Function           string       `json:"function,omitempty"`
+	Inputs             abi.Fields   `json:"inputs,omitempty"`
+	Calldata           []*felt.Felt `json:"calldata,omitempty"`
+	Error              string       `json:"error,omitempty"`
+}
+
+type DecodedTransaction struct {
+	TransactionHash *felt.Felt      `json:"transaction_hash"`
+	Type            TransactionType `json:"type"`
+	Calls           []DecodedCall   `json:"calls"`
+}
+
+type DecodedEvent struct {
+	Name   string     `json:"name"`
+	Fields abi.Fields `json:"fields"`
+}
+
+type DecodedResult struct {
+	Function string `json:"function"`
+	Outputs  []any  `json:"outputs"`
+}
+
+type CallResult struct {
+	Result      []*felt.Felt   `json:"result"`
+	Decoded     *DecodedResult `json:"decoded,omitempty"`
+	DecodeError string         `json:"decode_error,omitempty"`
+}
+
+/****************************************************
+		Decode Handlers
+*****************************************************/
+
+// DecodeTransaction decodes the calls a transaction makes according to the ABIs of the called
+// contracts at the block the transaction is included in, or the pending state for a pending transaction.
+// The calls of an invoke transaction sent by an account are recovered from the multicall calldata of
+// its __execute__ entry point.
+func (h *Handler) DecodeTransaction(hash felt.Felt) (*DecodedTransaction, *jsonrpc.Error) {
+	txn, state, stateCloser, rpcErr := h.transactionAndState(&hash)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	defer h.callAndLogErr(stateCloser, "Error closing state reader in juno_decodeTransaction")
+
+	decoded := &DecodedTransaction{
+		TransactionHash: txn.Hash(),
+		Type:            AdaptTransaction(txn).Type,
+		Calls:           []DecodedCall{},
+	}
+
+	switch t := txn.(type) {
+	case *core.InvokeTransaction:
+		if t.Version.Is(0) {
+			decoded.Calls = append(decoded.Calls, decodeCall(state, nil, t.ContractAddress, t.EntryPointSelector, t.CallData))
+		} else {
+			decoded.Calls = decodeMulticall(state, invokeSender(t), t.CallData)
+		}
+	case *core.L1HandlerTransaction:
+		decoded.Calls = append(decoded.Calls, decodeCall(state, nil, t.ContractAddress, t.EntryPointSelector, t.CallData))
+	case *core.DeployAccountTransaction:
+		decoded.Calls = append(decoded.Calls,
+			decodeCall(state, t.ClassHash, t.ContractAddress, constructorSelector, t.ConstructorCallData))
+	case *core.DeployTransaction:
+		decoded.Calls = append(decoded.Calls,
+			decodeCall(state, t.ClassHash, t.ContractAddress, constructorSelector, t.ConstructorCallData))
+	}
+	return decoded, nil
+}
+
+// transactionAndState returns the transaction with the given hash and the state after the block it is
+// included in, which is the pending state for a transaction of the pending block.
+func (h *Handler) transactionAndState(hash *felt.Felt) (core.Transaction, core.StateReader, func() error,
+	*jsonrpc.Error,
+) {
+	txn, err := h.bcReader.TransactionByHash(hash)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		pendingTxn := h.pendingTransaction(hash)
+		if pendingTxn == nil {
+			return nil, nil, nil, ErrTxnHashNotFound
+		}
+
+		state, stateCloser, err := h.syncReader.PendingState()
+		if err != nil {
+			return nil, nil, nil, ErrInternal.CloneWithData(err)
+		}
+		return pendingTxn, state, stateCloser, nil
+	} else if err != nil {
+		return nil, nil, nil, ErrInternal.CloneWithData(err)
+	}
+
+	_, _, blockNumber, err := h.bcReader.Receipt(hash)
+	if err != nil {
+		return nil, nil, nil, ErrInternal.CloneWithData(err)
+	}
+
+	state, stateCloser, err := h.bcReader.StateAtBlockNumber(blockNumber)
+	if err != nil {
+		return nil, nil, nil, ErrInternal.CloneWithData(err)
+	}
+	return txn, state, stateCloser, nil
+}
+
+// pendingTransaction returns the transaction of the pending block with the given hash, or nil if there is none.
+func (h *Handler) pendingTransaction(hash *felt.Felt) core.Transaction {
+	pending := h.syncReader.PendingBlock()
+	if pending == nil {
+		return nil
+	}
+	for _, txn := range pending.Transactions {
+		if txn.Hash().Equal(hash) {
+			return txn
+		}
+	}
+	return nil
+}
+
+// DecodeEvent decodes the keys and data of an event according to the ABI of the emitting contract
+// at the given block.
+func (h *Handler) DecodeEvent(event Event, id BlockID) (*DecodedEvent, *jsonrpc.Error) { //nolint:gocritic
+	if event.From == nil {
+		return nil, jsonrpc.Err(jsonrpc.InvalidParams, "from_address is required")
+	}
+
+	state, stateCloser, rpcErr := h.stateByBlockID(&id)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	defer h.callAndLogErr(stateCloser, "Error closing state reader in juno_decodeEvent")
+
+	contractABI, err := contractABI(state, nil, event.From)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, ErrContractNotFound
+	} else if err != nil {
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+
+	decoded, err := contractABI.DecodeEvent(event.Keys, event.Data)
+	if err != nil {
+		return nil, jsonrpc.Err(jsonrpc.InvalidParams, err.Error())
+	}
+	return &DecodedEvent{
+		Name:   decoded.Name,
+		Fields: decoded.Fields,
+	}, nil
+}
+
+// CallAndDecode calls a function like starknet_call and, if requested, decodes the returned felts
+// according to the ABI of the called contract. A decoding failure doesn't fail the call, it is
+// reported alongside the raw result instead.
+//
+//nolint:gocritic
+func (h *Handler) CallAndDecode(ctx context.Context, funcCall FunctionCall, id BlockID, decode bool) (*CallResult,
+	*jsonrpc.Error,
+) {
+	res, rpcErr := h.Call(ctx, funcCall, id)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	result := &CallResult{Result: res}
+	if !decode {
+		return result, nil
+	}
+
+	state, stateCloser, rpcErr := h.stateByBlockID(&id)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	defer h.callAndLogErr(stateCloser, "Error closing state reader in juno_call")
+
+	fn, err := contractFunction(state, nil, &funcCall.ContractAddress, &funcCall.EntryPointSelector)
+	if err == nil {
+		var outputs []any
+		if outputs, err = fn.abi.DecodeOutputs(fn.Function, res); err == nil {
+			result.Decoded = &DecodedResult{
+				Function: fn.Name,
+				Outputs:  outputs,
+			}
+		}
+	}
+	if err != nil {
+		result.DecodeError = err.Error()
+	}
+	return result, nil
+}
+
+func invokeSender(t *core.InvokeTransaction) *felt.Felt {
+	if t.SenderAddress != nil {
+		return t.SenderAddress
+	}
+	return t.ContractAddress
+}
+
+// contractABI parses the ABI of the given class, or of the class of the contract at address when
+// classHash is nil.
+func contractABI(state core.StateReader, classHash, address *felt.Felt) (*abi.ABI, error) {
+	if classHash == nil {
+		var err error
+		if classHash, err = state.ContractClassHash(address); err != nil {
+			return nil, err
+		}
+	}
+
+	declared, err := state.Class(classHash)
+	if err != nil {
+		return nil, err
+	}
+	return abi.New(declared.Class)
+}
+
+type boundFunction struct {
+	*abi.Function
+	abi *abi.ABI
+}
+
+func contractFunction(state core.StateReader, classHash, address, selector *felt.Felt) (*boundFunction, error) {
+	contractABI, err := contractABI(state, classHash, address)
+	if err != nil {
+		return nil, err
+	}
+
+	fn := contractABI.Function(selector)
+	if fn == nil {
+		return nil, abi.ErrUnknownFunction
+	}
+	return &boundFunction{Function: fn, abi: contractABI}, nil
+}
+
+func decodeCall(state core.StateReader, classHash, address, selector *felt.Felt, calldata []*felt.Felt) DecodedCall {
+	call := DecodedCall{
+		ContractAddress:    address,
+		EntryPointSelector: selector,
+	}
+
+	fn, err := contractFunction(state, classHash, address, selector)
+	if err == nil {
+		call.Function = fn.Name
+		call.Inputs, err = fn.abi.DecodeInputs(fn.Function, calldata)
+	}
+	if err != nil {
+		call.Calldata = calldata
+		call.Error = err.Error()
+	}
+	return call
+}
+
+type rawCall struct {
+	to       *felt.Felt
+	selector *felt.Felt
+	calldata []*felt.Felt
+}
+
+// decodeMulticall splits the __execute__ calldata of an account into the calls it makes and decodes
+// each of them. Accounts with a calldata layout that is not understood are decoded as a single call
+// to their own __execute__ entry point.
+func decodeMulticall(state core.StateReader, sender *felt.Felt, calldata []*felt.Felt) []DecodedCall {
+	var calls []rawCall
+	execute, err := contractFunction(state, nil, sender, executeSelector)
+	if err == nil && isLegacyExecute(execute.Function) {
+		calls, err = splitLegacyMulticall(calldata)
+	} else {
+		calls, err = splitMulticall(calldata)
+	}
+	if err != nil {
+		return []DecodedCall{decodeCall(state, nil, sender, executeSelector, calldata)}
+	}
+
+	decoded := make([]DecodedCall, 0, len(calls))
+	for _, call := range calls {
+		decoded = append(decoded, decodeCall(state, nil, call.to, call.selector, call.calldata))
+	}
+	return decoded
+}
+
+// isLegacyExecute reports whether a Cairo 0 account expects its calls as an array of
+// (to, selector, data_offset, data_len) entries followed by the concatenated calldata of all calls.
+func isLegacyExecute(execute *abi.Function) bool {
+	for _, input := range execute.Inputs {
+		if input.Name == "call_array" {
+			return true
+		}
+	}
+	return false
+}
+
+// boundedLength returns the value of a length prefix if it doesn't exceed limit.
+func boundedLength(f *felt.Felt, limit int) (uint64, bool) {
+	if f.Cmp(new(felt.Felt).SetUint64(uint64(limit))) > 0 {
+		return 0, false
+	}
+	return f.Uint64(), true
+}
+
+func splitMulticall(calldata []*felt.Felt) ([]rawCall, error) {
+	if len(calldata) == 0 {
+		return nil, errMalformedMulticall
+	}
+
+	rest := calldata[1:]
+	// every call takes at least three felts
+	n, ok := boundedLength(calldata[0], len(rest)/3)
+	if !ok {
+		return nil, errMalformedMulticall
+	}
+
+	calls := make([]rawCall, 0, n)
+	for range n {
+		if len(rest) < 3 {
+			return nil, errMalformedMulticall
+		}
+		dataLen, ok := boundedLength(rest[2], len(rest)-3)
+		if !ok {
+			return nil, errMalformedMulticall
+		}
+		calls = append(calls, rawCall{
+			to:       rest[0],
+			selector: rest[1],
+			calldata: rest[3 : 3+dataLen],
+		})
+		rest = rest[3+dataLen:]
+	}
+	if len(rest) != 0 {
+		return nil, errMalformedMulticall
+	}
+	return calls, nil
+}
+
+func splitLegacyMulticall(calldata []*felt.Felt) ([]rawCall, error) {
+	if len(calldata) < 2 {
+		return nil, errMalformedMulticall
+	}
+
+	// every call takes four felts in the call array, which is followed by the calldata length
+	n, ok := boundedLength(calldata[0], (len(calldata)-2)/4)
+	if !ok {
+		return nil, errMalformedMulticall
+	}
+
+	callArray := calldata[1 : 1+4*n]
+	data := calldata[2+4*n:]
+	if dataLen, ok := boundedLength(calldata[1+4*n], len(data)); !ok || dataLen != uint64(len(data)) {
+		return nil, errMalformedMulticall
+	}
+
+	calls := make([]rawCall, 0, n)
+	for i := range n {
+		entry := callArray[4*i : 4*i+4]
+		offset, ok := boundedLength(entry[2], len(data))
+		if !ok {
+			return nil, errMalformedMulticall
+		}
+		length, ok := boundedLength(entry[3], len(data)-int(offset))
+		if !ok {
+			return nil, errMalformedMulticall
+		}
+		calls = append(calls, rawCall{
+			to:       entry[0],
+			selector: entry[1],
+			calldata: data[offset : offset+length],
+		})
+	}
+	return calls, nil
+}
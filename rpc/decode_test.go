@@ -0,0 +1,354 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/mocks"
+	"github.com/NethermindEth/juno/rpc"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	tokenABI = `[
+		{"type": "struct", "name": "core::integer::u256", "members": [
+			{"name": "low", "type": "core::integer::u128"},
+			{"name": "high", "type": "core::integer::u128"}
+		]},
+		{"type": "function", "name": "transfer", "inputs": [
+			{"name": "recipient", "type": "core::starknet::contract_address::ContractAddress"},
+			{"name": "amount", "type": "core::integer::u256"}
+		], "outputs": [{"type": "core::bool"}], "state_mutability": "external"},
+		{"type": "event", "name": "token::Transfer", "kind": "struct", "members": [
+			{"name": "from", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"},
+			{"name": "to", "type": "core::starknet::contract_address::ContractAddress", "kind": "key"},
+			{"name": "value", "type": "core::integer::u256", "kind": "data"}
+		]},
+		{"type": "event", "name": "token::Event", "kind": "enum", "variants": [
+			{"name": "Transfer", "type": "token::Transfer", "kind": "nested"}
+		]}
+	]`
+	accountABI = `[
+		{"type": "function", "name": "__execute__", "inputs": [
+			{"name": "calls", "type": "core::array::Array::<core::starknet::account::Call>"}
+		], "outputs": [], "state_mutability": "external"}
+	]`
+	legacyAccountABI = `[
+		{"type": "struct", "name": "AccountCallArray", "size": 4, "members": [
+			{"name": "to", "type": "felt", "offset": 0},
+			{"name": "selector", "type": "felt", "offset": 1},
+			{"name": "data_offset", "type": "felt", "offset": 2},
+			{"name": "data_len", "type": "felt", "offset": 3}
+		]},
+		{"type": "function", "name": "__execute__", "inputs": [
+			{"name": "call_array_len", "type": "felt"},
+			{"name": "call_array", "type": "AccountCallArray*"},
+			{"name": "calldata_len", "type": "felt"},
+			{"name": "calldata", "type": "felt*"}
+		], "outputs": [{"name": "response_len", "type": "felt"}, {"name": "response", "type": "felt*"}]}
+	]`
+)
+
+func felts(values ...uint64) []*felt.Felt {
+	result := make([]*felt.Felt, 0, len(values))
+	for _, v := range values {
+		result = append(result, new(felt.Felt).SetUint64(v))
+	}
+	return result
+}
+
+func marshalJSON(t *testing.T, v any) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestDecodeTransaction(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	mockSyncReader := mocks.NewMockSyncReader(mockCtrl)
+	mockState := mocks.NewMockStateHistoryReader(mockCtrl)
+	handler := rpc.New(mockReader, mockSyncReader, nil, "", utils.NewNopZapLogger())
+
+	account := new(felt.Felt).SetUint64(0xacc)
+	legacyAccount := new(felt.Felt).SetUint64(0x1acc)
+	token := new(felt.Felt).SetUint64(0x70c)
+	unknown := new(felt.Felt).SetUint64(0xdead)
+	accountClassHash := new(felt.Felt).SetUint64(1)
+	legacyAccountClassHash := new(felt.Felt).SetUint64(2)
+	tokenClassHash := new(felt.Felt).SetUint64(3)
+	transfer := crypto.StarknetKeccak([]byte("transfer"))
+
+	classes := map[felt.Felt]*core.DeclaredClass{
+		*accountClassHash:       {Class: &core.Cairo1Class{Abi: accountABI}},
+		*legacyAccountClassHash: {Class: &core.Cairo0Class{Abi: json.RawMessage(legacyAccountABI)}},
+		*tokenClassHash:         {Class: &core.Cairo1Class{Abi: tokenABI}},
+	}
+	contracts := map[felt.Felt]*felt.Felt{
+		*account:       accountClassHash,
+		*legacyAccount: legacyAccountClassHash,
+		*token:         tokenClassHash,
+	}
+	mockState.EXPECT().ContractClassHash(gomock.Any()).DoAndReturn(func(address *felt.Felt) (*felt.Felt, error) {
+		if classHash, ok := contracts[*address]; ok {
+			return classHash, nil
+		}
+		return nil, db.ErrKeyNotFound
+	}).AnyTimes()
+	mockState.EXPECT().Class(gomock.Any()).DoAndReturn(func(classHash *felt.Felt) (*core.DeclaredClass, error) {
+		if class, ok := classes[*classHash]; ok {
+			return class, nil
+		}
+		return nil, db.ErrKeyNotFound
+	}).AnyTimes()
+
+	expectTransaction := func(txn core.Transaction) {
+		mockReader.EXPECT().TransactionByHash(txn.Hash()).Return(txn, nil)
+		mockReader.EXPECT().Receipt(txn.Hash()).Return(nil, nil, uint64(7), nil)
+		mockReader.EXPECT().StateAtBlockNumber(uint64(7)).Return(mockState, nopCloser, nil)
+	}
+
+	t.Run("transaction not found", func(t *testing.T) {
+		mockReader.EXPECT().TransactionByHash(&felt.Zero).Return(nil, db.ErrKeyNotFound)
+		mockSyncReader.EXPECT().PendingBlock().Return(nil)
+
+		decoded, rpcErr := handler.DecodeTransaction(felt.Zero)
+		require.Nil(t, decoded)
+		assert.Equal(t, rpc.ErrTxnHashNotFound, rpcErr)
+	})
+
+	t.Run("pending transaction", func(t *testing.T) {
+		txn := &core.InvokeTransaction{
+			TransactionHash:    new(felt.Felt).SetUint64(0x10),
+			Version:            new(core.TransactionVersion).SetUint64(0),
+			ContractAddress:    token,
+			EntryPointSelector: transfer,
+			CallData:           felts(0xb0b, 100, 0),
+		}
+		mockReader.EXPECT().TransactionByHash(txn.TransactionHash).Return(nil, db.ErrKeyNotFound)
+		mockSyncReader.EXPECT().PendingBlock().Return(&core.Block{Transactions: []core.Transaction{txn}})
+		mockSyncReader.EXPECT().PendingState().Return(mockState, nopCloser, nil)
+
+		decoded, rpcErr := handler.DecodeTransaction(*txn.TransactionHash)
+		require.Nil(t, rpcErr)
+		require.Len(t, decoded.Calls, 1)
+		assert.Equal(t, "transfer", decoded.Calls[0].Function)
+		assert.Equal(t, `{"recipient":"0xb0b","amount":"100"}`, marshalJSON(t, decoded.Calls[0].Inputs))
+	})
+
+	t.Run("multicall", func(t *testing.T) {
+		calldata := []*felt.Felt{new(felt.Felt).SetUint64(2), token, transfer}
+		calldata = append(calldata, felts(3, 0xb0b, 100, 0)...)
+		calldata = append(calldata, unknown, transfer)
+		calldata = append(calldata, felts(1, 42)...)
+		txn := &core.InvokeTransaction{
+			TransactionHash: new(felt.Felt).SetUint64(0x11),
+			Version:         new(core.TransactionVersion).SetUint64(1),
+			SenderAddress:   account,
+			CallData:        calldata,
+		}
+		expectTransaction(txn)
+
+		decoded, rpcErr := handler.DecodeTransaction(*txn.TransactionHash)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, rpc.TxnInvoke, decoded.Type)
+		require.Len(t, decoded.Calls, 2)
+
+		assert.Equal(t, token, decoded.Calls[0].ContractAddress)
+		assert.Equal(t, "transfer", decoded.Calls[0].Function)
+		assert.Equal(t, `{"recipient":"0xb0b","amount":"100"}`, marshalJSON(t, decoded.Calls[0].Inputs))
+		assert.Empty(t, decoded.Calls[0].Error)
+
+		// calls to unknown contracts are returned undecoded
+		assert.Equal(t, unknown, decoded.Calls[1].ContractAddress)
+		assert.Empty(t, decoded.Calls[1].Function)
+		assert.Equal(t, felts(42), decoded.Calls[1].Calldata)
+		assert.NotEmpty(t, decoded.Calls[1].Error)
+	})
+
+	t.Run("legacy multicall", func(t *testing.T) {
+		calldata := []*felt.Felt{new(felt.Felt).SetUint64(1), token, transfer}
+		calldata = append(calldata, felts(0, 3, 3, 0xb0b, 1, 2)...)
+		txn := &core.InvokeTransaction{
+			TransactionHash: new(felt.Felt).SetUint64(0x12),
+			Version:         new(core.TransactionVersion).SetUint64(1),
+			SenderAddress:   legacyAccount,
+			CallData:        calldata,
+		}
+		expectTransaction(txn)
+
+		decoded, rpcErr := handler.DecodeTransaction(*txn.TransactionHash)
+		require.Nil(t, rpcErr)
+		require.Len(t, decoded.Calls, 1)
+		assert.Equal(t, "transfer", decoded.Calls[0].Function)
+		assert.Equal(t, `{"recipient":"0xb0b","amount":"680564733841876926926749214863536422913"}`,
+			marshalJSON(t, decoded.Calls[0].Inputs))
+	})
+
+	t.Run("malformed multicall falls back to __execute__", func(t *testing.T) {
+		txn := &core.InvokeTransaction{
+			TransactionHash: new(felt.Felt).SetUint64(0x13),
+			Version:         new(core.TransactionVersion).SetUint64(3),
+			SenderAddress:   account,
+			CallData:        felts(5, 1),
+		}
+		expectTransaction(txn)
+
+		decoded, rpcErr := handler.DecodeTransaction(*txn.TransactionHash)
+		require.Nil(t, rpcErr)
+		require.Len(t, decoded.Calls, 1)
+		assert.Equal(t, account, decoded.Calls[0].ContractAddress)
+		assert.Equal(t, "__execute__", decoded.Calls[0].Function)
+		assert.Equal(t, txn.CallData, decoded.Calls[0].Calldata)
+		assert.NotEmpty(t, decoded.Calls[0].Error)
+	})
+
+	t.Run("declare", func(t *testing.T) {
+		txn := &core.DeclareTransaction{
+			TransactionHash: new(felt.Felt).SetUint64(0x14),
+			Version:         new(core.TransactionVersion).SetUint64(2),
+		}
+		expectTransaction(txn)
+
+		decoded, rpcErr := handler.DecodeTransaction(*txn.TransactionHash)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, rpc.TxnDeclare, decoded.Type)
+		assert.Empty(t, decoded.Calls)
+	})
+}
+
+func TestDecodeEvent(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	mockState := mocks.NewMockStateHistoryReader(mockCtrl)
+	handler := rpc.New(mockReader, nil, nil, "", utils.NewNopZapLogger())
+
+	token := new(felt.Felt).SetUint64(0x70c)
+	tokenClassHash := new(felt.Felt).SetUint64(3)
+	transferKey := crypto.StarknetKeccak([]byte("Transfer"))
+
+	t.Run("unknown contract", func(t *testing.T) {
+		mockReader.EXPECT().HeadState().Return(mockState, nopCloser, nil)
+		mockState.EXPECT().ContractClassHash(token).Return(nil, db.ErrKeyNotFound)
+
+		decoded, rpcErr := handler.DecodeEvent(rpc.Event{From: token}, rpc.BlockID{Latest: true})
+		require.Nil(t, decoded)
+		assert.Equal(t, rpc.ErrContractNotFound, rpcErr)
+	})
+
+	t.Run("state error", func(t *testing.T) {
+		mockReader.EXPECT().HeadState().Return(mockState, nopCloser, nil)
+		mockState.EXPECT().ContractClassHash(token).Return(nil, errors.New("some error"))
+
+		decoded, rpcErr := handler.DecodeEvent(rpc.Event{From: token}, rpc.BlockID{Latest: true})
+		require.Nil(t, decoded)
+		assert.Equal(t, rpc.ErrInternal.CloneWithData("some error"), rpcErr)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		mockReader.EXPECT().HeadState().Return(mockState, nopCloser, nil)
+		mockState.EXPECT().ContractClassHash(token).Return(tokenClassHash, nil)
+		mockState.EXPECT().Class(tokenClassHash).Return(&core.DeclaredClass{Class: &core.Cairo1Class{Abi: tokenABI}}, nil)
+
+		decoded, rpcErr := handler.DecodeEvent(rpc.Event{
+			From: token,
+			Keys: append([]*felt.Felt{transferKey}, felts(1, 2)...),
+			Data: felts(5, 0),
+		}, rpc.BlockID{Latest: true})
+		require.Nil(t, rpcErr)
+		assert.Equal(t, "token::Transfer", decoded.Name)
+		assert.Equal(t, `{"from":"0x1","to":"0x2","value":"5"}`, marshalJSON(t, decoded.Fields))
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		mockReader.EXPECT().HeadState().Return(mockState, nopCloser, nil)
+		mockState.EXPECT().ContractClassHash(token).Return(tokenClassHash, nil)
+		mockState.EXPECT().Class(tokenClassHash).Return(&core.DeclaredClass{Class: &core.Cairo1Class{Abi: tokenABI}}, nil)
+
+		decoded, rpcErr := handler.DecodeEvent(rpc.Event{
+			From: token,
+			Keys: felts(1),
+		}, rpc.BlockID{Latest: true})
+		require.Nil(t, decoded)
+		require.NotNil(t, rpcErr)
+	})
+}
+
+func TestCallAndDecode(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	mockState := mocks.NewMockStateHistoryReader(mockCtrl)
+	mockVM := mocks.NewMockVM(mockCtrl)
+	handler := rpc.New(mockReader, nil, mockVM, "", utils.NewNopZapLogger())
+
+	token := new(felt.Felt).SetUint64(0x70c)
+	tokenClassHash := new(felt.Felt).SetUint64(3)
+	call := rpc.FunctionCall{
+		ContractAddress:    *token,
+		EntryPointSelector: *crypto.StarknetKeccak([]byte("transfer")),
+	}
+
+	expectCall := func(res []*felt.Felt) {
+		mockReader.EXPECT().HeadState().Return(mockState, nopCloser, nil)
+		mockReader.EXPECT().HeadsHeader().Return(new(core.Header), nil)
+		mockState.EXPECT().ContractClassHash(token).Return(tokenClassHash, nil)
+		mockReader.EXPECT().Network().Return(&utils.Mainnet)
+		mockVM.EXPECT().Call(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(res, nil)
+	}
+	expectDecode := func() {
+		mockReader.EXPECT().HeadState().Return(mockState, nopCloser, nil)
+		mockState.EXPECT().ContractClassHash(token).Return(tokenClassHash, nil)
+		mockState.EXPECT().Class(tokenClassHash).Return(&core.DeclaredClass{Class: &core.Cairo1Class{Abi: tokenABI}}, nil)
+	}
+
+	t.Run("without decoding", func(t *testing.T) {
+		expectCall(felts(1))
+
+		res, rpcErr := handler.CallAndDecode(context.Background(), call, rpc.BlockID{Latest: true}, false)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.CallResult{Result: felts(1)}, res)
+	})
+
+	t.Run("decoded", func(t *testing.T) {
+		expectCall(felts(1))
+		expectDecode()
+
+		res, rpcErr := handler.CallAndDecode(context.Background(), call, rpc.BlockID{Latest: true}, true)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, &rpc.CallResult{
+			Result: felts(1),
+			Decoded: &rpc.DecodedResult{
+				Function: "transfer",
+				Outputs:  []any{true},
+			},
+		}, res)
+	})
+
+	t.Run("undecodable result", func(t *testing.T) {
+		expectCall(felts(1, 2))
+		expectDecode()
+
+		res, rpcErr := handler.CallAndDecode(context.Background(), call, rpc.BlockID{Latest: true}, true)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, felts(1, 2), res.Result)
+		assert.Nil(t, res.Decoded)
+		assert.NotEmpty(t, res.DecodeError)
+	})
+}
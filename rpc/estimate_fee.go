@@ -1,6 +1,7 @@
 package rpc
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -41,10 +42,10 @@ type FeeEstimate struct {
 		Estimate Fee Handlers
 *****************************************************/
 
-func (h *Handler) EstimateFee(broadcastedTxns []BroadcastedTransaction,
+func (h *Handler) EstimateFee(ctx context.Context, broadcastedTxns []BroadcastedTransaction,
 	simulationFlags []SimulationFlag, id BlockID,
 ) ([]FeeEstimate, http.Header, *jsonrpc.Error) {
-	result, httpHeader, err := h.simulateTransactions(id, broadcastedTxns, append(simulationFlags, SkipFeeChargeFlag), true)
+	result, httpHeader, err := h.simulateTransactions(ctx, id, broadcastedTxns, append(simulationFlags, SkipFeeChargeFlag), true)
 	if err != nil {
 		return nil, httpHeader, err
 	}
@@ -54,16 +55,19 @@ func (h *Handler) EstimateFee(broadcastedTxns []BroadcastedTransaction,
 	}), httpHeader, nil
 }
 
-func (h *Handler) EstimateMessageFee(msg MsgFromL1, id BlockID) (*FeeEstimate, http.Header, *jsonrpc.Error) { //nolint:gocritic
-	return h.estimateMessageFee(msg, id, h.EstimateFee)
+//nolint:gocritic
+func (h *Handler) EstimateMessageFee(ctx context.Context, msg MsgFromL1, id BlockID) (*FeeEstimate, http.Header,
+	*jsonrpc.Error,
+) {
+	return h.estimateMessageFee(ctx, msg, id, h.EstimateFee)
 }
 
-type estimateFeeHandler func(broadcastedTxns []BroadcastedTransaction,
+type estimateFeeHandler func(ctx context.Context, broadcastedTxns []BroadcastedTransaction,
 	simulationFlags []SimulationFlag, id BlockID,
 ) ([]FeeEstimate, http.Header, *jsonrpc.Error)
 
 //nolint:gocritic
-func (h *Handler) estimateMessageFee(msg MsgFromL1, id BlockID, f estimateFeeHandler) (*FeeEstimate,
+func (h *Handler) estimateMessageFee(ctx context.Context, msg MsgFromL1, id BlockID, f estimateFeeHandler) (*FeeEstimate,
 	http.Header, *jsonrpc.Error,
 ) {
 	calldata := make([]*felt.Felt, 0, len(msg.Payload)+1)
@@ -85,7 +89,7 @@ func (h *Handler) estimateMessageFee(msg MsgFromL1, id BlockID, f estimateFeeHan
 		// Must be greater than zero to successfully execute transaction.
 		PaidFeeOnL1: new(felt.Felt).SetUint64(1),
 	}
-	estimates, httpHeader, rpcErr := f([]BroadcastedTransaction{tx}, nil, id)
+	estimates, httpHeader, rpcErr := f(ctx, []BroadcastedTransaction{tx}, nil, id)
 	if rpcErr != nil {
 		if rpcErr.Code == ErrTransactionExecutionError.Code {
 			data := rpcErr.Data.(TransactionExecutionErrorData)
@@ -0,0 +1,23 @@
+package rpc
+
+type EventListener interface {
+	OnQueuedNotificationsChange(delta int)
+	OnNotificationsDropped(policy OverflowPolicy, count int)
+}
+
+type SelectiveListener struct {
+	OnQueuedNotificationsChangeCb func(delta int)
+	OnNotificationsDroppedCb      func(policy OverflowPolicy, count int)
+}
+
+func (l *SelectiveListener) OnQueuedNotificationsChange(delta int) {
+	if l.OnQueuedNotificationsChangeCb != nil {
+		l.OnQueuedNotificationsChangeCb(delta)
+	}
+}
+
+func (l *SelectiveListener) OnNotificationsDropped(policy OverflowPolicy, count int) {
+	if l.OnNotificationsDroppedCb != nil {
+		l.OnNotificationsDroppedCb(policy, count)
+	}
+}
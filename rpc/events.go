@@ -1,6 +1,8 @@
 package rpc
 
 import (
+	"context"
+
 	"github.com/NethermindEth/juno/blockchain"
 	"github.com/NethermindEth/juno/core/felt"
 	"github.com/NethermindEth/juno/jsonrpc"
@@ -52,7 +54,7 @@ type EventsChunk struct {
 //
 // It follows the specification defined here:
 // https://github.com/starkware-libs/starknet-specs/blob/94a969751b31f5d3e25a0c6850c723ddadeeb679/api/starknet_api_openrpc.json#L642
-func (h *Handler) Events(args EventsArg) (*EventsChunk, *jsonrpc.Error) {
+func (h *Handler) Events(ctx context.Context, args EventsArg) (*EventsChunk, *jsonrpc.Error) {
 	if args.ChunkSize > maxEventChunkSize {
 		return nil, ErrPageSizeTooBig
 	} else {
@@ -89,7 +91,7 @@ func (h *Handler) Events(args EventsArg) (*EventsChunk, *jsonrpc.Error) {
 		return nil, ErrBlockNotFound
 	}
 
-	filteredEvents, cToken, err := filter.Events(cToken, args.ChunkSize)
+	filteredEvents, cToken, err := filter.Events(ctx, cToken, args.ChunkSize)
 	if err != nil {
 		return nil, ErrInternal
 	}
@@ -122,6 +124,7 @@ func (h *Handler) Events(args EventsArg) (*EventsChunk, *jsonrpc.Error) {
 // unsubscribe assumes h.mu is unlocked. It releases all subscription resources.
 func (h *Handler) unsubscribe(sub *subscription, id uint64) {
 	sub.cancel()
+	sub.queue.close()
 	h.mu.Lock()
 	delete(h.subscriptions, id)
 	h.mu.Unlock()
@@ -62,14 +62,14 @@ func TestEvents(t *testing.T) {
 	t.Run("filter non-existent", func(t *testing.T) {
 		t.Run("block number", func(t *testing.T) {
 			args.ToBlock = &rpc.BlockID{Number: 55}
-			events, err := handler.Events(args)
+			events, err := handler.Events(context.Background(), args)
 			require.Nil(t, err)
 			require.Len(t, events.Events, 5)
 		})
 
 		t.Run("block hash", func(t *testing.T) {
 			args.ToBlock = &rpc.BlockID{Hash: new(felt.Felt).SetUint64(55)}
-			_, err := handler.Events(args)
+			_, err := handler.Events(context.Background(), args)
 			require.Equal(t, rpc.ErrBlockNotFound, err)
 		})
 	})
@@ -77,21 +77,21 @@ func TestEvents(t *testing.T) {
 	t.Run("filter with no from_block", func(t *testing.T) {
 		args.FromBlock = nil
 		args.ToBlock = &rpc.BlockID{Latest: true}
-		_, err := handler.Events(args)
+		_, err := handler.Events(context.Background(), args)
 		require.Nil(t, err)
 	})
 
 	t.Run("filter with no to_block", func(t *testing.T) {
 		args.FromBlock = &rpc.BlockID{Number: 0}
 		args.ToBlock = nil
-		_, err := handler.Events(args)
+		_, err := handler.Events(context.Background(), args)
 		require.Nil(t, err)
 	})
 
 	t.Run("filter with no address", func(t *testing.T) {
 		args.ToBlock = &rpc.BlockID{Latest: true}
 		args.Address = nil
-		_, err := handler.Events(args)
+		_, err := handler.Events(context.Background(), args)
 		require.Nil(t, err)
 	})
 
@@ -100,7 +100,7 @@ func TestEvents(t *testing.T) {
 		t.Run("get canonical events without pagination", func(t *testing.T) {
 			args.ToBlock = &rpc.BlockID{Latest: true}
 			args.Address = from
-			events, err := handler.Events(args)
+			events, err := handler.Events(context.Background(), args)
 			require.Nil(t, err)
 			require.Len(t, events.Events, 4)
 			require.Empty(t, events.ContinuationToken)
@@ -112,7 +112,7 @@ func TestEvents(t *testing.T) {
 			args.ChunkSize = 1
 
 			for i := 0; i < len(allEvents)+1; i++ {
-				events, err := handler.Events(args)
+				events, err := handler.Events(context.Background(), args)
 				require.Nil(t, err)
 				accEvents = append(accEvents, events.Events...)
 				args.ContinuationToken = events.ContinuationToken
@@ -130,7 +130,7 @@ func TestEvents(t *testing.T) {
 		t.Run("get all events without pagination", func(t *testing.T) {
 			args.ChunkSize = 100
 			args.Keys = append(args.Keys, []felt.Felt{*key})
-			events, err := handler.Events(args)
+			events, err := handler.Events(context.Background(), args)
 			require.Nil(t, err)
 			require.Len(t, events.Events, 1)
 			require.Empty(t, events.ContinuationToken)
@@ -155,7 +155,7 @@ func TestEvents(t *testing.T) {
 
 	t.Run("large page size", func(t *testing.T) {
 		args.ChunkSize = 10240 + 1
-		events, err := handler.Events(args)
+		events, err := handler.Events(context.Background(), args)
 		require.Equal(t, rpc.ErrPageSizeTooBig, err)
 		require.Nil(t, events)
 	})
@@ -163,7 +163,7 @@ func TestEvents(t *testing.T) {
 	t.Run("too many keys", func(t *testing.T) {
 		args.ChunkSize = 2
 		args.Keys = make([][]felt.Felt, 1024+1)
-		events, err := handler.Events(args)
+		events, err := handler.Events(context.Background(), args)
 		require.Equal(t, rpc.ErrTooManyKeysInFilter, err)
 		require.Nil(t, events)
 	})
@@ -174,12 +174,12 @@ func TestEvents(t *testing.T) {
 		args.ChunkSize = 100
 		args.Keys = make([][]felt.Felt, 0)
 		args.Keys = append(args.Keys, []felt.Felt{*key})
-		events, err := handler.Events(args)
+		events, err := handler.Events(context.Background(), args)
 		require.Nil(t, err)
 		require.Equal(t, "1-0", events.ContinuationToken)
 		require.Empty(t, events.Events)
 		handler = handler.WithFilterLimit(7)
-		events, err = handler.Events(args)
+		events, err = handler.Events(context.Background(), args)
 		require.Nil(t, err)
 		require.Empty(t, events.ContinuationToken)
 		require.NotEmpty(t, events.Events)
@@ -196,7 +196,7 @@ func TestEvents(t *testing.T) {
 				ContinuationToken: "",
 			},
 		}
-		events, err := handler.Events(args)
+		events, err := handler.Events(context.Background(), args)
 		require.Nil(t, err)
 		require.Len(t, events.Events, 2)
 		require.Empty(t, events.ContinuationToken)
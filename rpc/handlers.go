@@ -70,14 +70,24 @@ var (
 	ErrTooManyAddressesInFilter        = &jsonrpc.Error{Code: 67, Message: "Too many addresses in filter sender_address filter"}
 	ErrTooManyBlocksBack               = &jsonrpc.Error{Code: 68, Message: fmt.Sprintf("Cannot go back more than %v blocks", maxBlocksBack)}
 	ErrCallOnPending                   = &jsonrpc.Error{Code: 69, Message: "This method does not support being called on the pending block"}
+	ErrMessageNotFound                 = &jsonrpc.Error{Code: 1000, Message: "Message not found"}
+	ErrL1SettlementNotFound            = &jsonrpc.Error{Code: 1001, Message: "L1 settlement of the block not found"}
+	ErrP2PNotEnabled                   = &jsonrpc.Error{Code: 1002, Message: "P2P is not enabled"}
+	ErrAdminNotEnabled                 = &jsonrpc.Error{Code: 1003, Message: "Admin API is not enabled"}
+	ErrAdminActionFailed               = &jsonrpc.Error{Code: 1004, Message: "Admin action failed"}
+	ErrTooManyStorageKeysInFilter      = &jsonrpc.Error{Code: 1005, Message: "Too many storage keys in filter"}
+	ErrTooManyClassHashesInFilter      = &jsonrpc.Error{Code: 1006, Message: "Too many class hashes in filter"}
+	ErrSenderTxnNotFound               = &jsonrpc.Error{Code: 1007, Message: "No transaction found for the sender and nonce"}
 )
 
 const (
-	maxBlocksBack      = 1024
-	maxEventChunkSize  = 10240
-	maxEventFilterKeys = 1024
-	traceCacheSize     = 128
-	throttledVMErr     = "VM throughput limit reached"
+	maxBlocksBack         = 1024
+	maxEventChunkSize     = 10240
+	maxContractsChunkSize = 1024
+	maxMessagesChunkSize  = 1024
+	maxEventFilterKeys    = 1024
+	traceCacheSize        = 128
+	throttledVMErr        = "VM throughput limit reached"
 )
 
 type traceCacheKey struct {
@@ -102,6 +112,14 @@ type Handler struct {
 	mu            stdsync.Mutex // protects subscriptions.
 	subscriptions map[uint64]*subscription
 
+	subscriptionQueueSize int
+	overflowPolicy        OverflowPolicy
+	listener              EventListener
+
+	reorgHistoryMu stdsync.Mutex // protects reorgHistory.
+	reorgHistory   []*sync.ReorgBlockRange
+	eventsFilters  *lru.Cache[felt.Felt, *eventsFilter]
+
 	blockTraceCache *lru.Cache[traceCacheKey, []TracedBlockTransaction]
 
 	filterLimit  uint
@@ -109,12 +127,16 @@ type Handler struct {
 
 	l1Client        l1Client
 	coreContractABI abi.ABI
+	peerReputation  peerReputation
+	peerManager     peerManager
+	nodeAdmin       nodeAdmin
 }
 
 type subscription struct {
 	cancel func()
 	wg     conc.WaitGroup
 	conn   jsonrpc.Conn
+	queue  *subscriptionQueue
 }
 
 func New(bcReader blockchain.Reader, syncReader sync.Reader, virtualMachine vm.VM, version string,
@@ -142,6 +164,11 @@ func New(bcReader blockchain.Reader, syncReader sync.Reader, virtualMachine vm.V
 		l1Heads:       feed.New[*core.L1Head](),
 		subscriptions: make(map[uint64]*subscription),
 
+		subscriptionQueueSize: defaultSubscriptionQueueSize,
+		overflowPolicy:        OverflowDisconnect,
+		listener:              &SelectiveListener{},
+
+		eventsFilters:   lru.NewCache[felt.Felt, *eventsFilter](eventsFiltersCacheSize),
 		blockTraceCache: lru.NewCache[traceCacheKey, []TracedBlockTransaction](traceCacheSize),
 		filterLimit:     math.MaxUint,
 		coreContractABI: contractABI,
@@ -164,6 +191,24 @@ func (h *Handler) WithCallMaxSteps(maxSteps uint64) *Handler {
 	return h
 }
 
+// WithSubscriptionQueueSize sets how many notifications can be queued for each subscription.
+func (h *Handler) WithSubscriptionQueueSize(size int) *Handler {
+	h.subscriptionQueueSize = size
+	return h
+}
+
+// WithOverflowPolicy sets what happens to the notifications that don't fit in the queue of a subscription.
+func (h *Handler) WithOverflowPolicy(policy OverflowPolicy) *Handler {
+	h.overflowPolicy = policy
+	return h
+}
+
+// WithListener registers an EventListener
+func (h *Handler) WithListener(listener EventListener) *Handler {
+	h.listener = listener
+	return h
+}
+
 func (h *Handler) WithIDGen(idgen func() uint64) *Handler {
 	h.idgen = idgen
 	return h
@@ -174,6 +219,22 @@ func (h *Handler) WithFeeder(feederClient *feeder.Client) *Handler {
 	return h
 }
 
+func (h *Handler) WithPeerReputation(peerReputation peerReputation) *Handler {
+	h.peerReputation = peerReputation
+	return h
+}
+
+func (h *Handler) WithPeerManager(peerManager peerManager) *Handler {
+	h.peerManager = peerManager
+	return h
+}
+
+// WithNodeAdmin enables the admin methods that control the node.
+func (h *Handler) WithNodeAdmin(nodeAdmin nodeAdmin) *Handler {
+	h.nodeAdmin = nodeAdmin
+	return h
+}
+
 func (h *Handler) WithGateway(gatewayClient Gateway) *Handler {
 	h.gatewayClient = gatewayClient
 	return h
@@ -184,16 +245,18 @@ func (h *Handler) Run(ctx context.Context) error {
 	reorgsSub := h.syncReader.SubscribeReorg().Subscription
 	pendingTxsSub := h.syncReader.SubscribePendingTxs().Subscription
 	l1HeadsSub := h.bcReader.SubscribeL1Head().Subscription
+	reorgHistorySub := h.reorgs.Subscribe()
 	defer newHeadsSub.Unsubscribe()
 	defer reorgsSub.Unsubscribe()
 	defer pendingTxsSub.Unsubscribe()
 	defer l1HeadsSub.Unsubscribe()
+	defer reorgHistorySub.Unsubscribe()
 	feed.Tee(newHeadsSub, h.newHeads)
 	feed.Tee(reorgsSub, h.reorgs)
 	feed.Tee(pendingTxsSub, h.pendingTxs)
 	feed.Tee(l1HeadsSub, h.l1Heads)
 
-	<-ctx.Done()
+	h.recordReorgs(ctx, reorgHistorySub)
 	for _, sub := range h.subscriptions {
 		sub.wg.Wait()
 	}
@@ -314,6 +377,81 @@ func (h *Handler) Methods() ([]jsonrpc.Method, string) { //nolint: funlen
 			Name:    "juno_version",
 			Handler: h.Version,
 		},
+		{
+			Name:    "juno_getTransactionBySenderAndNonce",
+			Params:  []jsonrpc.Parameter{{Name: "address"}, {Name: "nonce"}},
+			Handler: h.TransactionBySenderAndNonce,
+		},
+		{
+			Name:    "juno_getTransactionsBySender",
+			Params:  []jsonrpc.Parameter{{Name: "address"}, {Name: "from_nonce", Optional: true}, {Name: "limit", Optional: true}},
+			Handler: h.TransactionsBySender,
+		},
+		{
+			Name:    "juno_getContractInfo",
+			Params:  []jsonrpc.Parameter{{Name: "address"}},
+			Handler: h.ContractInfo,
+		},
+		{
+			Name:    "juno_getClassDeclaration",
+			Params:  []jsonrpc.Parameter{{Name: "class_hash"}},
+			Handler: h.ClassDeclaration,
+		},
+		{
+			Name:    "juno_getContractsByClassHash",
+			Params:  []jsonrpc.Parameter{{Name: "class_hash"}, {Name: "chunk_size"}, {Name: "continuation_token", Optional: true}},
+			Handler: h.ContractsByClassHash,
+		},
+		{
+			Name:    "juno_decodeTransaction",
+			Params:  []jsonrpc.Parameter{{Name: "transaction_hash"}},
+			Handler: h.DecodeTransaction,
+		},
+		{
+			Name:    "juno_decodeEvent",
+			Params:  []jsonrpc.Parameter{{Name: "event"}, {Name: "block_id"}},
+			Handler: h.DecodeEvent,
+		},
+		{
+			Name:    "juno_call",
+			Params:  []jsonrpc.Parameter{{Name: "request"}, {Name: "block_id"}, {Name: "decode", Optional: true}},
+			Handler: h.CallAndDecode,
+		},
+		{
+			Name:    "juno_getL1VerifiedHeight",
+			Handler: h.L1VerifiedHeight,
+		},
+		{
+			Name:    "juno_getL1ToL2Message",
+			Params:  []jsonrpc.Parameter{{Name: "message_hash"}},
+			Handler: h.L1ToL2Message,
+		},
+		{
+			Name:    "juno_getL2ToL1Message",
+			Params:  []jsonrpc.Parameter{{Name: "message_hash"}},
+			Handler: h.L2ToL1Message,
+		},
+		{
+			Name: "juno_getL1ToL2Messages",
+			Params: []jsonrpc.Parameter{
+				{Name: "address"}, {Name: "status", Optional: true}, {Name: "chunk_size"},
+				{Name: "continuation_token", Optional: true},
+			},
+			Handler: h.L1ToL2Messages,
+		},
+		{
+			Name: "juno_getL2ToL1Messages",
+			Params: []jsonrpc.Parameter{
+				{Name: "address"}, {Name: "status", Optional: true}, {Name: "chunk_size"},
+				{Name: "continuation_token", Optional: true},
+			},
+			Handler: h.L2ToL1Messages,
+		},
+		{
+			Name:    "juno_getBlockL1Info",
+			Params:  []jsonrpc.Parameter{{Name: "block_id"}},
+			Handler: h.BlockL1Info,
+		},
 		{
 			Name:    "starknet_getTransactionStatus",
 			Params:  []jsonrpc.Parameter{{Name: "transaction_hash"}},
@@ -373,6 +511,36 @@ func (h *Handler) Methods() ([]jsonrpc.Method, string) { //nolint: funlen
 			Params:  []jsonrpc.Parameter{{Name: "transaction_details", Optional: true}, {Name: "sender_address", Optional: true}},
 			Handler: h.SubscribePendingTxs,
 		},
+		{
+			Name:    "juno_subscribeL1Head",
+			Params:  []jsonrpc.Parameter{{Name: "with_blocks", Optional: true}},
+			Handler: h.SubscribeL1Head,
+		},
+		{
+			Name:    "juno_subscribeStorageChanges",
+			Params:  []jsonrpc.Parameter{{Name: "contract_address"}, {Name: "keys", Optional: true}, {Name: "pending", Optional: true}},
+			Handler: h.SubscribeStorageChanges,
+		},
+		{
+			Name:    "juno_subscribeContractChanges",
+			Params:  []jsonrpc.Parameter{{Name: "address"}, {Name: "pending", Optional: true}},
+			Handler: h.SubscribeContractChanges,
+		},
+		{
+			Name:    "juno_subscribeNewClasses",
+			Params:  []jsonrpc.Parameter{{Name: "include_definition", Optional: true}},
+			Handler: h.SubscribeNewClasses,
+		},
+		{
+			Name:    "juno_subscribeDeployments",
+			Params:  []jsonrpc.Parameter{{Name: "class_hashes", Optional: true}},
+			Handler: h.SubscribeDeployments,
+		},
+		{
+			Name:    "juno_resubscribe",
+			Params:  []jsonrpc.Parameter{{Name: "cursor"}},
+			Handler: h.Resubscribe,
+		},
 		{
 			Name:    "starknet_unsubscribe",
 			Params:  []jsonrpc.Parameter{{Name: "id"}},
@@ -498,6 +666,81 @@ func (h *Handler) MethodsV0_7() ([]jsonrpc.Method, string) { //nolint: funlen
 			Name:    "juno_version",
 			Handler: h.Version,
 		},
+		{
+			Name:    "juno_getTransactionBySenderAndNonce",
+			Params:  []jsonrpc.Parameter{{Name: "address"}, {Name: "nonce"}},
+			Handler: h.TransactionBySenderAndNonce,
+		},
+		{
+			Name:    "juno_getTransactionsBySender",
+			Params:  []jsonrpc.Parameter{{Name: "address"}, {Name: "from_nonce", Optional: true}, {Name: "limit", Optional: true}},
+			Handler: h.TransactionsBySender,
+		},
+		{
+			Name:    "juno_getContractInfo",
+			Params:  []jsonrpc.Parameter{{Name: "address"}},
+			Handler: h.ContractInfo,
+		},
+		{
+			Name:    "juno_getClassDeclaration",
+			Params:  []jsonrpc.Parameter{{Name: "class_hash"}},
+			Handler: h.ClassDeclaration,
+		},
+		{
+			Name:    "juno_getContractsByClassHash",
+			Params:  []jsonrpc.Parameter{{Name: "class_hash"}, {Name: "chunk_size"}, {Name: "continuation_token", Optional: true}},
+			Handler: h.ContractsByClassHash,
+		},
+		{
+			Name:    "juno_decodeTransaction",
+			Params:  []jsonrpc.Parameter{{Name: "transaction_hash"}},
+			Handler: h.DecodeTransaction,
+		},
+		{
+			Name:    "juno_decodeEvent",
+			Params:  []jsonrpc.Parameter{{Name: "event"}, {Name: "block_id"}},
+			Handler: h.DecodeEvent,
+		},
+		{
+			Name:    "juno_call",
+			Params:  []jsonrpc.Parameter{{Name: "request"}, {Name: "block_id"}, {Name: "decode", Optional: true}},
+			Handler: h.CallAndDecode,
+		},
+		{
+			Name:    "juno_getL1VerifiedHeight",
+			Handler: h.L1VerifiedHeight,
+		},
+		{
+			Name:    "juno_getL1ToL2Message",
+			Params:  []jsonrpc.Parameter{{Name: "message_hash"}},
+			Handler: h.L1ToL2Message,
+		},
+		{
+			Name:    "juno_getL2ToL1Message",
+			Params:  []jsonrpc.Parameter{{Name: "message_hash"}},
+			Handler: h.L2ToL1Message,
+		},
+		{
+			Name: "juno_getL1ToL2Messages",
+			Params: []jsonrpc.Parameter{
+				{Name: "address"}, {Name: "status", Optional: true}, {Name: "chunk_size"},
+				{Name: "continuation_token", Optional: true},
+			},
+			Handler: h.L1ToL2Messages,
+		},
+		{
+			Name: "juno_getL2ToL1Messages",
+			Params: []jsonrpc.Parameter{
+				{Name: "address"}, {Name: "status", Optional: true}, {Name: "chunk_size"},
+				{Name: "continuation_token", Optional: true},
+			},
+			Handler: h.L2ToL1Messages,
+		},
+		{
+			Name:    "juno_getBlockL1Info",
+			Params:  []jsonrpc.Parameter{{Name: "block_id"}},
+			Handler: h.BlockL1Info,
+		},
 		{
 			Name:    "starknet_getTransactionStatus",
 			Params:  []jsonrpc.Parameter{{Name: "transaction_hash"}},
@@ -542,6 +785,11 @@ func (h *Handler) MethodsV0_7() ([]jsonrpc.Method, string) { //nolint: funlen
 			Params:  []jsonrpc.Parameter{{Name: "block", Optional: true}},
 			Handler: h.SubscribeNewHeads,
 		},
+		{
+			Name:    "juno_subscribeL1Head",
+			Params:  []jsonrpc.Parameter{{Name: "with_blocks", Optional: true}},
+			Handler: h.SubscribeL1Head,
+		},
 		{
 			Name:    "starknet_unsubscribe",
 			Params:  []jsonrpc.Parameter{{Name: "id"}},
@@ -554,3 +802,53 @@ func (h *Handler) MethodsV0_7() ([]jsonrpc.Method, string) { //nolint: funlen
 		},
 	}, "/v0_7"
 }
+
+// AdminMethods are the methods that control the node, which are only served by the authenticated admin server.
+func (h *Handler) AdminMethods() ([]jsonrpc.Method, string) {
+	return []jsonrpc.Method{
+		{
+			Name:    "juno_admin_setLogLevel",
+			Params:  []jsonrpc.Parameter{{Name: "level"}},
+			Handler: h.SetLogLevel,
+		},
+		{
+			Name:    "juno_admin_pauseSync",
+			Handler: h.PauseSync,
+		},
+		{
+			Name:    "juno_admin_resumeSync",
+			Handler: h.ResumeSync,
+		},
+		{
+			Name:    "juno_admin_revertBlocks",
+			Params:  []jsonrpc.Parameter{{Name: "count"}},
+			Handler: h.RevertBlocks,
+		},
+		{
+			Name:    "juno_admin_compactDB",
+			Handler: h.CompactDB,
+		},
+		{
+			Name:    "juno_admin_config",
+			Handler: h.NodeConfig,
+		},
+		{
+			Name:    "juno_admin_peers",
+			Handler: h.Peers,
+		},
+		{
+			Name:    "juno_admin_addPeer",
+			Params:  []jsonrpc.Parameter{{Name: "address"}},
+			Handler: h.AddPeer,
+		},
+		{
+			Name:    "juno_admin_banPeer",
+			Params:  []jsonrpc.Parameter{{Name: "peer_id"}},
+			Handler: h.BanPeer,
+		},
+		{
+			Name:    "juno_admin_peerScores",
+			Handler: h.PeerScores,
+		},
+	}, "/"
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
 	"github.com/NethermindEth/juno/jsonrpc"
 	"github.com/ethereum/go-ethereum/common"
 	"golang.org/x/crypto/sha3"
@@ -80,7 +82,22 @@ func (h *Handler) GetMessageStatus(ctx context.Context, l1TxnHash *common.Hash)
 	return results, nil
 }
 
+// messageToL2Logs returns the hashes of the messages sent to L2 by an L1 transaction. Transactions of
+// finalised L1 blocks are found in the local message index, the others are fetched from L1.
 func (h *Handler) messageToL2Logs(ctx context.Context, txHash *common.Hash) ([]*common.Hash, *jsonrpc.Error) {
+	msgs, err := h.bcReader.L1ToL2MessagesByL1TxnHash(*txHash)
+	if err != nil {
+		return nil, ErrInternal.CloneWithData(err)
+	}
+	if len(msgs) > 0 {
+		messageHashes := make([]*common.Hash, len(msgs))
+		for i, msg := range msgs {
+			hash := msg.Hash()
+			messageHashes[i] = &hash
+		}
+		return messageHashes, nil
+	}
+
 	if h.l1Client == nil {
 		return nil, jsonrpc.Err(jsonrpc.InternalError, errors.New("11 client not found, cannot serve starknet_getMessage"))
 	}
@@ -110,3 +127,281 @@ func (h *Handler) messageToL2Logs(ctx context.Context, txHash *common.Hash) ([]*
 	}
 	return messageHashes, nil
 }
+
+type L1VerifiedBlock struct {
+	BlockHash   *felt.Felt `json:"block_hash"`
+	BlockNumber uint64     `json:"block_number"`
+}
+
+// L1VerifiedHeight returns the latest block whose hash and state root were checked against the ones
+// finalised on L1.
+func (h *Handler) L1VerifiedHeight() (*L1VerifiedBlock, *jsonrpc.Error) {
+	height, err := h.bcReader.L1VerifiedHeight()
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, ErrNoBlock
+		}
+		return nil, ErrInternal.CloneWithData(err)
+	}
+
+	header, err := h.bcReader.BlockHeaderByNumber(height)
+	if err != nil {
+		return nil, ErrInternal.CloneWithData(err)
+	}
+	return &L1VerifiedBlock{
+		BlockHash:   header.Hash,
+		BlockNumber: header.Number,
+	}, nil
+}
+
+// MessageStatus is the status of a message in the core contract.
+type MessageStatus core.MessageStatus
+
+func (s MessageStatus) MarshalText() ([]byte, error) {
+	switch core.MessageStatus(s) {
+	case core.MessagePending:
+		return []byte("PENDING"), nil
+	case core.MessageConsumed:
+		return []byte("CONSUMED"), nil
+	case core.MessageCancellationStarted:
+		return []byte("CANCELLATION_STARTED"), nil
+	case core.MessageCancelled:
+		return []byte("CANCELLED"), nil
+	default:
+		return nil, fmt.Errorf("unknown MessageStatus %v", s)
+	}
+}
+
+func (s *MessageStatus) UnmarshalText(data []byte) error {
+	switch string(data) {
+	case "PENDING":
+		*s = MessageStatus(core.MessagePending)
+	case "CONSUMED":
+		*s = MessageStatus(core.MessageConsumed)
+	case "CANCELLATION_STARTED":
+		*s = MessageStatus(core.MessageCancellationStarted)
+	case "CANCELLED":
+		*s = MessageStatus(core.MessageCancelled)
+	default:
+		return fmt.Errorf("unknown MessageStatus: %q", string(data))
+	}
+	return nil
+}
+
+type L1ToL2Message struct {
+	MessageHash                common.Hash    `json:"message_hash"`
+	FromAddress                common.Address `json:"from_address"`
+	ToAddress                  *felt.Felt     `json:"to_address"`
+	EntryPointSelector         *felt.Felt     `json:"entry_point_selector"`
+	Payload                    []*felt.Felt   `json:"payload"`
+	Nonce                      *felt.Felt     `json:"nonce"`
+	Fee                        *felt.Felt     `json:"paid_fee_on_l1,omitempty"`
+	Status                     MessageStatus  `json:"status"`
+	SentTxnHash                *common.Hash   `json:"l1_transaction_hash,omitempty"`
+	ConsumedTxnHash            *common.Hash   `json:"consumed_transaction_hash,omitempty"`
+	CancellationStartedTxnHash *common.Hash   `json:"cancellation_started_transaction_hash,omitempty"`
+	CancelledTxnHash           *common.Hash   `json:"cancelled_transaction_hash,omitempty"`
+	L1HandlerTxnHash           *felt.Felt     `json:"l1_handler_transaction_hash,omitempty"`
+}
+
+type L2ToL1Message struct {
+	MessageHash         common.Hash    `json:"message_hash"`
+	FromAddress         *felt.Felt     `json:"from_address"`
+	ToAddress           common.Address `json:"to_address"`
+	Payload             []*felt.Felt   `json:"payload"`
+	Status              MessageStatus  `json:"status"`
+	LoggedCount         uint64         `json:"logged_count"`
+	ConsumedCount       uint64         `json:"consumed_count"`
+	LastLoggedTxnHash   *common.Hash   `json:"last_logged_transaction_hash,omitempty"`
+	LastConsumedTxnHash *common.Hash   `json:"last_consumed_transaction_hash,omitempty"`
+}
+
+type L1ToL2MessagesChunk struct {
+	Messages          []*L1ToL2Message `json:"messages"`
+	ContinuationToken string           `json:"continuation_token,omitempty"`
+}
+
+type L2ToL1MessagesChunk struct {
+	Messages          []*L2ToL1Message `json:"messages"`
+	ContinuationToken string           `json:"continuation_token,omitempty"`
+}
+
+// L1ToL2Message returns a message sent to L2 with its status in the core contract and the hash of the
+// L1 handler transaction that executed it, if any.
+func (h *Handler) L1ToL2Message(hash common.Hash) (*L1ToL2Message, *jsonrpc.Error) {
+	msg, err := h.bcReader.L1ToL2Message(hash)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, ErrInternal.CloneWithData(err)
+	}
+	return h.adaptL1ToL2Message(hash, msg)
+}
+
+// L2ToL1Message returns a message sent to L1 with its status in the core contract.
+func (h *Handler) L2ToL1Message(hash common.Hash) (*L2ToL1Message, *jsonrpc.Error) {
+	msg, err := h.bcReader.L2ToL1Message(hash)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, ErrInternal.CloneWithData(err)
+	}
+	return adaptL2ToL1Message(hash, msg), nil
+}
+
+// L1ToL2Messages returns the messages sent to L2 by or to the given address, optionally filtered by
+// status, in pages of chunkSize messages. The continuation token of a page resumes the listing.
+func (h *Handler) L1ToL2Messages(address felt.Felt, status *MessageStatus, chunkSize uint64,
+	continuationToken string,
+) (*L1ToL2MessagesChunk, *jsonrpc.Error) {
+	startAt, rpcErr := messagesStartAt(chunkSize, continuationToken)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	// fetch one more message than requested to know whether there is a next page
+	msgs, err := h.bcReader.L1ToL2Messages(&address, (*core.MessageStatus)(status), startAt, chunkSize+1)
+	if err != nil {
+		return nil, ErrInternal.CloneWithData(err)
+	}
+
+	chunk := &L1ToL2MessagesChunk{Messages: []*L1ToL2Message{}}
+	for i, msg := range msgs {
+		hash := msg.Hash()
+		if uint64(i) == chunkSize {
+			chunk.ContinuationToken = hash.Hex()
+			break
+		}
+		adapted, rpcErr := h.adaptL1ToL2Message(hash, msg)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+		chunk.Messages = append(chunk.Messages, adapted)
+	}
+	return chunk, nil
+}
+
+// L2ToL1Messages returns the messages sent to L1 by or to the given address, optionally filtered by
+// status, in pages of chunkSize messages. The continuation token of a page resumes the listing.
+func (h *Handler) L2ToL1Messages(address felt.Felt, status *MessageStatus, chunkSize uint64,
+	continuationToken string,
+) (*L2ToL1MessagesChunk, *jsonrpc.Error) {
+	startAt, rpcErr := messagesStartAt(chunkSize, continuationToken)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	// fetch one more message than requested to know whether there is a next page
+	msgs, err := h.bcReader.L2ToL1Messages(&address, (*core.MessageStatus)(status), startAt, chunkSize+1)
+	if err != nil {
+		return nil, ErrInternal.CloneWithData(err)
+	}
+
+	chunk := &L2ToL1MessagesChunk{Messages: []*L2ToL1Message{}}
+	for i, msg := range msgs {
+		hash := msg.Hash()
+		if uint64(i) == chunkSize {
+			chunk.ContinuationToken = hash.Hex()
+			break
+		}
+		chunk.Messages = append(chunk.Messages, adaptL2ToL1Message(hash, msg))
+	}
+	return chunk, nil
+}
+
+func messagesStartAt(chunkSize uint64, continuationToken string) (common.Hash, *jsonrpc.Error) {
+	if chunkSize == 0 {
+		return common.Hash{}, jsonrpc.Err(jsonrpc.InvalidParams, "chunk_size must be positive")
+	} else if chunkSize > maxMessagesChunkSize {
+		return common.Hash{}, ErrPageSizeTooBig
+	}
+
+	var startAt common.Hash
+	if continuationToken != "" {
+		if err := startAt.UnmarshalText([]byte(continuationToken)); err != nil {
+			return common.Hash{}, ErrInvalidContinuationToken
+		}
+	}
+	return startAt, nil
+}
+
+func (h *Handler) adaptL1ToL2Message(hash common.Hash, msg *core.L1ToL2MessageLog) (*L1ToL2Message, *jsonrpc.Error) {
+	l1HandlerTxnHash, err := h.bcReader.L1HandlerTxnHash(&hash)
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return nil, ErrInternal.CloneWithData(err)
+	}
+
+	return &L1ToL2Message{
+		MessageHash:                hash,
+		FromAddress:                msg.From,
+		ToAddress:                  msg.To,
+		EntryPointSelector:         msg.Selector,
+		Payload:                    msg.Payload,
+		Nonce:                      msg.Nonce,
+		Fee:                        msg.Fee,
+		Status:                     MessageStatus(msg.Status()),
+		SentTxnHash:                msg.SentTxnHash,
+		ConsumedTxnHash:            msg.ConsumedTxnHash,
+		CancellationStartedTxnHash: msg.CancellationStartedTxnHash,
+		CancelledTxnHash:           msg.CancelledTxnHash,
+		L1HandlerTxnHash:           l1HandlerTxnHash,
+	}, nil
+}
+
+func adaptL2ToL1Message(hash common.Hash, msg *core.L2ToL1MessageLog) *L2ToL1Message {
+	return &L2ToL1Message{
+		MessageHash:         hash,
+		FromAddress:         msg.From,
+		ToAddress:           msg.To,
+		Payload:             msg.Payload,
+		Status:              MessageStatus(msg.Status()),
+		LoggedCount:         msg.Logged,
+		ConsumedCount:       msg.Consumed,
+		LastLoggedTxnHash:   msg.LastLoggedTxnHash,
+		LastConsumedTxnHash: msg.LastConsumedTxnHash,
+	}
+}
+
+type BlockL1Info struct {
+	BlockHash        *felt.Felt  `json:"block_hash"`
+	BlockNumber      uint64      `json:"block_number"`
+	L1BlockHash      common.Hash `json:"l1_block_hash"`
+	L1BlockNumber    uint64      `json:"l1_block_number"`
+	L1TxnHash        common.Hash `json:"l1_transaction_hash"`
+	FirstBlockNumber uint64      `json:"first_block_number"`
+	LastBlockNumber  uint64      `json:"last_block_number"`
+	StateRoot        *felt.Felt  `json:"state_root"`
+}
+
+// BlockL1Info returns the L1 transaction whose state update settled the given block, along with the
+// range of blocks settled by that update and the state root it committed to.
+func (h *Handler) BlockL1Info(id BlockID) (*BlockL1Info, *jsonrpc.Error) {
+	if id.Pending {
+		return nil, ErrCallOnPending
+	}
+
+	header, rpcErr := h.blockHeaderByID(&id)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	update, err := h.bcReader.L1StateUpdateByBlockNumber(header.Number)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return nil, ErrL1SettlementNotFound
+		}
+		return nil, ErrInternal.CloneWithData(err)
+	}
+	return &BlockL1Info{
+		BlockHash:        header.Hash,
+		BlockNumber:      header.Number,
+		L1BlockHash:      update.L1BlockHash,
+		L1BlockNumber:    update.L1BlockNumber,
+		L1TxnHash:        update.L1TxnHash,
+		FirstBlockNumber: update.FirstBlockNumber,
+		LastBlockNumber:  update.BlockNumber,
+		StateRoot:        update.StateRoot,
+	}, nil
+}
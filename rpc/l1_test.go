@@ -7,12 +7,16 @@ import (
 
 	"github.com/NethermindEth/juno/clients/feeder"
 	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
 	"github.com/NethermindEth/juno/mocks"
 	"github.com/NethermindEth/juno/rpc"
 	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
 	"github.com/NethermindEth/juno/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -84,6 +88,7 @@ func TestGetMessageStatus(t *testing.T) {
 				l1handlerTxns[i] = txn
 			}
 
+			mockReader.EXPECT().L1ToL2MessagesByL1TxnHash(test.l1TxnHash).Return(nil, nil)
 			mockSubscriber.EXPECT().TransactionReceipt(gomock.Any(), gomock.Any()).Return(&test.l1TxnReceipt, nil)
 			for i, msg := range test.msgs {
 				mockReader.EXPECT().L1HandlerTxnHash(&test.msgHashes[i]).Return(msg.L1HandlerHash, nil)
@@ -98,3 +103,205 @@ func TestGetMessageStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestL1VerifiedHeight(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	handler := rpc.New(mockReader, nil, nil, "", utils.NewNopZapLogger())
+
+	t.Run("nothing verified yet", func(t *testing.T) {
+		mockReader.EXPECT().L1VerifiedHeight().Return(uint64(0), db.ErrKeyNotFound)
+
+		block, rpcErr := handler.L1VerifiedHeight()
+		require.Nil(t, block)
+		require.Equal(t, rpc.ErrNoBlock, rpcErr)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		header := &core.Header{Number: 7, Hash: new(felt.Felt).SetUint64(0xabc)}
+		mockReader.EXPECT().L1VerifiedHeight().Return(uint64(7), nil)
+		mockReader.EXPECT().BlockHeaderByNumber(uint64(7)).Return(header, nil)
+
+		block, rpcErr := handler.L1VerifiedHeight()
+		require.Nil(t, rpcErr)
+		require.Equal(t, &rpc.L1VerifiedBlock{BlockHash: header.Hash, BlockNumber: 7}, block)
+	})
+}
+
+func TestL1Messages(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	mockSyncReader := mocks.NewMockSyncReader(mockCtrl)
+	handler := rpc.New(mockReader, mockSyncReader, nil, "", utils.NewNopZapLogger())
+
+	sentTxnHash := common.HexToHash("0xa1")
+	newL1ToL2Message := func(nonce uint64) *core.L1ToL2MessageLog {
+		return &core.L1ToL2MessageLog{
+			L1ToL2Message: core.L1ToL2Message{
+				From:     common.HexToAddress("0x1"),
+				To:       new(felt.Felt).SetUint64(2),
+				Selector: new(felt.Felt).SetUint64(3),
+				Payload:  []*felt.Felt{new(felt.Felt).SetUint64(4)},
+				Nonce:    new(felt.Felt).SetUint64(nonce),
+			},
+			Fee:         new(felt.Felt).SetUint64(5),
+			SentTxnHash: &sentTxnHash,
+		}
+	}
+	msg := newL1ToL2Message(0)
+	msgHash := msg.Hash()
+
+	t.Run("message not found", func(t *testing.T) {
+		mockReader.EXPECT().L1ToL2Message(msgHash).Return(nil, db.ErrKeyNotFound)
+		_, rpcErr := handler.L1ToL2Message(msgHash)
+		require.Equal(t, rpc.ErrMessageNotFound, rpcErr)
+
+		mockReader.EXPECT().L2ToL1Message(msgHash).Return(nil, db.ErrKeyNotFound)
+		_, rpcErr = handler.L2ToL1Message(msgHash)
+		require.Equal(t, rpc.ErrMessageNotFound, rpcErr)
+	})
+
+	t.Run("message sent to L2", func(t *testing.T) {
+		l1HandlerTxnHash := new(felt.Felt).SetUint64(6)
+		mockReader.EXPECT().L1ToL2Message(msgHash).Return(msg, nil)
+		mockReader.EXPECT().L1HandlerTxnHash(&msgHash).Return(l1HandlerTxnHash, nil)
+
+		got, rpcErr := handler.L1ToL2Message(msgHash)
+		require.Nil(t, rpcErr)
+		assert.Equal(t, msgHash, got.MessageHash)
+		assert.Equal(t, rpc.MessageStatus(core.MessagePending), got.Status)
+		assert.Equal(t, l1HandlerTxnHash, got.L1HandlerTxnHash)
+
+		gotJSON, err := json.Marshal(got)
+		require.NoError(t, err)
+		assert.Contains(t, string(gotJSON), `"status":"PENDING"`)
+		assert.NotContains(t, string(gotJSON), "consumed_transaction_hash")
+	})
+
+	t.Run("message sent to L1", func(t *testing.T) {
+		toL1 := &core.L2ToL1MessageLog{
+			L2ToL1Message: core.L2ToL1Message{
+				From:    new(felt.Felt).SetUint64(1),
+				To:      common.HexToAddress("0x2"),
+				Payload: []*felt.Felt{},
+			},
+			Logged:   2,
+			Consumed: 2,
+		}
+		mockReader.EXPECT().L2ToL1Message(toL1.Hash()).Return(toL1, nil)
+
+		got, rpcErr := handler.L2ToL1Message(toL1.Hash())
+		require.Nil(t, rpcErr)
+		assert.Equal(t, rpc.MessageStatus(core.MessageConsumed), got.Status)
+		assert.Equal(t, uint64(2), got.LoggedCount)
+	})
+
+	t.Run("list messages", func(t *testing.T) {
+		address := new(felt.Felt).SetUint64(2)
+		status := rpc.MessageStatus(core.MessagePending)
+		next := newL1ToL2Message(1)
+
+		mockReader.EXPECT().L1ToL2Messages(address, (*core.MessageStatus)(&status), common.Hash{}, uint64(2)).
+			Return([]*core.L1ToL2MessageLog{msg, next}, nil)
+		mockReader.EXPECT().L1HandlerTxnHash(&msgHash).Return(nil, db.ErrKeyNotFound)
+
+		chunk, rpcErr := handler.L1ToL2Messages(*address, &status, 1, "")
+		require.Nil(t, rpcErr)
+		require.Len(t, chunk.Messages, 1)
+		assert.Equal(t, msgHash, chunk.Messages[0].MessageHash)
+		assert.Nil(t, chunk.Messages[0].L1HandlerTxnHash)
+		assert.Equal(t, next.Hash().Hex(), chunk.ContinuationToken)
+
+		nextHash := next.Hash()
+		mockReader.EXPECT().L1ToL2Messages(address, nil, nextHash, uint64(2)).
+			Return([]*core.L1ToL2MessageLog{next}, nil)
+		mockReader.EXPECT().L1HandlerTxnHash(&nextHash).Return(nil, db.ErrKeyNotFound)
+
+		chunk, rpcErr = handler.L1ToL2Messages(*address, nil, 1, chunk.ContinuationToken)
+		require.Nil(t, rpcErr)
+		require.Len(t, chunk.Messages, 1)
+		assert.Empty(t, chunk.ContinuationToken)
+	})
+
+	t.Run("invalid listing parameters", func(t *testing.T) {
+		address := new(felt.Felt).SetUint64(2)
+		_, rpcErr := handler.L2ToL1Messages(*address, nil, 0, "")
+		require.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+
+		_, rpcErr = handler.L2ToL1Messages(*address, nil, 1025, "")
+		require.Equal(t, rpc.ErrPageSizeTooBig, rpcErr)
+
+		_, rpcErr = handler.L2ToL1Messages(*address, nil, 1, "not a hash")
+		require.Equal(t, rpc.ErrInvalidContinuationToken, rpcErr)
+	})
+
+	t.Run("status of a message in the local index", func(t *testing.T) {
+		l1HandlerTxnHash := new(felt.Felt).SetUint64(6)
+		mockReader.EXPECT().L1ToL2MessagesByL1TxnHash(sentTxnHash).Return([]*core.L1ToL2MessageLog{msg}, nil)
+		mockReader.EXPECT().L1HandlerTxnHash(&msgHash).Return(l1HandlerTxnHash, nil)
+		mockReader.EXPECT().TransactionByHash(l1HandlerTxnHash).Return(nil, db.ErrKeyNotFound)
+		mockSyncReader.EXPECT().PendingBlock().Return(nil)
+
+		_, rpcErr := handler.GetMessageStatus(context.Background(), &sentTxnHash)
+		require.Equal(t, rpc.ErrTxnHashNotFound, rpcErr)
+	})
+}
+
+func TestBlockL1Info(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	mockReader := mocks.NewMockReader(mockCtrl)
+	handler := rpc.New(mockReader, nil, nil, "", utils.NewNopZapLogger())
+
+	header := &core.Header{Number: 7, Hash: new(felt.Felt).SetUint64(0xabc)}
+
+	t.Run("pending block", func(t *testing.T) {
+		_, rpcErr := handler.BlockL1Info(rpc.BlockID{Pending: true})
+		require.Equal(t, rpc.ErrCallOnPending, rpcErr)
+	})
+
+	t.Run("block not found", func(t *testing.T) {
+		mockReader.EXPECT().BlockHeaderByNumber(uint64(7)).Return(nil, db.ErrKeyNotFound)
+		_, rpcErr := handler.BlockL1Info(rpc.BlockID{Number: 7})
+		require.Equal(t, rpc.ErrBlockNotFound, rpcErr)
+	})
+
+	t.Run("block not settled", func(t *testing.T) {
+		mockReader.EXPECT().HeadsHeader().Return(header, nil)
+		mockReader.EXPECT().L1StateUpdateByBlockNumber(uint64(7)).Return(nil, db.ErrKeyNotFound)
+		_, rpcErr := handler.BlockL1Info(rpc.BlockID{Latest: true})
+		require.Equal(t, rpc.ErrL1SettlementNotFound, rpcErr)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		update := &core.L1StateUpdate{
+			L1BlockNumber:    100,
+			L1BlockHash:      common.HexToHash("0x1"),
+			L1TxnHash:        common.HexToHash("0x2"),
+			FirstBlockNumber: 5,
+			BlockNumber:      9,
+			BlockHash:        new(felt.Felt).SetUint64(9),
+			StateRoot:        new(felt.Felt).SetUint64(3),
+		}
+		mockReader.EXPECT().BlockHeaderByHash(header.Hash).Return(header, nil)
+		mockReader.EXPECT().L1StateUpdateByBlockNumber(uint64(7)).Return(update, nil)
+
+		info, rpcErr := handler.BlockL1Info(rpc.BlockID{Hash: header.Hash})
+		require.Nil(t, rpcErr)
+		require.Equal(t, &rpc.BlockL1Info{
+			BlockHash:        header.Hash,
+			BlockNumber:      7,
+			L1BlockHash:      update.L1BlockHash,
+			L1BlockNumber:    100,
+			L1TxnHash:        update.L1TxnHash,
+			FirstBlockNumber: 5,
+			LastBlockNumber:  9,
+			StateRoot:        update.StateRoot,
+		}, info)
+	})
+}
@@ -0,0 +1,99 @@
+package rpc
+
+import (
+	"encoding"
+	"reflect"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/utils"
+)
+
+// https://github.com/starkware-libs/starknet-specs/blob/a789ccc3432c57777beceaa53a34a7ae2f25fda0/api/starknet_api_openrpc.json#L1244
+var feltSchema = &jsonrpc.Schema{
+	Title:       "Field element",
+	Description: "A field element represented by at most 63 hex digits",
+	Type:        "string",
+	Pattern:     "^0x(0|[a-fA-F1-9]{1}[a-fA-F0-9]{0,62})$",
+}
+
+// OpenRPCSchemas returns the schemas of the types that marshal themselves, which the OpenRPC documents of the
+// servers can't derive by reflection.
+func OpenRPCSchemas() map[reflect.Type]*jsonrpc.Schema {
+	return map[reflect.Type]*jsonrpc.Schema{
+		reflect.TypeFor[felt.Felt](): feltSchema,
+		reflect.TypeFor[BlockID]():   blockIDSchema(),
+		reflect.TypeFor[Sync]():      syncSchema(),
+		reflect.TypeFor[TransactionType](): enumSchema(TxnDeclare, TxnDeploy, TxnDeployAccount, TxnInvoke,
+			TxnL1Handler),
+		reflect.TypeFor[TxnStatus](): enumSchema(TxnStatusReceived, TxnStatusRejected, TxnStatusAcceptedOnL2,
+			TxnStatusAcceptedOnL1),
+		reflect.TypeFor[TxnExecutionStatus]():   enumSchema(TxnSuccess, TxnFailure),
+		reflect.TypeFor[TxnFinalityStatus]():    enumSchema(TxnAcceptedOnL2, TxnAcceptedOnL1),
+		reflect.TypeFor[DataAvailabilityMode](): enumSchema(DAModeL1, DAModeL2),
+		reflect.TypeFor[Resource]():             enumSchema(ResourceL1Gas, ResourceL2Gas),
+		reflect.TypeFor[BlockStatus]():          enumSchema(BlockPending, BlockAcceptedL2, BlockAcceptedL1, BlockRejected),
+		reflect.TypeFor[L1DAMode]():             enumSchema(Blob, Calldata),
+		reflect.TypeFor[FeeUnit]():              enumSchema(WEI, FRI),
+		reflect.TypeFor[MessageStatus](): enumSchema(MessageStatus(core.MessagePending),
+			MessageStatus(core.MessageConsumed), MessageStatus(core.MessageCancellationStarted),
+			MessageStatus(core.MessageCancelled)),
+		reflect.TypeFor[SimulationFlag](): {Type: "string", Enum: []any{"SKIP_VALIDATE", "SKIP_FEE_CHARGE"}},
+		reflect.TypeFor[utils.LogLevel](): enumSchema(utils.Ptr(utils.TRACE), utils.Ptr(utils.DEBUG),
+			utils.Ptr(utils.INFO), utils.Ptr(utils.WARN), utils.Ptr(utils.ERROR)),
+	}
+}
+
+// enumSchema describes a type marshalled to the text of one of values.
+func enumSchema(values ...encoding.TextMarshaler) *jsonrpc.Schema {
+	enum := make([]any, 0, len(values))
+	for _, value := range values {
+		text, err := value.MarshalText()
+		if err != nil {
+			panic(err)
+		}
+		enum = append(enum, string(text))
+	}
+	return &jsonrpc.Schema{Type: "string", Enum: enum}
+}
+
+// https://github.com/starkware-libs/starknet-specs/blob/a789ccc3432c57777beceaa53a34a7ae2f25fda0/api/starknet_api_openrpc.json#L814
+func blockIDSchema() *jsonrpc.Schema {
+	return &jsonrpc.Schema{
+		OneOf: []*jsonrpc.Schema{
+			{
+				Type:       "object",
+				Properties: map[string]*jsonrpc.Schema{"block_hash": feltSchema},
+				Required:   []string{"block_hash"},
+			},
+			{
+				Type:       "object",
+				Properties: map[string]*jsonrpc.Schema{"block_number": {Type: "integer", Minimum: new(float64)}},
+				Required:   []string{"block_number"},
+			},
+			{Type: "string", Enum: []any{"latest", "pending"}},
+		},
+	}
+}
+
+// Sync is false when the node isn't syncing.
+func syncSchema() *jsonrpc.Schema {
+	blockNumber := &jsonrpc.Schema{Type: "integer", Minimum: new(float64)}
+	return &jsonrpc.Schema{
+		OneOf: []*jsonrpc.Schema{
+			{Type: "boolean", Enum: []any{false}},
+			{
+				Type: "object",
+				Properties: map[string]*jsonrpc.Schema{
+					"starting_block_hash": feltSchema,
+					"starting_block_num":  blockNumber,
+					"current_block_hash":  feltSchema,
+					"current_block_num":   blockNumber,
+					"highest_block_hash":  feltSchema,
+					"highest_block_num":   blockNumber,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,41 @@
+package rpc_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/rpc"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenRPCDocument(t *testing.T) {
+	handler := rpc.New(nil, nil, nil, "", nil)
+	methods, _ := handler.Methods()
+	legacyMethods, _ := handler.MethodsV0_7()
+	refPattern := regexp.MustCompile(`"\$ref":"#/components/schemas/([^"]+)"`)
+
+	for name, methodSet := range map[string][]jsonrpc.Method{"v0_8": methods, "v0_7": legacyMethods} {
+		t.Run(name, func(t *testing.T) {
+			server := jsonrpc.NewServer(1, utils.NewNopZapLogger())
+			require.NoError(t, server.RegisterMethods(methodSet...))
+			doc := server.OpenRPCDocument(jsonrpc.OpenRPCInfo{}, rpc.OpenRPCSchemas())
+			require.Len(t, doc.Methods, len(methodSet))
+
+			encoded, err := json.Marshal(doc)
+			require.NoError(t, err)
+			for _, match := range refPattern.FindAllStringSubmatch(string(encoded), -1) {
+				assert.Contains(t, doc.Components.Schemas, match[1])
+			}
+
+			felt := doc.Components.Schemas["Felt"]
+			require.NotNil(t, felt)
+			assert.Regexp(t, felt.Pattern, "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7")
+			assert.Equal(t, []any{"DECLARE", "DEPLOY", "DEPLOY_ACCOUNT", "INVOKE", "L1_HANDLER"},
+				doc.Components.Schemas["TransactionType"].Enum)
+		})
+	}
+}
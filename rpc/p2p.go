@@ -0,0 +1,108 @@
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/p2p/reputation"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+type peerReputation interface {
+	Scores() []reputation.PeerScore
+}
+
+type peerManager interface {
+	Peers() []peer.AddrInfo
+	AddPeer(ctx context.Context, addr string) error
+	BanPeer(id peer.ID) error
+}
+
+type Peer struct {
+	PeerID    string   `json:"peer_id"`
+	Addresses []string `json:"addresses"`
+}
+
+type PeerScore struct {
+	PeerID string  `json:"peer_id"`
+	Score  float64 `json:"score"`
+	Bans   uint64  `json:"bans"`
+	// BannedUntil is the unix time in seconds until which the peer is banned
+	BannedUntil   *int64 `json:"banned_until,omitempty"`
+	BannedForever bool   `json:"banned_forever"`
+}
+
+/****************************************************
+		Admin Handlers
+*****************************************************/
+
+// PeerScores returns the reputation of the p2p peers of the node, including the banned ones.
+func (h *Handler) PeerScores() ([]PeerScore, *jsonrpc.Error) {
+	if h.peerReputation == nil {
+		return nil, ErrP2PNotEnabled
+	}
+
+	now := time.Now().Unix()
+	scores := h.peerReputation.Scores()
+	peerScores := make([]PeerScore, len(scores))
+	for i, score := range scores {
+		peerScores[i] = PeerScore{
+			PeerID:        score.ID.String(),
+			Score:         score.Score,
+			Bans:          score.Bans,
+			BannedForever: score.BannedForever,
+		}
+		// an expired ban is no longer relevant
+		if score.BannedUntil > now {
+			peerScores[i].BannedUntil = &score.BannedUntil
+		}
+	}
+	return peerScores, nil
+}
+
+// Peers returns the p2p peers the node is connected to.
+func (h *Handler) Peers() ([]Peer, *jsonrpc.Error) {
+	if h.peerManager == nil {
+		return nil, ErrP2PNotEnabled
+	}
+
+	addrInfos := h.peerManager.Peers()
+	peers := make([]Peer, len(addrInfos))
+	for i, addrInfo := range addrInfos {
+		peers[i] = Peer{PeerID: addrInfo.ID.String(), Addresses: make([]string, len(addrInfo.Addrs))}
+		for j, addr := range addrInfo.Addrs {
+			peers[i].Addresses[j] = addr.String()
+		}
+	}
+	return peers, nil
+}
+
+// AddPeer connects the node to the peer at address, a multiaddr which ends with the ID of the peer, such as
+// /ip4/127.0.0.1/tcp/7777/p2p/12D3KooW...
+func (h *Handler) AddPeer(ctx context.Context, address string) (bool, *jsonrpc.Error) {
+	if h.peerManager == nil {
+		return false, ErrP2PNotEnabled
+	}
+
+	if err := h.peerManager.AddPeer(ctx, address); err != nil {
+		return false, ErrAdminActionFailed.CloneWithData(err.Error())
+	}
+	return true, nil
+}
+
+// BanPeer disconnects the node from the peer and bans it permanently.
+func (h *Handler) BanPeer(peerID string) (bool, *jsonrpc.Error) {
+	if h.peerManager == nil {
+		return false, ErrP2PNotEnabled
+	}
+
+	id, err := peer.Decode(peerID)
+	if err != nil {
+		return false, jsonrpc.Err(jsonrpc.InvalidParams, err.Error())
+	}
+	if err = h.peerManager.BanPeer(id); err != nil {
+		return false, ErrAdminActionFailed.CloneWithData(err.Error())
+	}
+	return true, nil
+}
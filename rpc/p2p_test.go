@@ -0,0 +1,120 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/p2p/reputation"
+	"github.com/NethermindEth/juno/rpc"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReputation []reputation.PeerScore
+
+func (r fakeReputation) Scores() []reputation.PeerScore {
+	return r
+}
+
+func TestPeerScores(t *testing.T) {
+	handler := rpc.New(nil, nil, nil, "", utils.NewNopZapLogger())
+
+	t.Run("p2p disabled", func(t *testing.T) {
+		scores, rpcErr := handler.PeerScores()
+		assert.Nil(t, scores)
+		assert.Equal(t, rpc.ErrP2PNotEnabled, rpcErr)
+	})
+
+	t.Run("scores", func(t *testing.T) {
+		bannedUntil := time.Now().Add(time.Hour).Unix()
+		handler = handler.WithPeerReputation(fakeReputation{
+			{ID: peer.ID("banned"), Record: reputation.Record{Bans: 1, BannedUntil: bannedUntil}},
+			{ID: peer.ID("expired"), Record: reputation.Record{Bans: 2, BannedUntil: time.Now().Add(-time.Hour).Unix()}},
+			{ID: peer.ID("good"), Record: reputation.Record{Score: 12.5}},
+		})
+
+		scores, rpcErr := handler.PeerScores()
+		require.Nil(t, rpcErr)
+		assert.Equal(t, []rpc.PeerScore{
+			{PeerID: peer.ID("banned").String(), Bans: 1, BannedUntil: &bannedUntil},
+			{PeerID: peer.ID("expired").String(), Bans: 2},
+			{PeerID: peer.ID("good").String(), Score: 12.5},
+		}, scores)
+	})
+}
+
+type fakePeerManager struct {
+	peers  []peer.AddrInfo
+	added  []string
+	banned []peer.ID
+}
+
+func (m *fakePeerManager) Peers() []peer.AddrInfo {
+	return m.peers
+}
+
+func (m *fakePeerManager) AddPeer(_ context.Context, addr string) error {
+	if addr == "unreachable" {
+		return errors.New("dial failed")
+	}
+	m.added = append(m.added, addr)
+	return nil
+}
+
+func (m *fakePeerManager) BanPeer(id peer.ID) error {
+	m.banned = append(m.banned, id)
+	return nil
+}
+
+func TestPeerManagement(t *testing.T) {
+	handler := rpc.New(nil, nil, nil, "", utils.NewNopZapLogger())
+
+	t.Run("p2p disabled", func(t *testing.T) {
+		_, rpcErr := handler.Peers()
+		assert.Equal(t, rpc.ErrP2PNotEnabled, rpcErr)
+		_, rpcErr = handler.AddPeer(context.Background(), "")
+		assert.Equal(t, rpc.ErrP2PNotEnabled, rpcErr)
+		_, rpcErr = handler.BanPeer("")
+		assert.Equal(t, rpc.ErrP2PNotEnabled, rpcErr)
+	})
+
+	id, err := peer.Decode("12D3KooWLdURCjbp1D7hkXWk6ZVfcMDPtsNnPHuxoTcWXFtvrxGG")
+	require.NoError(t, err)
+	addr, err := multiaddr.NewMultiaddr("/ip4/127.0.0.1/tcp/7777")
+	require.NoError(t, err)
+	peerManager := &fakePeerManager{peers: []peer.AddrInfo{{ID: id, Addrs: []multiaddr.Multiaddr{addr}}}}
+	handler = handler.WithPeerManager(peerManager)
+
+	t.Run("peers", func(t *testing.T) {
+		peers, rpcErr := handler.Peers()
+		require.Nil(t, rpcErr)
+		assert.Equal(t, []rpc.Peer{{PeerID: id.String(), Addresses: []string{"/ip4/127.0.0.1/tcp/7777"}}}, peers)
+	})
+
+	t.Run("add peer", func(t *testing.T) {
+		added, rpcErr := handler.AddPeer(context.Background(), "/ip4/127.0.0.1/tcp/7777/p2p/"+id.String())
+		require.Nil(t, rpcErr)
+		assert.True(t, added)
+		assert.Equal(t, []string{"/ip4/127.0.0.1/tcp/7777/p2p/" + id.String()}, peerManager.added)
+
+		_, rpcErr = handler.AddPeer(context.Background(), "unreachable")
+		assert.Equal(t, rpc.ErrAdminActionFailed.CloneWithData("dial failed"), rpcErr)
+	})
+
+	t.Run("ban peer", func(t *testing.T) {
+		_, rpcErr := handler.BanPeer("not a peer id")
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+
+		banned, rpcErr := handler.BanPeer(id.String())
+		require.Nil(t, rpcErr)
+		assert.True(t, banned)
+		assert.Equal(t, []peer.ID{id}, peerManager.banned)
+	})
+}
@@ -0,0 +1,275 @@
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	stdsync "sync"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/feed"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/sync"
+)
+
+// maxReorgHistory is the number of recent reorgs remembered to tell clients resuming from an orphaned block where
+// the reorg started.
+const maxReorgHistory = 64
+
+// eventsFiltersCacheSize is the number of events filters remembered to resume events subscriptions with.
+const eventsFiltersCacheSize = 4096
+
+const (
+	newHeadsCursor = "newHeads"
+	eventsCursor   = "events"
+)
+
+// subscriptionCursor is the position of a notification in a new heads or events subscription. It is sent with each
+// notification, encoded as an opaque string, so that clients can continue the subscription after it with
+// juno_resubscribe.
+type subscriptionCursor struct {
+	Subscription string     `json:"subscription"`
+	BlockHash    *felt.Felt `json:"block_hash"`
+	BlockNumber  uint64     `json:"block_number"`
+	// EventIndex is the index of the event among the events of its block
+	EventIndex *uint64 `json:"event_index,omitempty"`
+	// Filter identifies the filter of an events subscription, which is kept by the node
+	Filter *felt.Felt `json:"filter,omitempty"`
+}
+
+func (c *subscriptionCursor) encode() (string, error) {
+	encoded, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+func decodeSubscriptionCursor(cursor string) (*subscriptionCursor, error) {
+	encoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("malformed cursor")
+	}
+	var c subscriptionCursor
+	if err = json.Unmarshal(encoded, &c); err != nil {
+		return nil, errors.New("malformed cursor")
+	}
+
+	if c.BlockHash == nil {
+		return nil, errors.New("cursor is missing the block hash")
+	}
+	switch c.Subscription {
+	case newHeadsCursor:
+	case eventsCursor:
+		if c.EventIndex == nil {
+			return nil, errors.New("cursor is missing the event index")
+		}
+		if c.Filter == nil {
+			return nil, errors.New("cursor is missing the events filter")
+		}
+	default:
+		return nil, fmt.Errorf("unknown subscription %q in cursor", c.Subscription)
+	}
+	return &c, nil
+}
+
+// Resubscribe continues the new heads or events subscription a cursor was sent with, from the notification right
+// after it, however far back it is. If the block of the cursor was orphaned since, a reorg notification is sent first
+// and the subscription continues from the start of the reorg.
+func (h *Handler) Resubscribe(ctx context.Context, cursor string) (*SubscriptionID, *jsonrpc.Error) {
+	w, ok := jsonrpc.ConnFromContext(ctx)
+	if !ok {
+		return nil, jsonrpc.Err(jsonrpc.MethodNotFound, nil)
+	}
+
+	c, err := decodeSubscriptionCursor(cursor)
+	if err != nil {
+		return nil, jsonrpc.Err(jsonrpc.InvalidParams, err.Error())
+	}
+	var filter *eventsFilter
+	if c.Subscription == eventsCursor {
+		if filter, ok = h.eventsFilters.Get(*c.Filter); !ok {
+			return nil, jsonrpc.Err(jsonrpc.InvalidParams, "the events filter of the cursor is no longer known")
+		}
+	}
+
+	latestHeader, err := h.bcReader.HeadsHeader()
+	if err != nil {
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+	orphaned, rpcErr := h.orphanedRange(c)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	from, skip := c.BlockNumber+1, uint64(0)
+	if c.Subscription == eventsCursor {
+		// the events of the cursor's block that follow it are yet to be sent
+		from, skip = c.BlockNumber, *c.EventIndex+1
+	}
+	if orphaned != nil {
+		from, skip = orphaned.StartBlockNum, 0
+	}
+
+	if c.Subscription == eventsCursor {
+		return h.subscribeEvents(ctx, w, filter.fromAddr, filter.keys, from, latestHeader.Number, skip, orphaned), nil
+	}
+
+	var startHeader *core.Header
+	if from <= latestHeader.Number {
+		if startHeader, err = h.bcReader.BlockHeaderByNumber(from); err != nil {
+			return nil, ErrInternal.CloneWithData(err.Error())
+		}
+	}
+	return h.subscribeNewHeads(ctx, w, startHeader, latestHeader, orphaned), nil
+}
+
+// orphanedRange returns the range of blocks that were orphaned, ending with the block of the cursor, or nil if the
+// block is still part of the chain. If the reorg is too old to be remembered, where the chain of the cursor forked
+// is unknown, so the range starts with the block of the cursor, the oldest one known to differ from the chain.
+func (h *Handler) orphanedRange(c *subscriptionCursor) (*sync.ReorgBlockRange, *jsonrpc.Error) {
+	header, err := h.bcReader.BlockHeaderByNumber(c.BlockNumber)
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return nil, ErrInternal.CloneWithData(err.Error())
+	}
+	if err == nil && header.Hash.Equal(c.BlockHash) {
+		return nil, nil
+	}
+
+	h.reorgHistoryMu.Lock()
+	defer h.reorgHistoryMu.Unlock()
+	for i := len(h.reorgHistory) - 1; i >= 0; i-- {
+		reorg := h.reorgHistory[i]
+		if reorg.StartBlockNum <= c.BlockNumber && c.BlockNumber <= reorg.EndBlockNum {
+			return &sync.ReorgBlockRange{
+				StartBlockHash: reorg.StartBlockHash,
+				StartBlockNum:  reorg.StartBlockNum,
+				EndBlockHash:   c.BlockHash,
+				EndBlockNum:    c.BlockNumber,
+			}, nil
+		}
+	}
+	return &sync.ReorgBlockRange{
+		StartBlockHash: c.BlockHash,
+		StartBlockNum:  c.BlockNumber,
+		EndBlockHash:   c.BlockHash,
+		EndBlockNum:    c.BlockNumber,
+	}, nil
+}
+
+// recordReorgs remembers the most recent reorgs until ctx is cancelled.
+func (h *Handler) recordReorgs(ctx context.Context, reorgSub *feed.Subscription[*sync.ReorgBlockRange]) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case reorg, ok := <-reorgSub.Recv():
+			if !ok {
+				return
+			}
+			h.recordReorg(reorg)
+		}
+	}
+}
+
+func (h *Handler) recordReorg(reorg *sync.ReorgBlockRange) {
+	h.reorgHistoryMu.Lock()
+	defer h.reorgHistoryMu.Unlock()
+
+	if len(h.reorgHistory) == maxReorgHistory {
+		h.reorgHistory = h.reorgHistory[1:]
+	}
+	h.reorgHistory = append(h.reorgHistory, reorg)
+}
+
+// eventsFilter is the filter of an events subscription, which cursors refer to by its id.
+type eventsFilter struct {
+	fromAddr *felt.Felt
+	keys     [][]felt.Felt
+}
+
+// eventsFilterID identifies an events filter. It is a hash of the filter, so that the same filter always has the
+// same id.
+func eventsFilterID(fromAddr *felt.Felt, keys [][]felt.Felt) *felt.Felt {
+	elems := make([]*felt.Felt, 0, 2+countEventFilterKeys(keys)) //nolint:mnd
+	if fromAddr != nil {
+		elems = append(elems, new(felt.Felt).SetUint64(1), fromAddr)
+	} else {
+		elems = append(elems, &felt.Zero)
+	}
+	for i := range keys {
+		elems = append(elems, new(felt.Felt).SetUint64(uint64(len(keys[i]))))
+		for j := range keys[i] {
+			elems = append(elems, &keys[i][j])
+		}
+	}
+	return crypto.PoseidonArray(elems...)
+}
+
+// rememberEventsFilter keeps an events filter so that subscriptions with it can be resumed from their cursors.
+func (h *Handler) rememberEventsFilter(fromAddr *felt.Felt, keys [][]felt.Felt) {
+	h.eventsFilters.Add(*eventsFilterID(fromAddr, keys), &eventsFilter{fromAddr: fromAddr, keys: keys})
+}
+
+// holdingConn holds back the new notifications of a subscription while it catches up with past blocks, so that they
+// are queued after the ones about past blocks. Catching up leaves half of the queue for them, so the subscription is
+// ended if more are held back.
+type holdingConn struct {
+	queue *subscriptionQueue
+
+	mu      stdsync.Mutex // protects the fields below.
+	held    []queuedNotification
+	holding bool
+}
+
+func holdNotifications(queue *subscriptionQueue) *holdingConn {
+	return &holdingConn{queue: queue, holding: true}
+}
+
+func (c *holdingConn) Write(p []byte) (int, error) {
+	if err := c.push(p, false); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *holdingConn) Equal(other jsonrpc.Conn) bool {
+	return c.queue.Equal(other)
+}
+
+func (c *holdingConn) push(data []byte, head bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.holding {
+		return c.queue.push(data, head)
+	}
+	if len(c.held) >= c.queue.size/2 { //nolint:mnd
+		c.queue.listener.OnNotificationsDropped(c.queue.policy, len(c.held)+1)
+		c.held = nil
+		c.queue.close()
+		c.queue.cancel()
+		return errSlowConsumer
+	}
+	c.held = append(c.held, queuedNotification{data: data, head: head})
+	return nil
+}
+
+// release queues the notifications held back, and the new ones from then on.
+func (c *holdingConn) release() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.holding = false
+	for _, n := range c.held {
+		if err := c.queue.push(n.data, n.head); err != nil {
+			break
+		}
+	}
+	c.held = nil
+}
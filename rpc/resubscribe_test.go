@@ -0,0 +1,272 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/NethermindEth/juno/blockchain"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/mocks"
+	"github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type testNotification struct {
+	Method string `json:"method"`
+	Params struct {
+		Result json.RawMessage `json:"result"`
+		Cursor string          `json:"cursor"`
+	} `json:"params"`
+}
+
+// resubscribe resumes from cursor over a pipe, and returns a decoder of the notifications sent on it.
+func resubscribe(t *testing.T, handler *Handler, cursor string) *json.Decoder {
+	t.Helper()
+
+	serverConn, clientConn := net.Pipe()
+	t.Cleanup(func() {
+		require.NoError(t, serverConn.Close())
+		require.NoError(t, clientConn.Close())
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	subCtx := context.WithValue(ctx, jsonrpc.ConnKey{}, &fakeConn{w: serverConn})
+	_, rpcErr := handler.Resubscribe(subCtx, cursor)
+	require.Nil(t, rpcErr)
+	return json.NewDecoder(clientConn)
+}
+
+func readNotification(t *testing.T, decoder *json.Decoder) testNotification {
+	t.Helper()
+
+	var notification testNotification
+	require.NoError(t, decoder.Decode(&notification))
+	return notification
+}
+
+func TestResubscribe(t *testing.T) {
+	log := utils.NewNopZapLogger()
+
+	t.Run("Return error if cursor is invalid", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		handler := New(mocks.NewMockReader(mockCtrl), mocks.NewMockSyncReader(mockCtrl), nil, "", log)
+		subCtx := context.WithValue(context.Background(), jsonrpc.ConnKey{}, &fakeConn{})
+
+		for _, cursor := range []string{"not base64!", "bm90IGpzb24", testNewHeadsCursor(t, &core.Header{})} {
+			id, rpcErr := handler.Resubscribe(subCtx, cursor)
+			assert.Nil(t, id)
+			require.NotNil(t, rpcErr)
+			assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+		}
+	})
+
+	headers := []*core.Header{
+		{Number: 0, Hash: new(felt.Felt).SetUint64(10)},
+		{Number: 1, Hash: new(felt.Felt).SetUint64(11)},
+		{Number: 2, Hash: new(felt.Felt).SetUint64(12)},
+	}
+
+	t.Run("New heads after the cursor", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockChain := mocks.NewMockReader(mockCtrl)
+		handler := New(mockChain, mocks.NewMockSyncReader(mockCtrl), nil, "", log)
+
+		mockChain.EXPECT().HeadsHeader().Return(headers[2], nil)
+		mockChain.EXPECT().BlockHeaderByNumber(uint64(1)).Return(headers[1], nil)
+		mockChain.EXPECT().BlockHeaderByNumber(uint64(2)).Return(headers[2], nil)
+
+		decoder := resubscribe(t, handler, testNewHeadsCursor(t, headers[1]))
+
+		notification := readNotification(t, decoder)
+		assert.Equal(t, "starknet_subscriptionNewHeads", notification.Method)
+		assert.Equal(t, testNewHeadsCursor(t, headers[2]), notification.Params.Cursor)
+	})
+
+	t.Run("New heads after an orphaned block", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockChain := mocks.NewMockReader(mockCtrl)
+		handler := New(mockChain, mocks.NewMockSyncReader(mockCtrl), nil, "", log)
+		handler.reorgHistory = []*sync.ReorgBlockRange{{
+			StartBlockHash: new(felt.Felt).SetUint64(21),
+			StartBlockNum:  1,
+			EndBlockHash:   new(felt.Felt).SetUint64(23),
+			EndBlockNum:    3,
+		}}
+
+		orphaned := &core.Header{Number: 2, Hash: new(felt.Felt).SetUint64(22)}
+		mockChain.EXPECT().HeadsHeader().Return(headers[2], nil)
+		mockChain.EXPECT().BlockHeaderByNumber(uint64(2)).Return(headers[2], nil).Times(2)
+		mockChain.EXPECT().BlockHeaderByNumber(uint64(1)).Return(headers[1], nil)
+
+		decoder := resubscribe(t, handler, testNewHeadsCursor(t, orphaned))
+
+		notification := readNotification(t, decoder)
+		assert.Equal(t, "starknet_subscriptionReorg", notification.Method)
+		assert.JSONEq(t, `{"starting_block_hash":"0x15","starting_block_number":1,"ending_block_hash":"0x16","ending_block_number":2}`,
+			string(notification.Params.Result))
+
+		for _, header := range headers[1:] {
+			notification = readNotification(t, decoder)
+			assert.Equal(t, "starknet_subscriptionNewHeads", notification.Method)
+			assert.Equal(t, testNewHeadsCursor(t, header), notification.Params.Cursor)
+		}
+	})
+
+	t.Run("New heads after a block orphaned by an unknown reorg", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockChain := mocks.NewMockReader(mockCtrl)
+		handler := New(mockChain, mocks.NewMockSyncReader(mockCtrl), nil, "", log)
+
+		orphaned := &core.Header{Number: 2, Hash: new(felt.Felt).SetUint64(22)}
+		mockChain.EXPECT().HeadsHeader().Return(headers[2], nil)
+		mockChain.EXPECT().BlockHeaderByNumber(uint64(2)).Return(headers[2], nil).Times(2)
+
+		decoder := resubscribe(t, handler, testNewHeadsCursor(t, orphaned))
+
+		// the reorg is reported from the block of the cursor
+		notification := readNotification(t, decoder)
+		assert.Equal(t, "starknet_subscriptionReorg", notification.Method)
+		assert.JSONEq(t, `{"starting_block_hash":"0x16","starting_block_number":2,"ending_block_hash":"0x16","ending_block_number":2}`,
+			string(notification.Params.Result))
+
+		notification = readNotification(t, decoder)
+		assert.Equal(t, "starknet_subscriptionNewHeads", notification.Method)
+		assert.Equal(t, testNewHeadsCursor(t, headers[2]), notification.Params.Cursor)
+	})
+
+	t.Run("New heads after a cursor more than maxBlocksBack blocks back", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockChain := mocks.NewMockReader(mockCtrl)
+		handler := New(mockChain, mocks.NewMockSyncReader(mockCtrl), nil, "", log)
+
+		headerByNumber := func(number uint64) (*core.Header, error) {
+			return &core.Header{Number: number, Hash: new(felt.Felt).SetUint64(number + 10)}, nil
+		}
+		latest, _ := headerByNumber(maxBlocksBack + 1)
+		mockChain.EXPECT().HeadsHeader().Return(latest, nil)
+		mockChain.EXPECT().BlockHeaderByNumber(gomock.Any()).DoAndReturn(headerByNumber).AnyTimes()
+
+		decoder := resubscribe(t, handler, testNewHeadsCursor(t, headers[0]))
+
+		for _, header := range headers[1:] {
+			notification := readNotification(t, decoder)
+			assert.Equal(t, "starknet_subscriptionNewHeads", notification.Method)
+			assert.Equal(t, testNewHeadsCursor(t, header), notification.Params.Cursor)
+		}
+	})
+
+	t.Run("Return error if the events filter of the cursor is unknown", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		handler := New(mocks.NewMockReader(mockCtrl), mocks.NewMockSyncReader(mockCtrl), nil, "", log)
+		subCtx := context.WithValue(context.Background(), jsonrpc.ConnKey{}, &fakeConn{})
+
+		event := &blockchain.FilteredEvent{BlockNumber: 2, BlockHash: headers[2].Hash}
+		id, rpcErr := handler.Resubscribe(subCtx, testEventsCursor(t, event, nil, nil))
+		assert.Nil(t, id)
+		require.NotNil(t, rpcErr)
+		assert.Equal(t, jsonrpc.InvalidParams, rpcErr.Code)
+	})
+
+	t.Run("Events after the cursor", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockChain := mocks.NewMockReader(mockCtrl)
+		mockEventFilterer := mocks.NewMockEventFilterer(mockCtrl)
+		handler := New(mockChain, mocks.NewMockSyncReader(mockCtrl), nil, "", log)
+
+		fromAddr := new(felt.Felt).SetUint64(1)
+		keys := [][]felt.Felt{{*new(felt.Felt).SetUint64(2)}}
+		handler.rememberEventsFilter(fromAddr, keys)
+		events := []*blockchain.FilteredEvent{
+			{Event: &core.Event{From: fromAddr}, BlockNumber: 2, BlockHash: headers[2].Hash, TransactionHash: &felt.Zero},
+			{Event: &core.Event{From: fromAddr}, BlockNumber: 2, BlockHash: headers[2].Hash, TransactionHash: new(felt.Felt).SetUint64(1), EventIndex: 1},
+		}
+
+		mockChain.EXPECT().HeadsHeader().Return(headers[2], nil)
+		mockChain.EXPECT().BlockHeaderByNumber(uint64(2)).Return(headers[2], nil)
+		mockChain.EXPECT().EventFilter(fromAddr, keys).Return(mockEventFilterer, nil)
+		mockEventFilterer.EXPECT().SetRangeEndBlockByNumber(gomock.Any(), uint64(2)).Return(nil).Times(2)
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(events, nil, nil)
+		mockEventFilterer.EXPECT().Close().AnyTimes()
+
+		decoder := resubscribe(t, handler, testEventsCursor(t, events[0], fromAddr, keys))
+
+		notification := readNotification(t, decoder)
+		assert.Equal(t, "starknet_subscriptionEvents", notification.Method)
+		assert.Equal(t, testEventsCursor(t, events[1], fromAddr, keys), notification.Params.Cursor)
+	})
+}
+
+func TestRecordReorg(t *testing.T) {
+	handler := New(nil, nil, nil, "", utils.NewNopZapLogger())
+	for i := range maxReorgHistory + 1 {
+		handler.recordReorg(&sync.ReorgBlockRange{StartBlockNum: uint64(i)})
+	}
+
+	// the oldest reorg is forgotten
+	require.Len(t, handler.reorgHistory, maxReorgHistory)
+	assert.Equal(t, uint64(1), handler.reorgHistory[0].StartBlockNum)
+	assert.Equal(t, uint64(maxReorgHistory), handler.reorgHistory[maxReorgHistory-1].StartBlockNum)
+}
+
+func TestEventsFilterID(t *testing.T) {
+	addr := new(felt.Felt).SetUint64(1)
+	keys := [][]felt.Felt{{*new(felt.Felt).SetUint64(2)}, {*new(felt.Felt).SetUint64(3)}}
+
+	assert.Equal(t, eventsFilterID(addr, keys), eventsFilterID(addr, keys))
+	assert.NotEqual(t, eventsFilterID(addr, keys), eventsFilterID(nil, keys))
+	assert.NotEqual(t, eventsFilterID(&felt.Zero, nil), eventsFilterID(nil, nil))
+	// keys are matched by position, so how they are grouped matters
+	assert.NotEqual(t, eventsFilterID(addr, keys), eventsFilterID(addr, [][]felt.Felt{{keys[0][0], keys[1][0]}}))
+}
+
+func TestHoldingConn(t *testing.T) {
+	q, conn, stats, cancelled := newTestQueue(t, OverflowDisconnect)
+	held := holdNotifications(q)
+
+	// new notifications are held back until the catch-up is done
+	_, err := held.Write([]byte("new"))
+	require.NoError(t, err)
+	_, err = q.catchUp(context.Background()).Write([]byte("past"))
+	require.NoError(t, err)
+	held.release()
+	drain(q)
+
+	assert.Equal(t, []string{"past", "new"}, conn.writes)
+	assert.False(t, *cancelled)
+	assert.Empty(t, stats.dropped)
+
+	t.Run("held notifications overflow the other half of the queue", func(t *testing.T) {
+		q, _, stats, cancelled := newTestQueue(t, OverflowDisconnect)
+		held := holdNotifications(q)
+		defer held.release()
+
+		_, err := held.Write([]byte("new 1"))
+		require.NoError(t, err)
+		_, err = held.Write([]byte("new 2"))
+		require.ErrorIs(t, err, errSlowConsumer)
+		assert.True(t, *cancelled)
+		assert.Equal(t, 2, stats.dropped[OverflowDisconnect])
+	})
+}
@@ -0,0 +1,93 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+)
+
+// SSESubscriptions lets the subscriptions be opened over server-sent events. The notifications of subscriptions to
+// new heads and events are sent with the number of their block as event ID, so that they can be resumed from the
+// block after the one the client last heard of. Since a block can have many events, the events of that block are
+// sent again when resuming an events subscription. The other subscriptions can't start from a past block, so their
+// notifications have no event ID.
+type SSESubscriptions struct{}
+
+var _ jsonrpc.SSESubscriptions = SSESubscriptions{}
+
+func (SSESubscriptions) Subscribes(method string) bool {
+	switch method {
+	case "starknet_subscribeNewHeads", "starknet_subscribeEvents", "starknet_subscribeTransactionStatus",
+		"starknet_subscribePendingTransactions", "juno_subscribeL1Head", "juno_subscribeStorageChanges",
+		"juno_subscribeContractChanges", "juno_subscribeNewClasses", "juno_subscribeDeployments":
+		return true
+	default:
+		return false
+	}
+}
+
+func (SSESubscriptions) EventID(method string, notification []byte) (string, bool) {
+	if method != "starknet_subscribeNewHeads" && method != "starknet_subscribeEvents" {
+		return "", false
+	}
+
+	var decoded struct {
+		Params struct {
+			Result struct {
+				BlockNumber         *uint64 `json:"block_number"`
+				StartingBlockNumber *uint64 `json:"starting_block_number"`
+			} `json:"result"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(notification, &decoded); err != nil {
+		return "", false
+	}
+
+	result := decoded.Params.Result
+	switch {
+	case result.BlockNumber != nil:
+		return strconv.FormatUint(*result.BlockNumber, 10), true
+	case result.StartingBlockNumber != nil && *result.StartingBlockNumber > 0:
+		// the blocks from the start of a reorg are sent again once resumed
+		return strconv.FormatUint(*result.StartingBlockNumber-1, 10), true
+	default:
+		return "", false
+	}
+}
+
+func (SSESubscriptions) Resume(req *jsonrpc.Request, lastEventID string) error {
+	lastBlock, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid last event id %q", lastEventID)
+	}
+
+	switch req.Method {
+	case "starknet_subscribeNewHeads":
+		return setParam(req, 0, "block", map[string]any{"block_number": lastBlock + 1})
+	case "starknet_subscribeEvents":
+		return setParam(req, 2, "block", map[string]any{"block_number": lastBlock}) //nolint:mnd
+	default:
+		return nil
+	}
+}
+
+// setParam sets the param at position in params given by position, or name in params given by name.
+func setParam(req *jsonrpc.Request, position int, name string, value any) error {
+	switch params := req.Params.(type) {
+	case nil:
+		req.Params = map[string]any{name: value}
+	case map[string]any:
+		params[name] = value
+	case []any:
+		for len(params) <= position {
+			params = append(params, nil)
+		}
+		params[position] = value
+		req.Params = params
+	default:
+		return fmt.Errorf("params should be an array or an object, got %T", params)
+	}
+	return nil
+}
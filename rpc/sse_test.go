@@ -0,0 +1,104 @@
+package rpc_test
+
+import (
+	"testing"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSESubscriptionsEventID(t *testing.T) {
+	subscriptions := rpc.SSESubscriptions{}
+
+	for name, test := range map[string]struct {
+		method       string
+		notification string
+		id           string
+	}{
+		"new head": {
+			method: "starknet_subscribeNewHeads",
+			notification: `{"jsonrpc":"2.0","method":"starknet_subscriptionNewHeads","params":{"subscription_id":1,` +
+				`"result":{"block_hash":"0x1","block_number":42}}}`,
+			id: "42",
+		},
+		"event": {
+			method: "starknet_subscribeEvents",
+			notification: `{"jsonrpc":"2.0","method":"starknet_subscriptionEvents","params":{"subscription_id":1,` +
+				`"result":{"from_address":"0x1","keys":[],"data":[],"block_number":7}}}`,
+			id: "7",
+		},
+		"reorg": {
+			method: "starknet_subscribeNewHeads",
+			notification: `{"jsonrpc":"2.0","method":"starknet_subscriptionReorg","params":{"subscription_id":1,` +
+				`"result":{"starting_block_hash":"0x1","starting_block_number":10,"ending_block_hash":"0x2","ending_block_number":12}}}`,
+			id: "9",
+		},
+		"transaction status": {
+			method: "starknet_subscribeTransactionStatus",
+			notification: `{"jsonrpc":"2.0","method":"starknet_subscriptionTransactionsStatus","params":{"subscription_id":1,` +
+				`"result":{"transaction_hash":"0x1","status":{"finality_status":"ACCEPTED_ON_L2"}}}}`,
+		},
+		"l1 head": {
+			method: "juno_subscribeL1Head",
+			notification: `{"jsonrpc":"2.0","method":"juno_subscriptionL1Head","params":{"subscription_id":1,` +
+				`"result":{"block_number":42,"block_hash":"0x1","state_root":"0x2"}}}`,
+		},
+		"reorg of a transaction status": {
+			method: "starknet_subscribeTransactionStatus",
+			notification: `{"jsonrpc":"2.0","method":"starknet_subscriptionReorg","params":{"subscription_id":1,` +
+				`"result":{"starting_block_hash":"0x1","starting_block_number":10,"ending_block_hash":"0x2","ending_block_number":12}}}`,
+		},
+		"response": {
+			method:       "starknet_subscribeNewHeads",
+			notification: `{"jsonrpc":"2.0","result":{"subscription_id":1},"id":1}`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			id, ok := subscriptions.EventID(test.method, []byte(test.notification))
+			assert.Equal(t, test.id != "", ok)
+			assert.Equal(t, test.id, id)
+		})
+	}
+}
+
+func TestSSESubscriptionsResume(t *testing.T) {
+	subscriptions := rpc.SSESubscriptions{}
+
+	for name, test := range map[string]struct {
+		req  jsonrpc.Request
+		want any
+	}{
+		"new heads without params": {
+			req:  jsonrpc.Request{Method: "starknet_subscribeNewHeads"},
+			want: map[string]any{"block": map[string]any{"block_number": uint64(6)}},
+		},
+		"new heads with positional params": {
+			req:  jsonrpc.Request{Method: "starknet_subscribeNewHeads", Params: []any{"latest"}},
+			want: []any{map[string]any{"block_number": uint64(6)}},
+		},
+		"events with named params": {
+			req:  jsonrpc.Request{Method: "starknet_subscribeEvents", Params: map[string]any{"from_address": "0x1"}},
+			want: map[string]any{"from_address": "0x1", "block": map[string]any{"block_number": uint64(5)}},
+		},
+		"events with positional params": {
+			req:  jsonrpc.Request{Method: "starknet_subscribeEvents", Params: []any{"0x1"}},
+			want: []any{"0x1", nil, map[string]any{"block_number": uint64(5)}},
+		},
+		"pending transactions": {
+			req:  jsonrpc.Request{Method: "starknet_subscribePendingTransactions", Params: []any{true}},
+			want: []any{true},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, subscriptions.Resume(&test.req, "5"))
+			assert.Equal(t, test.want, test.req.Params)
+		})
+	}
+
+	t.Run("invalid last event id", func(t *testing.T) {
+		req := jsonrpc.Request{Method: "starknet_subscribeNewHeads"}
+		require.EqualError(t, subscriptions.Resume(&req, "0x5"), `invalid last event id "0x5"`)
+	})
+}
@@ -0,0 +1,338 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/sourcegraph/conc"
+)
+
+type StorageChange struct {
+	Key      *felt.Felt `json:"key"`
+	OldValue *felt.Felt `json:"old_value"`
+	NewValue *felt.Felt `json:"new_value"`
+}
+
+type ValueChange struct {
+	OldValue *felt.Felt `json:"old_value"`
+	NewValue *felt.Felt `json:"new_value"`
+}
+
+// StorageChanges are the changes made by a block to the storage of a contract. The block hash is omitted for the
+// pending block.
+type StorageChanges struct {
+	BlockHash       *felt.Felt      `json:"block_hash,omitempty"`
+	BlockNumber     uint64          `json:"block_number"`
+	ContractAddress *felt.Felt      `json:"contract_address"`
+	Changes         []StorageChange `json:"changes"`
+}
+
+// ContractChanges are the changes made by a block to the state of a contract. The old class hash is null when the
+// contract is deployed. The block hash is omitted for the pending block.
+type ContractChanges struct {
+	BlockHash       *felt.Felt      `json:"block_hash,omitempty"`
+	BlockNumber     uint64          `json:"block_number"`
+	ContractAddress *felt.Felt      `json:"contract_address"`
+	Storage         []StorageChange `json:"storage,omitempty"`
+	Nonce           *ValueChange    `json:"nonce,omitempty"`
+	ClassHash       *ValueChange    `json:"class_hash,omitempty"`
+}
+
+func (c *ContractChanges) empty() bool {
+	return len(c.Storage) == 0 && c.Nonce == nil && c.ClassHash == nil
+}
+
+// SubscriptionError tells a client that the notification about a block was left out, because it couldn't be built.
+type SubscriptionError struct {
+	BlockNumber uint64         `json:"block_number"`
+	Error       *jsonrpc.Error `json:"error"`
+}
+
+// errStalePending is returned when the pending block doesn't follow the head yet.
+var errStalePending = errors.New("pending block doesn't follow the head")
+
+// contractWatch describes the changes a subscription is interested in.
+type contractWatch struct {
+	address *felt.Felt
+	// keys restricts the storage changes to these keys, unless it is empty
+	keys []felt.Felt
+	// storageOnly leaves out nonce changes and class replacements
+	storageOnly bool
+}
+
+// SubscribeStorageChanges notifies the changes to the storage of a contract, for the given keys or all of them, with
+// the values they replace. Changes in the pending block are also notified if pending is set, each time they differ.
+func (h *Handler) SubscribeStorageChanges(ctx context.Context, contractAddress felt.Felt, keys []felt.Felt,
+	pending *bool,
+) (*SubscriptionID, *jsonrpc.Error) {
+	if len(keys) > maxEventFilterKeys {
+		return nil, ErrTooManyStorageKeysInFilter
+	}
+	return h.subscribeContractState(ctx, &contractWatch{
+		address:     &contractAddress,
+		keys:        keys,
+		storageOnly: true,
+	}, pending != nil && *pending)
+}
+
+// SubscribeContractChanges notifies the changes to the storage, nonce and class of a contract, with the values they
+// replace. Changes in the pending block are also notified if pending is set, each time they differ.
+func (h *Handler) SubscribeContractChanges(ctx context.Context, address felt.Felt, pending *bool) (*SubscriptionID,
+	*jsonrpc.Error,
+) {
+	return h.subscribeContractState(ctx, &contractWatch{address: &address}, pending != nil && *pending)
+}
+
+func (h *Handler) subscribeContractState(ctx context.Context, watch *contractWatch, pending bool) (*SubscriptionID,
+	*jsonrpc.Error,
+) {
+	w, ok := jsonrpc.ConnFromContext(ctx)
+	if !ok {
+		return nil, jsonrpc.Err(jsonrpc.MethodNotFound, nil)
+	}
+
+	id, subscriptionCtx, sub := h.newSubscription(ctx, w)
+	w = sub.queue
+
+	headerSub := h.newHeads.Subscribe()
+	reorgSub := h.reorgs.Subscribe()
+	pendingSub := h.pendingTxs.Subscribe()
+	sub.wg.Go(func() {
+		defer func() {
+			h.unsubscribe(sub, id)
+			headerSub.Unsubscribe()
+			reorgSub.Unsubscribe()
+			pendingSub.Unsubscribe()
+		}()
+
+		var wg conc.WaitGroup
+		wg.Go(func() {
+			h.processReorgs(subscriptionCtx, reorgSub, w, id)
+		})
+
+		wg.Go(func() {
+			// lastPending is the last notification about the pending block, which is only sent again if it changes
+			var lastPending []byte
+			for {
+				select {
+				case <-subscriptionCtx.Done():
+					return
+				case header := <-headerSub.Recv():
+					lastPending = nil
+					changes, err := h.blockContractChanges(header, watch)
+					if err != nil {
+						h.log.Warnw("Error collecting contract changes", "block", header.Number, "err", err)
+						err = sendSubscriptionError(w, header.Number, ErrInternal.CloneWithData(err.Error()), id)
+					} else {
+						_, err = sendContractChanges(w, changes, watch, id, nil)
+					}
+					if err != nil {
+						h.log.Warnw("Error sending contract changes", "err", err)
+						return
+					}
+				case <-pendingSub.Recv():
+					if !pending {
+						continue
+					}
+					changes, err := h.pendingContractChanges(watch)
+					if err != nil {
+						h.log.Debugw("Error collecting pending contract changes", "err", err)
+						continue
+					}
+					sent, err := sendContractChanges(w, changes, watch, id, lastPending)
+					if err != nil {
+						h.log.Warnw("Error sending contract changes", "err", err)
+						return
+					}
+					if sent != nil {
+						lastPending = sent
+					}
+				}
+			}
+		})
+
+		wg.Wait()
+	})
+
+	return &SubscriptionID{ID: id}, nil
+}
+
+// blockContractChanges collects the changes made by the block of header, compared to its parent.
+func (h *Handler) blockContractChanges(header *core.Header, watch *contractWatch) (*ContractChanges, error) {
+	update, err := h.bcReader.StateUpdateByNumber(header.Number)
+	if err != nil {
+		return nil, err
+	}
+
+	var parentState core.StateReader
+	if header.Number > 0 {
+		state, closer, err := h.bcReader.StateAtBlockNumber(header.Number - 1)
+		if err != nil {
+			return nil, err
+		}
+		defer h.callAndLogErr(closer, "Error closing state reader in contract changes subscription")
+		parentState = state
+	}
+
+	changes := &ContractChanges{
+		BlockHash:       header.Hash,
+		BlockNumber:     header.Number,
+		ContractAddress: watch.address,
+	}
+	return changes, diffContract(changes, update.StateDiff, parentState, watch)
+}
+
+// pendingContractChanges collects the changes made by the pending block, compared to the head it follows.
+func (h *Handler) pendingContractChanges(watch *contractWatch) (*ContractChanges, error) {
+	pending, err := h.syncReader.Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := h.bcReader.HeadsHeader()
+	if err != nil {
+		return nil, err
+	}
+	if !head.Hash.Equal(pending.Block.ParentHash) {
+		return nil, errStalePending
+	}
+
+	headState, closer, err := h.bcReader.StateAtBlockHash(head.Hash)
+	if err != nil {
+		return nil, err
+	}
+	defer h.callAndLogErr(closer, "Error closing state reader in contract changes subscription")
+
+	changes := &ContractChanges{
+		BlockNumber:     pending.Block.Number,
+		ContractAddress: watch.address,
+	}
+	return changes, diffContract(changes, pending.StateUpdate.StateDiff, headState, watch)
+}
+
+// diffContract adds the changes made by diff to the watched contract to changes, with the values they replace in
+// state. A nil state has no contracts.
+func diffContract(changes *ContractChanges, diff *core.StateDiff, state core.StateReader, watch *contractWatch) error {
+	storage := diff.StorageDiffs[*watch.address]
+	keys := make([]felt.Felt, 0, len(storage))
+	for key := range storage {
+		if len(watch.keys) == 0 || slices.Contains(watch.keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b felt.Felt) int { return a.Cmp(&b) })
+
+	for _, key := range keys {
+		oldValue, err := oldStateValue(state, func(state core.StateReader) (*felt.Felt, error) {
+			return state.ContractStorage(watch.address, &key)
+		})
+		if err != nil {
+			return err
+		}
+		changes.Storage = append(changes.Storage, StorageChange{Key: &key, OldValue: oldValue, NewValue: storage[key]})
+	}
+	if watch.storageOnly {
+		return nil
+	}
+
+	if nonce, ok := diff.Nonces[*watch.address]; ok {
+		oldNonce, err := oldStateValue(state, func(state core.StateReader) (*felt.Felt, error) {
+			return state.ContractNonce(watch.address)
+		})
+		if err != nil {
+			return err
+		}
+		changes.Nonce = &ValueChange{OldValue: oldNonce, NewValue: nonce}
+	}
+
+	classHash, ok := diff.ReplacedClasses[*watch.address]
+	if deployedClassHash, deployed := diff.DeployedContracts[*watch.address]; deployed {
+		classHash, ok = deployedClassHash, true
+	}
+	if ok {
+		changes.ClassHash = &ValueChange{NewValue: classHash}
+		if state != nil {
+			oldClassHash, err := state.ContractClassHash(watch.address)
+			if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+				return err
+			}
+			changes.ClassHash.OldValue = oldClassHash
+		}
+	}
+	return nil
+}
+
+// oldStateValue reads a value from state, which is zero if the contract isn't deployed or state is nil.
+func oldStateValue(state core.StateReader, read func(core.StateReader) (*felt.Felt, error)) (*felt.Felt, error) {
+	if state == nil {
+		return &felt.Zero, nil
+	}
+	value, err := read(state)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return &felt.Zero, nil
+	}
+	return value, err
+}
+
+// sendSubscriptionError tells the client behind w that the notification about the block numbered blockNumber was
+// left out because of rpcErr.
+func sendSubscriptionError(w jsonrpc.Conn, blockNumber uint64, rpcErr *jsonrpc.Error, id uint64) error {
+	resp, err := json.Marshal(SubscriptionResponse{
+		Version: "2.0",
+		Method:  "juno_subscriptionError",
+		Params: map[string]any{
+			"subscription_id": id,
+			"result":          &SubscriptionError{BlockNumber: blockNumber, Error: rpcErr},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(resp)
+	return err
+}
+
+// sendContractChanges sends the changes, unless there are none or they were the last ones sent, and returns the
+// notification it sent.
+func sendContractChanges(w jsonrpc.Conn, changes *ContractChanges, watch *contractWatch, id uint64,
+	lastSent []byte,
+) ([]byte, error) {
+	method, result := "juno_subscriptionContractChanges", any(changes)
+	if watch.storageOnly {
+		method, result = "juno_subscriptionStorageChanges", &StorageChanges{
+			BlockHash:       changes.BlockHash,
+			BlockNumber:     changes.BlockNumber,
+			ContractAddress: changes.ContractAddress,
+			Changes:         changes.Storage,
+		}
+	}
+	if changes.empty() {
+		return nil, nil
+	}
+
+	resp, err := json.Marshal(SubscriptionResponse{
+		Version: "2.0",
+		Method:  method,
+		Params: map[string]any{
+			"subscription_id": id,
+			"result":          result,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(resp, lastSent) {
+		return nil, nil
+	}
+	if _, err = w.Write(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
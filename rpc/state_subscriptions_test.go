@@ -0,0 +1,241 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/NethermindEth/juno/mocks"
+	"github.com/NethermindEth/juno/sync"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDiffContract(t *testing.T) {
+	address := new(felt.Felt).SetUint64(1)
+	key1, key2 := new(felt.Felt).SetUint64(2), new(felt.Felt).SetUint64(3)
+	diff := &core.StateDiff{
+		StorageDiffs: map[felt.Felt]map[felt.Felt]*felt.Felt{
+			*address: {*key1: new(felt.Felt).SetUint64(6), *key2: new(felt.Felt).SetUint64(8)},
+		},
+		Nonces:          map[felt.Felt]*felt.Felt{*address: new(felt.Felt).SetUint64(2)},
+		ReplacedClasses: map[felt.Felt]*felt.Felt{*address: new(felt.Felt).SetUint64(9)},
+	}
+
+	t.Run("Storage changes of the watched keys", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockState := mocks.NewMockStateHistoryReader(mockCtrl)
+		mockState.EXPECT().ContractStorage(address, key1).Return(new(felt.Felt).SetUint64(5), nil)
+
+		changes := &ContractChanges{}
+		watch := &contractWatch{address: address, keys: []felt.Felt{*key1}, storageOnly: true}
+		require.NoError(t, diffContract(changes, diff, mockState, watch))
+		assert.Equal(t, []StorageChange{
+			{Key: key1, OldValue: new(felt.Felt).SetUint64(5), NewValue: new(felt.Felt).SetUint64(6)},
+		}, changes.Storage)
+		assert.Nil(t, changes.Nonce)
+		assert.Nil(t, changes.ClassHash)
+	})
+
+	t.Run("Contract changes", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockState := mocks.NewMockStateHistoryReader(mockCtrl)
+		mockState.EXPECT().ContractStorage(address, key1).Return(new(felt.Felt).SetUint64(5), nil)
+		mockState.EXPECT().ContractStorage(address, key2).Return(nil, db.ErrKeyNotFound)
+		mockState.EXPECT().ContractNonce(address).Return(new(felt.Felt).SetUint64(1), nil)
+		mockState.EXPECT().ContractClassHash(address).Return(new(felt.Felt).SetUint64(7), nil)
+
+		changes := &ContractChanges{}
+		require.NoError(t, diffContract(changes, diff, mockState, &contractWatch{address: address}))
+		assert.Equal(t, &ContractChanges{
+			Storage: []StorageChange{
+				{Key: key1, OldValue: new(felt.Felt).SetUint64(5), NewValue: new(felt.Felt).SetUint64(6)},
+				{Key: key2, OldValue: &felt.Zero, NewValue: new(felt.Felt).SetUint64(8)},
+			},
+			Nonce:     &ValueChange{OldValue: new(felt.Felt).SetUint64(1), NewValue: new(felt.Felt).SetUint64(2)},
+			ClassHash: &ValueChange{OldValue: new(felt.Felt).SetUint64(7), NewValue: new(felt.Felt).SetUint64(9)},
+		}, changes)
+	})
+
+	t.Run("Deployment in the genesis block", func(t *testing.T) {
+		deployment := &core.StateDiff{
+			DeployedContracts: map[felt.Felt]*felt.Felt{*address: new(felt.Felt).SetUint64(9)},
+		}
+
+		changes := &ContractChanges{}
+		require.NoError(t, diffContract(changes, deployment, nil, &contractWatch{address: address}))
+		assert.Equal(t, &ValueChange{NewValue: new(felt.Felt).SetUint64(9)}, changes.ClassHash)
+		assert.Empty(t, changes.Storage)
+		assert.Nil(t, changes.Nonce)
+	})
+}
+
+// subscribeOverPipe returns a context whose connection is one end of a pipe, and a decoder of what is written to it.
+func subscribeOverPipe(t *testing.T) (context.Context, *json.Decoder) {
+	t.Helper()
+
+	serverConn, clientConn := net.Pipe()
+	t.Cleanup(func() {
+		require.NoError(t, serverConn.Close())
+		require.NoError(t, clientConn.Close())
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return context.WithValue(ctx, jsonrpc.ConnKey{}, &fakeConn{w: serverConn}), json.NewDecoder(clientConn)
+}
+
+func TestSubscribeContractState(t *testing.T) {
+	log := utils.NewNopZapLogger()
+
+	address := new(felt.Felt).SetUint64(1)
+	key := new(felt.Felt).SetUint64(2)
+	header := &core.Header{Number: 1, Hash: new(felt.Felt).SetUint64(11)}
+	update := &core.StateUpdate{StateDiff: &core.StateDiff{
+		StorageDiffs: map[felt.Felt]map[felt.Felt]*felt.Felt{
+			*address: {*key: new(felt.Felt).SetUint64(6), *new(felt.Felt).SetUint64(3): new(felt.Felt).SetUint64(8)},
+		},
+		Nonces: map[felt.Felt]*felt.Felt{*address: new(felt.Felt).SetUint64(2)},
+	}}
+
+	t.Run("Storage changes", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockChain := mocks.NewMockReader(mockCtrl)
+		mockState := mocks.NewMockStateHistoryReader(mockCtrl)
+		handler := New(mockChain, mocks.NewMockSyncReader(mockCtrl), nil, "", log)
+
+		mockChain.EXPECT().StateUpdateByNumber(uint64(1)).Return(update, nil)
+		mockChain.EXPECT().StateAtBlockNumber(uint64(0)).Return(mockState, func() error { return nil }, nil)
+		mockState.EXPECT().ContractStorage(address, key).Return(new(felt.Felt).SetUint64(5), nil)
+
+		subCtx, decoder := subscribeOverPipe(t)
+		_, rpcErr := handler.SubscribeStorageChanges(subCtx, *address, []felt.Felt{*key}, nil)
+		require.Nil(t, rpcErr)
+		handler.newHeads.Send(header)
+
+		notification := readNotification(t, decoder)
+		assert.Equal(t, "juno_subscriptionStorageChanges", notification.Method)
+		assert.JSONEq(t, `{"block_hash":"0xb","block_number":1,"contract_address":"0x1","changes":[{"key":"0x2","old_value":"0x5","new_value":"0x6"}]}`,
+			string(notification.Params.Result))
+	})
+
+	t.Run("Contract changes in pending and new blocks", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockChain := mocks.NewMockReader(mockCtrl)
+		mockSyncer := mocks.NewMockSyncReader(mockCtrl)
+		mockState := mocks.NewMockStateHistoryReader(mockCtrl)
+		handler := New(mockChain, mockSyncer, nil, "", log)
+
+		head := &core.Header{Number: 1, Hash: new(felt.Felt).SetUint64(21)}
+		pending := &sync.Pending{
+			Block: &core.Block{Header: &core.Header{Number: 2, ParentHash: head.Hash}},
+			StateUpdate: &core.StateUpdate{StateDiff: &core.StateDiff{
+				ReplacedClasses: map[felt.Felt]*felt.Felt{*address: new(felt.Felt).SetUint64(9)},
+			}},
+		}
+		mockSyncer.EXPECT().Pending().Return(pending, nil).AnyTimes()
+		mockChain.EXPECT().HeadsHeader().Return(head, nil).AnyTimes()
+		mockChain.EXPECT().StateAtBlockHash(head.Hash).Return(mockState, func() error { return nil }, nil).AnyTimes()
+		mockState.EXPECT().ContractClassHash(address).Return(new(felt.Felt).SetUint64(7), nil).AnyTimes()
+
+		subCtx, decoder := subscribeOverPipe(t)
+		_, rpcErr := handler.SubscribeContractChanges(subCtx, *address, utils.Ptr(true))
+		require.Nil(t, rpcErr)
+		handler.pendingTxs.Send(nil)
+
+		notification := readNotification(t, decoder)
+		assert.Equal(t, "juno_subscriptionContractChanges", notification.Method)
+		assert.JSONEq(t, `{"block_number":2,"contract_address":"0x1","class_hash":{"old_value":"0x7","new_value":"0x9"}}`,
+			string(notification.Params.Result))
+
+		// the pending block didn't change, so the next notification is about the new block
+		mockChain.EXPECT().StateUpdateByNumber(uint64(1)).Return(update, nil)
+		mockChain.EXPECT().StateAtBlockNumber(uint64(0)).Return(mockState, func() error { return nil }, nil)
+		mockState.EXPECT().ContractStorage(address, gomock.Any()).Return(nil, db.ErrKeyNotFound).Times(2)
+		mockState.EXPECT().ContractNonce(address).Return(&felt.Zero, nil)
+		handler.pendingTxs.Send(nil)
+		handler.newHeads.Send(header)
+
+		notification = readNotification(t, decoder)
+		assert.Equal(t, "juno_subscriptionContractChanges", notification.Method)
+		assert.JSONEq(t, `{"block_hash":"0xb","block_number":1,"contract_address":"0x1","storage":[`+
+			`{"key":"0x2","old_value":"0x0","new_value":"0x6"},{"key":"0x3","old_value":"0x0","new_value":"0x8"}],`+
+			`"nonce":{"old_value":"0x0","new_value":"0x2"}}`, string(notification.Params.Result))
+	})
+	t.Run("Too many storage keys", func(t *testing.T) {
+		handler := New(nil, nil, nil, "", log)
+		subCtx, _ := subscribeOverPipe(t)
+		_, rpcErr := handler.SubscribeStorageChanges(subCtx, *address, make([]felt.Felt, maxEventFilterKeys+1), nil)
+		assert.Equal(t, ErrTooManyStorageKeysInFilter, rpcErr)
+	})
+
+	t.Run("Pending block that doesn't follow the head", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockChain := mocks.NewMockReader(mockCtrl)
+		mockSyncer := mocks.NewMockSyncReader(mockCtrl)
+		mockState := mocks.NewMockStateHistoryReader(mockCtrl)
+		handler := New(mockChain, mockSyncer, nil, "", log)
+
+		pending := &sync.Pending{
+			Block: &core.Block{Header: &core.Header{Number: 1, ParentHash: new(felt.Felt).SetUint64(20)}},
+			StateUpdate: &core.StateUpdate{StateDiff: &core.StateDiff{
+				ReplacedClasses: map[felt.Felt]*felt.Felt{*address: new(felt.Felt).SetUint64(9)},
+			}},
+		}
+		mockSyncer.EXPECT().Pending().Return(pending, nil)
+		mockChain.EXPECT().HeadsHeader().Return(header, nil)
+
+		subCtx, decoder := subscribeOverPipe(t)
+		_, rpcErr := handler.SubscribeContractChanges(subCtx, *address, utils.Ptr(true))
+		require.Nil(t, rpcErr)
+		handler.pendingTxs.Send(nil)
+
+		// the changes of the stale pending block aren't sent, so the next notification is about the new block
+		mockChain.EXPECT().StateUpdateByNumber(uint64(1)).Return(update, nil)
+		mockChain.EXPECT().StateAtBlockNumber(uint64(0)).Return(mockState, func() error { return nil }, nil)
+		mockState.EXPECT().ContractStorage(address, gomock.Any()).Return(nil, db.ErrKeyNotFound).Times(2)
+		mockState.EXPECT().ContractNonce(address).Return(&felt.Zero, nil)
+		handler.newHeads.Send(header)
+
+		notification := readNotification(t, decoder)
+		assert.Equal(t, "juno_subscriptionContractChanges", notification.Method)
+		assert.Contains(t, string(notification.Params.Result), `"block_hash":"0xb"`)
+	})
+
+	t.Run("Error collecting the changes of a block", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		t.Cleanup(mockCtrl.Finish)
+
+		mockChain := mocks.NewMockReader(mockCtrl)
+		handler := New(mockChain, mocks.NewMockSyncReader(mockCtrl), nil, "", log)
+
+		mockChain.EXPECT().StateUpdateByNumber(uint64(1)).Return(nil, db.ErrKeyNotFound)
+
+		subCtx, decoder := subscribeOverPipe(t)
+		_, rpcErr := handler.SubscribeStorageChanges(subCtx, *address, nil, nil)
+		require.Nil(t, rpcErr)
+		handler.newHeads.Send(header)
+
+		notification := readNotification(t, decoder)
+		assert.Equal(t, "juno_subscriptionError", notification.Method)
+		assert.JSONEq(t, `{"block_number":1,"error":{"code":-32603,"message":"Internal error","data":"key not found"}}`,
+			string(notification.Params.Result))
+	})
+}
@@ -0,0 +1,293 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	stdsync "sync"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+)
+
+const defaultSubscriptionQueueSize = 1024
+
+// OverflowPolicy decides what happens to the notifications of a subscription whose client doesn't read them as fast
+// as they are sent, once its queue is full.
+type OverflowPolicy string
+
+const (
+	// OverflowDisconnect ends the subscription.
+	OverflowDisconnect OverflowPolicy = "disconnect"
+	// OverflowDropOldest drops the oldest queued notification. The client is told how many notifications it missed
+	// with a juno_subscriptionGap notification before the next one it receives.
+	OverflowDropOldest OverflowPolicy = "drop-oldest"
+	// OverflowCoalesceHeads drops the oldest queued new head when a newer one is sent, since it supersedes it. The
+	// subscription is ended if there is no head to drop.
+	OverflowCoalesceHeads OverflowPolicy = "coalesce-heads"
+)
+
+func ParseOverflowPolicy(policy string) (OverflowPolicy, error) {
+	switch p := OverflowPolicy(policy); p {
+	case OverflowDisconnect, OverflowDropOldest, OverflowCoalesceHeads:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown subscription overflow policy %q, expected one of %q, %q or %q", policy,
+			OverflowDisconnect, OverflowDropOldest, OverflowCoalesceHeads)
+	}
+}
+
+var (
+	errSubscriptionClosed = errors.New("subscription closed")
+	errSlowConsumer       = errors.New("subscription queue is full")
+)
+
+type NotificationGap struct {
+	Dropped uint64 `json:"dropped"`
+}
+
+type queuedNotification struct {
+	data []byte
+	head bool
+}
+
+// subscriptionQueue holds the notifications of a subscription until they are written to its connection, so that a
+// slow client doesn't hold up the subscription. Notifications that don't fit are handled according to the overflow
+// policy, except when catching up with past blocks, which waits until the queue is at most half full instead.
+type subscriptionQueue struct {
+	conn     jsonrpc.Conn
+	id       uint64
+	size     int
+	policy   OverflowPolicy
+	cancel   func()
+	listener EventListener
+
+	mu    stdsync.Mutex // protects the fields below.
+	queue []queuedNotification
+	// dropped is the number of notifications dropped since the last one was written
+	dropped uint64
+	// closed is set once no more notifications can be queued
+	closed bool
+	// pushed is signalled when a notification is queued or the queue is closed
+	pushed chan struct{}
+	// popped is closed, and replaced, when room is made in the queue
+	popped chan struct{}
+}
+
+func newSubscriptionQueue(conn jsonrpc.Conn, id uint64, size int, policy OverflowPolicy, cancel func(),
+	listener EventListener,
+) *subscriptionQueue {
+	return &subscriptionQueue{
+		conn:     conn,
+		id:       id,
+		size:     size,
+		policy:   policy,
+		cancel:   cancel,
+		listener: listener,
+		pushed:   make(chan struct{}, 1),
+		popped:   make(chan struct{}),
+	}
+}
+
+// Write queues p, which must not be modified afterwards.
+func (q *subscriptionQueue) Write(p []byte) (int, error) {
+	if err := q.push(p, false); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (q *subscriptionQueue) Equal(other jsonrpc.Conn) bool {
+	return q.conn.Equal(other)
+}
+
+// push queues a notification, and applies the overflow policy if the queue is full.
+func (q *subscriptionQueue) push(data []byte, head bool) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return errSubscriptionClosed
+	}
+	if len(q.queue) >= q.size && !q.makeRoom(head) {
+		q.listener.OnNotificationsDropped(q.policy, len(q.queue)+1)
+		q.closeLocked()
+		q.cancel()
+		return errSlowConsumer
+	}
+	q.enqueueLocked(queuedNotification{data: data, head: head})
+	return nil
+}
+
+// makeRoom drops a queued notification as the overflow policy allows, and reports whether it did.
+func (q *subscriptionQueue) makeRoom(head bool) bool {
+	drop := -1
+	switch q.policy {
+	case OverflowDropOldest:
+		drop = 0
+		q.dropped++
+	case OverflowCoalesceHeads:
+		if head {
+			drop = slices.IndexFunc(q.queue, func(n queuedNotification) bool { return n.head })
+		}
+	}
+	if drop < 0 {
+		return false
+	}
+
+	q.queue = slices.Delete(q.queue, drop, drop+1)
+	q.listener.OnQueuedNotificationsChange(-1)
+	q.listener.OnNotificationsDropped(q.policy, 1)
+	return true
+}
+
+func (q *subscriptionQueue) enqueueLocked(n queuedNotification) {
+	q.queue = append(q.queue, n)
+	q.listener.OnQueuedNotificationsChange(1)
+	select {
+	case q.pushed <- struct{}{}:
+	default:
+	}
+}
+
+// catchUp returns a connection for notifications about past blocks, which waits for room in the queue rather than
+// overflowing it, so that half of the queue is left for new notifications.
+func (q *subscriptionQueue) catchUp(ctx context.Context) jsonrpc.Conn {
+	return &catchUpConn{ctx: ctx, queue: q}
+}
+
+type catchUpConn struct {
+	ctx   context.Context
+	queue *subscriptionQueue
+}
+
+func (c *catchUpConn) Write(p []byte) (int, error) {
+	q := c.queue
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return 0, errSubscriptionClosed
+		}
+		if len(q.queue) < (q.size+1)/2 {
+			q.enqueueLocked(queuedNotification{data: p})
+			q.mu.Unlock()
+			return len(p), nil
+		}
+		popped := q.popped
+		q.mu.Unlock()
+
+		select {
+		case <-c.ctx.Done():
+			return 0, c.ctx.Err()
+		case <-popped:
+		}
+	}
+}
+
+func (c *catchUpConn) Equal(other jsonrpc.Conn) bool {
+	return c.queue.Equal(other)
+}
+
+// close stops queueing notifications. The ones already queued are still written.
+func (q *subscriptionQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closeLocked()
+}
+
+func (q *subscriptionQueue) closeLocked() {
+	if q.closed {
+		return
+	}
+	q.closed = true
+	select {
+	case q.pushed <- struct{}{}:
+	default:
+	}
+	close(q.popped)
+}
+
+// discard closes the queue and drops the notifications in it.
+func (q *subscriptionQueue) discard() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closeLocked()
+	q.listener.OnQueuedNotificationsChange(-len(q.queue))
+	q.queue = nil
+}
+
+// run writes the queued notifications to the connection until the queue is closed and empty, or ctx is cancelled.
+// The subscription is ended if a notification can't be written.
+func (q *subscriptionQueue) run(ctx context.Context) {
+	defer q.discard()
+	for {
+		q.mu.Lock()
+		if len(q.queue) == 0 {
+			closed := q.closed
+			q.mu.Unlock()
+			if closed {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-q.pushed:
+			}
+			continue
+		}
+
+		notification, dropped := q.queue[0], q.dropped
+		q.queue = q.queue[1:]
+		q.dropped = 0
+		if !q.closed {
+			close(q.popped)
+			q.popped = make(chan struct{})
+		}
+		q.mu.Unlock()
+		q.listener.OnQueuedNotificationsChange(-1)
+
+		if err := q.write(notification.data, dropped); err != nil {
+			q.cancel()
+			return
+		}
+	}
+}
+
+// write writes a notification to the connection, after a gap notification if some were dropped before it.
+func (q *subscriptionQueue) write(data []byte, dropped uint64) error {
+	if dropped > 0 {
+		gap, err := json.Marshal(SubscriptionResponse{
+			Version: "2.0",
+			Method:  "juno_subscriptionGap",
+			Params: map[string]any{
+				"subscription_id": q.id,
+				"result":          &NotificationGap{Dropped: dropped},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err = q.conn.Write(gap); err != nil {
+			return err
+		}
+	}
+	_, err := q.conn.Write(data)
+	return err
+}
+
+// headPusher queues notifications, telling new heads apart so that they can be coalesced.
+type headPusher interface {
+	push(data []byte, head bool) error
+}
+
+// writeHead writes a new heads notification to w, allowing it to be coalesced with newer ones if w queues
+// notifications.
+func writeHead(w jsonrpc.Conn, data []byte) error {
+	if q, ok := w.(headPusher); ok {
+		return q.push(data, true)
+	}
+	_, err := w.Write(data)
+	return err
+}
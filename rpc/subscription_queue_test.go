@@ -0,0 +1,173 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	stdsync "sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/jsonrpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingConn struct {
+	mu     stdsync.Mutex
+	writes []string
+}
+
+func (c *recordingConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writes = append(c.writes, string(p))
+	return len(p), nil
+}
+
+func (c *recordingConn) Equal(other jsonrpc.Conn) bool {
+	return c == other
+}
+
+// queueStats are updated from the goroutines writing to and draining the queue.
+type queueStats struct {
+	mu      stdsync.Mutex
+	queued  int
+	dropped map[OverflowPolicy]int
+}
+
+func newTestQueue(t *testing.T, policy OverflowPolicy) (*subscriptionQueue, *recordingConn, *queueStats, *bool) {
+	t.Helper()
+
+	conn := &recordingConn{}
+	stats := &queueStats{dropped: make(map[OverflowPolicy]int)}
+	cancelled := new(bool)
+	listener := &SelectiveListener{
+		OnQueuedNotificationsChangeCb: func(delta int) {
+			stats.mu.Lock()
+			defer stats.mu.Unlock()
+			stats.queued += delta
+		},
+		OnNotificationsDroppedCb: func(policy OverflowPolicy, count int) {
+			stats.mu.Lock()
+			defer stats.mu.Unlock()
+			stats.dropped[policy] += count
+		},
+	}
+	return newSubscriptionQueue(conn, 1, 2, policy, func() { *cancelled = true }, listener), conn, stats, cancelled
+}
+
+// drain writes the queued notifications once the queue is closed.
+func drain(q *subscriptionQueue) {
+	q.close()
+	q.run(context.Background())
+}
+
+func TestSubscriptionQueueDisconnect(t *testing.T) {
+	q, conn, stats, cancelled := newTestQueue(t, OverflowDisconnect)
+
+	for _, n := range []string{"a", "b"} {
+		_, err := q.Write([]byte(n))
+		require.NoError(t, err)
+	}
+	_, err := q.Write([]byte("c"))
+	require.ErrorIs(t, err, errSlowConsumer)
+	assert.True(t, *cancelled)
+	assert.Equal(t, 3, stats.dropped[OverflowDisconnect])
+
+	_, err = q.Write([]byte("d"))
+	require.ErrorIs(t, err, errSubscriptionClosed)
+
+	q.discard()
+	assert.Empty(t, conn.writes)
+	assert.Zero(t, stats.queued)
+}
+
+func TestSubscriptionQueueDropOldest(t *testing.T) {
+	q, conn, stats, cancelled := newTestQueue(t, OverflowDropOldest)
+
+	for _, n := range []string{"a", "b", "c", "d"} {
+		_, err := q.Write([]byte(n))
+		require.NoError(t, err)
+	}
+	assert.False(t, *cancelled)
+	assert.Equal(t, 2, stats.queued)
+	assert.Equal(t, 2, stats.dropped[OverflowDropOldest])
+
+	drain(q)
+	assert.Equal(t, []string{
+		`{"jsonrpc":"2.0","method":"juno_subscriptionGap","params":{"result":{"dropped":2},"subscription_id":1}}`,
+		"c",
+		"d",
+	}, conn.writes)
+	assert.Zero(t, stats.queued)
+}
+
+func TestSubscriptionQueueCoalesceHeads(t *testing.T) {
+	q, conn, stats, cancelled := newTestQueue(t, OverflowCoalesceHeads)
+
+	require.NoError(t, writeHead(q, []byte("head 1")))
+	_, err := q.Write([]byte("reorg"))
+	require.NoError(t, err)
+	require.NoError(t, writeHead(q, []byte("head 2")))
+	require.NoError(t, writeHead(q, []byte("head 3")))
+	assert.False(t, *cancelled)
+	assert.Equal(t, 2, stats.dropped[OverflowCoalesceHeads])
+
+	drain(q)
+	assert.Equal(t, []string{"reorg", "head 3"}, conn.writes)
+
+	t.Run("other notifications can't be coalesced", func(t *testing.T) {
+		q, _, _, cancelled := newTestQueue(t, OverflowCoalesceHeads)
+
+		require.NoError(t, writeHead(q, []byte("head 1")))
+		require.NoError(t, writeHead(q, []byte("head 2")))
+		_, err := q.Write([]byte("reorg"))
+		require.ErrorIs(t, err, errSlowConsumer)
+		assert.True(t, *cancelled)
+	})
+}
+
+func TestSubscriptionQueueCatchUp(t *testing.T) {
+	q, conn, stats, cancelled := newTestQueue(t, OverflowDisconnect)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	catchUp := q.catchUp(ctx)
+
+	// catching up leaves half of the queue for new notifications
+	wrote := make(chan struct{})
+	go func() {
+		defer close(wrote)
+		for i := range 3 {
+			_, err := catchUp.Write([]byte(fmt.Sprint(i)))
+			assert.NoError(t, err)
+		}
+	}()
+
+	go q.run(ctx)
+	<-wrote
+	_, err := q.Write([]byte("new"))
+	require.NoError(t, err)
+	q.close()
+
+	require.Eventually(t, func() bool {
+		conn.mu.Lock()
+		defer conn.mu.Unlock()
+		return len(conn.writes) == 4
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"0", "1", "2", "new"}, conn.writes)
+	assert.False(t, *cancelled)
+	assert.Empty(t, stats.dropped)
+}
+
+func TestParseOverflowPolicy(t *testing.T) {
+	for _, policy := range []OverflowPolicy{OverflowDisconnect, OverflowDropOldest, OverflowCoalesceHeads} {
+		parsed, err := ParseOverflowPolicy(string(policy))
+		require.NoError(t, err)
+		assert.Equal(t, policy, parsed)
+	}
+
+	_, err := ParseOverflowPolicy("block")
+	require.EqualError(t, err,
+		`unknown subscription overflow policy "block", expected one of "disconnect", "drop-oldest" or "coalesce-heads"`)
+}
@@ -3,10 +3,12 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 
 	"github.com/NethermindEth/juno/blockchain"
 	"github.com/NethermindEth/juno/core"
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/db"
 	"github.com/NethermindEth/juno/feed"
 	"github.com/NethermindEth/juno/jsonrpc"
 	"github.com/NethermindEth/juno/sync"
@@ -21,6 +23,31 @@ type SubscriptionResponse struct {
 	Params  any    `json:"params"`
 }
 
+// OpensSubscription reports whether method starts a subscription, whose work outlives the request.
+func OpensSubscription(method string) bool {
+	return method == "juno_resubscribe" || SSESubscriptions{}.Subscribes(method)
+}
+
+// newSubscription registers a subscription of the client behind w. Its notifications should be written to its
+// queue, which is drained until the subscription ends or ctx is cancelled.
+func (h *Handler) newSubscription(ctx context.Context, w jsonrpc.Conn) (uint64, context.Context, *subscription) {
+	id := h.idgen()
+	subscriptionCtx, subscriptionCtxCancel := context.WithCancel(ctx)
+	sub := &subscription{
+		cancel: subscriptionCtxCancel,
+		conn:   w,
+	}
+	sub.queue = newSubscriptionQueue(w, id, h.subscriptionQueueSize, h.overflowPolicy, subscriptionCtxCancel, h.listener)
+	h.mu.Lock()
+	h.subscriptions[id] = sub
+	h.mu.Unlock()
+
+	sub.wg.Go(func() {
+		sub.queue.run(ctx)
+	})
+	return id, subscriptionCtx, sub
+}
+
 // SubscribeEvents creates a WebSocket stream which will fire events for new Starknet events with applied filters
 func (h *Handler) SubscribeEvents(ctx context.Context, fromAddr *felt.Felt, keys [][]felt.Felt,
 	blockID *BlockID,
@@ -30,11 +57,7 @@ func (h *Handler) SubscribeEvents(ctx context.Context, fromAddr *felt.Felt, keys
 		return nil, jsonrpc.Err(jsonrpc.MethodNotFound, nil)
 	}
 
-	lenKeys := len(keys)
-	for _, k := range keys {
-		lenKeys += len(k)
-	}
-	if lenKeys > maxEventFilterKeys {
+	if countEventFilterKeys(keys) > maxEventFilterKeys {
 		return nil, ErrTooManyKeysInFilter
 	}
 
@@ -47,15 +70,17 @@ func (h *Handler) SubscribeEvents(ctx context.Context, fromAddr *felt.Felt, keys
 		return nil, rpcErr
 	}
 
-	id := h.idgen()
-	subscriptionCtx, subscriptionCtxCancel := context.WithCancel(ctx)
-	sub := &subscription{
-		cancel: subscriptionCtxCancel,
-		conn:   w,
-	}
-	h.mu.Lock()
-	h.subscriptions[id] = sub
-	h.mu.Unlock()
+	return h.subscribeEvents(ctx, w, fromAddr, keys, requestedHeader.Number, headHeader.Number, 0, nil), nil
+}
+
+// subscribeEvents sends the events from the blocks from to to and then the ones from new blocks. The first skip
+// events of block from are left out. If the subscription is resumed from an orphaned block, orphaned is sent first.
+func (h *Handler) subscribeEvents(ctx context.Context, w jsonrpc.Conn, fromAddr *felt.Felt, keys [][]felt.Felt,
+	from, to, skip uint64, orphaned *sync.ReorgBlockRange,
+) *SubscriptionID {
+	h.rememberEventsFilter(fromAddr, keys)
+	id, subscriptionCtx, sub := h.newSubscription(ctx, w)
+	w = sub.queue
 
 	headerSub := h.newHeads.Subscribe()
 	reorgSub := h.reorgs.Subscribe() // as per the spec, reorgs are also sent in the events subscription
@@ -66,16 +91,31 @@ func (h *Handler) SubscribeEvents(ctx context.Context, fromAddr *felt.Felt, keys
 			reorgSub.Unsubscribe()
 		}()
 
-		// The specification doesn't enforce ordering of events therefore events from new blocks can be sent before
-		// old blocks.
+		if orphaned != nil {
+			if err := h.sendReorg(w, orphaned, id); err != nil {
+				h.log.Warnw("Error sending reorg", "err", err)
+				return
+			}
+		}
+
+		// Events from new blocks are held back until the ones from past blocks are sent
 		var wg conc.WaitGroup
+		if from <= to {
+			held := holdNotifications(sub.queue)
+			w = held
+			wg.Go(func() {
+				defer held.release()
+				h.processEvents(subscriptionCtx, sub.queue.catchUp(subscriptionCtx), id, from, to, skip, fromAddr, keys)
+			})
+		}
+
 		wg.Go(func() {
 			for {
 				select {
 				case <-subscriptionCtx.Done():
 					return
 				case header := <-headerSub.Recv():
-					h.processEvents(subscriptionCtx, w, id, header.Number, header.Number, fromAddr, keys)
+					h.processEvents(subscriptionCtx, w, id, header.Number, header.Number, 0, fromAddr, keys)
 				}
 			}
 		})
@@ -84,14 +124,18 @@ func (h *Handler) SubscribeEvents(ctx context.Context, fromAddr *felt.Felt, keys
 			h.processReorgs(subscriptionCtx, reorgSub, w, id)
 		})
 
-		wg.Go(func() {
-			h.processEvents(subscriptionCtx, w, id, requestedHeader.Number, headHeader.Number, fromAddr, keys)
-		})
-
 		wg.Wait()
 	})
 
-	return &SubscriptionID{ID: id}, nil
+	return &SubscriptionID{ID: id}
+}
+
+func countEventFilterKeys(keys [][]felt.Felt) int {
+	lenKeys := len(keys)
+	for _, k := range keys {
+		lenKeys += len(k)
+	}
+	return lenKeys
 }
 
 // SubscribeTransactionStatus subscribes to status changes of a transaction. It checks for updates each time a new block is added.
@@ -120,15 +164,8 @@ func (h *Handler) SubscribeTransactionStatus(ctx context.Context, txHash felt.Fe
 		return nil, rpcErr
 	}
 
-	id := h.idgen()
-	subscriptionCtx, subscriptionCtxCancel := context.WithCancel(ctx)
-	sub := &subscription{
-		cancel: subscriptionCtxCancel,
-		conn:   w,
-	}
-	h.mu.Lock()
-	h.subscriptions[id] = sub
-	h.mu.Unlock()
+	id, subscriptionCtx, sub := h.newSubscription(ctx, w)
+	w = sub.queue
 
 	l2HeadSub := h.newHeads.Subscribe()
 	l1HeadSub := h.l1Heads.Subscribe()
@@ -221,7 +258,10 @@ func (h *Handler) SubscribeTransactionStatus(ctx context.Context, txHash felt.Fe
 	return &SubscriptionID{ID: id}, nil
 }
 
-func (h *Handler) processEvents(ctx context.Context, w jsonrpc.Conn, id, from, to uint64, fromAddr *felt.Felt, keys [][]felt.Felt) {
+// processEvents sends the events from the blocks from to to, leaving out the first skip events of block from.
+func (h *Handler) processEvents(ctx context.Context, w jsonrpc.Conn, id, from, to, skip uint64, fromAddr *felt.Felt,
+	keys [][]felt.Felt,
+) {
 	filter, err := h.bcReader.EventFilter(fromAddr, keys)
 	if err != nil {
 		h.log.Warnw("Error creating event filter", "err", err)
@@ -235,26 +275,26 @@ func (h *Handler) processEvents(ctx context.Context, w jsonrpc.Conn, id, from, t
 		return
 	}
 
-	filteredEvents, cToken, err := filter.Events(nil, subscribeEventsChunkSize)
+	filteredEvents, cToken, err := filter.Events(ctx, nil, subscribeEventsChunkSize)
 	if err != nil {
 		h.log.Warnw("Error filtering events", "err", err)
 		return
 	}
 
-	err = sendEvents(ctx, w, filteredEvents, id)
+	err = sendEvents(ctx, w, skipEvents(filteredEvents, from, skip), id, fromAddr, keys)
 	if err != nil {
 		h.log.Warnw("Error sending events", "err", err)
 		return
 	}
 
 	for cToken != nil {
-		filteredEvents, cToken, err = filter.Events(cToken, subscribeEventsChunkSize)
+		filteredEvents, cToken, err = filter.Events(ctx, cToken, subscribeEventsChunkSize)
 		if err != nil {
 			h.log.Warnw("Error filtering events", "err", err)
 			return
 		}
 
-		err = sendEvents(ctx, w, filteredEvents, id)
+		err = sendEvents(ctx, w, skipEvents(filteredEvents, from, skip), id, fromAddr, keys)
 		if err != nil {
 			h.log.Warnw("Error sending events", "err", err)
 			return
@@ -262,7 +302,24 @@ func (h *Handler) processEvents(ctx context.Context, w jsonrpc.Conn, id, from, t
 	}
 }
 
-func sendEvents(ctx context.Context, w jsonrpc.Conn, events []*blockchain.FilteredEvent, id uint64) error {
+// skipEvents leaves out the first skip events of block.
+func skipEvents(events []*blockchain.FilteredEvent, block, skip uint64) []*blockchain.FilteredEvent {
+	if skip == 0 {
+		return events
+	}
+	remaining := events[:0:0]
+	for _, event := range events {
+		if event.BlockNumber != block || event.EventIndex >= skip {
+			remaining = append(remaining, event)
+		}
+	}
+	return remaining
+}
+
+func sendEvents(ctx context.Context, w jsonrpc.Conn, events []*blockchain.FilteredEvent, id uint64, fromAddr *felt.Felt,
+	keys [][]felt.Felt,
+) error {
+	filterID := eventsFilterID(fromAddr, keys)
 	for _, event := range events {
 		select {
 		case <-ctx.Done():
@@ -279,12 +336,24 @@ func sendEvents(ctx context.Context, w jsonrpc.Conn, events []*blockchain.Filter
 				},
 			}
 
+			cursor, err := (&subscriptionCursor{
+				Subscription: eventsCursor,
+				BlockHash:    event.BlockHash,
+				BlockNumber:  event.BlockNumber,
+				EventIndex:   &event.EventIndex,
+				Filter:       filterID,
+			}).encode()
+			if err != nil {
+				return err
+			}
+
 			resp, err := json.Marshal(SubscriptionResponse{
 				Version: "2.0",
 				Method:  "starknet_subscriptionEvents",
 				Params: map[string]any{
 					"subscription_id": id,
 					"result":          emittedEvent,
+					"cursor":          cursor,
 				},
 			})
 			if err != nil {
@@ -316,15 +385,16 @@ func (h *Handler) SubscribeNewHeads(ctx context.Context, blockID *BlockID) (*Sub
 		return nil, rpcErr
 	}
 
-	id := h.idgen()
-	subscriptionCtx, subscriptionCtxCancel := context.WithCancel(ctx)
-	sub := &subscription{
-		cancel: subscriptionCtxCancel,
-		conn:   w,
-	}
-	h.mu.Lock()
-	h.subscriptions[id] = sub
-	h.mu.Unlock()
+	return h.subscribeNewHeads(ctx, w, startHeader, latestHeader, nil), nil
+}
+
+// subscribeNewHeads sends the headers from startHeader, unless it is nil, to latestHeader and then the new ones.
+// If the subscription is resumed from an orphaned block, orphaned is sent first.
+func (h *Handler) subscribeNewHeads(ctx context.Context, w jsonrpc.Conn, startHeader, latestHeader *core.Header,
+	orphaned *sync.ReorgBlockRange,
+) *SubscriptionID {
+	id, subscriptionCtx, sub := h.newSubscription(ctx, w)
+	w = sub.queue
 
 	headerSub := h.newHeads.Subscribe()
 	reorgSub := h.reorgs.Subscribe() // as per the spec, reorgs are also sent in the new heads subscription
@@ -335,14 +405,27 @@ func (h *Handler) SubscribeNewHeads(ctx context.Context, blockID *BlockID) (*Sub
 			reorgSub.Unsubscribe()
 		}()
 
-		var wg conc.WaitGroup
-
-		wg.Go(func() {
-			if err := h.sendHistoricalHeaders(subscriptionCtx, startHeader, latestHeader, w, id); err != nil {
-				h.log.Errorw("Error sending old headers", "err", err)
+		if orphaned != nil {
+			if err := h.sendReorg(w, orphaned, id); err != nil {
+				h.log.Warnw("Error sending reorg", "err", err)
 				return
 			}
-		})
+		}
+
+		// New headers are held back until the past ones are sent
+		var wg conc.WaitGroup
+		if startHeader != nil {
+			held := holdNotifications(sub.queue)
+			w = held
+			wg.Go(func() {
+				defer held.release()
+				catchUp := sub.queue.catchUp(subscriptionCtx)
+				if err := h.sendHistoricalHeaders(subscriptionCtx, startHeader, latestHeader, catchUp, id); err != nil {
+					h.log.Errorw("Error sending old headers", "err", err)
+					return
+				}
+			})
+		}
 
 		wg.Go(func() {
 			h.processReorgs(subscriptionCtx, reorgSub, w, id)
@@ -355,7 +438,7 @@ func (h *Handler) SubscribeNewHeads(ctx context.Context, blockID *BlockID) (*Sub
 		wg.Wait()
 	})
 
-	return &SubscriptionID{ID: id}, nil
+	return &SubscriptionID{ID: id}
 }
 
 // SubscribePendingTxs creates a WebSocket stream which will fire events when a new pending transaction is added.
@@ -371,15 +454,8 @@ func (h *Handler) SubscribePendingTxs(ctx context.Context, getDetails *bool, sen
 		return nil, ErrTooManyAddressesInFilter
 	}
 
-	id := h.idgen()
-	subscriptionCtx, subscriptionCtxCancel := context.WithCancel(ctx)
-	sub := &subscription{
-		cancel: subscriptionCtxCancel,
-		conn:   w,
-	}
-	h.mu.Lock()
-	h.subscriptions[id] = sub
-	h.mu.Unlock()
+	id, subscriptionCtx, sub := h.newSubscription(ctx, w)
+	w = sub.queue
 
 	pendingTxsSub := h.pendingTxs.Subscribe()
 	sub.wg.Go(func() {
@@ -560,19 +636,28 @@ func (h *Handler) processNewHeaders(ctx context.Context, headerSub *feed.Subscri
 
 // sendHeader creates a request and sends it to the client
 func (h *Handler) sendHeader(w jsonrpc.Conn, header *core.Header, id uint64) error {
+	cursor, err := (&subscriptionCursor{
+		Subscription: newHeadsCursor,
+		BlockHash:    header.Hash,
+		BlockNumber:  header.Number,
+	}).encode()
+	if err != nil {
+		return err
+	}
+
 	resp, err := json.Marshal(SubscriptionResponse{
 		Version: "2.0",
 		Method:  "starknet_subscriptionNewHeads",
 		Params: map[string]any{
 			"subscription_id": id,
 			"result":          adaptBlockHeader(header),
+			"cursor":          cursor,
 		},
 	})
 	if err != nil {
 		return err
 	}
-	_, err = w.Write(resp)
-	return err
+	return writeHead(w, resp)
 }
 
 func (h *Handler) processReorgs(ctx context.Context, reorgSub *feed.Subscription[*sync.ReorgBlockRange], w jsonrpc.Conn, id uint64) {
@@ -654,3 +739,105 @@ func (h *Handler) sendTxnStatus(w jsonrpc.Conn, status SubscriptionTransactionSt
 	_, err = w.Write(resp)
 	return err
 }
+
+type L1HeadUpdate struct {
+	BlockHash   *felt.Felt `json:"block_hash"`
+	BlockNumber uint64     `json:"block_number"`
+	NewRoot     *felt.Felt `json:"new_root"`
+	// AcceptedOnL1Blocks are the blocks that moved from ACCEPTED_ON_L2 to ACCEPTED_ON_L1 with this head
+	AcceptedOnL1Blocks *BlockRange `json:"accepted_on_l1_blocks,omitempty"`
+}
+
+// BlockRange is an inclusive range of block numbers.
+type BlockRange struct {
+	FirstBlockNumber uint64 `json:"first_block_number"`
+	LastBlockNumber  uint64 `json:"last_block_number"`
+}
+
+// SubscribeL1Head creates a WebSocket stream which will fire events when a new L1 head is finalised,
+// starting with the current one. If withBlocks is set, each new head also lists the blocks that it
+// moved from ACCEPTED_ON_L2 to ACCEPTED_ON_L1.
+func (h *Handler) SubscribeL1Head(ctx context.Context, withBlocks *bool) (*SubscriptionID, *jsonrpc.Error) {
+	w, ok := jsonrpc.ConnFromContext(ctx)
+	if !ok {
+		return nil, jsonrpc.Err(jsonrpc.MethodNotFound, nil)
+	}
+
+	// subscribe before reading the current head so that no head is missed in between
+	l1HeadSub := h.l1Heads.Subscribe()
+	head, err := h.bcReader.L1Head()
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		l1HeadSub.Unsubscribe()
+		return nil, ErrInternal.CloneWithData(err)
+	}
+
+	id, subscriptionCtx, sub := h.newSubscription(ctx, w)
+	w = sub.queue
+
+	sub.wg.Go(func() {
+		defer func() {
+			h.unsubscribe(sub, id)
+			l1HeadSub.Unsubscribe()
+		}()
+
+		if head != nil {
+			if err := h.sendL1Head(w, &L1HeadUpdate{
+				BlockHash:   head.BlockHash,
+				BlockNumber: head.BlockNumber,
+				NewRoot:     head.StateRoot,
+			}, id); err != nil {
+				h.log.Warnw("Error sending L1 head", "err", err)
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-subscriptionCtx.Done():
+				return
+			case newHead := <-l1HeadSub.Recv():
+				update := &L1HeadUpdate{
+					BlockHash:   newHead.BlockHash,
+					BlockNumber: newHead.BlockNumber,
+					NewRoot:     newHead.StateRoot,
+				}
+				if withBlocks != nil && *withBlocks {
+					update.AcceptedOnL1Blocks = acceptedOnL1Blocks(head, newHead)
+				}
+				if err := h.sendL1Head(w, update, id); err != nil {
+					h.log.Warnw("Error sending L1 head", "err", err)
+					return
+				}
+				head = newHead
+			}
+		}
+	})
+
+	return &SubscriptionID{ID: id}, nil
+}
+
+// acceptedOnL1Blocks returns the range of blocks between two L1 heads. If the previous head is not
+// known, the range only holds the new head.
+func acceptedOnL1Blocks(previous, head *core.L1Head) *BlockRange {
+	from := head.BlockNumber
+	if previous != nil && previous.BlockNumber < head.BlockNumber {
+		from = previous.BlockNumber + 1
+	}
+	return &BlockRange{FirstBlockNumber: from, LastBlockNumber: head.BlockNumber}
+}
+
+func (h *Handler) sendL1Head(w jsonrpc.Conn, update *L1HeadUpdate, id uint64) error {
+	resp, err := json.Marshal(SubscriptionResponse{
+		Version: "2.0",
+		Method:  "juno_subscriptionL1Head",
+		Params: map[string]any{
+			"subscription_id": id,
+			"result":          update,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(resp)
+	return err
+}
@@ -190,7 +190,7 @@ func TestSubscribeEvents(t *testing.T) {
 		mockChain.EXPECT().EventFilter(fromAddr, keys).Return(mockEventFilterer, nil)
 
 		mockEventFilterer.EXPECT().SetRangeEndBlockByNumber(gomock.Any(), gomock.Any()).Return(nil).MaxTimes(2)
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return(filteredEvents, nil, nil)
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(filteredEvents, nil, nil)
 		mockEventFilterer.EXPECT().Close().AnyTimes()
 
 		serverConn, clientConn := net.Pipe()
@@ -205,8 +205,8 @@ func TestSubscribeEvents(t *testing.T) {
 		require.Nil(t, rpcErr)
 
 		var marshalledResponses [][]byte
-		for _, e := range emittedEvents {
-			resp, err := marshalSubEventsResp(e, id.ID)
+		for i, e := range emittedEvents {
+			resp, err := marshalSubEventsResp(e, id.ID, testEventsCursor(t, filteredEvents[i], fromAddr, keys))
 			require.NoError(t, err)
 			marshalledResponses = append(marshalledResponses, resp)
 		}
@@ -235,9 +235,9 @@ func TestSubscribeEvents(t *testing.T) {
 
 		cToken := new(blockchain.ContinuationToken)
 		mockEventFilterer.EXPECT().SetRangeEndBlockByNumber(gomock.Any(), gomock.Any()).Return(nil).MaxTimes(2)
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return(
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 			[]*blockchain.FilteredEvent{filteredEvents[0]}, cToken, nil)
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return(
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return(
 			[]*blockchain.FilteredEvent{filteredEvents[1]}, nil, nil)
 		mockEventFilterer.EXPECT().Close().AnyTimes()
 
@@ -253,8 +253,8 @@ func TestSubscribeEvents(t *testing.T) {
 		require.Nil(t, rpcErr)
 
 		var marshalledResponses [][]byte
-		for _, e := range emittedEvents {
-			resp, err := marshalSubEventsResp(e, id.ID)
+		for i, e := range emittedEvents {
+			resp, err := marshalSubEventsResp(e, id.ID, testEventsCursor(t, filteredEvents[i], fromAddr, keys))
 			require.NoError(t, err)
 			marshalledResponses = append(marshalledResponses, resp)
 		}
@@ -284,7 +284,7 @@ func TestSubscribeEvents(t *testing.T) {
 		mockChain.EXPECT().EventFilter(fromAddr, keys).Return(mockEventFilterer, nil)
 
 		mockEventFilterer.EXPECT().SetRangeEndBlockByNumber(gomock.Any(), gomock.Any()).Return(nil).MaxTimes(2)
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return([]*blockchain.FilteredEvent{filteredEvents[0]}, nil, nil)
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return([]*blockchain.FilteredEvent{filteredEvents[0]}, nil, nil)
 		mockEventFilterer.EXPECT().Close().AnyTimes()
 
 		serverConn, clientConn := net.Pipe()
@@ -298,7 +298,7 @@ func TestSubscribeEvents(t *testing.T) {
 		id, rpcErr := handler.SubscribeEvents(subCtx, fromAddr, keys, nil)
 		require.Nil(t, rpcErr)
 
-		resp, err := marshalSubEventsResp(emittedEvents[0], id.ID)
+		resp, err := marshalSubEventsResp(emittedEvents[0], id.ID, testEventsCursor(t, filteredEvents[0], fromAddr, keys))
 		require.NoError(t, err)
 
 		got := make([]byte, len(resp))
@@ -309,11 +309,11 @@ func TestSubscribeEvents(t *testing.T) {
 		mockChain.EXPECT().EventFilter(fromAddr, keys).Return(mockEventFilterer, nil)
 
 		mockEventFilterer.EXPECT().SetRangeEndBlockByNumber(gomock.Any(), gomock.Any()).Return(nil).MaxTimes(2)
-		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any()).Return([]*blockchain.FilteredEvent{filteredEvents[1]}, nil, nil)
+		mockEventFilterer.EXPECT().Events(gomock.Any(), gomock.Any(), gomock.Any()).Return([]*blockchain.FilteredEvent{filteredEvents[1]}, nil, nil)
 
 		headerFeed.Send(&core.Header{Number: b1.Number + 1})
 
-		resp, err = marshalSubEventsResp(emittedEvents[1], id.ID)
+		resp, err = marshalSubEventsResp(emittedEvents[1], id.ID, testEventsCursor(t, filteredEvents[1], fromAddr, keys))
 		require.NoError(t, err)
 
 		got = make([]byte, len(resp))
@@ -676,7 +676,7 @@ func TestSubscribeNewHeads(t *testing.T) {
 		// Receive a block header.
 		_, headerGot, err := conn.Read(ctx)
 		require.NoError(t, err)
-		require.Equal(t, newHeadsResponse(id), string(headerGot))
+		require.Equal(t, newHeadsResponse(t, id), string(headerGot))
 	})
 }
 
@@ -712,8 +712,8 @@ func TestSubscribeNewHeadsHistorical(t *testing.T) {
 	require.Equal(t, subResp(id), got)
 
 	// Check block 0 content
-	want := `{"jsonrpc":"2.0","method":"starknet_subscriptionNewHeads","params":{"result":{"block_hash":"0x47c3637b57c2b079b93c61539950c17e868a28f46cdef28f88521067f21e943","parent_hash":"0x0","block_number":0,"new_root":"0x21870ba80540e7831fb21c591ee93481f5ae1bb71ff85a86ddd465be4eddee6","timestamp":1637069048,"sequencer_address":"0x0","l1_gas_price":{"price_in_fri":"0x0","price_in_wei":"0x0"},"l1_data_gas_price":{"price_in_fri":"0x0","price_in_wei":"0x0"},"l1_da_mode":"CALLDATA","starknet_version":""},"subscription_id":%d}}`
-	want = fmt.Sprintf(want, id)
+	want := `{"jsonrpc":"2.0","method":"starknet_subscriptionNewHeads","params":{"cursor":%q,"result":{"block_hash":"0x47c3637b57c2b079b93c61539950c17e868a28f46cdef28f88521067f21e943","parent_hash":"0x0","block_number":0,"new_root":"0x21870ba80540e7831fb21c591ee93481f5ae1bb71ff85a86ddd465be4eddee6","timestamp":1637069048,"sequencer_address":"0x0","l1_gas_price":{"price_in_fri":"0x0","price_in_wei":"0x0"},"l1_data_gas_price":{"price_in_fri":"0x0","price_in_wei":"0x0"},"l1_da_mode":"CALLDATA","starknet_version":""},"subscription_id":%d}}`
+	want = fmt.Sprintf(want, testNewHeadsCursor(t, block0.Header), id)
 	_, block0Got, err := conn.Read(ctx)
 	require.NoError(t, err)
 	require.Equal(t, want, string(block0Got))
@@ -724,7 +724,7 @@ func TestSubscribeNewHeadsHistorical(t *testing.T) {
 	// Check new block content
 	_, newBlockGot, err := conn.Read(ctx)
 	require.NoError(t, err)
-	require.Equal(t, newHeadsResponse(id), string(newBlockGot))
+	require.Equal(t, newHeadsResponse(t, id), string(newBlockGot))
 }
 
 func TestMultipleSubscribeNewHeadsAndUnsubscribe(t *testing.T) {
@@ -784,11 +784,11 @@ func TestMultipleSubscribeNewHeadsAndUnsubscribe(t *testing.T) {
 	// Receive a block header.
 	_, firstHeaderGot, err := conn1.Read(ctx)
 	require.NoError(t, err)
-	require.Equal(t, newHeadsResponse(firstID), string(firstHeaderGot))
+	require.Equal(t, newHeadsResponse(t, firstID), string(firstHeaderGot))
 
 	_, secondHeaderGot, err := conn2.Read(ctx)
 	require.NoError(t, err)
-	require.Equal(t, newHeadsResponse(secondID), string(secondHeaderGot))
+	require.Equal(t, newHeadsResponse(t, secondID), string(secondHeaderGot))
 
 	// Unsubscribe
 	unsubMsg := `{"jsonrpc":"2.0","id":1,"method":"starknet_unsubscribe","params":[%d]}`
@@ -1003,6 +1003,56 @@ func TestSubscribePendingTxs(t *testing.T) {
 	})
 }
 
+func TestSubscribeL1Head(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	t.Cleanup(mockCtrl.Finish)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	mockChain := mocks.NewMockReader(mockCtrl)
+	l1Feed := feed.New[*core.L1Head]()
+	mockChain.EXPECT().SubscribeL1Head().Return(blockchain.L1HeadSubscription{Subscription: l1Feed.Subscribe()})
+	mockChain.EXPECT().L1Head().Return(&core.L1Head{
+		BlockNumber: 5,
+		BlockHash:   new(felt.Felt).SetUint64(0x5),
+		StateRoot:   new(felt.Felt).SetUint64(0x50),
+	}, nil)
+
+	handler, server := setupRPC(t, ctx, mockChain, newFakeSyncer())
+	conn := createWsConn(t, ctx, server)
+
+	id := uint64(1)
+	handler.WithIDGen(func() uint64 { return id })
+
+	got := sendWsMessage(t, ctx, conn, `{"jsonrpc":"2.0","id":1,"method":"juno_subscribeL1Head","params":{"with_blocks":true}}`)
+	require.Equal(t, subResp(id), got)
+
+	// the current head is sent first
+	_, current, err := conn.Read(ctx)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf(`{"jsonrpc":"2.0","method":"juno_subscriptionL1Head","params":{"result":{"block_hash":"0x5","block_number":5,"new_root":"0x50"},"subscription_id":%d}}`, id), string(current)) //nolint:lll
+
+	l1Feed.Send(&core.L1Head{
+		BlockNumber: 8,
+		BlockHash:   new(felt.Felt).SetUint64(0x8),
+		StateRoot:   new(felt.Felt).SetUint64(0x80),
+	})
+
+	_, next, err := conn.Read(ctx)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf(`{"jsonrpc":"2.0","method":"juno_subscriptionL1Head","params":{"result":{"block_hash":"0x8","block_number":8,"new_root":"0x80","accepted_on_l1_blocks":{"first_block_number":6,"last_block_number":8}},"subscription_id":%d}}`, id), string(next)) //nolint:lll
+}
+
+func TestAcceptedOnL1Blocks(t *testing.T) {
+	assert.Equal(t, &BlockRange{FirstBlockNumber: 8, LastBlockNumber: 8}, acceptedOnL1Blocks(nil, &core.L1Head{BlockNumber: 8}))
+	assert.Equal(t, &BlockRange{FirstBlockNumber: 6, LastBlockNumber: 8},
+		acceptedOnL1Blocks(&core.L1Head{BlockNumber: 5}, &core.L1Head{BlockNumber: 8}))
+	// large jumps of the L1 head are sent in full
+	assert.Equal(t, &BlockRange{FirstBlockNumber: 1, LastBlockNumber: 5000},
+		acceptedOnL1Blocks(&core.L1Head{BlockNumber: 0}, &core.L1Head{BlockNumber: 5000}))
+}
+
 func createWsConn(t *testing.T, ctx context.Context, server *jsonrpc.Server) *websocket.Conn {
 	ws := jsonrpc.NewWebsocket(server, nil, utils.NewNopZapLogger())
 	httpSrv := httptest.NewServer(ws)
@@ -1025,8 +1075,11 @@ func subMsg(method string) string {
 	return fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":%q}`, method)
 }
 
-func newHeadsResponse(id uint64) string {
-	return fmt.Sprintf(`{"jsonrpc":"2.0","method":"starknet_subscriptionNewHeads","params":{"result":{"block_hash":"0x4e1f77f39545afe866ac151ac908bd1a347a2a8a7d58bef1276db4f06fdf2f6","parent_hash":"0x2a70fb03fe363a2d6be843343a1d81ce6abeda1e9bd5cc6ad8fa9f45e30fdeb","block_number":2,"new_root":"0x3ceee867d50b5926bb88c0ec7e0b9c20ae6b537e74aac44b8fcf6bb6da138d9","timestamp":1637084470,"sequencer_address":"0x0","l1_gas_price":{"price_in_fri":"0x0","price_in_wei":"0x0"},"l1_data_gas_price":{"price_in_fri":"0x0","price_in_wei":"0x0"},"l1_da_mode":"CALLDATA","starknet_version":""},"subscription_id":%d}}`, id)
+func newHeadsResponse(t *testing.T, id uint64) string {
+	t.Helper()
+
+	cursor := testNewHeadsCursor(t, testHeader(t))
+	return fmt.Sprintf(`{"jsonrpc":"2.0","method":"starknet_subscriptionNewHeads","params":{"cursor":%q,"result":{"block_hash":"0x4e1f77f39545afe866ac151ac908bd1a347a2a8a7d58bef1276db4f06fdf2f6","parent_hash":"0x2a70fb03fe363a2d6be843343a1d81ce6abeda1e9bd5cc6ad8fa9f45e30fdeb","block_number":2,"new_root":"0x3ceee867d50b5926bb88c0ec7e0b9c20ae6b537e74aac44b8fcf6bb6da138d9","timestamp":1637084470,"sequencer_address":"0x0","l1_gas_price":{"price_in_fri":"0x0","price_in_wei":"0x0"},"l1_data_gas_price":{"price_in_fri":"0x0","price_in_wei":"0x0"},"l1_da_mode":"CALLDATA","starknet_version":""},"subscription_id":%d}}`, cursor, id)
 }
 
 // setupRPC creates a RPC handler that runs in a goroutine and a JSONRPC server that can be used to test subscriptions
@@ -1060,17 +1113,44 @@ func sendWsMessage(t *testing.T, ctx context.Context, conn *websocket.Conn, mess
 	return string(response)
 }
 
-func marshalSubEventsResp(e *EmittedEvent, id uint64) ([]byte, error) {
+func marshalSubEventsResp(e *EmittedEvent, id uint64, cursor string) ([]byte, error) {
 	return json.Marshal(SubscriptionResponse{
 		Version: "2.0",
 		Method:  "starknet_subscriptionEvents",
 		Params: map[string]any{
 			"subscription_id": id,
 			"result":          e,
+			"cursor":          cursor,
 		},
 	})
 }
 
+func testNewHeadsCursor(t *testing.T, header *core.Header) string {
+	t.Helper()
+
+	cursor, err := (&subscriptionCursor{
+		Subscription: newHeadsCursor,
+		BlockHash:    header.Hash,
+		BlockNumber:  header.Number,
+	}).encode()
+	require.NoError(t, err)
+	return cursor
+}
+
+func testEventsCursor(t *testing.T, e *blockchain.FilteredEvent, fromAddr *felt.Felt, keys [][]felt.Felt) string {
+	t.Helper()
+
+	cursor, err := (&subscriptionCursor{
+		Subscription: eventsCursor,
+		BlockHash:    e.BlockHash,
+		BlockNumber:  e.BlockNumber,
+		EventIndex:   &e.EventIndex,
+		Filter:       eventsFilterID(fromAddr, keys),
+	}).encode()
+	require.NoError(t, err)
+	return cursor
+}
+
 func testHeader(t *testing.T) *core.Header {
 	t.Helper()
 
@@ -0,0 +1,167 @@
+package abi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+)
+
+var (
+	ErrUnknownFunction = errors.New("unknown function")
+	ErrUnknownEvent    = errors.New("unknown event")
+)
+
+// Param is a named, typed element of a function signature, struct, enum or event.
+type Param struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Kind is only set for members and variants of Cairo 1 events: key, data, nested or flat.
+	Kind string `json:"kind,omitempty"`
+}
+
+// Function is an external function, constructor or L1 handler of a class.
+type Function struct {
+	Name     string
+	Selector *felt.Felt
+	Inputs   []Param
+	Outputs  []Param
+}
+
+// event is either a Cairo 1 event struct or enum, a pre Cairo 2 event, or a Cairo 0 event.
+type event struct {
+	name     string
+	kind     string // struct or enum for Cairo 1 events
+	keys     []Param
+	data     []Param
+	members  []Param
+	variants []Param
+}
+
+// ABI resolves selectors to functions and events of a class, and decodes felts into typed values
+// according to the types it declares.
+type ABI struct {
+	cairo0    bool
+	functions map[felt.Felt]*Function
+	structs   map[string][]Param
+	enums     map[string][]Param
+	events    map[string]*event
+	// events that are identified by the selector of their name as the first key, i.e. all events
+	// but Cairo 2 event structs and enums
+	eventSelectors map[felt.Felt]*event
+	// event enums that every Cairo 2 event of the contract is serialised from
+	rootEvents []*event
+}
+
+type entry struct {
+	Type     string  `json:"type"`
+	Name     string  `json:"name"`
+	Kind     string  `json:"kind"`
+	Inputs   []Param `json:"inputs"`
+	Outputs  []Param `json:"outputs"`
+	Members  []Param `json:"members"`
+	Variants []Param `json:"variants"`
+	Keys     []Param `json:"keys"`
+	Data     []Param `json:"data"`
+	Items    []entry `json:"items"`
+}
+
+// New parses the ABI of the given class.
+func New(class core.Class) (*ABI, error) {
+	switch c := class.(type) {
+	case *core.Cairo0Class:
+		return parse(c.Abi, true)
+	case *core.Cairo1Class:
+		return parse([]byte(c.Abi), false)
+	default:
+		return nil, fmt.Errorf("unsupported class type %T", class)
+	}
+}
+
+func parse(raw []byte, cairo0 bool) (*ABI, error) {
+	var entries []entry
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return nil, fmt.Errorf("unmarshal abi: %v", err)
+		}
+	}
+
+	a := &ABI{
+		cairo0:         cairo0,
+		functions:      make(map[felt.Felt]*Function),
+		structs:        make(map[string][]Param),
+		enums:          make(map[string][]Param),
+		events:         make(map[string]*event),
+		eventSelectors: make(map[felt.Felt]*event),
+	}
+	var events []*event
+	for i := range entries {
+		events = a.add(&entries[i], events)
+	}
+	a.findRootEvents(events)
+	return a, nil
+}
+
+func (a *ABI) add(e *entry, events []*event) []*event {
+	switch e.Type {
+	case "function", "constructor", "l1_handler":
+		selector := crypto.StarknetKeccak([]byte(e.Name))
+		a.functions[*selector] = &Function{
+			Name:     e.Name,
+			Selector: selector,
+			Inputs:   e.Inputs,
+			Outputs:  e.Outputs,
+		}
+	case "interface":
+		for i := range e.Items {
+			events = a.add(&e.Items[i], events)
+		}
+	case "struct":
+		a.structs[e.Name] = e.Members
+	case "enum":
+		a.enums[e.Name] = e.Variants
+	case "event":
+		ev := &event{
+			name:     e.Name,
+			kind:     e.Kind,
+			keys:     e.Keys,
+			data:     e.Data,
+			members:  e.Members,
+			variants: e.Variants,
+		}
+		if e.Kind == "" && e.Inputs != nil {
+			// events of Cairo 1 classes compiled before Cairo 2 don't have key members
+			ev.data = e.Inputs
+		}
+		a.events[e.Name] = ev
+		events = append(events, ev)
+		if e.Kind == "" {
+			a.eventSelectors[*crypto.StarknetKeccak([]byte(e.Name))] = ev
+		}
+	}
+	return events
+}
+
+// findRootEvents finds the event enums that are not a variant of another event enum. Each of them is
+// the event type of a contract, which every event the contract emits is serialised from.
+func (a *ABI) findRootEvents(events []*event) {
+	nested := make(map[string]struct{})
+	for _, ev := range events {
+		for _, variant := range ev.variants {
+			nested[variant.Type] = struct{}{}
+		}
+	}
+	for _, ev := range events {
+		if _, ok := nested[ev.name]; !ok && ev.kind == "enum" {
+			a.rootEvents = append(a.rootEvents, ev)
+		}
+	}
+}
+
+// Function returns the function with the given selector, or nil if the ABI doesn't declare it.
+func (a *ABI) Function(selector *felt.Felt) *Function {
+	return a.functions[*selector]
+}
@@ -0,0 +1,255 @@
+package abi_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/NethermindEth/juno/clients/feeder"
+	"github.com/NethermindEth/juno/core"
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/juno/starknet/abi"
+	adaptfeeder "github.com/NethermindEth/juno/starknetdata/feeder"
+	"github.com/NethermindEth/juno/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func feltsOf(values ...uint64) []*felt.Felt {
+	felts := make([]*felt.Felt, 0, len(values))
+	for _, v := range values {
+		felts = append(felts, new(felt.Felt).SetUint64(v))
+	}
+	return felts
+}
+
+func selector(name string) *felt.Felt {
+	return crypto.StarknetKeccak([]byte(name))
+}
+
+func classABI(t *testing.T, network *utils.Network, classHash string) *abi.ABI {
+	t.Helper()
+
+	gw := adaptfeeder.New(feeder.NewTestClient(t, network))
+	class, err := gw.Class(context.Background(), utils.HexToFelt(t, classHash))
+	require.NoError(t, err)
+
+	a, err := abi.New(class)
+	require.NoError(t, err)
+	return a
+}
+
+func marshal(t *testing.T, v any) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestCairo1ERC20(t *testing.T) {
+	a := classABI(t, &utils.Integration, "0x6b3da05b352f93912df0593a703f1884c4c607523bb33feaff4940635ef050d")
+
+	t.Run("unknown function", func(t *testing.T) {
+		assert.Nil(t, a.Function(selector("mint")))
+	})
+
+	t.Run("transfer", func(t *testing.T) {
+		fn := a.Function(selector("transfer"))
+		require.NotNil(t, fn)
+		assert.Equal(t, "transfer", fn.Name)
+
+		inputs, err := a.DecodeInputs(fn, feltsOf(0x123, 5, 1))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"recipient":"0x123","amount":"340282366920938463463374607431768211461"}`, marshal(t, inputs))
+
+		outputs, err := a.DecodeOutputs(fn, feltsOf(1))
+		require.NoError(t, err)
+		assert.Equal(t, []any{true}, outputs)
+	})
+
+	t.Run("malformed calldata", func(t *testing.T) {
+		fn := a.Function(selector("transfer"))
+		_, err := a.DecodeInputs(fn, feltsOf(0x123, 5))
+		require.Error(t, err)
+
+		_, err = a.DecodeInputs(fn, feltsOf(0x123, 5, 1, 7))
+		require.Error(t, err)
+	})
+
+	t.Run("nested component event", func(t *testing.T) {
+		keys := append([]*felt.Felt{selector("Transfer")}, feltsOf(0x1, 0x2)...)
+		ev, err := a.DecodeEvent(keys, feltsOf(10, 0))
+		require.NoError(t, err)
+		assert.Equal(t, "openzeppelin::token::erc20::erc20::ERC20Component::Transfer", ev.Name)
+		assert.Equal(t, `{"from":"0x1","to":"0x2","value":"10"}`, marshal(t, ev.Fields))
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		_, err := a.DecodeEvent([]*felt.Felt{selector("Mint")}, nil)
+		require.ErrorIs(t, err, abi.ErrUnknownEvent)
+	})
+}
+
+func TestCairo1Types(t *testing.T) {
+	class := &core.Cairo1Class{Abi: `[
+		{"type": "struct", "name": "core::array::Span::<core::felt252>", "members": [
+			{"name": "snapshot", "type": "@core::array::Array::<core::felt252>"}
+		]},
+		{"type": "struct", "name": "example::Point", "members": [
+			{"name": "x", "type": "core::integer::i32"},
+			{"name": "y", "type": "core::integer::u64"}
+		]},
+		{"type": "enum", "name": "core::option::Option::<example::Point>", "variants": [
+			{"name": "Some", "type": "example::Point"},
+			{"name": "None", "type": "()"}
+		]},
+		{"type": "function", "name": "everything", "inputs": [
+			{"name": "name", "type": "core::byte_array::ByteArray"},
+			{"name": "point", "type": "core::option::Option::<example::Point>"},
+			{"name": "none", "type": "core::option::Option::<example::Point>"},
+			{"name": "span", "type": "core::array::Span::<core::felt252>"},
+			{"name": "pair", "type": "(core::bool, core::integer::u8)"}
+		], "outputs": [], "state_mutability": "view"}
+	]`}
+	a, err := abi.New(class)
+	require.NoError(t, err)
+
+	fn := a.Function(selector("everything"))
+	require.NotNil(t, fn)
+
+	minusThree := new(felt.Felt).Sub(&felt.Zero, new(felt.Felt).SetUint64(3))
+	calldata := []*felt.Felt{
+		// "juno" as a ByteArray without full words
+		new(felt.Felt).SetUint64(0), new(felt.Felt).SetBytes([]byte("juno")), new(felt.Felt).SetUint64(4),
+		// Some(Point { x: -3, y: 7 })
+		new(felt.Felt).SetUint64(0), minusThree, new(felt.Felt).SetUint64(7),
+		// None
+		new(felt.Felt).SetUint64(1),
+	}
+	calldata = append(calldata, feltsOf(2, 0xa, 0xb, 0, 255)...)
+
+	inputs, err := a.DecodeInputs(fn, calldata)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":"juno","point":{"Some":{"x":"-3","y":"7"}},"none":{"None":null},`+
+		`"span":["0xa","0xb"],"pair":[false,"255"]}`, marshal(t, inputs))
+
+	t.Run("invalid enum variant", func(t *testing.T) {
+		invalid := append([]*felt.Felt{}, calldata...)
+		invalid[3] = new(felt.Felt).SetUint64(2)
+		_, err := a.DecodeInputs(fn, invalid)
+		require.Error(t, err)
+	})
+
+	t.Run("array length exceeding calldata", func(t *testing.T) {
+		invalid := append([]*felt.Felt{}, calldata[:7]...)
+		invalid = append(invalid, feltsOf(1_000_000)...)
+		_, err := a.DecodeInputs(fn, invalid)
+		require.Error(t, err)
+	})
+}
+
+func TestCairo1EventsWithoutKeys(t *testing.T) {
+	a := classABI(t, &utils.Integration, "0x1cd2edfb485241c4403254d550de0a097fa76743cd30696f714a491a454bad5")
+
+	ev, err := a.DecodeEvent([]*felt.Felt{selector("simple_event")}, feltsOf(1, 2, 3, 4))
+	require.NoError(t, err)
+	assert.Equal(t, "simple_event", ev.Name)
+	assert.Equal(t, `{"argument":"0x1","my_array":["0x3","0x4"]}`, marshal(t, ev.Fields))
+}
+
+func TestCairo0(t *testing.T) {
+	a := classABI(t, &utils.Sepolia, "0x772164c9d6179a89e7f1167f099219f47d752304b16ed01f081b6e0b45c93c3")
+
+	t.Run("pointer arguments", func(t *testing.T) {
+		fn := a.Function(selector("advance_counter"))
+		require.NotNil(t, fn)
+
+		inputs, err := a.DecodeInputs(fn, feltsOf(1, 2, 3, 4))
+		require.NoError(t, err)
+		assert.Equal(t, `{"index":"0x1","diffs_len":"0x2","diffs":["0x3","0x4"]}`, marshal(t, inputs))
+	})
+
+	t.Run("struct with named tuple", func(t *testing.T) {
+		fn := a.Function(selector("xor_counters"))
+		require.NotNil(t, fn)
+
+		inputs, err := a.DecodeInputs(fn, feltsOf(1, 2, 3))
+		require.NoError(t, err)
+		assert.Equal(t, `{"index_and_x":{"index":"0x1","values":{"x":"0x2","y":"0x3"}}}`, marshal(t, inputs))
+	})
+
+	t.Run("event", func(t *testing.T) {
+		ev, err := a.DecodeEvent([]*felt.Felt{selector("log_storage_cells")}, feltsOf(1, 0xa, 0xb))
+		require.NoError(t, err)
+		assert.Equal(t, "log_storage_cells", ev.Name)
+		assert.Equal(t, `{"storage_cells_len":"0x1","storage_cells":[{"key":"0xa","value":"0xb"}]}`, marshal(t, ev.Fields))
+	})
+}
+
+func TestRecursiveTypes(t *testing.T) {
+	t.Run("cairo 1", func(t *testing.T) {
+		a, err := abi.New(&core.Cairo1Class{Abi: `[
+			{"type": "struct", "name": "example::Node", "members": [
+				{"name": "next", "type": "core::zeroable::NonZero::<example::Node>"}
+			]},
+			{"type": "function", "name": "follow", "inputs": [{"name": "node", "type": "example::Node"}],
+				"outputs": [], "state_mutability": "view"},
+			{"type": "event", "name": "example::Ping", "kind": "enum", "variants": [
+				{"name": "Pong", "type": "example::Pong", "kind": "flat"}
+			]},
+			{"type": "event", "name": "example::Pong", "kind": "enum", "variants": [
+				{"name": "Ping", "type": "example::Ping", "kind": "flat"}
+			]},
+			{"type": "event", "name": "example::Event", "kind": "enum", "variants": [
+				{"name": "Ping", "type": "example::Ping", "kind": "flat"}
+			]}
+		]`})
+		require.NoError(t, err)
+
+		_, err = a.DecodeInputs(a.Function(selector("follow")), feltsOf(1))
+		require.ErrorContains(t, err, "nested more than")
+
+		_, err = a.DecodeEvent(feltsOf(1), nil)
+		require.ErrorContains(t, err, "nested more than")
+	})
+
+	t.Run("cairo 0", func(t *testing.T) {
+		a, err := abi.New(&core.Cairo0Class{Abi: json.RawMessage(`[
+			{"type": "struct", "name": "Node", "members": [{"name": "next", "type": "(next: Node)", "offset": 0}], "size": 1},
+			{"type": "function", "name": "follow", "inputs": [{"name": "node", "type": "Node"}], "outputs": []}
+		]`)})
+		require.NoError(t, err)
+
+		_, err = a.DecodeInputs(a.Function(selector("follow")), feltsOf(1, 2, 3))
+		require.ErrorContains(t, err, "nested more than")
+	})
+}
+
+func TestTooManyValues(t *testing.T) {
+	// every level multiplies the number of values by eight without taking up any felts
+	var entries []string
+	for level := range 7 {
+		member := fmt.Sprintf("example::Level%d", level+1)
+		if level == 6 {
+			member = "()"
+		}
+		members := make([]string, 0, 8)
+		for i := range 8 {
+			members = append(members, fmt.Sprintf(`{"name": "m%d", "type": %q}`, i, member))
+		}
+		entries = append(entries, fmt.Sprintf(`{"type": "struct", "name": "example::Level%d", "members": [%s]}`,
+			level, strings.Join(members, ",")))
+	}
+	entries = append(entries, `{"type": "function", "name": "wide", "inputs": [{"name": "root", "type": "example::Level0"}],
+		"outputs": [], "state_mutability": "view"}`)
+
+	a, err := abi.New(&core.Cairo1Class{Abi: "[" + strings.Join(entries, ",") + "]"})
+	require.NoError(t, err)
+
+	_, err = a.DecodeInputs(a.Function(selector("wide")), nil)
+	require.ErrorContains(t, err, "values decoded")
+}
@@ -0,0 +1,564 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/NethermindEth/juno/core/crypto"
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/consensys/gnark-crypto/ecc/stark-curve/fp"
+)
+
+const (
+	bytes31Size         = 31
+	shortStringByteSize = 32
+	// maxDepth is how deeply types can be nested in a decoded value, so that types referring to
+	// themselves can't recurse without end
+	maxDepth = 64
+	// maxElements is how many values a single decoding can produce, so that types that take up no
+	// felts can't make the output grow without bound
+	maxElements = 100_000
+)
+
+var (
+	errNotEnoughFelts = errors.New("not enough felts")
+	errTooDeep        = fmt.Errorf("types nested more than %d levels deep", maxDepth)
+	errTooManyValues  = fmt.Errorf("more than %d values decoded", maxElements)
+)
+
+// Field is a named decoded value.
+type Field struct {
+	Name  string
+	Value any
+}
+
+// Fields are the decoded members of a struct, arguments of a function or fields of an event. They are
+// marshalled into a JSON object that keeps their declaration order.
+type Fields []Field
+
+func (f Fields) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range f {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(field.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Event is a decoded event.
+type Event struct {
+	Name   string `json:"name"`
+	Fields Fields `json:"fields"`
+}
+
+// DecodeInputs decodes the calldata of a call to fn.
+func (a *ABI) DecodeInputs(fn *Function, calldata []*felt.Felt) (Fields, error) {
+	d := a.newDecoder(calldata)
+	fields, err := d.params(fn.Inputs)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s inputs: %w", fn.Name, err)
+	}
+	return fields, d.done()
+}
+
+// DecodeOutputs decodes the values returned by a call to fn.
+func (a *ABI) DecodeOutputs(fn *Function, result []*felt.Felt) ([]any, error) {
+	d := a.newDecoder(result)
+	fields, err := d.params(fn.Outputs)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s outputs: %w", fn.Name, err)
+	}
+
+	values := make([]any, 0, len(fields))
+	for _, field := range fields {
+		values = append(values, field.Value)
+	}
+	return values, d.done()
+}
+
+// DecodeEvent decodes an event emitted by a contract of this class.
+func (a *ABI) DecodeEvent(keys, data []*felt.Felt) (*Event, error) {
+	if len(keys) == 0 {
+		return nil, ErrUnknownEvent
+	}
+
+	// Cairo 2 events are serialised from the event enum of the contract
+	for _, root := range a.rootEvents {
+		ev, fields, err := a.decodeEventEnum(root, keys, data, 0)
+		if errors.Is(err, ErrUnknownEvent) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		return &Event{Name: ev.name, Fields: fields}, nil
+	}
+
+	ev, ok := a.eventSelectors[*keys[0]]
+	if !ok {
+		return nil, ErrUnknownEvent
+	}
+
+	keyDecoder, dataDecoder := a.newDecoder(keys[1:]), a.newDecoder(data)
+	keyFields, err := keyDecoder.params(ev.keys)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s keys: %w", ev.name, err)
+	}
+	dataFields, err := dataDecoder.params(ev.data)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s data: %w", ev.name, err)
+	}
+	if err = errors.Join(keyDecoder.done(), dataDecoder.done()); err != nil {
+		return nil, err
+	}
+	return &Event{Name: ev.name, Fields: append(keyFields, dataFields...)}, nil
+}
+
+// decodeEventEnum follows the variants of an event enum down to the event struct that was emitted. A
+// nested variant adds the selector of its name to the keys, while a flat variant doesn't. The depth is the
+// number of enums followed to get to this one.
+func (a *ABI) decodeEventEnum(enum *event, keys, data []*felt.Felt, depth int) (*event, Fields, error) {
+	if depth >= maxDepth {
+		return nil, nil, errTooDeep
+	}
+	for _, variant := range enum.variants {
+		inner, ok := a.events[variant.Type]
+		if !ok {
+			continue
+		}
+
+		variantKeys := keys
+		if variant.Kind == "nested" {
+			if len(keys) == 0 || !keys[0].Equal(crypto.StarknetKeccak([]byte(variant.Name))) {
+				continue
+			}
+			variantKeys = keys[1:]
+		}
+
+		switch inner.kind {
+		case "enum":
+			ev, fields, err := a.decodeEventEnum(inner, variantKeys, data, depth+1)
+			if errors.Is(err, ErrUnknownEvent) {
+				continue
+			}
+			return ev, fields, err
+		case "struct":
+			if variant.Kind != "nested" {
+				// a flat struct variant can't be told apart from the other variants
+				continue
+			}
+			fields, err := a.decodeEventStruct(inner, variantKeys, data)
+			return inner, fields, err
+		}
+	}
+	return nil, nil, ErrUnknownEvent
+}
+
+func (a *ABI) decodeEventStruct(ev *event, keys, data []*felt.Felt) (Fields, error) {
+	keyDecoder, dataDecoder := a.newDecoder(keys), a.newDecoder(data)
+	fields := make(Fields, 0, len(ev.members))
+	for _, member := range ev.members {
+		var d *decoder
+		switch member.Kind {
+		case "key":
+			d = keyDecoder
+		case "data":
+			d = dataDecoder
+		default:
+			return nil, fmt.Errorf("decode %s: unsupported member kind %q", ev.name, member.Kind)
+		}
+
+		value, err := d.value(member.Type)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s.%s: %w", ev.name, member.Name, err)
+		}
+		fields = append(fields, Field{Name: member.Name, Value: value})
+	}
+	return fields, errors.Join(keyDecoder.done(), dataDecoder.done())
+}
+
+type decoder struct {
+	abi   *ABI
+	felts []*felt.Felt
+	// the last felt decoded by a Cairo 0 parameter list, which holds the length of the array that follows it
+	lastFelt *felt.Felt
+	// depth is the number of types the value being decoded is nested in
+	depth int
+	// decoded is the number of values decoded so far
+	decoded int
+}
+
+func (a *ABI) newDecoder(felts []*felt.Felt) *decoder {
+	return &decoder{abi: a, felts: felts}
+}
+
+func (d *decoder) next() (*felt.Felt, error) {
+	if len(d.felts) == 0 {
+		return nil, errNotEnoughFelts
+	}
+	f := d.felts[0]
+	d.felts = d.felts[1:]
+	return f, nil
+}
+
+func (d *decoder) length() (uint64, error) {
+	f, err := d.next()
+	if err != nil {
+		return 0, err
+	}
+	return d.checkLength(f)
+}
+
+// checkLength rejects lengths that can't be satisfied by the remaining felts, so that malformed input
+// can't cause large allocations.
+func (d *decoder) checkLength(f *felt.Felt) (uint64, error) {
+	n := f.BigInt(new(big.Int))
+	if !n.IsUint64() || n.Uint64() > uint64(len(d.felts)) {
+		return 0, fmt.Errorf("invalid length %s", f)
+	}
+	return n.Uint64(), nil
+}
+
+func (d *decoder) done() error {
+	if len(d.felts) > 0 {
+		return fmt.Errorf("%d unexpected trailing felts", len(d.felts))
+	}
+	return nil
+}
+
+func (d *decoder) params(params []Param) (Fields, error) {
+	fields := make(Fields, 0, len(params))
+	for _, param := range params {
+		value, err := d.value(param.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", param.Name, err)
+		}
+		fields = append(fields, Field{Name: param.Name, Value: value})
+	}
+	return fields, nil
+}
+
+func (d *decoder) value(typ string) (any, error) {
+	if d.depth >= maxDepth {
+		return nil, errTooDeep
+	}
+	if d.decoded >= maxElements {
+		return nil, errTooManyValues
+	}
+	d.decoded++
+	d.depth++
+	defer func() { d.depth-- }()
+
+	if d.abi.cairo0 {
+		return d.cairo0Value(typ)
+	}
+	return d.cairo1Value(typ)
+}
+
+func (d *decoder) hex() (any, error) {
+	f, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+	return f.String(), nil
+}
+
+func (d *decoder) decimal() (any, error) {
+	f, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+	return f.Text(10), nil //nolint:mnd
+}
+
+func (d *decoder) signed() (any, error) {
+	f, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+
+	// negative values are represented as the field prime minus their absolute value
+	n := f.BigInt(new(big.Int))
+	if n.Cmp(halfPrime) > 0 {
+		n.Sub(n, fieldPrime)
+	}
+	return n.String(), nil
+}
+
+var (
+	fieldPrime = fp.Modulus()
+	halfPrime  = new(big.Int).Rsh(fieldPrime, 1)
+)
+
+func (d *decoder) u256() (any, error) {
+	low, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+	high, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+
+	n := high.BigInt(new(big.Int))
+	n.Lsh(n, 128) //nolint:mnd
+	return n.Add(n, low.BigInt(new(big.Int))).String(), nil
+}
+
+func (d *decoder) byteArray() (any, error) {
+	n, err := d.length()
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	for range n {
+		word, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		wordBytes := word.Bytes()
+		data = append(data, wordBytes[shortStringByteSize-bytes31Size:]...)
+	}
+
+	pendingWord, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+	pendingWordLen, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+	pendingLen := pendingWordLen.Uint64()
+	if !pendingWordLen.Equal(new(felt.Felt).SetUint64(pendingLen)) || pendingLen >= bytes31Size {
+		return nil, fmt.Errorf("invalid pending word length %s", pendingWordLen)
+	}
+	pendingBytes := pendingWord.Bytes()
+	data = append(data, pendingBytes[shortStringByteSize-pendingLen:]...)
+
+	if utf8.Valid(data) {
+		return string(data), nil
+	}
+	return "0x" + hex.EncodeToString(data), nil
+}
+
+func (d *decoder) array(elementType string) (any, error) {
+	n, err := d.length()
+	if err != nil {
+		return nil, err
+	}
+	return d.elements(elementType, n)
+}
+
+func (d *decoder) elements(elementType string, n uint64) (any, error) {
+	values := make([]any, 0, n)
+	for range n {
+		value, err := d.value(elementType)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func (d *decoder) tuple(typ string) (any, error) {
+	elements := splitTuple(typ)
+	values := make([]any, 0, len(elements))
+	var fields Fields
+	for _, element := range elements {
+		// Cairo 0 tuples may name their elements
+		name, elementType, named := strings.Cut(element, ": ")
+		if !named {
+			elementType = element
+		}
+
+		value, err := d.value(elementType)
+		if err != nil {
+			return nil, err
+		}
+		if named {
+			fields = append(fields, Field{Name: name, Value: value})
+		}
+		values = append(values, value)
+	}
+
+	if fields != nil {
+		return fields, nil
+	}
+	return values, nil
+}
+
+func (d *decoder) structValue(name string, members []Param) (any, error) {
+	fields := make(Fields, 0, len(members))
+	for _, member := range members {
+		value, err := d.value(member.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", name, member.Name, err)
+		}
+		fields = append(fields, Field{Name: member.Name, Value: value})
+	}
+	return fields, nil
+}
+
+func (d *decoder) enumValue(name string, variants []Param) (any, error) {
+	index, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+
+	i := index.BigInt(new(big.Int))
+	if !i.IsUint64() || i.Uint64() >= uint64(len(variants)) {
+		return nil, fmt.Errorf("%s: invalid variant %s", name, index)
+	}
+
+	variant := variants[i.Uint64()]
+	value, err := d.value(variant.Type)
+	if err != nil {
+		return nil, fmt.Errorf("%s::%s: %w", name, variant.Name, err)
+	}
+	return Fields{{Name: variant.Name, Value: value}}, nil
+}
+
+// cairo1Value decodes a value of a Cairo 1 type, as serialised by the Serde trait.
+func (d *decoder) cairo1Value(typ string) (any, error) {
+	// snapshots are serialised like the value they refer to
+	typ = strings.TrimPrefix(typ, "@")
+
+	switch typ {
+	case "()":
+		return nil, nil
+	case "core::felt252",
+		"core::starknet::contract_address::ContractAddress",
+		"core::starknet::class_hash::ClassHash",
+		"core::starknet::eth_address::EthAddress",
+		"core::starknet::storage_access::StorageAddress",
+		"core::bytes_31::bytes31":
+		return d.hex()
+	case "core::bool":
+		f, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		return !f.IsZero(), nil
+	case "core::integer::u8", "core::integer::u16", "core::integer::u32", "core::integer::u64",
+		"core::integer::u128", "core::integer::usize":
+		return d.decimal()
+	case "core::integer::i8", "core::integer::i16", "core::integer::i32", "core::integer::i64",
+		"core::integer::i128":
+		return d.signed()
+	case "core::integer::u256":
+		return d.u256()
+	case "core::byte_array::ByteArray":
+		return d.byteArray()
+	}
+
+	if strings.HasPrefix(typ, "(") {
+		return d.tuple(typ)
+	}
+	for _, generic := range []string{"core::array::Array::<", "core::array::Span::<"} {
+		if elementType, ok := genericArgument(typ, generic); ok {
+			return d.array(elementType)
+		}
+	}
+	if innerType, ok := genericArgument(typ, "core::zeroable::NonZero::<"); ok {
+		return d.value(innerType)
+	}
+	if members, ok := d.abi.structs[typ]; ok {
+		return d.structValue(typ, members)
+	}
+	if variants, ok := d.abi.enums[typ]; ok {
+		return d.enumValue(typ, variants)
+	}
+	return nil, fmt.Errorf("unsupported type %s", typ)
+}
+
+// cairo0Value decodes a value of a Cairo 0 type. Arrays are preceded by a felt holding their length.
+func (d *decoder) cairo0Value(typ string) (any, error) {
+	if elementType, ok := strings.CutSuffix(typ, "*"); ok {
+		if d.lastFelt == nil {
+			return nil, fmt.Errorf("missing length of %s", typ)
+		}
+		n, err := d.checkLength(d.lastFelt)
+		if err != nil {
+			return nil, err
+		}
+		d.lastFelt = nil
+		return d.elements(elementType, n)
+	}
+
+	switch {
+	case typ == "felt":
+		f, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		d.lastFelt = f
+		return f.String(), nil
+	case strings.HasPrefix(typ, "("):
+		return d.tuple(typ)
+	}
+
+	members, ok := d.abi.structs[typ]
+	if !ok {
+		return nil, fmt.Errorf("unsupported type %s", typ)
+	}
+	if typ == "Uint256" && len(members) == 2 && members[0].Name == "low" && members[1].Name == "high" {
+		return d.u256()
+	}
+	return d.structValue(typ, members)
+}
+
+func genericArgument(typ, prefix string) (string, bool) {
+	if !strings.HasPrefix(typ, prefix) || !strings.HasSuffix(typ, ">") {
+		return "", false
+	}
+	return typ[len(prefix) : len(typ)-1], true
+}
+
+// splitTuple splits a tuple type into the types of its elements.
+func splitTuple(typ string) []string {
+	inner := strings.TrimSuffix(strings.TrimPrefix(typ, "("), ")")
+	if strings.TrimSpace(inner) == "" {
+		return nil
+	}
+
+	var (
+		elements []string
+		depth    int
+		start    int
+	)
+	for i, c := range inner {
+		switch c {
+		case '(', '<':
+			depth++
+		case ')', '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				elements = append(elements, strings.TrimSpace(inner[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(elements, strings.TrimSpace(inner[start:]))
+}
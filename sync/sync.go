@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	stdsync "sync"
 	"sync/atomic"
 	"time"
 
@@ -129,6 +130,14 @@ type Synchronizer struct {
 	plugin              junoplugin.JunoPlugin
 
 	currReorg *ReorgBlockRange // If nil, no reorg is happening
+
+	pauseMu stdsync.Mutex // protects the fields below.
+	// resumed is closed when syncing is resumed, it is nil unless syncing is paused
+	resumed chan struct{}
+	// streamDone is closed once the blocks that were being fetched and stored when syncing was paused are
+	streamDone chan struct{}
+	// cancelStream stops the current stream of blocks, it is nil unless blocks are being synced
+	cancelStream context.CancelFunc
 }
 
 func New(bc *blockchain.Blockchain, starkNetData starknetdata.StarknetData, log utils.SimpleLogger,
@@ -383,7 +392,8 @@ func (s *Synchronizer) syncBlocks(syncCtx context.Context) {
 	}
 
 	fetchers, verifiers := s.setupWorkers()
-	streamCtx, streamCancel := context.WithCancel(syncCtx)
+	streamCtx, streamCancel := s.newStreamContext(syncCtx)
+	defer s.clearStreamContext()
 
 	go s.pollLatest(syncCtx, latestSem)
 	pendingSem := make(chan struct{}, 1)
@@ -396,17 +406,15 @@ func (s *Synchronizer) syncBlocks(syncCtx context.Context) {
 			fetchers.Wait()
 			verifiers.Wait()
 
-			select {
-			case <-syncCtx.Done():
+			if !s.waitUntilResumed(syncCtx) {
 				pendingSem <- struct{}{}
 				latestSem <- struct{}{}
 				return
-			default:
-				streamCtx, streamCancel = context.WithCancel(syncCtx)
-				nextHeight = s.nextHeight()
-				fetchers, verifiers = s.setupWorkers()
-				s.log.Warnw("Restarting sync process", "height", nextHeight, "catchUpMode", s.catchUpMode)
 			}
+			streamCtx, streamCancel = s.newStreamContext(syncCtx)
+			nextHeight = s.nextHeight()
+			fetchers, verifiers = s.setupWorkers()
+			s.log.Warnw("Restarting sync process", "height", nextHeight, "catchUpMode", s.catchUpMode)
 		default:
 			curHeight, curStreamCtx, curCancel := nextHeight, streamCtx, streamCancel
 			fetchers.Go(func() stream.Callback {
@@ -420,6 +428,140 @@ func (s *Synchronizer) syncBlocks(syncCtx context.Context) {
 	}
 }
 
+// newStreamContext returns the context of a stream of blocks, which is cancelled when syncing is paused.
+func (s *Synchronizer) newStreamContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	streamCtx, cancel := context.WithCancel(ctx)
+
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+	s.cancelStream = cancel
+	if s.resumed != nil {
+		cancel()
+	}
+	return streamCtx, cancel
+}
+
+func (s *Synchronizer) clearStreamContext() {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+	s.cancelStream = nil
+}
+
+// waitUntilResumed blocks while syncing is paused, and reports whether syncing should go on.
+func (s *Synchronizer) waitUntilResumed(ctx context.Context) bool {
+	s.pauseMu.Lock()
+	resumed := s.resumed
+	if s.streamDone != nil {
+		close(s.streamDone)
+		s.streamDone = nil
+	}
+	s.pauseMu.Unlock()
+
+	if resumed == nil {
+		return ctx.Err() == nil
+	}
+	select {
+	case <-ctx.Done():
+		return false
+	case <-resumed:
+		return true
+	}
+}
+
+// Pause stops fetching and storing blocks, and polling the pending block, until Resume is called. It reports
+// whether syncing was running.
+func (s *Synchronizer) Pause() bool {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	if s.resumed != nil {
+		return false
+	}
+	s.resumed = make(chan struct{})
+	if s.cancelStream != nil {
+		s.streamDone = make(chan struct{})
+		s.cancelStream()
+	}
+	s.log.Infow("Paused syncing")
+	return true
+}
+
+// Resume continues syncing after Pause, and reports whether it was paused.
+func (s *Synchronizer) Resume() bool {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+
+	if s.resumed == nil {
+		return false
+	}
+	close(s.resumed)
+	s.resumed = nil
+	s.log.Infow("Resumed syncing")
+	return true
+}
+
+func (s *Synchronizer) Paused() bool {
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+	return s.resumed != nil
+}
+
+// RevertBlocks reverts the count most recent blocks while syncing is paused, once the blocks that were being stored
+// when it was paused are, and notifies the reorg. It returns the new head. The genesis block can't be reverted.
+func (s *Synchronizer) RevertBlocks(ctx context.Context, count uint64) (*core.Header, error) {
+	s.pauseMu.Lock()
+	streamDone := s.streamDone
+	s.pauseMu.Unlock()
+	if streamDone != nil {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-streamDone:
+		}
+	}
+
+	// syncing can't be resumed while blocks are reverted
+	s.pauseMu.Lock()
+	defer s.pauseMu.Unlock()
+	if s.resumed == nil {
+		return nil, errors.New("syncing must be paused to revert blocks")
+	}
+
+	head, err := s.blockchain.HeadsHeader()
+	if err != nil {
+		return nil, err
+	}
+	if count > head.Number {
+		return nil, fmt.Errorf("cannot revert %d blocks, there are only %d blocks after the genesis block", count, head.Number)
+	}
+
+	reorg := &ReorgBlockRange{EndBlockHash: head.Hash, EndBlockNum: head.Number}
+	for range count {
+		if s.plugin != nil {
+			s.handlePluginRevertBlock()
+		}
+		if err = s.blockchain.RevertHead(); err != nil {
+			break
+		}
+		s.log.Infow("Reverted HEAD", "reverted", head.Hash)
+		s.listener.OnReorg(head.Number)
+		reorg.StartBlockHash, reorg.StartBlockNum = head.Hash, head.Number
+
+		if head, err = s.blockchain.HeadsHeader(); err != nil {
+			break
+		}
+	}
+	if reorg.StartBlockHash != nil {
+		// the pending block followed a reverted block
+		s.pending.Store(nil)
+		s.reorgFeed.Send(reorg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return head, nil
+}
+
 func maxWorkers() int {
 	return min(16, runtime.GOMAXPROCS(0)) //nolint:mnd
 }
@@ -472,6 +614,9 @@ func (s *Synchronizer) pollPending(ctx context.Context, sem chan struct{}) {
 			pendingPollTicker.Stop()
 			return
 		case <-pendingPollTicker.C:
+			if s.Paused() {
+				continue
+			}
 			select {
 			case sem <- struct{}{}:
 				go func() {
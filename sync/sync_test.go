@@ -185,6 +185,74 @@ func TestReorg(t *testing.T) {
 	})
 }
 
+func TestPauseAndRevertBlocks(t *testing.T) {
+	testDB := pebble.NewMemTest(t)
+	bc := blockchain.New(testDB, &utils.Mainnet, nil)
+	gw := adaptfeeder.New(feeder.NewTestClient(t, &utils.Mainnet))
+	synchronizer := sync.New(bc, gw, utils.NewNopZapLogger(), 0, false, testDB)
+	reorgSub := synchronizer.SubscribeReorg()
+	t.Cleanup(reorgSub.Unsubscribe)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, synchronizer.Run(ctx))
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	headNumber := func() uint64 {
+		head, err := bc.HeadsHeader()
+		if err != nil {
+			return 0
+		}
+		return head.Number
+	}
+	require.Eventually(t, func() bool { return headNumber() == 2 }, 5*timeout, 10*time.Millisecond)
+
+	_, err := synchronizer.RevertBlocks(ctx, 1)
+	require.EqualError(t, err, "syncing must be paused to revert blocks")
+
+	require.True(t, synchronizer.Pause())
+	require.False(t, synchronizer.Pause())
+	assert.True(t, synchronizer.Paused())
+
+	_, err = synchronizer.RevertBlocks(ctx, 3)
+	require.EqualError(t, err, "cannot revert 3 blocks, there are only 2 blocks after the genesis block")
+
+	orphanedHead, err := bc.HeadsHeader()
+	require.NoError(t, err)
+	orphanedStart, err := bc.BlockHeaderByNumber(1)
+	require.NoError(t, err)
+	require.NoError(t, synchronizer.StorePending(&sync.Pending{
+		Block: &core.Block{Header: &core.Header{Number: 3, ParentHash: orphanedHead.Hash}},
+	}))
+	require.NotNil(t, synchronizer.PendingBlock())
+	head, err := synchronizer.RevertBlocks(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), head.Number)
+	assert.Nil(t, synchronizer.PendingBlock())
+
+	reorg := <-reorgSub.Recv()
+	assert.Equal(t, &sync.ReorgBlockRange{
+		StartBlockHash: orphanedStart.Hash,
+		StartBlockNum:  1,
+		EndBlockHash:   orphanedHead.Hash,
+		EndBlockNum:    2,
+	}, reorg)
+
+	// the reverted blocks are only synced again once syncing is resumed
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, uint64(0), headNumber())
+	require.True(t, synchronizer.Resume())
+	require.False(t, synchronizer.Resume())
+	require.Eventually(t, func() bool { return headNumber() == 2 }, 5*timeout, 10*time.Millisecond)
+}
+
 func TestPending(t *testing.T) {
 	client := feeder.NewTestClient(t, &utils.Mainnet)
 	gw := adaptfeeder.New(client)
@@ -101,6 +101,8 @@ type SimpleLogger interface {
 
 type ZapLogger struct {
 	*zap.SugaredLogger
+	// level is shared with the loggers derived from this one, so that changing it applies to all of them
+	level zap.AtomicLevel
 }
 
 const traceLevel = zapcore.Level(-2)
@@ -124,7 +126,7 @@ func (l *ZapLogger) Tracew(msg string, keysAndValues ...interface{}) {
 var _ Logger = (*ZapLogger)(nil)
 
 func NewNopZapLogger() *ZapLogger {
-	return &ZapLogger{zap.NewNop().Sugar()}
+	return &ZapLogger{SugaredLogger: zap.NewNop().Sugar(), level: zap.NewAtomicLevel()}
 }
 
 func NewZapLogger(logLevel LogLevel, colour bool) (*ZapLogger, error) {
@@ -139,16 +141,9 @@ func NewZapLogger(logLevel LogLevel, colour bool) (*ZapLogger, error) {
 		enc.AppendString(t.Local().Format("15:04:05.000 02/01/2006 -07:00"))
 	}
 
-	var level zapcore.Level
-	var err error
-	levelStr := logLevel.String()
-	if logLevel == TRACE {
-		level = traceLevel
-	} else {
-		level, err = zapcore.ParseLevel(levelStr)
-		if err != nil {
-			return nil, err
-		}
+	level, err := zapLevel(logLevel)
+	if err != nil {
+		return nil, err
 	}
 	config.Level.SetLevel(level)
 	log, err := config.Build()
@@ -156,7 +151,40 @@ func NewZapLogger(logLevel LogLevel, colour bool) (*ZapLogger, error) {
 		return nil, err
 	}
 
-	return &ZapLogger{log.Sugar()}, nil
+	return &ZapLogger{SugaredLogger: log.Sugar(), level: config.Level}, nil
+}
+
+func zapLevel(logLevel LogLevel) (zapcore.Level, error) {
+	if logLevel == TRACE {
+		return traceLevel, nil
+	}
+	return zapcore.ParseLevel(logLevel.String())
+}
+
+// Level returns the level the logger currently logs at.
+func (l *ZapLogger) Level() LogLevel {
+	switch l.level.Level() {
+	case traceLevel:
+		return TRACE
+	case zapcore.DebugLevel:
+		return DEBUG
+	case zapcore.InfoLevel:
+		return INFO
+	case zapcore.WarnLevel:
+		return WARN
+	default:
+		return ERROR
+	}
+}
+
+// SetLevel changes the level of the logger, and of the loggers derived from it, while it is in use.
+func (l *ZapLogger) SetLevel(logLevel LogLevel) error {
+	level, err := zapLevel(logLevel)
+	if err != nil {
+		return err
+	}
+	l.level.SetLevel(level)
+	return nil
 }
 
 func (l *ZapLogger) Warningf(msg string, args ...any) {
@@ -8,6 +8,7 @@ import (
 	"github.com/NethermindEth/juno/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
 )
 
 var levelStrings = map[utils.LogLevel]string{
@@ -107,3 +108,20 @@ func TestZapWithoutColour(t *testing.T) {
 		})
 	}
 }
+
+func TestZapSetLevel(t *testing.T) {
+	log, err := utils.NewZapLogger(utils.INFO, false)
+	require.NoError(t, err)
+	derived := log.Desugar().Core()
+	assert.False(t, derived.Enabled(zapcore.DebugLevel))
+
+	for level, str := range levelStrings {
+		t.Run("level: "+str, func(t *testing.T) {
+			require.NoError(t, log.SetLevel(level))
+			assert.Equal(t, level, log.Level())
+		})
+	}
+
+	require.NoError(t, log.SetLevel(utils.DEBUG))
+	assert.True(t, derived.Enabled(zapcore.DebugLevel))
+}
@@ -23,6 +23,8 @@ type Network struct {
 	L2ChainID           string             `json:"l2_chain_id" validate:"required"`
 	CoreContractAddress common.Address     `json:"core_contract_address" validate:"required"`
 	BlockHashMetaInfo   *BlockHashMetaInfo `json:"block_hash_meta_info"`
+	// UDCAddress is the address of the Universal Deployer Contract, nil if the network doesn't have one
+	UDCAddress *felt.Felt `json:"udc_address"`
 }
 
 type BlockHashMetaInfo struct {
@@ -37,6 +39,7 @@ type BlockHashMetaInfo struct {
 var (
 	fallBackSequencerAddressMainnet, _ = new(felt.Felt).SetString("0x021f4b90b0377c82bf330b7b5295820769e72d79d8acd0effa0ebde6e9988bc5")
 	fallBackSequencerAddress, _        = new(felt.Felt).SetString("0x046a89ae102987331d369645031b49c27738ed096f2789c24449966da4c6de6b")
+	udcAddress, _                      = new(felt.Felt).SetString("0x041a78e741e5af2fec34b695679bc6891742439f7afb8484ecd7766661ad02bf")
 	// The following are necessary for Cobra and Viper, respectively, to unmarshal log level CLI/config parameters properly.
 	_ pflag.Value              = (*Network)(nil)
 	_ encoding.TextUnmarshaler = (*Network)(nil)
@@ -53,6 +56,7 @@ var (
 			First07Block:             833,
 			FallBackSequencerAddress: fallBackSequencerAddressMainnet,
 		},
+		UDCAddress: udcAddress,
 	}
 	Goerli = Network{
 		Name:       "goerli",
@@ -67,6 +71,7 @@ var (
 			UnverifiableRange:        []uint64{119802, 148428},
 			FallBackSequencerAddress: fallBackSequencerAddress,
 		},
+		UDCAddress: udcAddress,
 	}
 	Goerli2 = Network{
 		Name:       "goerli2",
@@ -80,6 +85,7 @@ var (
 			First07Block:             0,
 			FallBackSequencerAddress: fallBackSequencerAddress,
 		},
+		UDCAddress: udcAddress,
 	}
 	Integration = Network{
 		Name:       "integration",
@@ -94,6 +100,7 @@ var (
 			UnverifiableRange:        []uint64{0, 110511},
 			FallBackSequencerAddress: fallBackSequencerAddress,
 		},
+		UDCAddress: udcAddress,
 	}
 	Sepolia = Network{
 		Name:       "sepolia",
@@ -107,6 +114,7 @@ var (
 			First07Block:             0,
 			FallBackSequencerAddress: fallBackSequencerAddress,
 		},
+		UDCAddress: udcAddress,
 	}
 	SepoliaIntegration = Network{
 		Name:       "sepolia-integration",
@@ -120,6 +128,7 @@ var (
 			First07Block:             0,
 			FallBackSequencerAddress: fallBackSequencerAddress,
 		},
+		UDCAddress: udcAddress,
 	}
 )
 
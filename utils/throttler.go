@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"errors"
 	"math"
 	"sync/atomic"
@@ -30,14 +31,20 @@ func (t *Throttler[T]) WithMaxQueueLen(maxQueueLen int32) *Throttler[T] {
 	return t
 }
 
-// Do lets caller acquire the resource within the context of a callback
-func (t *Throttler[T]) Do(doer func(resource *T) error) error {
+// Do lets caller acquire the resource within the context of a callback. It gives up waiting for the resource
+// once ctx is done.
+func (t *Throttler[T]) Do(ctx context.Context, doer func(resource *T) error) error {
 	queueLen := t.queue.Add(1)
 	if queueLen > t.maxQueueLen {
 		t.queue.Add(-1)
 		return ErrResourceBusy
 	}
-	t.sem <- struct{}{}
+	select {
+	case t.sem <- struct{}{}:
+	case <-ctx.Done():
+		t.queue.Add(-1)
+		return ctx.Err()
+	}
 	defer func() {
 		<-t.sem
 	}()
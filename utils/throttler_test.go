@@ -1,6 +1,7 @@
 package utils_test
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"sync/atomic"
@@ -31,7 +32,7 @@ func TestThrottler(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			require.NoError(t, throttledRes.Do(doer))
+			require.NoError(t, throttledRes.Do(context.Background(), doer))
 		}()
 		time.Sleep(time.Millisecond)
 	}
@@ -46,7 +47,7 @@ func TestThrottler(t *testing.T) {
 	do() // should be queued
 	assert.Equal(t, 2, throttledRes.QueueLen())
 
-	require.ErrorIs(t, throttledRes.Do(doer), utils.ErrResourceBusy)
+	require.ErrorIs(t, throttledRes.Do(context.Background(), doer), utils.ErrResourceBusy)
 
 	waitOn <- struct{}{} // release one of the slots
 	time.Sleep(time.Millisecond)
@@ -60,4 +61,26 @@ func TestThrottler(t *testing.T) {
 	waitOn <- struct{}{}
 	wg.Wait()
 	assert.Equal(t, int64(4), runCount)
+
+	t.Run("queued callers give up once their context is done", func(t *testing.T) {
+		throttledRes := utils.NewThrottler(1, new(int))
+		release := make(chan struct{})
+		go func() {
+			_ = throttledRes.Do(context.Background(), func(*int) error {
+				<-release
+				return nil
+			})
+		}()
+		time.Sleep(time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := throttledRes.Do(ctx, func(*int) error {
+			t.Error("resource acquired after the context was done")
+			return nil
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 0, throttledRes.QueueLen())
+		close(release)
+	})
 }
@@ -45,6 +45,13 @@ extern "C" {
     ) -> c_int;
     fn JunoStateGetCompiledClass(reader_handle: usize, class_hash: *const c_uchar)
         -> *const c_char;
+    fn JunoIsCancelled(reader_handle: usize) -> c_uchar;
+}
+
+/// Reports whether Juno cancelled the execution using the given reader handle, for example because the RPC
+/// request that started it timed out.
+pub fn is_cancelled(reader_handle: usize) -> bool {
+    unsafe { JunoIsCancelled(reader_handle) != 0 }
 }
 
 struct CachedContractClass {
@@ -64,6 +71,14 @@ impl JunoStateReader {
     pub fn new(handle: usize, height: u64) -> Self {
         Self { handle, height }
     }
+
+    /// Fails every read once the execution is cancelled, which aborts it.
+    fn check_cancelled(&self) -> StateResult<()> {
+        if is_cancelled(self.handle) {
+            return Err(StateError::StateReadError("execution cancelled".to_string()));
+        }
+        Ok(())
+    }
 }
 
 impl StateReader for JunoStateReader {
@@ -72,6 +87,7 @@ impl StateReader for JunoStateReader {
         contract_address: ContractAddress,
         key: StorageKey,
     ) -> StateResult<StarkFelt> {
+        self.check_cancelled()?;
         let addr = felt_to_byte_array(contract_address.0.key());
         let storage_key = felt_to_byte_array(key.0.key());
         let mut buffer: [u8; 32] = [0; 32];
@@ -98,6 +114,7 @@ impl StateReader for JunoStateReader {
     /// Returns the nonce of the given contract instance.
     /// Default: 0 for an uninitialized contract address.
     fn get_nonce_at(&self, contract_address: ContractAddress) -> StateResult<Nonce> {
+        self.check_cancelled()?;
         let addr = felt_to_byte_array(contract_address.0.key());
         let mut buffer: [u8; 32] = [0; 32];
         let wrote = unsafe { JunoStateGetNonceAt(self.handle, addr.as_ptr(), buffer.as_mut_ptr()) };
@@ -115,6 +132,7 @@ impl StateReader for JunoStateReader {
     /// Returns the class hash of the contract class at the given contract instance.
     /// Default: 0 (uninitialized class hash) for an uninitialized contract address.
     fn get_class_hash_at(&self, contract_address: ContractAddress) -> StateResult<ClassHash> {
+        self.check_cancelled()?;
         let addr = felt_to_byte_array(contract_address.0.key());
         let mut buffer: [u8; 32] = [0; 32];
         let wrote =
@@ -132,6 +150,7 @@ impl StateReader for JunoStateReader {
 
     /// Returns the contract class of the given class hash.
     fn get_compiled_contract_class(&self, class_hash: ClassHash) -> StateResult<ContractClass> {
+        self.check_cancelled()?;
         if let Some(cached_class) = CLASS_CACHE.lock().unwrap().cache_get(&class_hash) {
             // skip the cache if it comes from a height higher than ours. Class might be undefined on the height
             // that we are reading from right now.
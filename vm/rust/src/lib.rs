@@ -4,7 +4,7 @@ mod juno_state_reader;
 #[macro_use]
 extern crate lazy_static;
 
-use crate::juno_state_reader::{ptr_to_felt, JunoStateReader};
+use crate::juno_state_reader::{is_cancelled, ptr_to_felt, JunoStateReader};
 use std::{
     collections::HashMap,
     ffi::{c_char, c_longlong, c_uchar, c_ulonglong, c_void, CStr, CString},
@@ -236,6 +236,11 @@ pub extern "C" fn cairoVMExecute(
     let mut trace_buffer = Vec::with_capacity(10_000);
 
     for (txn_index, txn_and_query_bit) in txns_and_query_bits.iter().enumerate() {
+        if is_cancelled(reader_handle) {
+            report_error(reader_handle, "execution cancelled", -1);
+            return;
+        }
+
         let class_info = match txn_and_query_bit.txn.clone() {
             StarknetApiTransaction::Declare(_) => {
                 if classes.is_empty() {
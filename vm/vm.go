@@ -45,6 +45,7 @@ extern void freeString(char* str);
 import "C"
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -59,12 +60,14 @@ import (
 	"github.com/NethermindEth/juno/utils"
 )
 
+// VM executes Cairo code. Executions are aborted once ctx is done, in which case the error of ctx is returned.
+//
 //go:generate mockgen -destination=../mocks/mock_vm.go -package=mocks github.com/NethermindEth/juno/vm VM
 type VM interface {
-	Call(callInfo *CallInfo, blockInfo *BlockInfo, state core.StateReader, network *utils.Network,
+	Call(ctx context.Context, callInfo *CallInfo, blockInfo *BlockInfo, state core.StateReader, network *utils.Network,
 		maxSteps uint64) ([]*felt.Felt, error)
-	Execute(txns []core.Transaction, declaredClasses []core.Class, paidFeesOnL1 []*felt.Felt, blockInfo *BlockInfo,
-		state core.StateReader, network *utils.Network, skipChargeFee, skipValidate, errOnRevert bool,
+	Execute(ctx context.Context, txns []core.Transaction, declaredClasses []core.Class, paidFeesOnL1 []*felt.Felt,
+		blockInfo *BlockInfo, state core.StateReader, network *utils.Network, skipChargeFee, skipValidate, errOnRevert bool,
 	) ([]*felt.Felt, []core.GasConsumed, []TransactionTrace, uint64, error)
 }
 
@@ -82,6 +85,8 @@ func New(concurrencyMode bool, log utils.SimpleLogger) VM {
 
 // callContext manages the context that a Call instance executes on
 type callContext struct {
+	// ctx cancels the call once done
+	ctx context.Context
 	// state that the call is running on
 	state core.StateReader
 	log   utils.SimpleLogger
@@ -107,6 +112,16 @@ func unwrapContext(readerHandle C.uintptr_t) *callContext {
 	return context
 }
 
+// JunoIsCancelled is polled by the VM, which aborts the execution once it returns 1.
+//
+//export JunoIsCancelled
+func JunoIsCancelled(readerHandle C.uintptr_t) C.uchar {
+	if unwrapContext(readerHandle).ctx.Err() != nil {
+		return 1
+	}
+	return 0
+}
+
 //export JunoReportError
 func JunoReportError(readerHandle C.uintptr_t, txnIndex C.long, str *C.char) {
 	context := unwrapContext(readerHandle)
@@ -218,10 +233,11 @@ func makeCBlockInfo(blockInfo *BlockInfo) C.BlockInfo {
 	return cBlockInfo
 }
 
-func (v *vm) Call(callInfo *CallInfo, blockInfo *BlockInfo, state core.StateReader,
+func (v *vm) Call(ctx context.Context, callInfo *CallInfo, blockInfo *BlockInfo, state core.StateReader,
 	network *utils.Network, maxSteps uint64,
 ) ([]*felt.Felt, error) {
 	context := &callContext{
+		ctx:      ctx,
 		state:    state,
 		response: []*felt.Felt{},
 		log:      v.log,
@@ -250,6 +266,9 @@ func (v *vm) Call(callInfo *CallInfo, blockInfo *BlockInfo, state core.StateRead
 	C.free(unsafe.Pointer(chainID))
 	C.free(unsafe.Pointer(cBlockInfo.version))
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if context.err != "" {
 		return nil, errors.New(context.err)
 	}
@@ -257,11 +276,12 @@ func (v *vm) Call(callInfo *CallInfo, blockInfo *BlockInfo, state core.StateRead
 }
 
 // Execute executes a given transaction set and returns the gas spent per transaction
-func (v *vm) Execute(txns []core.Transaction, declaredClasses []core.Class, paidFeesOnL1 []*felt.Felt,
+func (v *vm) Execute(ctx context.Context, txns []core.Transaction, declaredClasses []core.Class, paidFeesOnL1 []*felt.Felt,
 	blockInfo *BlockInfo, state core.StateReader, network *utils.Network,
 	skipChargeFee, skipValidate, errOnRevert bool,
 ) ([]*felt.Felt, []core.GasConsumed, []TransactionTrace, uint64, error) {
 	context := &callContext{
+		ctx:   ctx,
 		state: state,
 		log:   v.log,
 	}
@@ -322,6 +342,9 @@ func (v *vm) Execute(txns []core.Transaction, declaredClasses []core.Class, paid
 	C.free(unsafe.Pointer(chainID))
 	C.free(unsafe.Pointer(cBlockInfo.version))
 
+	if err := ctx.Err(); err != nil {
+		return nil, nil, nil, 0, err
+	}
 	if context.err != "" {
 		if context.errTxnIndex >= 0 {
 			return nil, nil, nil, 0, TransactionExecutionError{
@@ -46,7 +46,7 @@ func TestV0Call(t *testing.T) {
 
 	entryPoint := utils.HexToFelt(t, "0x39e11d48192e4333233c7eb19d10ad67c362bb28580c604d67884c85da39695")
 
-	ret, err := New(false, nil).Call(&CallInfo{
+	ret, err := New(false, nil).Call(context.Background(), &CallInfo{
 		ContractAddress: contractAddr,
 		ClassHash:       classHash,
 		Selector:        entryPoint,
@@ -66,7 +66,7 @@ func TestV0Call(t *testing.T) {
 		},
 	}, nil))
 
-	ret, err = New(false, nil).Call(&CallInfo{
+	ret, err = New(false, nil).Call(context.Background(), &CallInfo{
 		ContractAddress: contractAddr,
 		ClassHash:       classHash,
 		Selector:        entryPoint,
@@ -110,7 +110,7 @@ func TestV1Call(t *testing.T) {
 	// test_storage_read
 	entryPoint := utils.HexToFelt(t, "0x5df99ae77df976b4f0e5cf28c7dcfe09bd6e81aab787b19ac0c08e03d928cf")
 	storageLocation := utils.HexToFelt(t, "0x44")
-	ret, err := New(false, log).Call(&CallInfo{
+	ret, err := New(false, log).Call(context.Background(), &CallInfo{
 		ContractAddress: contractAddr,
 		Selector:        entryPoint,
 		Calldata: []felt.Felt{
@@ -132,7 +132,7 @@ func TestV1Call(t *testing.T) {
 		},
 	}, nil))
 
-	ret, err = New(false, log).Call(&CallInfo{
+	ret, err = New(false, log).Call(context.Background(), &CallInfo{
 		ContractAddress: contractAddr,
 		Selector:        entryPoint,
 		Calldata: []felt.Felt{
@@ -174,7 +174,7 @@ func TestCall_MaxSteps(t *testing.T) {
 
 	entryPoint := utils.HexToFelt(t, "0x39e11d48192e4333233c7eb19d10ad67c362bb28580c604d67884c85da39695")
 
-	_, err = New(false, nil).Call(&CallInfo{
+	_, err = New(false, nil).Call(context.Background(), &CallInfo{
 		ContractAddress: contractAddr,
 		ClassHash:       classHash,
 		Selector:        entryPoint,
@@ -195,7 +195,7 @@ func TestExecute(t *testing.T) {
 	state := core.NewState(txn)
 
 	t.Run("empty transaction list", func(t *testing.T) {
-		_, _, _, _, err := New(false, nil).Execute([]core.Transaction{}, []core.Class{}, []*felt.Felt{}, &BlockInfo{
+		_, _, _, _, err := New(false, nil).Execute(context.Background(), []core.Transaction{}, []core.Class{}, []*felt.Felt{}, &BlockInfo{
 			Header: &core.Header{
 				Timestamp:        1666877926,
 				SequencerAddress: utils.HexToFelt(t, "0x46a89ae102987331d369645031b49c27738ed096f2789c24449966da4c6de6b"),
@@ -207,7 +207,7 @@ func TestExecute(t *testing.T) {
 		require.NoError(t, err)
 	})
 	t.Run("zero data", func(t *testing.T) {
-		_, _, _, _, err := New(false, nil).Execute(nil, nil, []*felt.Felt{}, &BlockInfo{
+		_, _, _, _, err := New(false, nil).Execute(context.Background(), nil, nil, []*felt.Felt{}, &BlockInfo{
 			Header: &core.Header{
 				SequencerAddress: &felt.Zero,
 				GasPrice:         &felt.Zero,